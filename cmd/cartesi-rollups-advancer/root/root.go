@@ -9,6 +9,7 @@ import (
 	"github.com/cartesi/rollups-node/internal/advancer"
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/repository/factory"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/cartesi/rollups-node/pkg/service"
 
@@ -89,25 +90,37 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	logOutput, err := service.OpenLogOutput(cfg.LogOutput)
+	cobra.CheckErr(err)
+
 	createInfo := advancer.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
 			LogColor:             cfg.LogColor,
+			LogFormat:            cfg.LogFormat,
+			LogOutput:            logOutput,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
 			TelemetryAddress:     cfg.TelemetryAddress,
+			ShutdownTimeout:      cfg.MaxShutdownTime,
 			PollInterval:         cfg.AdvancerPollingInterval,
 		},
 		Config: *cfg,
 	}
-	var err error
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
+	cobra.CheckErr(err)
+	defer shutdownTracing(context.Background())
+
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(ctx, cfg.DatabaseConnection.String())
 	cobra.CheckErr(err)
-	defer createInfo.Repository.Close()
 
 	advancerService, err := advancer.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
+	defer func() {
+		advancerService.Logger.Info("Closing the repository")
+		createInfo.Repository.Close()
+	}()
 
 	cobra.CheckErr(advancerService.Serve())
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/cartesi/rollups-node/internal/claimer"
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/repository/factory"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/cartesi/rollups-node/pkg/service"
 
@@ -92,21 +93,31 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	logOutput, err := service.OpenLogOutput(cfg.LogOutput)
+	cobra.CheckErr(err)
+
 	createInfo := claimer.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
 			LogColor:             cfg.LogColor,
+			LogFormat:            cfg.LogFormat,
+			LogOutput:            logOutput,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
 			TelemetryAddress:     cfg.TelemetryAddress,
+			ShutdownTimeout:      cfg.MaxShutdownTime,
 			PollInterval:         cfg.ClaimerPollingInterval,
 		},
 		Config: *cfg,
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
+	cobra.CheckErr(err)
+	defer shutdownTracing(context.Background())
+
 	rclient := retryablehttp.NewClient()
-	rclient.Logger = service.NewLogger(cfg.LogLevel, cfg.LogColor).With("service", serviceName)
+	rclient.Logger = service.NewLoggerTo(logOutput, cfg.LogFormat, cfg.LogLevel, cfg.LogColor).With("service", serviceName)
 	rclient.RetryMax = int(cfg.BlockchainHttpMaxRetries)
 	rclient.RetryWaitMin = cfg.BlockchainHttpRetryMinWait
 	rclient.RetryWaitMax = cfg.BlockchainHttpRetryMaxWait
@@ -118,10 +129,13 @@ func run(cmd *cobra.Command, args []string) {
 
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(ctx, cfg.DatabaseConnection.String())
 	cobra.CheckErr(err)
-	defer createInfo.Repository.Close()
 
 	claimerService, err := claimer.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
+	defer func() {
+		claimerService.Logger.Info("Closing the repository")
+		createInfo.Repository.Close()
+	}()
 
 	err = claimerService.Serve()
 	cobra.CheckErr(err)
@@ -5,6 +5,7 @@ package app
 
 import (
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/app/execution-parameters"
+	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/app/history"
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/app/list"
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/app/register"
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/app/remove"
@@ -21,6 +22,7 @@ func init() {
 	Cmd.AddCommand(register.Cmd)
 	Cmd.AddCommand(list.Cmd)
 	Cmd.AddCommand(status.Cmd)
+	Cmd.AddCommand(history.Cmd)
 	Cmd.AddCommand(remove.Cmd)
 	Cmd.AddCommand(execution.Cmd)
 }
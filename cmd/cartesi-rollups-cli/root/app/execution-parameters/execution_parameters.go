@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -40,13 +41,20 @@ cartesi-rollups-cli app execution-parameters load echo-dapp < echo-dapp-params.j
 Note: Duration values can be set using time suffixes (e.g., "11s", "1m", "1h", or "1h20m0.5s").
       When using 'dump' and 'load', durations are represented in nanoseconds.
 
-      Snapshot policy is one of: NONE, EVERY_INPUT, EVERY_EPOCH`
+      Snapshot policy is one of: NONE, EVERY_INPUT, EVERY_EPOCH, EVERY_N_INPUTS,
+      EVERY_DURATION, EVERY_N_CYCLES. The last three snapshot after
+      snapshot_input_interval inputs, snapshot_time_interval of wall-clock time
+      or snapshot_cycle_interval machine cycles since the previous snapshot.
+
+      Retained epochs is how many accepted epochs keep their input and report
+      payloads when running 'cartesi-rollups-cli db prune'. Zero retains all.`
 
 const maxJSONSize = 1 << 20 // 1MB limit
 const maxParamLength = 100
 const maxValueLength = 100
 const maxDuration = 24 * time.Hour
 const maxConcurrentInspects = 1000
+const validSnapshotPolicies = "NONE, EVERY_INPUT, EVERY_EPOCH, EVERY_N_INPUTS, EVERY_DURATION, EVERY_N_CYCLES"
 
 func setHelpFunc(cmd *cobra.Command) {
 	origHelpFunc := cmd.HelpFunc()
@@ -302,7 +310,15 @@ func runLoad(cmd *cobra.Command, args []string) {
 		cobra.CheckErr(fmt.Errorf("input exceeds maximum allowed size of %d bytes", maxJSONSize))
 	}
 
-	var params model.ExecutionParameters
+	// Fields missing from the input, like the ones added after it was dumped,
+	// keep their current values
+	current, err := repo.GetExecutionParameters(ctx, app.ID)
+	cobra.CheckErr(err)
+	if current == nil {
+		fmt.Fprintf(os.Stderr, "execution parameters of %q not found\n", nameOrAddress)
+		os.Exit(1)
+	}
+	params := *current
 	decoder := json.NewDecoder(strings.NewReader(string(data)))
 	decoder.DisallowUnknownFields() // Prevent unexpected fields
 	err = decoder.Decode(&params)
@@ -351,6 +367,14 @@ func getParameterValue(params *model.ExecutionParameters, parameter string) (str
 		return params.FastDeadline.String(), nil
 	case "max_concurrent_inspects":
 		return fmt.Sprintf("%d", params.MaxConcurrentInspects), nil
+	case "retained_epochs":
+		return fmt.Sprintf("%d", params.RetainedEpochs), nil
+	case "snapshot_input_interval":
+		return fmt.Sprintf("%d", params.SnapshotInputInterval), nil
+	case "snapshot_time_interval":
+		return params.SnapshotTimeInterval.String(), nil
+	case "snapshot_cycle_interval":
+		return fmt.Sprintf("%d", params.SnapshotCycleInterval), nil
 	default:
 		return "", fmt.Errorf("unknown parameter: %s", parameter)
 	}
@@ -366,12 +390,10 @@ func setParameterValue(params *model.ExecutionParameters, parameter, value strin
 	switch parameter {
 	case "snapshot_policy":
 		value = strings.ToUpper(value)
-		switch model.SnapshotPolicy(value) {
-		case model.SnapshotPolicy_None, model.SnapshotPolicy_EveryInput, model.SnapshotPolicy_EveryEpoch:
-			params.SnapshotPolicy = model.SnapshotPolicy(value)
-		default:
-			return fmt.Errorf("invalid snapshot policy: %s. Valid values are: NONE, EVERY_INPUT, EVERY_EPOCH", value)
+		if !slices.Contains(model.SnapshotPolicyAllValues, model.SnapshotPolicy(value)) {
+			return fmt.Errorf("invalid snapshot policy: %s. Valid values are: %s", value, validSnapshotPolicies)
 		}
+		params.SnapshotPolicy = model.SnapshotPolicy(value)
 	case "advance_inc_cycles":
 		val, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
@@ -444,6 +466,30 @@ func setParameterValue(params *model.ExecutionParameters, parameter, value strin
 			return fmt.Errorf("invalid value for max_concurrent_inspects: %w", err)
 		}
 		params.MaxConcurrentInspects = uint32(val)
+	case "retained_epochs":
+		val, err := strconv.ParseUint(value, 10, 63)
+		if err != nil {
+			return fmt.Errorf("invalid value for retained_epochs: %w", err)
+		}
+		params.RetainedEpochs = val
+	case "snapshot_input_interval":
+		val, err := strconv.ParseUint(value, 10, 63)
+		if err != nil {
+			return fmt.Errorf("invalid value for snapshot_input_interval: %w", err)
+		}
+		params.SnapshotInputInterval = val
+	case "snapshot_time_interval":
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for snapshot_time_interval: %w", err)
+		}
+		params.SnapshotTimeInterval = duration
+	case "snapshot_cycle_interval":
+		val, err := strconv.ParseUint(value, 10, 63)
+		if err != nil {
+			return fmt.Errorf("invalid value for snapshot_cycle_interval: %w", err)
+		}
+		params.SnapshotCycleInterval = val
 	default:
 		return fmt.Errorf("unknown parameter: %s", parameter)
 	}
@@ -487,14 +533,20 @@ func validateParameters(params *model.ExecutionParameters) error {
 	}
 
 	// Validate snapshot policy
-	validPolicy := false
-	switch params.SnapshotPolicy {
-	case model.SnapshotPolicy_None, model.SnapshotPolicy_EveryInput, model.SnapshotPolicy_EveryEpoch:
-		validPolicy = true
+	if !slices.Contains(model.SnapshotPolicyAllValues, params.SnapshotPolicy) {
+		return fmt.Errorf("invalid snapshot policy: %s. Valid values are: %s", params.SnapshotPolicy, validSnapshotPolicies)
+	}
+
+	if params.SnapshotInputInterval == 0 {
+		return fmt.Errorf("snapshot_input_interval must be greater than 0")
+	}
+
+	if params.SnapshotTimeInterval <= 0 {
+		return fmt.Errorf("snapshot_time_interval must be greater than 0")
 	}
 
-	if !validPolicy {
-		return fmt.Errorf("invalid snapshot policy: %s. Valid values are: NONE, EVERY_INPUT, EVERY_EPOCH", params.SnapshotPolicy)
+	if params.SnapshotCycleInterval == 0 {
+		return fmt.Errorf("snapshot_cycle_interval must be greater than 0")
 	}
 
 	return nil
@@ -514,4 +566,8 @@ func printParameters(params *model.ExecutionParameters) {
 	fmt.Printf("store_deadline: %s\n", params.StoreDeadline)
 	fmt.Printf("fast_deadline: %s\n", params.FastDeadline)
 	fmt.Printf("max_concurrent_inspects: %d\n", params.MaxConcurrentInspects)
+	fmt.Printf("retained_epochs: %d\n", params.RetainedEpochs)
+	fmt.Printf("snapshot_input_interval: %d\n", params.SnapshotInputInterval)
+	fmt.Printf("snapshot_time_interval: %s\n", params.SnapshotTimeInterval)
+	fmt.Printf("snapshot_cycle_interval: %d\n", params.SnapshotCycleInterval)
 }
@@ -0,0 +1,71 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository/factory"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "history [app-name-or-address]",
+	Short:   "Lists the state changes of an application",
+	Example: examples,
+	Args:    cobra.ExactArgs(1),
+	Run:     run,
+	Long: `
+Supported Environment Variables:
+  CARTESI_DATABASE_CONNECTION                    Database connection string`,
+}
+
+const examples = `# List the state changes of an application, the oldest first:
+cartesi-rollups-cli app history echo-dapp`
+
+func init() {
+	origHelpFunc := Cmd.HelpFunc()
+	Cmd.SetHelpFunc(func(command *cobra.Command, strings []string) {
+		command.Flags().Lookup("database-connection").Hidden = false
+		origHelpFunc(command, strings)
+	})
+}
+
+func run(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+
+	nameOrAddress, err := config.ToApplicationNameOrAddressFromString(args[0])
+	cobra.CheckErr(err)
+
+	dsn, err := config.GetDatabaseConnection()
+	cobra.CheckErr(err)
+
+	repo, err := factory.NewRepositoryFromConnectionString(ctx, dsn.String())
+	cobra.CheckErr(err)
+	defer repo.Close()
+
+	app, err := repo.GetApplication(ctx, nameOrAddress)
+	cobra.CheckErr(err)
+	if app == nil {
+		fmt.Fprintf(os.Stderr, "application %q not found\n", nameOrAddress)
+		os.Exit(1)
+	}
+
+	history, err := repo.ListApplicationStateHistory(ctx, nameOrAddress)
+	cobra.CheckErr(err)
+
+	if history == nil {
+		history = []*model.ApplicationStateChange{}
+	}
+
+	result, err := json.MarshalIndent(history, "", "    ")
+	cobra.CheckErr(err)
+
+	fmt.Println(string(result))
+}
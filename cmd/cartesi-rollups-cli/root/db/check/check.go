@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/cartesi/rollups-node/internal/config"
-	"github.com/cartesi/rollups-node/internal/repository/postgres/schema"
+	"github.com/cartesi/rollups-node/internal/repository/factory"
 	"github.com/spf13/cobra"
 )
 
@@ -34,9 +34,9 @@ func run(cmd *cobra.Command, args []string) {
 	dsnURL, err := config.GetDatabaseConnection()
 	cobra.CheckErr(err)
 
-	var s *schema.Schema
+	var s factory.Schema
 	for i := range 5 {
-		s, err = schema.New(dsnURL.String())
+		s, err = factory.NewSchemaFromConnectionString(dsnURL.String())
 		if err == nil {
 			break
 		}
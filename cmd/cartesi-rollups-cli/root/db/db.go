@@ -5,6 +5,7 @@ package db
 import (
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/db/check"
 	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/db/init"
+	"github.com/cartesi/rollups-node/cmd/cartesi-rollups-cli/root/db/prune"
 
 	"github.com/spf13/cobra"
 )
@@ -17,4 +18,5 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(initialize.Cmd)
 	Cmd.AddCommand(check.Cmd)
+	Cmd.AddCommand(prune.Cmd)
 }
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/internal/repository/factory"
 	"github.com/cartesi/rollups-node/internal/repository/postgres/schema"
+	sqliteschema "github.com/cartesi/rollups-node/internal/repository/sqlite/schema"
 	"github.com/spf13/cobra"
 )
 
@@ -36,14 +38,14 @@ func init() {
 }
 
 func run(cmd *cobra.Command, args []string) {
-	var s *schema.Schema
+	var s factory.Schema
 	var err error
 
 	dsnURL, err := config.GetDatabaseConnection()
 	cobra.CheckErr(err)
 
 	for i := range 5 {
-		s, err = schema.New(dsnURL.String())
+		s, err = factory.NewSchemaFromConnectionString(dsnURL.String())
 		if err == nil {
 			break
 		}
@@ -60,7 +62,8 @@ func run(cmd *cobra.Command, args []string) {
 	// Check if a schema already exists
 	version, _, err := s.Version()
 	if err != nil {
-		if errors.Is(err, schema.ErrNoValidDatabaseSchema) {
+		if errors.Is(err, schema.ErrNoValidDatabaseSchema) ||
+			errors.Is(err, sqliteschema.ErrNoValidDatabaseSchema) {
 			// No schema exists, so create it
 			err = s.Upgrade()
 			cobra.CheckErr(err)
@@ -73,7 +76,7 @@ func run(cmd *cobra.Command, args []string) {
 		}
 		cobra.CheckErr(err)
 	}
-	if version == schema.ExpectedVersion {
+	if _, err := s.ValidateVersion(); err == nil {
 		fmt.Printf("Database Schema is already at the correct version: %d\n", version)
 		return
 	}
@@ -0,0 +1,104 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+package prune
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/factory"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "prune [app-name-or-address]",
+	Short:   "Prune the payloads of old accepted epochs",
+	Example: examples,
+	Args:    cobra.MaximumNArgs(1),
+	Run:     run,
+	Long: `
+Clears the input and report payloads of the accepted epochs that fall out of
+the application retention policy (the retained_epochs execution parameter).
+Outputs and their proofs are always kept, and so are the inputs after the
+last snapshot. The node already applies the policy whenever a claim is
+accepted; this command applies it right away, or with another number of
+epochs. Without arguments, every application is pruned.
+
+Supported Environment Variables:
+  CARTESI_DATABASE_CONNECTION                    Database connection string`,
+}
+
+const examples = `# Prune every application according to its retention policy:
+cartesi-rollups-cli db prune
+
+# Prune a single application:
+cartesi-rollups-cli db prune echo-dapp
+
+# Keep only the last 10 accepted epochs, regardless of the policy:
+cartesi-rollups-cli db prune echo-dapp --retained-epochs 10`
+
+var retainedEpochs uint64
+
+func init() {
+	Cmd.Flags().Uint64Var(&retainedEpochs, "retained-epochs", 0,
+		"Number of accepted epochs to retain, overriding the application policy")
+
+	origHelpFunc := Cmd.HelpFunc()
+	Cmd.SetHelpFunc(func(command *cobra.Command, strings []string) {
+		command.Flags().Lookup("verbose").Hidden = false
+		command.Flags().Lookup("database-connection").Hidden = false
+		origHelpFunc(command, strings)
+	})
+}
+
+func run(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+
+	if cmd.Flags().Changed("retained-epochs") && retainedEpochs == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --retained-epochs must be greater than zero")
+		os.Exit(1)
+	}
+
+	dsn, err := config.GetDatabaseConnection()
+	cobra.CheckErr(err)
+
+	repo, err := factory.NewRepositoryFromConnectionString(ctx, dsn.String())
+	cobra.CheckErr(err)
+	defer repo.Close()
+
+	var apps []*model.Application
+	if len(args) == 1 {
+		nameOrAddress, err := config.ToApplicationNameOrAddressFromString(args[0])
+		cobra.CheckErr(err)
+
+		app, err := repo.GetApplication(ctx, nameOrAddress)
+		cobra.CheckErr(err)
+		if app == nil {
+			fmt.Fprintf(os.Stderr, "application %q not found\n", nameOrAddress)
+			os.Exit(1)
+		}
+		apps = append(apps, app)
+	} else {
+		apps, _, err = repo.ListApplications(ctx, repository.ApplicationFilter{}, repository.Pagination{}, false)
+		cobra.CheckErr(err)
+	}
+
+	for _, app := range apps {
+		if cmd.Flags().Changed("retained-epochs") {
+			app.ExecutionParameters.RetainedEpochs = retainedEpochs
+		}
+
+		result, err := repository.PruneApplication(ctx, repo, app)
+		cobra.CheckErr(err)
+		if result == nil {
+			fmt.Printf("Application %s: nothing to prune\n", app.Name)
+			continue
+		}
+		fmt.Printf("Application %s: pruned %d inputs and %d reports before epoch %d\n",
+			app.Name, result.Inputs, result.Reports, result.EpochIndex)
+	}
+}
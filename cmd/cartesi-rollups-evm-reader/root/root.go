@@ -10,6 +10,7 @@ import (
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/evmreader"
 	"github.com/cartesi/rollups-node/internal/repository/factory"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -109,20 +110,29 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	logOutput, err := service.OpenLogOutput(cfg.LogOutput)
+	cobra.CheckErr(err)
+
 	createInfo := evmreader.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
 			LogColor:             cfg.LogColor,
+			LogFormat:            cfg.LogFormat,
+			LogOutput:            logOutput,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
 			TelemetryAddress:     cfg.TelemetryAddress,
+			ShutdownTimeout:      cfg.MaxShutdownTime,
 		},
 		Config: *cfg,
 	}
 
-	var err error
-	logger := service.NewLogger(cfg.LogLevel, cfg.LogColor).With("service", serviceName)
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
+	cobra.CheckErr(err)
+	defer shutdownTracing(context.Background())
+
+	logger := service.NewLoggerTo(logOutput, cfg.LogFormat, cfg.LogLevel, cfg.LogColor).With("service", serviceName)
 	createInfo.EthClient, err = createEthClient(ctx, cfg.BlockchainHttpEndpoint.String(), logger)
 	cobra.CheckErr(err)
 
@@ -131,10 +141,13 @@ func run(cmd *cobra.Command, args []string) {
 
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(ctx, cfg.DatabaseConnection.String())
 	cobra.CheckErr(err)
-	defer createInfo.Repository.Close()
 
 	readerService, err := evmreader.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
+	defer func() {
+		readerService.Logger.Info("Closing the repository")
+		createInfo.Repository.Close()
+	}()
 
 	cobra.CheckErr(readerService.Serve())
 }
@@ -71,24 +71,32 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	logOutput, err := service.OpenLogOutput(cfg.LogOutput)
+	cobra.CheckErr(err)
+
 	createInfo := jsonrpc.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
 			LogColor:             cfg.LogColor,
-			EnableSignalHandling: false,
+			LogFormat:            cfg.LogFormat,
+			LogOutput:            logOutput,
+			EnableSignalHandling: true,
 			TelemetryCreate:      true,
 			TelemetryAddress:     cfg.TelemetryAddress,
+			ShutdownTimeout:      cfg.MaxShutdownTime,
 		},
 		Config: *cfg,
 	}
-	var err error
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(ctx, cfg.DatabaseConnection.String())
 	cobra.CheckErr(err)
-	defer createInfo.Repository.Close()
 
 	jsonrpcService, err := jsonrpc.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
+	defer func() {
+		jsonrpcService.Logger.Info("Closing the repository")
+		createInfo.Repository.Close()
+	}()
 
 	cobra.CheckErr(jsonrpcService.Serve())
 }
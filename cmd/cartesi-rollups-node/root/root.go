@@ -10,6 +10,7 @@ import (
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/node"
 	"github.com/cartesi/rollups-node/internal/repository/factory"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -151,36 +152,48 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	logOutput, err := service.OpenLogOutput(cfg.LogOutput)
+	cobra.CheckErr(err)
+
 	createInfo := node.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
 			LogColor:             cfg.LogColor,
+			LogFormat:            cfg.LogFormat,
+			LogOutput:            logOutput,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
 			TelemetryAddress:     cfg.TelemetryAddress,
+			ShutdownTimeout:      cfg.MaxShutdownTime,
 		},
 		Config: *cfg,
 	}
 
-	var err error
-	logger := service.NewLogger(cfg.LogLevel, cfg.LogColor).With("service", "evm-reader")
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
+	cobra.CheckErr(err)
+	defer shutdownTracing(context.Background())
+
+	logger := service.NewLoggerTo(logOutput, cfg.LogFormat, cfg.LogLevel, cfg.LogColor).With("service", "evm-reader")
 	createInfo.ReaderClient, err = createEthClient(ctx, cfg.BlockchainHttpEndpoint.String(), logger)
 	cobra.CheckErr(err)
 
 	createInfo.ReaderWSClient, err = ethclient.DialContext(ctx, cfg.BlockchainWsEndpoint.String())
 	cobra.CheckErr(err)
 
-	logger = service.NewLogger(cfg.LogLevel, cfg.LogColor).With("service", "claimer")
+	logger = service.NewLoggerTo(logOutput, cfg.LogFormat, cfg.LogLevel, cfg.LogColor).With("service", "claimer")
 	createInfo.ClaimerClient, err = createEthClient(ctx, cfg.BlockchainHttpEndpoint.String(), logger)
 	cobra.CheckErr(err)
 
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(ctx, cfg.DatabaseConnection.String())
 	cobra.CheckErr(err)
-	defer createInfo.Repository.Close()
 
 	nodeService, err := node.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
+	defer func() {
+		nodeService.Logger.Info("Closing the repository")
+		createInfo.Repository.Close()
+	}()
 
 	cobra.CheckErr(nodeService.Serve())
 }
@@ -9,11 +9,11 @@ import (
 	"os"
 	"time"
 
-	"github.com/cartesi/rollups-node/internal/repository/postgres/schema"
+	"github.com/cartesi/rollups-node/internal/repository/factory"
 )
 
 func main() {
-	var s *schema.Schema
+	var s factory.Schema
 	var err error
 
 	dbConnString, ok := os.LookupEnv("CARTESI_DATABASE_CONNECTION")
@@ -29,7 +29,7 @@ func main() {
 	}
 
 	for i := 0; i < 5; i++ {
-		s, err = schema.New(uri.String())
+		s, err = factory.NewSchemaFromConnectionString(uri.String())
 		if err == nil {
 			break
 		}
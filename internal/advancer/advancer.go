@@ -11,13 +11,18 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/inspect"
 	"github.com/cartesi/rollups-node/internal/manager"
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/pkg/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -28,6 +33,8 @@ var (
 	ErrNoInputs = errors.New("no inputs")
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/internal/advancer")
+
 // AdvancerRepository defines the repository interface needed by the Advancer service
 type AdvancerRepository interface {
 	ListInputs(ctx context.Context, nameOrAddress string, f repository.InputFilter, p repository.Pagination, descending bool) ([]*Input, uint64, error)
@@ -35,16 +42,46 @@ type AdvancerRepository interface {
 	StoreAdvanceResult(ctx context.Context, appID int64, ar *AdvanceResult) error
 	UpdateEpochsInputsProcessed(ctx context.Context, nameOrAddress string) (int64, error)
 	UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error
+	SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error
+	ListApplications(ctx context.Context, f repository.ApplicationFilter, p repository.Pagination, descending bool) ([]*Application, uint64, error)
 	GetEpoch(ctx context.Context, nameOrAddress string, index uint64) (*Epoch, error)
+	ListEpochs(ctx context.Context, nameOrAddress string, f repository.EpochFilter, p repository.Pagination, descending bool) ([]*Epoch, uint64, error)
 	UpdateInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64, snapshotURI string) error
-	GetLastSnapshot(ctx context.Context, nameOrAddress string) (*Input, error)
+	ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error
+	ListSnapshots(ctx context.Context, nameOrAddress string) ([]*Input, error)
+	ListSnapshotURIs(ctx context.Context) ([]string, error)
 	GetLastProcessedInput(ctx context.Context, appAddress string) (*Input, error)
 }
 
 // Service is the main advancer service that processes inputs through Cartesi machines
 type Service struct {
 	service.Service
-	snapshotsDir   string
+	// configuration in effect, updated by Reload
+	config            config.AdvancerConfig
+	snapshotsDir      string
+	snapshotRetention config.SnapshotRetention
+	snapshotsRetained uint64
+	// held for reading while a snapshot is created and for writing while the
+	// unreferenced ones are removed
+	snapshotsMutex sync.RWMutex
+	// held while snapshots are pruned and while an uploaded one replaces its
+	// local copy in the input
+	pruneMutex sync.Mutex
+	// nil unless snapshots are uploaded to a remote storage
+	snapshotStorage snapshot.Storage
+	snapshotUploads chan snapshotUpload
+	// progress of each application since its last snapshot, for the
+	// interval snapshot policies
+	snapshotProgress      map[int64]*snapshotProgress
+	snapshotProgressMutex sync.Mutex
+	// advance the applications apart from each other
+	workers *workerPool
+	// attempts to recover the inoperable applications, used by
+	// recoverApplications only
+	recoveries map[int64]*recovery
+	// stops the recovery loop, which closes recoveryDone once it returns
+	recoveryCancel context.CancelFunc
+	recoveryDone   chan struct{}
 	repository     AdvancerRepository
 	machineManager manager.MachineProvider
 	inspector      *inspect.Inspector
@@ -69,6 +106,12 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 	s := &Service{}
 	c.Impl = s
 
+	// wake up on new inputs and closed epochs instead of waiting for the next poll
+	if c.NotifyConnection == "" && c.Config.DatabaseConnection != nil {
+		c.NotifyConnection = c.Config.DatabaseConnection.String()
+		c.NotifyChannels = []string{repository.InputAddedChannel, repository.EpochClosedChannel}
+	}
+
 	err = service.Create(ctx, &c.CreateInfo, &s.Service)
 	if err != nil {
 		return nil, err
@@ -88,6 +131,9 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		c.Config.FeatureMachineHashCheckEnabled,
 	)
 	s.machineManager = manager
+	if err := manager.SetServersDir(c.Config.RemoteMachinePidsDir); err != nil {
+		s.Logger.Warn("Machine servers left by a previous run are not killed", "error", err)
+	}
 
 	// Initialize the inspect service if enabled
 	if c.Config.FeatureInspectEnabled {
@@ -95,28 +141,113 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 			c.Repository,
 			manager,
 			c.Config.InspectAddress,
-			c.LogLevel,
-			c.LogColor,
+			service.NewLoggerTo(c.LogOutput, c.LogFormat, s.LogLevel, c.LogColor).With("service", "inspect"),
 		)
 	}
 
+	s.config = c.Config
 	s.snapshotsDir = c.Config.SnapshotsDir
+	s.snapshotRetention = c.Config.SnapshotsRetention
+	s.snapshotsRetained = c.Config.SnapshotsRetained
+	s.workers = newWorkerPool(c.Config.AdvancerMaxConcurrency)
+
+	uri, err := config.GetSnapshotsRemoteUri()
+	if err == nil {
+		s.snapshotStorage, err = snapshot.New(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the remote snapshot storage: %w", err)
+		}
+		manager.SetSnapshotStorage(s.snapshotStorage, s.snapshotsDir)
+		s.snapshotUploads = make(chan snapshotUpload, snapshotUploadQueueSize)
+		go s.uploadSnapshots(s.Context)
+	} else if !errors.Is(err, config.ErrNotDefined) {
+		return nil, err
+	}
+
+	if c.Config.SnapshotsGcInterval > 0 {
+		go s.collectSnapshotsEvery(s.Context, c.Config.SnapshotsGcInterval)
+	}
+
+	// apart from the ticks, so rebuilding a machine does not delay the others
+	if c.Config.FeatureApplicationRecoveryEnabled && c.Config.AdvancerPollingInterval > 0 {
+		s.startRecovery(c.Config.AdvancerPollingInterval)
+	}
+
+	s.AddHealthCheck("database", c.Repository.Ping)
+	s.AddHealthCheck("tick", s.TickCheck)
+	s.AddHealthCheck("machines", s.checkMachines)
 
 	return s, nil
 }
 
 // Service interface implementation
-func (s *Service) Alive() bool     { return true }
-func (s *Service) Ready() bool     { return true }
-func (s *Service) Reload() []error { return nil }
+func (s *Service) Alive() bool {
+	// a machine that does not respond only makes the service unready, as its
+	// application is recovered apart from the others
+	return s.Running.Load() && s.TickCheck(s.Context) == nil
+}
+func (s *Service) Ready() bool { return s.Health(s.Context).Ready }
+
+// Reload applies the log level, the polling interval and the limits of
+// concurrent inspects of the applications
+func (s *Service) Reload() []error {
+	c, err := config.LoadAdvancerConfig()
+	if err != nil {
+		return []error{err}
+	}
+	s.SetLogLevel(c.LogLevel)
+	s.SetPollInterval(c.AdvancerPollingInterval)
+	s.config.LogLevel = c.LogLevel
+	s.config.AdvancerPollingInterval = c.AdvancerPollingInterval
+	s.ReportRestartRequired(config.Changed(&s.config, c))
+
+	if err := s.machineManager.Reload(s.Context); err != nil {
+		return []error{err}
+	}
+	return nil
+}
 func (s *Service) Tick() []error {
 	if err := s.Step(s.Context); err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			return joined.Unwrap()
+		}
 		return []error{err}
 	}
 	return []error{}
 }
+
+// Stop shuts down the inspect server and then the machines, after the inputs
+// in progress were processed and the recovery in progress was canceled
 func (s *Service) Stop(b bool) []error {
-	return nil
+	var errs []error
+	if s.recoveryCancel != nil {
+		s.recoveryCancel()
+		<-s.recoveryDone
+	}
+	if !s.workers.wait(s.ShutdownTimeout) {
+		s.Logger.Warn("The applications did not finish advancing in time, canceling them",
+			"timeout", s.ShutdownTimeout)
+		s.Cancel()
+		if !s.workers.wait(s.ShutdownTimeout) {
+			s.Logger.Error("The applications did not stop after being canceled",
+				"timeout", s.ShutdownTimeout)
+		}
+	}
+	s.workers.close()
+	if s.HTTPServer != nil {
+		s.Logger.Info("Stopping the inspect server")
+		ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+		if err := s.HTTPServer.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		cancel()
+	}
+	s.Logger.Info("Closing the machines")
+	if err := s.machineManager.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
 }
 func (s *Service) Serve() error {
 	if s.inspector != nil && s.HTTPServerFunc != nil {
@@ -128,62 +259,102 @@ func (s *Service) String() string {
 	return s.Name
 }
 
-// getUnprocessedInputs retrieves inputs that haven't been processed yet
-func getUnprocessedInputs(ctx context.Context, repo AdvancerRepository, appAddress string) ([]*Input, uint64, error) {
+// checkMachines fails when the machine of any application does not respond
+func (s *Service) checkMachines(ctx context.Context) error {
+	var errs []error
+	for _, app := range s.machineManager.Applications() {
+		machine, exists := s.machineManager.GetMachine(app.ID)
+		if !exists {
+			continue
+		}
+		if err := machine.Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("machine of application %v: %w", app.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getUnprocessedInputs retrieves a page of the inputs that haven't been
+// processed yet, after the given index if any
+func getUnprocessedInputs(
+	ctx context.Context,
+	repo AdvancerRepository,
+	appAddress string,
+	after *uint64,
+) ([]*Input, uint64, error) {
 	f := repository.InputFilter{Status: Pointer(InputCompletionStatus_None)}
-	return repo.ListInputs(ctx, appAddress, f, repository.Pagination{}, false)
+	p := repository.Pagination{Limit: repository.DefaultStreamPageSize, After: after}
+	return repo.ListInputs(ctx, appAddress, f, p, false)
 }
 
 // Step performs one processing cycle of the advancer
-// It updates machines, gets unprocessed inputs, processes them, and updates epochs
+// It updates machines and wakes up the worker of each application, which
+// advances it apart from the others, up to AdvancerMaxConcurrency at a time.
+// Step does not wait for them: an application still busy since a previous
+// Step, or being recovered, is skipped. It returns the joined errors of the
+// applications that finished since the previous Step.
 func (s *Service) Step(ctx context.Context) error {
 	// Check for context cancellation
 	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	// Update the machine manager with any new or disabled applications
-	err := s.machineManager.UpdateMachines(ctx)
+	// Update the machine manager with any new or disabled applications,
+	// except those still being advanced or being recovered.
+	// Snapshots are not collected meanwhile, as they may be downloaded.
+	s.snapshotsMutex.RLock()
+	err := s.machineManager.UpdateMachines(ctx, s.workers.busyApplications())
+	s.snapshotsMutex.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	// Get all applications with active machines
-	apps := s.machineManager.Applications()
+	// Advance all applications with active machines
+	skipped := s.workers.dispatch(ctx, s.machineManager.Applications(), s.stepApplication)
+	for _, app := range skipped {
+		s.Logger.Debug("Application is still being advanced, skipping it", "application", app.Name)
+	}
+	return s.workers.takeErrors()
+}
 
-	// Process inputs for each application
-	for _, app := range apps {
-		appAddress := app.IApplicationAddress.String()
+// stepApplication processes the unprocessed inputs of an application in order
+// and updates its epochs
+func (s *Service) stepApplication(ctx context.Context, app *Application) error {
+	appAddress := app.IApplicationAddress.String()
 
-		err := s.handleEpochSnapshotAfterInputProcessed(ctx, app)
-		if err != nil {
-			return err
-		}
+	err := s.handleEpochSnapshotAfterInputProcessed(ctx, app)
+	if err != nil {
+		return err
+	}
 
-		// Get unprocessed inputs for this application
+	// Process the unprocessed inputs of this application, a page at a time
+	var after *uint64
+	for {
 		s.Logger.Debug("Querying for unprocessed inputs", "application", app.Name)
-		inputs, _, err := getUnprocessedInputs(ctx, s.repository, appAddress)
+		inputs, _, err := getUnprocessedInputs(ctx, s.repository, appAddress, after)
 		if err != nil {
 			return err
 		}
 
-		// Process the inputs
 		s.Logger.Debug("Processing inputs", "application", app.Name, "count", len(inputs))
 		err = s.processInputs(ctx, app, inputs)
 		if err != nil {
 			return err
 		}
-
-		// Update epochs to mark inputs as processed
-		rows, err := s.repository.UpdateEpochsInputsProcessed(ctx, appAddress)
-		if err != nil {
-			return err
-		}
-		if rows > 0 {
-			s.Logger.Info("Epochs updated to Inputs Processed", "application", app.Name, "count", rows)
+		if uint64(len(inputs)) < repository.DefaultStreamPageSize || s.Stopping() {
+			break
 		}
+		after = &inputs[len(inputs)-1].Index
 	}
 
+	// Update epochs to mark inputs as processed
+	rows, err := s.repository.UpdateEpochsInputsProcessed(ctx, appAddress)
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		s.Logger.Info("Epochs updated to Inputs Processed", "application", app.Name, "count", rows)
+	}
 	return nil
 }
 
@@ -206,63 +377,124 @@ func (s *Service) processInputs(ctx context.Context, app *Application, inputs []
 		if err := ctx.Err(); err != nil {
 			return err
 		}
+		// Leave the remaining inputs to the next run when stopping
+		if s.Stopping() {
+			return nil
+		}
+		if err := s.processInput(ctx, app, machine, input); err != nil {
+			return err
+		}
+		s.Heartbeat()
+	}
 
-		s.Logger.Info("Processing input",
-			"application", app.Name,
-			"epoch", input.EpochIndex,
-			"index", input.Index)
-
-		// Advance the machine with this input
-		result, err := machine.Advance(ctx, input.RawData, input.Index)
-		if err != nil {
-			// If there's an error, mark the application as inoperable
-			s.Logger.Error("Error executing advance",
-				"application", app.Name,
-				"index", input.Index,
-				"error", err)
+	return nil
+}
 
-			// If the error is due to context cancellation, don't mark as inoperable
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return err
-			}
+// processInput advances the machine of app with input and stores the result.
+func (s *Service) processInput(
+	ctx context.Context,
+	app *Application,
+	machine manager.MachineInstance,
+	input *Input,
+) (err error) {
+	ctx, span := tracing.Start(ctx, tracer, "ProcessInput",
+		tracing.ApplicationAddress(app.IApplicationAddress),
+		tracing.EpochIndex(input.EpochIndex),
+		tracing.InputIndex(input.Index))
+	defer func() { tracing.End(span, err) }()
+
+	s.Logger.Info("Processing input",
+		"application", app.Name,
+		"epoch_index", input.EpochIndex,
+		"input_index", input.Index)
 
-			reason := err.Error()
-			updateErr := s.repository.UpdateApplicationState(ctx, app.ID, ApplicationState_Inoperable, &reason)
-			if updateErr != nil {
-				s.Logger.Error("Failed to update application state",
-					"application", app.Name,
-					"error", updateErr)
-			}
+	// Advance the machine with this input
+	result, err := machine.Advance(ctx, input.RawData, input.Index)
+	if err != nil {
+		// If there's an error, mark the application as inoperable
+		s.Logger.Error("Error executing advance",
+			"application", app.Name,
+			"input_index", input.Index,
+			"error", err)
 
+		// If the error is due to context cancellation, don't mark as inoperable
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			return err
 		}
 
-		// Store the result in the database
-		err = s.repository.StoreAdvanceResult(ctx, input.EpochApplicationID, result)
-		if err != nil {
-			s.Logger.Error("Failed to store advance result",
-				"application", app.Name,
-				"index", input.Index,
-				"error", err)
+		// If the input was reverted by a chain reorganization after the machine
+		// processed it, the machine is rebuilt on the next step
+		if errors.Is(err, manager.ErrInvalidInputIndex) && input.Index < machine.ProcessedInputs() {
 			return err
 		}
 
-		// Create a snapshot if needed
-		if result.Status == InputCompletionStatus_Accepted {
-			err = s.handleSnapshot(ctx, app, machine, input)
-			if err != nil {
-				s.Logger.Error("Failed to create snapshot",
-					"application", app.Name,
-					"index", input.Index,
-					"error", err)
-				// Continue processing even if snapshot creation fails
-			}
+		updateErr := s.repository.SetApplicationInoperable(ctx, app.ID, reasonCode(err), err.Error())
+		if updateErr != nil {
+			s.Logger.Error("Failed to update application state",
+				"application", app.Name,
+				"error", updateErr)
 		}
+
+		return err
 	}
+	span.SetAttributes(attribute.String("cartesi.input.status", string(result.Status)))
 
+	// Store the result in the database
+	err = s.storeAdvanceResult(ctx, app, input, result)
+	if errors.Is(err, repository.ErrInputReverted) {
+		// the machine is rebuilt on the next step
+		s.Logger.Warn("Input was reverted while it was processed, discarding its result",
+			"application", app.Name,
+			"input_index", input.Index)
+		return err
+	}
+	if err != nil {
+		s.Logger.Error("Failed to store advance result",
+			"application", app.Name,
+			"input_index", input.Index,
+			"error", err)
+		return err
+	}
+
+	observeAdvance(app, result)
+	if err := s.addSnapshotCycles(ctx, app, result.Cycles); err != nil {
+		s.Logger.Error("Failed to count the cycles towards the next snapshot",
+			"application", app.Name,
+			"input_index", input.Index,
+			"error", err)
+	}
+
+	// Create a snapshot if needed
+	if result.Status == InputCompletionStatus_Accepted {
+		err := s.handleSnapshot(ctx, app, machine, input)
+		if err != nil {
+			s.Logger.Error("Failed to create snapshot",
+				"application", app.Name,
+				"input_index", input.Index,
+				"error", err)
+			// Continue processing even if snapshot creation fails
+		}
+	}
 	return nil
 }
 
+// storeAdvanceResult stores the result of input processed by app.
+func (s *Service) storeAdvanceResult(
+	ctx context.Context,
+	app *Application,
+	input *Input,
+	result *AdvanceResult,
+) (err error) {
+	ctx, span := tracing.Start(ctx, tracer, "StoreAdvanceResult",
+		tracing.ApplicationAddress(app.IApplicationAddress),
+		tracing.InputIndex(input.Index),
+		attribute.Int("cartesi.outputs.count", len(result.Outputs)),
+		attribute.Int("cartesi.reports.count", len(result.Reports)))
+	defer func() { tracing.End(span, err) }()
+	result.InputBlockHash = input.BlockHash
+	return s.repository.StoreAdvanceResult(ctx, input.EpochApplicationID, result)
+}
+
 // handleEpochSnapshotAfterInputProcessed handles the snapshot creation after when an epoch is closed after an input was processed
 func (s *Service) handleEpochSnapshotAfterInputProcessed(ctx context.Context, app *Application) error {
 	// Check if the application has a epoch snapshot policy
@@ -304,6 +536,21 @@ func (s *Service) handleSnapshot(ctx context.Context, app *Application, machine
 		return s.createSnapshot(ctx, app, machine, input)
 	}
 
+	// For the interval policies, create a snapshot once the interval since
+	// the last one has passed
+	if policy == SnapshotPolicy_EveryNInputs ||
+		policy == SnapshotPolicy_EveryDuration ||
+		policy == SnapshotPolicy_EveryNCycles {
+		due, err := s.snapshotDue(ctx, app, input)
+		if err != nil {
+			return err
+		}
+		if due {
+			return s.createSnapshot(ctx, app, machine, input)
+		}
+		return nil
+	}
+
 	// For EVERY_EPOCH policy, check if this is the last input of the epoch
 	if policy == SnapshotPolicy_EveryEpoch {
 		// Get the epoch for this input
@@ -337,9 +584,10 @@ func (s *Service) createSnapshot(ctx context.Context, app *Application, machine
 	if input.SnapshotURI != nil {
 		s.Logger.Debug("Skipping snapshot, input already has a snapshot",
 			"application", app.Name,
-			"epoch", input.EpochIndex,
-			"input", input.Index,
+			"epoch_index", input.EpochIndex,
+			"input_index", input.Index,
 			"path", *input.SnapshotURI)
+		s.resetSnapshotProgress(app, input)
 		return nil
 	}
 
@@ -350,8 +598,8 @@ func (s *Service) createSnapshot(ctx context.Context, app *Application, machine
 
 	s.Logger.Info("Creating snapshot",
 		"application", app.Name,
-		"epoch", input.EpochIndex,
-		"input", input.Index,
+		"epoch_index", input.EpochIndex,
+		"input_index", input.Index,
 		"path", snapshotPath)
 
 	// Ensure the parent directory exists
@@ -361,17 +609,31 @@ func (s *Service) createSnapshot(ctx context.Context, app *Application, machine
 		}
 	}
 
-	// Remove previous snapshot if it exists
-	previousSnapshot, err := s.repository.GetLastSnapshot(ctx, app.IApplicationAddress.String())
-	if err != nil {
-		s.Logger.Error("Failed to get previous snapshot",
+	if err := s.storeSnapshot(ctx, machine, input, snapshotPath); err != nil {
+		return err
+	}
+	s.resetSnapshotProgress(app, input)
+	s.queueSnapshotUpload(app, input, snapshotPath)
+
+	// Remove the snapshots the retention policy no longer keeps
+	if err := s.pruneSnapshots(ctx, app); err != nil {
+		s.Logger.Error("Failed to prune snapshots",
 			"application", app.Name,
 			"error", err)
-		// Continue even if we can't get the previous snapshot
+		// Continue even if we can't remove the previous snapshots
 	}
 
+	return nil
+}
+
+// storeSnapshot writes the snapshot and records it in the input, so it is
+// never seen unreferenced by collectSnapshots
+func (s *Service) storeSnapshot(ctx context.Context, machine manager.MachineInstance, input *Input, snapshotPath string) error {
+	s.snapshotsMutex.RLock()
+	defer s.snapshotsMutex.RUnlock()
+
 	// Create the snapshot
-	err = machine.CreateSnapshot(ctx, input.Index+1, snapshotPath)
+	err := machine.CreateSnapshot(ctx, input.Index+1, snapshotPath)
 	if err != nil {
 		return err
 	}
@@ -384,30 +646,22 @@ func (s *Service) createSnapshot(ctx context.Context, app *Application, machine
 	if err != nil {
 		return fmt.Errorf("failed to update input snapshot URI: %w", err)
 	}
-
-	// Remove previous snapshot if it exists
-	if previousSnapshot != nil && previousSnapshot.Index != input.Index && previousSnapshot.SnapshotURI != nil {
-		// Only remove if it's a different snapshot than the one we just created
-		if err := s.removeSnapshot(*previousSnapshot.SnapshotURI, app.Name); err != nil {
-			s.Logger.Error("Failed to remove previous snapshot",
-				"application", app.Name,
-				"snapshot", *previousSnapshot.SnapshotURI,
-				"error", err)
-			// Continue even if we can't remove the previous snapshot
-		}
-	}
-
 	return nil
 }
 
-// removeSnapshot safely removes a previous snapshot
-func (s *Service) removeSnapshot(snapshotPath string, appName string) error {
+// removeSnapshot safely removes a snapshot, along with the local copy of a
+// remote one
+func (s *Service) removeSnapshot(ctx context.Context, snapshotPath string, appName string) error {
+	if snapshot.IsRemote(snapshotPath) {
+		return s.removeRemoteSnapshot(ctx, snapshotPath, appName)
+	}
+
 	// Safety check: ensure the path contains the application name and is in the snapshots directory
 	if !strings.HasPrefix(snapshotPath, s.snapshotsDir) || !strings.Contains(snapshotPath, appName) {
 		return fmt.Errorf("invalid snapshot path: %s", snapshotPath)
 	}
 
-	s.Logger.Debug("Removing previous snapshot", "application", appName, "path", snapshotPath)
+	s.Logger.Debug("Removing snapshot", "application", appName, "path", snapshotPath)
 
 	// Check if the path exists before attempting to remove it
 	if _, err := os.Stat(snapshotPath); os.IsNotExist(err) {
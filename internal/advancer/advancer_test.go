@@ -4,22 +4,35 @@
 package advancer
 
 import (
+	"cmp"
 	"context"
 	crand "crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	mrand "math/rand"
+	"os"
+	"path"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/manager"
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -33,6 +46,7 @@ func newMockAdvancerService(machineManager *MockMachineManager, repo *MockReposi
 	s := &Service{
 		machineManager: machineManager,
 		repository:     repo,
+		workers:        newWorkerPool(1),
 	}
 	serviceArgs := &service.CreateInfo{Name: "advancer", Impl: s}
 	err := service.Create(context.Background(), serviceArgs, &s.Service)
@@ -42,6 +56,14 @@ func newMockAdvancerService(machineManager *MockMachineManager, repo *MockReposi
 	return s, nil
 }
 
+// stepAndWait runs Step and waits for the applications it woke up, returning
+// their errors too
+func stepAndWait(ctx context.Context, advancer *Service) error {
+	err := advancer.Step(ctx)
+	advancer.workers.running.Wait()
+	return errors.Join(err, advancer.workers.takeErrors())
+}
+
 func (s *AdvancerSuite) TestServiceInterface() {
 	s.Run("ServiceMethods", func() {
 		require := s.Require()
@@ -52,8 +74,11 @@ func (s *AdvancerSuite) TestServiceInterface() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
+		s.T().Setenv("CARTESI_DATABASE_CONNECTION", "memory://")
+		s.T().Setenv("CARTESI_TELEMETRY_ADDRESS", ":10000")
+
 		// Test service interface methods
-		require.True(advancer.Alive())
+		require.False(advancer.Alive()) // not serving yet
 		require.True(advancer.Ready())
 		require.Empty(advancer.Reload())
 		require.Empty(advancer.Stop(false))
@@ -66,13 +91,66 @@ func (s *AdvancerSuite) TestServiceInterface() {
 		}
 		tickErrors := advancer.Tick()
 		require.Empty(tickErrors)
+		advancer.workers.running.Wait()
 
-		// Test Tick with error
+		// Test Tick with error, reported by the next Tick
 		repository.UpdateEpochsError = errors.New("update epochs error")
+		require.Empty(advancer.Tick())
+		advancer.workers.running.Wait()
 		tickErrors = advancer.Tick()
 		require.NotEmpty(tickErrors)
 		require.Contains(tickErrors[0].Error(), "update epochs error")
 	})
+
+	s.Run("Reload", func() {
+		require := s.Require()
+
+		advancer, err := newMockAdvancerService(newMockMachineManager(), &MockRepository{})
+		require.Nil(err)
+		require.Equal(slog.LevelInfo, advancer.LogLevel.Level())
+
+		s.T().Setenv("CARTESI_DATABASE_CONNECTION", "memory://")
+		s.T().Setenv("CARTESI_TELEMETRY_ADDRESS", ":10000")
+		s.T().Setenv("CARTESI_LOG_LEVEL", "debug")
+		s.T().Setenv("CARTESI_ADVANCER_POLLING_INTERVAL", "7")
+		require.Empty(advancer.Reload())
+		require.Equal(slog.LevelDebug, advancer.LogLevel.Level())
+		require.Equal(7*time.Second, advancer.PollInterval)
+		require.Equal(slog.LevelDebug, advancer.config.LogLevel)
+	})
+
+	s.Run("Health", func() {
+		require := s.Require()
+
+		machineManager := newMockMachineManager()
+		advancer, err := newMockAdvancerService(machineManager, &MockRepository{})
+		require.Nil(err)
+		advancer.AddHealthCheck("machines", advancer.checkMachines)
+
+		advancer.PollInterval = time.Minute
+		advancer.Running.Store(true)
+		advancer.Heartbeat()
+
+		app := newMockMachine(1)
+		machineManager.Map[1] = *app
+		require.True(advancer.Alive())
+		require.True(advancer.Ready())
+
+		// a machine that does not respond is recovered, not restarted with the node
+		app.PingError = errors.New("machine server is gone")
+		machineManager.Map[1] = *app
+		require.True(advancer.Alive())
+		require.False(advancer.Ready())
+
+		health := advancer.Health(context.Background())
+		require.Equal("advancer", health.Service)
+		require.Len(health.Checks, 1)
+		require.False(health.Checks[0].Ok)
+		require.Contains(health.Checks[0].Error, "machine server is gone")
+
+		advancer.Running.Store(false)
+		require.False(advancer.Alive())
+	})
 }
 
 func (s *AdvancerSuite) TestStep() {
@@ -104,7 +182,7 @@ func (s *AdvancerSuite) TestStep() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
-		err = advancer.Step(context.Background())
+		err = stepAndWait(context.Background(), advancer)
 		require.Nil(err)
 
 		require.Len(repository.StoredResults, 3)
@@ -131,7 +209,7 @@ func (s *AdvancerSuite) TestStep() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
-		err = advancer.Step(context.Background())
+		err = stepAndWait(context.Background(), advancer)
 		require.Error(err)
 		require.Contains(err.Error(), "update epochs error")
 	})
@@ -149,7 +227,7 @@ func (s *AdvancerSuite) TestStep() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
-		err = advancer.Step(context.Background())
+		err = stepAndWait(context.Background(), advancer)
 		require.Error(err)
 		require.Contains(err.Error(), "update machines error")
 	})
@@ -169,11 +247,202 @@ func (s *AdvancerSuite) TestStep() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
-		err = advancer.Step(context.Background())
+		err = stepAndWait(context.Background(), advancer)
 		require.Error(err)
 		require.Contains(err.Error(), "get inputs error")
 	})
 
+	s.Run("Error/IsolatedPerApplication", func() {
+		require := s.Require()
+
+		machineManager := newMockMachineManager()
+		app1 := newMockMachine(1)
+		app2 := newMockMachine(2)
+		app1.AdvanceError = errors.New("advance error")
+		machineManager.Map[1] = *app1
+		machineManager.Map[2] = *app2
+
+		repository := &MockRepository{
+			GetInputsReturn: map[common.Address][]*Input{
+				app1.Application.IApplicationAddress: {
+					newInput(app1.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+				},
+				app2.Application.IApplicationAddress: {
+					newInput(app2.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+					newInput(app2.Application.ID, 0, 1, marshal(randomAdvanceResult(1))),
+				},
+			},
+		}
+
+		advancer, err := newMockAdvancerService(machineManager, repository)
+		require.NotNil(advancer)
+		require.Nil(err)
+		advancer.workers = newWorkerPool(2)
+
+		err = stepAndWait(context.Background(), advancer)
+		require.Error(err)
+		require.Contains(err.Error(), "advance error")
+		require.Equal(1, repository.ApplicationStateUpdates)
+		require.Len(repository.StoredResults, 2)
+	})
+
+	s.Run("Concurrency", func() {
+		require := s.Require()
+
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		hook := func() {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}
+
+		machineManager := newMockMachineManager()
+		repository := &MockRepository{GetInputsReturn: map[common.Address][]*Input{}}
+		for id := int64(1); id <= 4; id++ {
+			app := newMockMachine(id)
+			app.AdvanceHook = hook
+			machineManager.Map[id] = *app
+			repository.GetInputsReturn[app.Application.IApplicationAddress] = []*Input{
+				newInput(id, 0, 0, marshal(randomAdvanceResult(0))),
+				newInput(id, 0, 1, marshal(randomAdvanceResult(1))),
+			}
+		}
+
+		advancer, err := newMockAdvancerService(machineManager, repository)
+		require.NotNil(advancer)
+		require.Nil(err)
+		advancer.workers = newWorkerPool(2)
+
+		err = stepAndWait(context.Background(), advancer)
+		require.Nil(err)
+		require.Len(repository.StoredResults, 8)
+		require.Equal(2, maxRunning)
+	})
+
+	s.Run("SkipsBusyApplication", func() {
+		require := s.Require()
+
+		release := make(chan struct{})
+		machineManager := newMockMachineManager()
+		slow := newMockMachine(1)
+		slow.AdvanceHook = func() { <-release }
+		fast := newMockMachine(2)
+		machineManager.Map[1] = *slow
+		machineManager.Map[2] = *fast
+
+		repository := &MockRepository{
+			GetInputsReturn: map[common.Address][]*Input{
+				slow.Application.IApplicationAddress: {
+					newInput(slow.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+				},
+				fast.Application.IApplicationAddress: {
+					newInput(fast.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+				},
+			},
+		}
+
+		advancer, err := newMockAdvancerService(machineManager, repository)
+		require.NotNil(advancer)
+		require.Nil(err)
+		advancer.workers = newWorkerPool(2)
+		busy := func(id int64) bool { return advancer.workers.workers[id].busy.Load() }
+
+		// the fast application keeps advancing while the slow one is busy
+		for range 2 {
+			require.Nil(advancer.Step(context.Background()))
+			require.Eventually(func() bool { return !busy(fast.Application.ID) }, time.Second, time.Millisecond)
+			require.True(busy(slow.Application.ID))
+		}
+		repository.mu.Lock()
+		require.Len(repository.StoredResults, 2)
+		repository.mu.Unlock()
+
+		close(release)
+		advancer.workers.running.Wait()
+		require.Len(repository.StoredResults, 3)
+	})
+
+	s.Run("PagesInputs", func() {
+		require := s.Require()
+
+		machineManager := newMockMachineManager()
+		app1 := newMockMachine(1)
+		machineManager.Map[1] = *app1
+
+		pageSize := repository.DefaultStreamPageSize
+		inputs := make([]*Input, pageSize+1)
+		for i := range inputs {
+			inputs[i] = newInput(app1.Application.ID, 0, uint64(i), marshal(randomAdvanceResult(uint64(i))))
+		}
+		repo := &MockRepository{
+			GetInputsReturn: map[common.Address][]*Input{
+				app1.Application.IApplicationAddress: inputs,
+			},
+		}
+
+		advancer, err := newMockAdvancerService(machineManager, repo)
+		require.NotNil(advancer)
+		require.Nil(err)
+
+		require.Nil(stepAndWait(context.Background(), advancer))
+		require.Len(repo.StoredResults, pageSize+1)
+		require.Len(repo.ListInputsPages, 2)
+		require.Nil(repo.ListInputsPages[0].After)
+		require.Equal(uint64(pageSize-1), *repo.ListInputsPages[1].After)
+	})
+
+	s.Run("QuitsWithQueuedWake", func() {
+		require := s.Require()
+
+		pool := newWorkerPool(1)
+		w := &worker{wake: make(chan *Application, 1), quit: make(chan struct{})}
+		w.busy.Store(true)
+		pool.running.Add(1)
+		w.wake <- newMockMachine(1).Application
+		close(w.quit)
+
+		// whichever case the worker picks, the queued run is accounted for
+		go pool.work(context.Background(), w, func(context.Context, *Application) error { return nil })
+		require.True(pool.wait(time.Second))
+		require.False(w.busy.Load())
+	})
+
+	s.Run("SkipsRecoveringApplication", func() {
+		require := s.Require()
+
+		machineManager := newMockMachineManager()
+		app1 := newMockMachine(1)
+		machineManager.Map[1] = *app1
+		repository := &MockRepository{
+			GetInputsReturn: map[common.Address][]*Input{
+				app1.Application.IApplicationAddress: {
+					newInput(app1.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+				},
+			},
+		}
+
+		advancer, err := newMockAdvancerService(machineManager, repository)
+		require.NotNil(advancer)
+		require.Nil(err)
+
+		// neither advanced nor updated while its machine is rebuilt
+		require.True(advancer.workers.reserve(app1.Application.ID))
+		require.False(advancer.workers.reserve(app1.Application.ID))
+		require.Nil(stepAndWait(context.Background(), advancer))
+		require.Empty(repository.StoredResults)
+		require.Contains(machineManager.UpdateMachinesBusy, app1.Application.ID)
+
+		advancer.workers.release(app1.Application.ID)
+		require.Nil(stepAndWait(context.Background(), advancer))
+		require.Len(repository.StoredResults, 1)
+	})
+
 	s.Run("NoInputs", func() {
 		require := s.Require()
 
@@ -191,7 +460,7 @@ func (s *AdvancerSuite) TestStep() {
 		require.NotNil(advancer)
 		require.Nil(err)
 
-		err = advancer.Step(context.Background())
+		err = stepAndWait(context.Background(), advancer)
 		require.Nil(err)
 		require.Len(repository.StoredResults, 0)
 	})
@@ -213,12 +482,34 @@ func (s *AdvancerSuite) TestGetUnprocessedInputs() {
 			},
 		}
 
-		result, count, err := getUnprocessedInputs(context.Background(), repository, app1.Application.IApplicationAddress.String())
+		result, count, err := getUnprocessedInputs(context.Background(), repository, app1.Application.IApplicationAddress.String(), nil)
 		require.Nil(err)
 		require.Equal(uint64(2), count)
 		require.Equal(inputs, result)
 	})
 
+	s.Run("AfterIndex", func() {
+		require := s.Require()
+
+		app1 := newMockMachine(1)
+		inputs := []*Input{
+			newInput(app1.Application.ID, 0, 0, marshal(randomAdvanceResult(0))),
+			newInput(app1.Application.ID, 0, 1, marshal(randomAdvanceResult(1))),
+		}
+
+		repo := &MockRepository{
+			GetInputsReturn: map[common.Address][]*Input{
+				app1.Application.IApplicationAddress: inputs,
+			},
+		}
+
+		result, _, err := getUnprocessedInputs(context.Background(), repo,
+			app1.Application.IApplicationAddress.String(), Pointer(uint64(0)))
+		require.Nil(err)
+		require.Equal(inputs[1:], result)
+		require.Equal(uint64(repository.DefaultStreamPageSize), repo.ListInputsPages[0].Limit)
+	})
+
 	s.Run("Error", func() {
 		require := s.Require()
 
@@ -227,7 +518,7 @@ func (s *AdvancerSuite) TestGetUnprocessedInputs() {
 			GetInputsError: errors.New("list inputs error"),
 		}
 
-		_, _, err := getUnprocessedInputs(context.Background(), repository, app1.Application.IApplicationAddress.String())
+		_, _, err := getUnprocessedInputs(context.Background(), repository, app1.Application.IApplicationAddress.String(), nil)
 		require.Error(err)
 		require.Contains(err.Error(), "list inputs error")
 	})
@@ -261,6 +552,7 @@ func (s *AdvancerSuite) TestProcess() {
 		require.Equal(ApplicationState_Inoperable, repository.LastApplicationState)
 		require.NotNil(repository.LastApplicationStateReason)
 		require.Equal("advance error", *repository.LastApplicationStateReason)
+		require.Equal(Pointer(ReasonCode_MachineFailure), repository.LastApplicationReasonCode)
 	})
 
 	s.Run("ApplicationStateUpdateError", func() {
@@ -297,6 +589,47 @@ func (s *AdvancerSuite) TestProcess() {
 		require.Len(repository.StoredResults, 7)
 	})
 
+	s.Run("Metrics", func() {
+		require := s.Require()
+
+		_, _, advancer, app := setup()
+		app.Application.Name = "metrics"
+		result := randomAdvanceResult(0)
+		result.Status = InputCompletionStatus_Rejected
+		result.Cycles = 1000
+		inputs := []*Input{newInput(app.Application.ID, 0, 0, marshal(result))}
+
+		err := advancer.processInputs(context.Background(), app.Application, inputs)
+		require.Nil(err)
+		require.Equal(1.0, testutil.ToFloat64(
+			inputsProcessedMetric.WithLabelValues("metrics", string(InputCompletionStatus_Rejected))))
+		require.Equal(1000.0, testutil.ToFloat64(cyclesMetric.WithLabelValues("metrics")))
+	})
+
+	s.Run("Tracing", func() {
+		require := s.Require()
+
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		otel.SetTracerProvider(provider)
+		defer provider.Shutdown(context.Background())
+
+		_, _, advancer, app := setup()
+		inputs := []*Input{newInput(app.Application.ID, 0, 3, marshal(randomAdvanceResult(3)))}
+
+		err := advancer.processInputs(context.Background(), app.Application, inputs)
+		require.Nil(err)
+
+		spans := recorder.Ended()
+		require.Len(spans, 2)
+		store, process := spans[0], spans[1]
+		require.Equal("StoreAdvanceResult", store.Name())
+		require.Equal("ProcessInput", process.Name())
+		require.Equal(process.SpanContext().SpanID(), store.Parent().SpanID())
+		require.Contains(process.Attributes(), tracing.ApplicationAddress(app.Application.IApplicationAddress))
+		require.Contains(process.Attributes(), tracing.InputIndex(3))
+	})
+
 	s.Run("Noop", func() {
 		s.Run("NoInputs", func() {
 			require := s.Require()
@@ -338,6 +671,24 @@ func (s *AdvancerSuite) TestProcess() {
 			require.Len(repository.StoredResults, 1)
 		})
 
+		s.Run("RevertedInput", func() {
+			require := s.Require()
+
+			machineManager, repository, advancer, app := setup()
+			app.AdvanceError = fmt.Errorf("%w: processed inputs is 3 and index is 1",
+				manager.ErrInvalidInputIndex)
+			app.ProcessedInputs = 3
+			machineManager.Map[1] = *app
+			inputs := []*Input{
+				newInput(app.Application.ID, 0, 1, marshal(randomAdvanceResult(1))),
+			}
+
+			// the machine is rebuilt instead of marking the application inoperable
+			err := advancer.processInputs(context.Background(), app.Application, inputs)
+			require.ErrorIs(err, manager.ErrInvalidInputIndex)
+			require.Equal(0, repository.ApplicationStateUpdates)
+		})
+
 		s.Run("StoreAdvance", func() {
 			require := s.Require()
 
@@ -353,6 +704,23 @@ func (s *AdvancerSuite) TestProcess() {
 			require.Contains(err.Error(), "store-advance error")
 			require.Len(repository.StoredResults, 1)
 		})
+
+		s.Run("RevertedWhileProcessing", func() {
+			require := s.Require()
+
+			_, repo, advancer, app := setup()
+			input := newInput(app.Application.ID, 0, 0, marshal(randomAdvanceResult(0)))
+			blockHash := common.HexToHash("0xb1")
+			input.BlockHash = &blockHash
+			repo.StoreAdvanceError = repository.ErrInputReverted
+
+			// the result is discarded and the application kept enabled
+			err := advancer.processInputs(context.Background(), app.Application, []*Input{input})
+			require.ErrorIs(err, repository.ErrInputReverted)
+			require.Len(repo.StoredResults, 1)
+			require.Equal(&blockHash, repo.StoredResults[0].InputBlockHash)
+			require.Equal(0, repo.ApplicationStateUpdates)
+		})
 	})
 }
 
@@ -380,7 +748,7 @@ func (s *AdvancerSuite) TestContextCancellation() {
 		// Start the Step operation in a goroutine
 		errCh := make(chan error)
 		go func() {
-			errCh <- advancer.Step(ctx)
+			errCh <- stepAndWait(ctx, advancer)
 		}()
 
 		// Cancel the context after a short delay
@@ -503,10 +871,259 @@ func (s *AdvancerSuite) TestErrorRecovery() {
 	})
 }
 
+func (s *AdvancerSuite) TestSnapshotRetention() {
+	newSnapshots := func(dir string, epochs ...uint64) map[uint64]*Input {
+		snapshots := map[uint64]*Input{}
+		for index, epoch := range epochs {
+			uri := path.Join(dir, fmt.Sprintf("app_epoch%d_input%d", epoch, index))
+			s.Require().Nil(os.Mkdir(uri, 0755))
+			snapshots[uint64(index)] = &Input{EpochIndex: epoch, Index: uint64(index), SnapshotURI: &uri}
+		}
+		return snapshots
+	}
+	app := &Application{Name: "app", IApplicationAddress: randomAddress()}
+
+	s.Run("Last", func() {
+		require := s.Require()
+
+		dir := s.T().TempDir()
+		repository := &MockRepository{Snapshots: newSnapshots(dir, 0, 0, 1)}
+		advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+		require.Nil(err)
+		advancer.snapshotsDir = dir
+		advancer.snapshotsRetained = 2
+
+		require.Nil(advancer.pruneSnapshots(context.Background(), app))
+		require.ElementsMatch([]uint64{1, 2}, slices.Collect(maps.Keys(repository.Snapshots)))
+		require.NoDirExists(path.Join(dir, "app_epoch0_input0"))
+		require.DirExists(path.Join(dir, "app_epoch0_input1"))
+		require.DirExists(path.Join(dir, "app_epoch1_input2"))
+	})
+
+	s.Run("Epoch", func() {
+		require := s.Require()
+
+		dir := s.T().TempDir()
+		repository := &MockRepository{
+			Snapshots:        newSnapshots(dir, 0, 0, 1, 2, 3),
+			ListEpochsReturn: []*Epoch{{Index: 1}, {Index: 0}},
+		}
+		advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+		require.Nil(err)
+		advancer.snapshotsDir = dir
+		advancer.snapshotRetention = config.SnapshotRetentionEpoch
+		advancer.snapshotsRetained = 2
+
+		// the most recent one and the last one of each accepted epoch
+		require.Nil(advancer.pruneSnapshots(context.Background(), app))
+		require.ElementsMatch([]uint64{1, 2, 4}, slices.Collect(maps.Keys(repository.Snapshots)))
+		require.NoDirExists(path.Join(dir, "app_epoch0_input0"))
+		require.NoDirExists(path.Join(dir, "app_epoch2_input3"))
+	})
+}
+
+func (s *AdvancerSuite) TestCollectSnapshots() {
+	require := s.Require()
+
+	dir := s.T().TempDir()
+	referenced := path.Join(dir, "app_epoch0_input0")
+	unreferenced := path.Join(dir, "app_epoch0_input1")
+	interrupted := path.Join(dir, "app_epoch0_input3.tmp-1234")
+	other := path.Join(dir, "other")
+	file := path.Join(dir, "app_epoch0_input2")
+	for _, dir := range []string{referenced, unreferenced, interrupted, other} {
+		require.Nil(os.Mkdir(dir, 0755))
+	}
+	require.Nil(os.WriteFile(file, nil, 0644))
+
+	repository := &MockRepository{Snapshots: map[uint64]*Input{0: {SnapshotURI: &referenced}}}
+	advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+	require.Nil(err)
+	advancer.snapshotsDir = dir
+
+	removed, err := advancer.collectSnapshots(context.Background())
+	require.Nil(err)
+	require.Equal(2, removed)
+	require.DirExists(referenced)
+	require.NoDirExists(unreferenced)
+	require.NoDirExists(interrupted)
+	require.DirExists(other)
+	require.FileExists(file)
+}
+
+func (s *AdvancerSuite) TestRemoteSnapshots() {
+	app := &Application{Name: "app", IApplicationAddress: randomAddress()}
+	newAdvancer := func(repository *MockRepository) (*Service, string, string) {
+		dir := s.T().TempDir()
+		remoteDir := s.T().TempDir()
+		advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+		s.Require().Nil(err)
+		advancer.snapshotsDir = dir
+		advancer.snapshotsRetained = 1
+		advancer.snapshotStorage, err = snapshot.NewFileStorage(remoteDir)
+		s.Require().Nil(err)
+		return advancer, dir, remoteDir
+	}
+	newSnapshot := func(dir string, index uint64) (*Input, string) {
+		snapshotPath := path.Join(dir, fmt.Sprintf("app_epoch0_input%d", index))
+		s.Require().Nil(os.Mkdir(snapshotPath, 0755))
+		s.Require().Nil(os.WriteFile(path.Join(snapshotPath, "config.json"), []byte("{}"), 0644))
+		return &Input{Index: index, SnapshotURI: &snapshotPath}, snapshotPath
+	}
+
+	s.Run("Upload", func() {
+		require := s.Require()
+
+		repository := &MockRepository{}
+		advancer, dir, remoteDir := newAdvancer(repository)
+		input, snapshotPath := newSnapshot(dir, 0)
+		repository.Snapshots = map[uint64]*Input{0: input}
+
+		require.Nil(advancer.uploadSnapshot(context.Background(),
+			snapshotUpload{app: app, input: input, path: snapshotPath}))
+		uri := *repository.Snapshots[0].SnapshotURI
+		require.Equal("file://"+path.Join(remoteDir, "app_epoch0_input0"), uri)
+		require.FileExists(path.Join(remoteDir, "app_epoch0_input0", "config.json"))
+
+		// the local copy is kept as a cache of the referenced remote snapshot
+		removed, err := advancer.collectSnapshots(context.Background())
+		require.Nil(err)
+		require.Equal(0, removed)
+		require.DirExists(snapshotPath)
+
+		// pruning removes both copies
+		next, _ := newSnapshot(dir, 1)
+		repository.Snapshots[1] = next
+		require.Nil(advancer.pruneSnapshots(context.Background(), app))
+		require.NoDirExists(snapshotPath)
+		require.NoDirExists(path.Join(remoteDir, "app_epoch0_input0"))
+	})
+
+	s.Run("PrunedWhileUploading", func() {
+		require := s.Require()
+
+		repository := &MockRepository{}
+		advancer, dir, remoteDir := newAdvancer(repository)
+		input, snapshotPath := newSnapshot(dir, 0)
+
+		require.Nil(advancer.uploadSnapshot(context.Background(),
+			snapshotUpload{app: app, input: input, path: snapshotPath}))
+		require.Empty(repository.Snapshots)
+		require.NoDirExists(path.Join(remoteDir, "app_epoch0_input0"))
+	})
+}
+
+func (s *AdvancerSuite) TestSnapshotPolicies() {
+	newApp := func(params ExecutionParameters) *Application {
+		return &Application{ID: 1, IApplicationAddress: randomAddress(), ExecutionParameters: params}
+	}
+
+	s.Run("EveryNInputs", func() {
+		require := s.Require()
+
+		// counted from the most recent snapshot
+		uri := "app_epoch0_input4"
+		repository := &MockRepository{Snapshots: map[uint64]*Input{4: {Index: 4, SnapshotURI: &uri}}}
+		advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+		require.Nil(err)
+		app := newApp(ExecutionParameters{
+			SnapshotPolicy:        SnapshotPolicy_EveryNInputs,
+			SnapshotInputInterval: 3,
+		})
+
+		for index, expected := range map[uint64]bool{5: false, 6: false, 7: true} {
+			due, err := advancer.snapshotDue(context.Background(), app, &Input{Index: index})
+			require.Nil(err)
+			require.Equal(expected, due, "input %d", index)
+		}
+
+		advancer.resetSnapshotProgress(app, &Input{Index: 7})
+		due, err := advancer.snapshotDue(context.Background(), app, &Input{Index: 8})
+		require.Nil(err)
+		require.False(due)
+		due, err = advancer.snapshotDue(context.Background(), app, &Input{Index: 10})
+		require.Nil(err)
+		require.True(due)
+	})
+
+	s.Run("EveryDuration", func() {
+		require := s.Require()
+
+		advancer, err := newMockAdvancerService(newMockMachineManager(), &MockRepository{})
+		require.Nil(err)
+		app := newApp(ExecutionParameters{
+			SnapshotPolicy:       SnapshotPolicy_EveryDuration,
+			SnapshotTimeInterval: time.Hour,
+		})
+
+		due, err := advancer.snapshotDue(context.Background(), app, &Input{Index: 0})
+		require.Nil(err)
+		require.False(due)
+
+		advancer.snapshotProgress[app.ID].time = time.Now().Add(-time.Hour)
+		due, err = advancer.snapshotDue(context.Background(), app, &Input{Index: 1})
+		require.Nil(err)
+		require.True(due)
+	})
+
+	s.Run("EveryDurationSinceSnapshot", func() {
+		require := s.Require()
+
+		// the snapshot was taken an hour ago, but its input was updated since
+		uri := "/snapshots/app_epoch0_input4"
+		repository := &MockRepository{Snapshots: map[uint64]*Input{4: {
+			Index:       4,
+			SnapshotURI: &uri,
+			CreatedAt:   time.Now().Add(-time.Hour),
+			UpdatedAt:   time.Now(),
+		}}}
+		advancer, err := newMockAdvancerService(newMockMachineManager(), repository)
+		require.Nil(err)
+		app := newApp(ExecutionParameters{
+			SnapshotPolicy:       SnapshotPolicy_EveryDuration,
+			SnapshotTimeInterval: time.Hour,
+		})
+
+		due, err := advancer.snapshotDue(context.Background(), app, &Input{Index: 5})
+		require.Nil(err)
+		require.True(due)
+	})
+
+	s.Run("EveryNCycles", func() {
+		require := s.Require()
+
+		advancer, err := newMockAdvancerService(newMockMachineManager(), &MockRepository{})
+		require.Nil(err)
+		app := newApp(ExecutionParameters{
+			SnapshotPolicy:        SnapshotPolicy_EveryNCycles,
+			SnapshotCycleInterval: 1000,
+		})
+
+		// the cycles of the first input count, before its snapshot is considered
+		require.Nil(advancer.addSnapshotCycles(context.Background(), app, 600))
+		due, err := advancer.snapshotDue(context.Background(), app, &Input{Index: 0})
+		require.Nil(err)
+		require.False(due)
+
+		require.Nil(advancer.addSnapshotCycles(context.Background(), app, 400))
+		due, err = advancer.snapshotDue(context.Background(), app, &Input{Index: 1})
+		require.Nil(err)
+		require.True(due)
+
+		advancer.resetSnapshotProgress(app, &Input{Index: 1})
+		due, err = advancer.snapshotDue(context.Background(), app, &Input{Index: 2})
+		require.Nil(err)
+		require.False(due)
+	})
+}
+
 type MockMachineImpl struct {
-	Application  *Application
-	AdvanceBlock bool
-	AdvanceError error
+	Application     *Application
+	AdvanceBlock    bool
+	AdvanceError    error
+	AdvanceHook     func()
+	ProcessedInputs uint64
+	PingError       error
 }
 
 func (mock *MockMachineImpl) Advance(
@@ -530,6 +1147,10 @@ func (mock *MockMachineImpl) Advance(
 		return nil, ctx.Err()
 	}
 
+	if mock.AdvanceHook != nil {
+		mock.AdvanceHook()
+	}
+
 	// If there's a predefined error, return it
 	if mock.AdvanceError != nil {
 		return nil, mock.AdvanceError
@@ -557,6 +1178,11 @@ func newMockMachine(id int64) *MockMachineImpl {
 type MockMachineManager struct {
 	Map                 map[int64]MockMachineImpl
 	UpdateMachinesError error
+	UpdateMachinesBusy  map[int64]struct{}
+	RecoverMachineError error
+	RecoverMachineHook  func(context.Context)
+	Recovered           []*Application
+	Closed              bool
 }
 
 func newMockMachineManager() *MockMachineManager {
@@ -581,10 +1207,15 @@ func (mock *MockMachineManager) GetMachine(appID int64) (manager.MachineInstance
 	return mockInstance, true
 }
 
-func (mock *MockMachineManager) UpdateMachines(ctx context.Context) error {
+func (mock *MockMachineManager) UpdateMachines(ctx context.Context, busy map[int64]struct{}) error {
+	mock.UpdateMachinesBusy = busy
 	return mock.UpdateMachinesError
 }
 
+func (mock *MockMachineManager) Reload(ctx context.Context) error {
+	return nil
+}
+
 func (mock *MockMachineManager) Applications() []*Application {
 	apps := make([]*Application, 0, len(mock.Map))
 	for _, v := range mock.Map {
@@ -598,6 +1229,26 @@ func (mock *MockMachineManager) HasMachine(appID int64) bool {
 	return exists
 }
 
+func (mock *MockMachineManager) RecoverMachine(ctx context.Context, app *Application) error {
+	mock.Recovered = append(mock.Recovered, app)
+	if mock.RecoverMachineHook != nil {
+		mock.RecoverMachineHook(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if mock.RecoverMachineError != nil {
+		return mock.RecoverMachineError
+	}
+	mock.Map[app.ID] = MockMachineImpl{Application: app}
+	return nil
+}
+
+func (mock *MockMachineManager) Close() error {
+	mock.Closed = true
+	return nil
+}
+
 // MockMachineInstance is a test implementation of manager.MachineInstance
 type MockMachineInstance struct {
 	application *Application
@@ -632,6 +1283,28 @@ func (m *MockMachineInstance) CreateSnapshot(ctx context.Context, processInputs
 	return nil
 }
 
+// ProcessedInputs implements the MachineInstance interface for testing
+func (m *MockMachineInstance) ProcessedInputs() uint64 {
+	return m.machineImpl.ProcessedInputs
+}
+
+// Hash implements the MachineInstance interface for testing
+func (m *MockMachineInstance) Hash(ctx context.Context) (common.Hash, error) {
+	// Not used in advancer tests, but needed to satisfy the interface
+	return common.Hash{}, nil
+}
+
+// Ping implements the MachineInstance interface for testing
+func (m *MockMachineInstance) Ping(ctx context.Context) error {
+	return m.machineImpl.PingError
+}
+
+// SetMaxConcurrentInspects implements the MachineInstance interface for testing
+func (m *MockMachineInstance) SetMaxConcurrentInspects(limit uint32) error {
+	// Not used in advancer tests, but needed to satisfy the interface
+	return nil
+}
+
 // Close implements the MachineInstance interface for testing
 func (m *MockMachineInstance) Close() error {
 	// Not used in advancer tests, but needed to satisfy the interface
@@ -649,13 +1322,18 @@ type MockRepository struct {
 	UpdateApplicationStateError error
 	UpdateEpochsError           error
 	UpdateEpochsCount           int64
-	GetLastSnapshotReturn       *Input
-	GetLastSnapshotError        error
+	ListEpochsReturn            []*Epoch
+	Applications                []*Application
+
+	// inputs with a snapshot, by index
+	Snapshots map[uint64]*Input
 
 	StoredResults              []*AdvanceResult
+	ListInputsPages            []repository.Pagination
 	ApplicationStateUpdates    int
 	LastApplicationState       ApplicationState
 	LastApplicationStateReason *string
+	LastApplicationReasonCode  *ReasonCode
 
 	mu sync.Mutex
 }
@@ -678,8 +1356,27 @@ func (mock *MockRepository) ListInputs(
 		return nil, 0, ctx.Err()
 	}
 
+	mock.mu.Lock()
+	mock.ListInputsPages = append(mock.ListInputsPages, p)
+	mock.mu.Unlock()
+
 	address := common.HexToAddress(nameOrAddress)
-	return mock.GetInputsReturn[address], uint64(len(mock.GetInputsReturn[address])), mock.GetInputsError
+	inputs := mock.GetInputsReturn[address]
+	if p.After != nil {
+		start := len(inputs)
+		for i, input := range inputs {
+			if input.Index > *p.After {
+				start = i
+				break
+			}
+		}
+		inputs = inputs[start:]
+	}
+	total := uint64(len(inputs))
+	if p.Limit > 0 && uint64(len(inputs)) > p.Limit {
+		inputs = inputs[:p.Limit]
+	}
+	return inputs, total, mock.GetInputsError
 }
 
 func (mock *MockRepository) StoreAdvanceResult(
@@ -716,15 +1413,61 @@ func (mock *MockRepository) UpdateEpochsInputsProcessed(ctx context.Context, nam
 }
 
 func (mock *MockRepository) UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error {
+	return mock.updateApplicationState(ctx, appID, state, reason, nil)
+}
+
+func (mock *MockRepository) SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error {
+	return mock.updateApplicationState(ctx, appID, ApplicationState_Inoperable, &reason, &code)
+}
+
+func (mock *MockRepository) updateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state ApplicationState,
+	reason *string,
+	code *ReasonCode,
+) error {
 	// Check for context cancellation
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
 
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
 	mock.ApplicationStateUpdates++
 	mock.LastApplicationState = state
 	mock.LastApplicationStateReason = reason
-	return mock.UpdateApplicationStateError
+	mock.LastApplicationReasonCode = code
+	if mock.UpdateApplicationStateError != nil {
+		return mock.UpdateApplicationStateError
+	}
+	for _, app := range mock.Applications {
+		if app.ID == appID {
+			app.State = state
+			app.Reason = reason
+			app.ReasonCode = code
+		}
+	}
+	return nil
+}
+
+func (mock *MockRepository) ListApplications(
+	ctx context.Context,
+	f repository.ApplicationFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*Application, uint64, error) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	var apps []*Application
+	for _, app := range mock.Applications {
+		if f.State == nil || app.State == *f.State {
+			apps = append(apps, app)
+		}
+	}
+	return apps, uint64(len(apps)), nil
 }
 
 func (mock *MockRepository) GetEpoch(ctx context.Context, nameOrAddress string, index uint64) (*Epoch, error) {
@@ -778,18 +1521,58 @@ func (mock *MockRepository) GetLastProcessedInput(ctx context.Context, appAddres
 	return lastInput, nil
 }
 
+func (mock *MockRepository) ListEpochs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.EpochFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*Epoch, uint64, error) {
+	return mock.ListEpochsReturn, uint64(len(mock.ListEpochsReturn)), nil
+}
+
 func (mock *MockRepository) UpdateInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64, snapshotURI string) error {
-	// Not used in most advancer tests, but needed to satisfy the interface
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	if mock.Snapshots == nil {
+		mock.Snapshots = map[uint64]*Input{}
+	}
+	mock.Snapshots[inputIndex] = &Input{EpochApplicationID: appId, Index: inputIndex, SnapshotURI: &snapshotURI}
+	return nil
+}
+
+func (mock *MockRepository) ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	delete(mock.Snapshots, inputIndex)
 	return nil
 }
 
-func (mock *MockRepository) GetLastSnapshot(ctx context.Context, nameOrAddress string) (*Input, error) {
+func (mock *MockRepository) ListSnapshots(ctx context.Context, nameOrAddress string) ([]*Input, error) {
 	// Check for context cancellation
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	return mock.GetLastSnapshotReturn, mock.GetLastSnapshotError
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	snapshots := slices.Collect(maps.Values(mock.Snapshots))
+	slices.SortFunc(snapshots, func(a, b *Input) int { return cmp.Compare(b.Index, a.Index) })
+	return snapshots, nil
+}
+
+func (mock *MockRepository) ListSnapshotURIs(ctx context.Context) ([]string, error) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	var uris []string
+	for _, snapshot := range mock.Snapshots {
+		uris = append(uris, *snapshot.SnapshotURI)
+	}
+	return uris, nil
 }
 
 // ------------------------------------------------------------------------------------------------
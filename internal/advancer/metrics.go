@@ -0,0 +1,39 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package advancer
+
+import (
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	inputsProcessedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "advancer",
+		Name:      "inputs_processed_total",
+		Help:      "Inputs processed, per application and completion status.",
+	}, []string{"application", "status"})
+	advanceDurationMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cartesi",
+		Subsystem: "advancer",
+		Name:      "advance_duration_seconds",
+		Help:      "Time the machine took to process an input, per application.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10), // nolint: mnd
+	}, []string{"application"})
+	cyclesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "advancer",
+		Name:      "cycles_total",
+		Help:      "Machine cycles spent processing inputs, per application.",
+	}, []string{"application"})
+)
+
+// observeAdvance records the result of an input processed by app.
+func observeAdvance(app *Application, result *AdvanceResult) {
+	inputsProcessedMetric.WithLabelValues(app.Name, string(result.Status)).Inc()
+	advanceDurationMetric.WithLabelValues(app.Name).Observe(result.ExecutionTime.Seconds())
+	cyclesMetric.WithLabelValues(app.Name).Add(float64(result.Cycles))
+}
@@ -0,0 +1,263 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package advancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/manager"
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const maxReasonLength = 4096
+
+var recoveriesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "cartesi",
+	Subsystem: "advancer",
+	Name:      "recoveries_total",
+	Help:      "Attempts to recover inoperable applications, per application and result.",
+}, []string{"application", "result"})
+
+// Errors that mean the machine or the application itself is broken.
+// Checked first, as an error may also wrap a transient one.
+var fatalErrors = []error{
+	manager.ErrMachineHashMismatch,
+	rollupsmachine.ErrException,
+	rollupsmachine.ErrHalted,
+	rollupsmachine.ErrOutputsLimitExceeded,
+	rollupsmachine.ErrCycleLimitExceeded,
+	rollupsmachine.ErrPayloadLengthLimitExceeded,
+	rollupsmachine.ErrNotAtManualYield,
+	rollupsmachine.ErrUnreachable,
+}
+
+// Errors that mean the machine server or the connection to it failed
+var transientErrors = []error{
+	cartesimachine.ErrCartesiMachine,
+	cartesimachine.ErrTimedOut,
+	cartesimachine.ErrOrphanServer,
+	manager.ErrMachineClosed,
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.EPIPE,
+	io.ErrUnexpectedEOF,
+	os.ErrDeadlineExceeded,
+}
+
+// IsTransientError reports whether an application whose machine failed with
+// err may work again once its machine is rebuilt
+func IsTransientError(err error) bool {
+	for _, fatal := range fatalErrors {
+		if errors.Is(err, fatal) {
+			return false
+		}
+	}
+	for _, transient := range transientErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// reasonCode classifies the failure of the machine of an application with err
+func reasonCode(err error) ReasonCode {
+	if IsTransientError(err) {
+		return ReasonCode_MachineServerFailure
+	}
+	return ReasonCode_MachineFailure
+}
+
+// recovery tracks the attempts to recover an application
+type recovery struct {
+	attempts    uint64
+	next        time.Time
+	recoveredAt time.Time // zero unless the last attempt succeeded
+}
+
+// recoveryBackoff is how long to wait before the attempt after attempts
+// failed ones
+func (s *Service) recoveryBackoff(attempts uint64) time.Duration {
+	backoff := s.config.RecoveryMinBackoff
+	for range attempts {
+		if backoff >= s.config.RecoveryMaxBackoff/2 { // nolint: mnd
+			return s.config.RecoveryMaxBackoff
+		}
+		backoff *= 2
+	}
+	return min(backoff, s.config.RecoveryMaxBackoff)
+}
+
+// recoverApplications tries to recover the applications that became
+// inoperable because their machine server failed, with an exponential backoff
+// between the attempts. The applications made inoperable for any other reason,
+// such as the claim mismatches found by the validator and the claimer, are
+// left alone. An application recovered again before it ran for the maximum
+// backoff keeps counting its attempts. It runs apart from Step, so each
+// application is reserved while its machine is rebuilt.
+func (s *Service) recoverApplications(ctx context.Context) error {
+	f := repository.ApplicationFilter{State: Pointer(ApplicationState_Inoperable)}
+	apps, _, err := s.repository.ListApplications(ctx, f, repository.Pagination{}, false)
+	if err != nil {
+		return err
+	}
+
+	if s.recoveries == nil {
+		s.recoveries = map[int64]*recovery{}
+	}
+
+	now := time.Now()
+	inoperable := map[int64]struct{}{}
+	for _, app := range apps {
+		if app.ReasonCode == nil || *app.ReasonCode != ReasonCode_MachineServerFailure {
+			delete(s.recoveries, app.ID)
+			continue
+		}
+		inoperable[app.ID] = struct{}{}
+
+		r, ok := s.recoveries[app.ID]
+		if !ok {
+			r = &recovery{}
+			s.recoveries[app.ID] = r
+		}
+		if !ok || !r.recoveredAt.IsZero() {
+			if !r.recoveredAt.IsZero() && now.Sub(r.recoveredAt) > s.config.RecoveryMaxBackoff {
+				r.attempts = 0
+			}
+			r.recoveredAt = time.Time{}
+			r.next = now.Add(s.recoveryBackoff(r.attempts))
+			s.Logger.Warn("Application is inoperable, trying to recover it",
+				"application", app.Name,
+				"reason", *app.Reason,
+				"attempts", r.attempts,
+				"backoff", r.next.Sub(now))
+		}
+		if now.Before(r.next) {
+			continue
+		}
+
+		if r.attempts >= s.config.RecoveryMaxAttempts {
+			reason := truncateReason(fmt.Sprintf("automatic recovery gave up after %d attempts: %s",
+				r.attempts, *app.Reason))
+			s.Logger.Error("Giving up recovering application", "application", app.Name, "attempts", r.attempts)
+			recoveriesMetric.WithLabelValues(app.Name, "given_up").Inc()
+			delete(s.recoveries, app.ID)
+			err := s.repository.SetApplicationInoperable(ctx, app.ID, ReasonCode_MachineFailure, reason)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// the worker of an application may still be leaving it
+		if !s.workers.reserve(app.ID) {
+			continue
+		}
+		r.attempts++
+		err := s.recoverMachine(ctx, app)
+		if ctx.Err() != nil {
+			s.workers.release(app.ID)
+			return ctx.Err()
+		}
+		if err == nil {
+			reason := truncateReason("recovered from: " + *app.Reason)
+			err := s.repository.UpdateApplicationState(ctx, app.ID, ApplicationState_Enabled, &reason)
+			s.workers.release(app.ID)
+			if err != nil {
+				return err
+			}
+			s.Logger.Info("Application recovered", "application", app.Name, "attempts", r.attempts)
+			recoveriesMetric.WithLabelValues(app.Name, "recovered").Inc()
+			r.recoveredAt = now
+			continue
+		}
+		s.workers.release(app.ID)
+		recoveriesMetric.WithLabelValues(app.Name, "failed").Inc()
+		if IsTransientError(err) {
+			r.next = now.Add(s.recoveryBackoff(r.attempts))
+			s.Logger.Warn("Failed to recover application, trying again later",
+				"application", app.Name,
+				"attempts", r.attempts,
+				"backoff", r.next.Sub(now),
+				"error", err)
+			continue
+		}
+		s.Logger.Error("Failed to recover application", "application", app.Name, "error", err)
+		delete(s.recoveries, app.ID)
+		reason := truncateReason(fmt.Sprintf("automatic recovery failed: %v", err))
+		err = s.repository.SetApplicationInoperable(ctx, app.ID, ReasonCode_MachineFailure, reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	// forget the applications that kept running since they were recovered
+	for id, r := range s.recoveries {
+		if _, ok := inoperable[id]; !ok && now.Sub(r.recoveredAt) > s.config.RecoveryMaxBackoff {
+			delete(s.recoveries, id)
+		}
+	}
+	return nil
+}
+
+// recoverApplicationsEvery runs recoverApplications every interval, until
+// ctx is canceled
+func (s *Service) recoverApplicationsEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.recoverApplications(ctx); err != nil && ctx.Err() == nil {
+				s.Logger.Error("Failed to recover inoperable applications", "error", err)
+			}
+		}
+	}
+}
+
+// startRecovery runs recoverApplicationsEvery until Stop cancels it
+func (s *Service) startRecovery(interval time.Duration) {
+	var ctx context.Context
+	ctx, s.recoveryCancel = context.WithCancel(s.Context)
+	s.recoveryDone = make(chan struct{})
+	go func() {
+		defer close(s.recoveryDone)
+		s.recoverApplicationsEvery(ctx, interval)
+	}()
+}
+
+// recoverMachine rebuilds the machine of app as it will be once enabled
+func (s *Service) recoverMachine(ctx context.Context, app *Application) error {
+	enabled := *app
+	enabled.State = ApplicationState_Enabled
+	enabled.Reason = nil
+	enabled.ReasonCode = nil
+
+	// snapshots are not collected meanwhile, as they may be downloaded
+	s.snapshotsMutex.RLock()
+	defer s.snapshotsMutex.RUnlock()
+	return s.machineManager.RecoverMachine(ctx, &enabled)
+}
+
+// truncateReason keeps reason within the length of the reason column
+func truncateReason(reason string) string {
+	if len(reason) <= maxReasonLength {
+		return reason
+	}
+	return strings.ToValidUTF8(reason[:maxReasonLength], "")
+}
@@ -0,0 +1,169 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package advancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/internal/manager"
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
+)
+
+func (s *AdvancerSuite) TestIsTransientError() {
+	s.True(IsTransientError(errors.Join(cartesimachine.ErrCartesiMachine, syscall.ECONNREFUSED)))
+	s.True(IsTransientError(fmt.Errorf("failed to advance: %w", manager.ErrMachineClosed)))
+	s.False(IsTransientError(rollupsmachine.ErrException))
+	s.False(IsTransientError(errors.Join(rollupsmachine.ErrException, cartesimachine.ErrCartesiMachine)))
+	s.False(IsTransientError(errors.New("computed claim does not match event")))
+	s.False(IsTransientError(errors.New(cartesimachine.ErrCartesiMachine.Error() + ": connection refused")))
+}
+
+func newRecoveringAdvancer(
+	s *AdvancerSuite,
+	minBackoff time.Duration,
+	maxAttempts uint64,
+	code *ReasonCode,
+	reason string,
+) (*Service, *MockMachineManager, *MockRepository, *Application) {
+	machineManager := newMockMachineManager()
+	app := &Application{
+		ID:                  1,
+		Name:                "app",
+		IApplicationAddress: randomAddress(),
+		State:               ApplicationState_Inoperable,
+		Reason:              &reason,
+		ReasonCode:          code,
+	}
+	repository := &MockRepository{Applications: []*Application{app}}
+	advancer, err := newMockAdvancerService(machineManager, repository)
+	s.Require().Nil(err)
+	advancer.config = config.AdvancerConfig{
+		FeatureApplicationRecoveryEnabled: true,
+		RecoveryMinBackoff:                minBackoff,
+		RecoveryMaxBackoff:                time.Hour,
+		RecoveryMaxAttempts:               maxAttempts,
+	}
+	return advancer, machineManager, repository, app
+}
+
+func (s *AdvancerSuite) TestRecoverApplications() {
+	s.Run("RecoversTransientFailure", func() {
+		require := s.Require()
+		reason := cartesimachine.ErrCartesiMachine.Error() + "\nconnection reset by peer"
+		advancer, machineManager, _, app := newRecoveringAdvancer(s, 0, 3,
+			Pointer(ReasonCode_MachineServerFailure), reason)
+
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Len(machineManager.Recovered, 1)
+		require.Equal(ApplicationState_Enabled, machineManager.Recovered[0].State)
+		require.True(machineManager.HasMachine(app.ID))
+		require.Equal(ApplicationState_Enabled, app.State)
+		require.Equal("recovered from: "+reason, *app.Reason)
+		require.Nil(app.ReasonCode)
+	})
+
+	s.Run("LeavesFatalFailure", func() {
+		require := s.Require()
+		advancer, machineManager, repository, app := newRecoveringAdvancer(s, 0, 3,
+			Pointer(ReasonCode_MachineFailure), rollupsmachine.ErrCycleLimitExceeded.Error())
+
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Empty(machineManager.Recovered)
+		require.Zero(repository.ApplicationStateUpdates)
+		require.Equal(ApplicationState_Inoperable, app.State)
+	})
+
+	s.Run("LeavesUnclassifiedFailure", func() {
+		require := s.Require()
+		advancer, machineManager, repository, app := newRecoveringAdvancer(s, 0, 3,
+			nil, cartesimachine.ErrCartesiMachine.Error())
+
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Empty(machineManager.Recovered)
+		require.Zero(repository.ApplicationStateUpdates)
+		require.Equal(ApplicationState_Inoperable, app.State)
+	})
+
+	s.Run("SkipsReservedApplication", func() {
+		require := s.Require()
+		advancer, machineManager, _, app := newRecoveringAdvancer(s, 0, 3,
+			Pointer(ReasonCode_MachineServerFailure), cartesimachine.ErrTimedOut.Error())
+
+		require.True(advancer.workers.reserve(app.ID))
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Empty(machineManager.Recovered)
+
+		advancer.workers.release(app.ID)
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Len(machineManager.Recovered, 1)
+		require.True(advancer.workers.reserve(app.ID))
+	})
+
+	s.Run("WaitsForBackoff", func() {
+		require := s.Require()
+		advancer, machineManager, _, _ := newRecoveringAdvancer(s, time.Hour, 3,
+			Pointer(ReasonCode_MachineServerFailure), cartesimachine.ErrTimedOut.Error())
+
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Empty(machineManager.Recovered)
+	})
+
+	s.Run("GivesUpAfterMaxAttempts", func() {
+		require := s.Require()
+		advancer, machineManager, _, app := newRecoveringAdvancer(s, 0, 2,
+			Pointer(ReasonCode_MachineServerFailure), cartesimachine.ErrOrphanServer.Error())
+		machineManager.RecoverMachineError = cartesimachine.ErrCartesiMachine
+
+		for range 4 {
+			require.Nil(advancer.recoverApplications(context.Background()))
+		}
+		require.Len(machineManager.Recovered, 2)
+		require.Equal(ApplicationState_Inoperable, app.State)
+		require.Contains(*app.Reason, "automatic recovery gave up after 2 attempts")
+		require.Equal(Pointer(ReasonCode_MachineFailure), app.ReasonCode)
+	})
+
+	s.Run("StopsOnFatalRecoveryError", func() {
+		require := s.Require()
+		advancer, machineManager, _, app := newRecoveringAdvancer(s, 0, 3,
+			Pointer(ReasonCode_MachineServerFailure), cartesimachine.ErrCartesiMachine.Error())
+		machineManager.RecoverMachineError = fmt.Errorf("%w: expected 0x01, got 0x02", manager.ErrMachineHashMismatch)
+
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Nil(advancer.recoverApplications(context.Background()))
+		require.Len(machineManager.Recovered, 1)
+		require.Equal(ApplicationState_Inoperable, app.State)
+		require.Contains(*app.Reason, "automatic recovery failed: "+manager.ErrMachineHashMismatch.Error())
+		require.Equal(Pointer(ReasonCode_MachineFailure), app.ReasonCode)
+	})
+
+	s.Run("StopWaitsForRecovery", func() {
+		require := s.Require()
+		advancer, machineManager, _, _ := newRecoveringAdvancer(s, 0, 3,
+			Pointer(ReasonCode_MachineServerFailure), cartesimachine.ErrCartesiMachine.Error())
+		started := make(chan struct{})
+		var returned atomic.Bool
+		machineManager.RecoverMachineHook = func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			returned.Store(true)
+		}
+
+		advancer.startRecovery(time.Millisecond)
+		<-started
+		require.Empty(advancer.Stop(false))
+		// the machines are only closed after the recovery was canceled
+		require.True(returned.Load())
+		require.True(machineManager.Closed)
+	})
+}
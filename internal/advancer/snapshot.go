@@ -0,0 +1,348 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package advancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+)
+
+// snapshotUploadQueueSize is how many snapshots may wait to be uploaded before
+// new ones are kept only locally
+const snapshotUploadQueueSize = 16
+
+// snapshotUpload is a snapshot waiting to be uploaded to the remote storage
+type snapshotUpload struct {
+	app   *Application
+	input *Input
+	path  string
+}
+
+// snapshotNamePattern matches the names createSnapshot gives to the snapshots,
+// and to their temporary directories while they are written
+var snapshotNamePattern = regexp.MustCompile(`_epoch\d+_input\d+(\.tmp-\d+)?$`)
+
+// snapshotProgress is what an application went through since its last
+// snapshot, or since the node started when it has none
+type snapshotProgress struct {
+	// index of the first input after the last snapshot
+	nextInput uint64
+	time      time.Time
+	cycles    uint64
+}
+
+// snapshotDue tells whether the interval snapshot policy of app requires a
+// snapshot after input. Cycles are only counted from the moment the node
+// started, as they are not kept with the snapshots.
+func (s *Service) snapshotDue(ctx context.Context, app *Application, input *Input) (bool, error) {
+	progress, err := s.getSnapshotProgress(ctx, app)
+	if err != nil {
+		return false, err
+	}
+	params := app.ExecutionParameters
+	switch params.SnapshotPolicy {
+	case SnapshotPolicy_EveryNInputs:
+		return input.Index+1-progress.nextInput >= max(params.SnapshotInputInterval, 1), nil
+	case SnapshotPolicy_EveryDuration:
+		return time.Since(progress.time) >= params.SnapshotTimeInterval, nil
+	case SnapshotPolicy_EveryNCycles:
+		return progress.cycles >= params.SnapshotCycleInterval, nil
+	default:
+		return false, nil
+	}
+}
+
+// getSnapshotProgress returns the progress of app, starting it from its most
+// recent snapshot the first time
+func (s *Service) getSnapshotProgress(ctx context.Context, app *Application) (*snapshotProgress, error) {
+	s.snapshotProgressMutex.Lock()
+	progress, ok := s.snapshotProgress[app.ID]
+	s.snapshotProgressMutex.Unlock()
+	if ok {
+		return progress, nil
+	}
+
+	snapshots, err := s.repository.ListSnapshots(ctx, app.IApplicationAddress.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	progress = &snapshotProgress{time: time.Now()}
+	if len(snapshots) > 0 {
+		// the input row is updated after its snapshot, when it is uploaded or
+		// pruned, so its creation time is the one that stays put
+		progress.nextInput = snapshots[0].Index + 1
+		progress.time = snapshots[0].CreatedAt
+	}
+
+	s.snapshotProgressMutex.Lock()
+	defer s.snapshotProgressMutex.Unlock()
+	if s.snapshotProgress == nil {
+		s.snapshotProgress = map[int64]*snapshotProgress{}
+	}
+	s.snapshotProgress[app.ID] = progress
+	return progress, nil
+}
+
+// addSnapshotCycles counts the cycles of an input towards the next snapshot of
+// the EVERY_N_CYCLES policy
+func (s *Service) addSnapshotCycles(ctx context.Context, app *Application, cycles uint64) error {
+	if app.ExecutionParameters.SnapshotPolicy != SnapshotPolicy_EveryNCycles {
+		return nil
+	}
+	progress, err := s.getSnapshotProgress(ctx, app)
+	if err != nil {
+		return err
+	}
+	s.snapshotProgressMutex.Lock()
+	defer s.snapshotProgressMutex.Unlock()
+	progress.cycles += cycles
+	return nil
+}
+
+// resetSnapshotProgress restarts the progress of app after the snapshot of input
+func (s *Service) resetSnapshotProgress(app *Application, input *Input) {
+	s.snapshotProgressMutex.Lock()
+	defer s.snapshotProgressMutex.Unlock()
+	if s.snapshotProgress == nil {
+		s.snapshotProgress = map[int64]*snapshotProgress{}
+	}
+	s.snapshotProgress[app.ID] = &snapshotProgress{nextInput: input.Index + 1, time: time.Now()}
+}
+
+// pruneSnapshots removes the snapshots of app not kept by the retention policy
+func (s *Service) pruneSnapshots(ctx context.Context, app *Application) error {
+	s.pruneMutex.Lock()
+	defer s.pruneMutex.Unlock()
+
+	snapshots, err := s.repository.ListSnapshots(ctx, app.IApplicationAddress.String())
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	retained, err := s.retainedSnapshots(ctx, app, snapshots)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, snapshot := range snapshots {
+		if retained[snapshot.Index] {
+			continue
+		}
+		err := s.repository.ClearInputSnapshotURI(ctx, snapshot.EpochApplicationID, snapshot.Index)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.removeSnapshot(ctx, *snapshot.SnapshotURI, app.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retainedSnapshots returns the input indexes of the snapshots kept by the
+// retention policy, given the snapshots of app from the most recent on
+func (s *Service) retainedSnapshots(
+	ctx context.Context,
+	app *Application,
+	snapshots []*Input,
+) (map[uint64]bool, error) {
+	count := max(s.snapshotsRetained, 1)
+	retained := map[uint64]bool{}
+
+	switch s.snapshotRetention {
+	case config.SnapshotRetentionEpoch:
+		retained[snapshots[0].Index] = true
+
+		status := EpochStatus_ClaimAccepted
+		epochs, _, err := s.repository.ListEpochs(ctx, app.IApplicationAddress.String(),
+			repository.EpochFilter{Status: &status}, repository.Pagination{Limit: count}, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accepted epochs: %w", err)
+		}
+		for _, epoch := range epochs {
+			for _, snapshot := range snapshots {
+				if snapshot.EpochIndex == epoch.Index {
+					retained[snapshot.Index] = true
+					break
+				}
+			}
+		}
+	default:
+		for _, snapshot := range snapshots[:min(count, uint64(len(snapshots)))] {
+			retained[snapshot.Index] = true
+		}
+	}
+	return retained, nil
+}
+
+// collectSnapshots removes the snapshot directories no input references,
+// like the ones left behind by an interrupted snapshot or by reverted inputs.
+// The local copies of referenced remote snapshots are kept.
+// It returns how many were removed.
+func (s *Service) collectSnapshots(ctx context.Context) (int, error) {
+	s.snapshotsMutex.Lock()
+	defer s.snapshotsMutex.Unlock()
+
+	entries, err := os.ReadDir(s.snapshotsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	uris, err := s.repository.ListSnapshotURIs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list snapshot URIs: %w", err)
+	}
+	referenced := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		if snapshot.IsRemote(uri) {
+			referenced[path.Join(s.snapshotsDir, snapshot.Name(uri))] = true
+		} else {
+			referenced[path.Clean(uri)] = true
+		}
+	}
+
+	removed := 0
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() || !snapshotNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		snapshotPath := path.Join(s.snapshotsDir, entry.Name())
+		if referenced[snapshotPath] {
+			continue
+		}
+		s.Logger.Info("Removing unreferenced snapshot", "path", snapshotPath)
+		if err := os.RemoveAll(snapshotPath); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+// collectSnapshotsEvery runs collectSnapshots every interval until ctx is done
+func (s *Service) collectSnapshotsEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.collectSnapshots(ctx)
+			if err != nil {
+				s.Logger.Error("Failed to remove unreferenced snapshots", "error", err)
+			}
+			if removed > 0 {
+				s.Logger.Info("Removed unreferenced snapshots", "count", removed)
+			}
+		}
+	}
+}
+
+// queueSnapshotUpload schedules the upload of the snapshot of input at
+// snapshotPath, if there is a remote storage. When too many uploads are
+// pending, the snapshot is kept only locally.
+func (s *Service) queueSnapshotUpload(app *Application, input *Input, snapshotPath string) {
+	if s.snapshotStorage == nil {
+		return
+	}
+	select {
+	case s.snapshotUploads <- snapshotUpload{app: app, input: input, path: snapshotPath}:
+	default:
+		s.Logger.Warn("Too many snapshots waiting to be uploaded, keeping it only locally",
+			"application", app.Name,
+			"input_index", input.Index,
+			"path", snapshotPath)
+	}
+}
+
+// uploadSnapshots uploads the queued snapshots, one at a time, until ctx is done
+func (s *Service) uploadSnapshots(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case upload := <-s.snapshotUploads:
+			if err := s.uploadSnapshot(ctx, upload); err != nil {
+				s.Logger.Error("Failed to upload snapshot",
+					"application", upload.app.Name,
+					"input_index", upload.input.Index,
+					"path", upload.path,
+					"error", err)
+			}
+		}
+	}
+}
+
+// uploadSnapshot uploads a snapshot and makes its input reference the remote
+// copy. The local one is kept as a cache. If the snapshot was pruned during
+// the upload, the remote copy is removed instead.
+func (s *Service) uploadSnapshot(ctx context.Context, upload snapshotUpload) error {
+	uri, err := s.snapshotStorage.Upload(ctx, upload.path, path.Base(upload.path))
+	if err != nil {
+		return err
+	}
+
+	s.pruneMutex.Lock()
+	defer s.pruneMutex.Unlock()
+
+	snapshots, err := s.repository.ListSnapshots(ctx, upload.app.IApplicationAddress.String())
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	for _, input := range snapshots {
+		if input.Index == upload.input.Index && *input.SnapshotURI == upload.path {
+			s.Logger.Info("Uploaded snapshot",
+				"application", upload.app.Name,
+				"input_index", upload.input.Index,
+				"uri", uri)
+			return s.repository.UpdateInputSnapshotURI(ctx, input.EpochApplicationID, input.Index, uri)
+		}
+	}
+
+	s.Logger.Debug("Snapshot pruned while uploading, removing the remote copy",
+		"application", upload.app.Name,
+		"uri", uri)
+	return s.snapshotStorage.Delete(ctx, uri)
+}
+
+// removeRemoteSnapshot removes a snapshot from the remote storage and its
+// local copy, if any
+func (s *Service) removeRemoteSnapshot(ctx context.Context, uri string, appName string) error {
+	name := snapshot.Name(uri)
+	if !strings.Contains(name, appName) || !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot URI: %s", uri)
+	}
+	if s.snapshotStorage == nil {
+		return fmt.Errorf("no remote snapshot storage configured for %s", uri)
+	}
+
+	s.Logger.Debug("Removing snapshot", "application", appName, "uri", uri)
+	if err := s.snapshotStorage.Delete(ctx, uri); err != nil {
+		return err
+	}
+	return os.RemoveAll(path.Join(s.snapshotsDir, name))
+}
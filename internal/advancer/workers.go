@@ -0,0 +1,196 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package advancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+)
+
+// worker advances one application, apart from the others, for as long as its
+// machine is managed
+type worker struct {
+	wake chan *Application // the application to advance next, at most one
+	quit chan struct{}     // closed when the application is no longer managed
+	busy atomic.Bool
+}
+
+// workerPool keeps a worker per application across the ticks, so a slow
+// application does not delay the others, and limits how many of them advance
+// at once
+type workerPool struct {
+	mutex    sync.Mutex
+	workers  map[int64]*worker
+	reserved map[int64]struct{} // applications whose machine is being recovered
+	slots    chan struct{}
+	errs     []error // of the runs finished since the last Step
+	running  sync.WaitGroup
+}
+
+func newWorkerPool(maxConcurrency uint64) *workerPool {
+	return &workerPool{
+		workers:  map[int64]*worker{},
+		reserved: map[int64]struct{}{},
+		slots:    make(chan struct{}, max(maxConcurrency, 1)),
+	}
+}
+
+// reserve keeps an application from being advanced, and its machine from
+// being updated, until it is released. It fails if the application is being
+// advanced or is already reserved.
+func (p *workerPool) reserve(id int64) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.reserved[id]; ok {
+		return false
+	}
+	if w, ok := p.workers[id]; ok && w.busy.Load() {
+		return false
+	}
+	p.reserved[id] = struct{}{}
+	return true
+}
+
+// release undoes reserve
+func (p *workerPool) release(id int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.reserved, id)
+}
+
+// dispatch wakes up the worker of each application, starting the missing
+// ones, and stops those of the applications left out. It returns the
+// applications skipped because their worker is still busy since a previous
+// tick, or because they are reserved.
+func (p *workerPool) dispatch(
+	ctx context.Context,
+	apps []*Application,
+	step func(context.Context, *Application) error,
+) []*Application {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	managed := map[int64]struct{}{}
+	var skipped []*Application
+	for _, app := range apps {
+		managed[app.ID] = struct{}{}
+		if _, ok := p.reserved[app.ID]; ok {
+			skipped = append(skipped, app)
+			continue
+		}
+		w, exists := p.workers[app.ID]
+		if !exists {
+			w = &worker{wake: make(chan *Application, 1), quit: make(chan struct{})}
+			p.workers[app.ID] = w
+			go p.work(ctx, w, step)
+		}
+		if !w.busy.CompareAndSwap(false, true) {
+			skipped = append(skipped, app)
+			continue
+		}
+		p.running.Add(1)
+		w.wake <- app
+	}
+	for id, w := range p.workers {
+		if _, ok := managed[id]; !ok {
+			close(w.quit)
+			delete(p.workers, id)
+		}
+	}
+	return skipped
+}
+
+// busyApplications returns the IDs of the applications being advanced or
+// reserved
+func (p *workerPool) busyApplications() map[int64]struct{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	busy := map[int64]struct{}{}
+	for id := range p.reserved {
+		busy[id] = struct{}{}
+	}
+	for id, w := range p.workers {
+		if w.busy.Load() {
+			busy[id] = struct{}{}
+		}
+	}
+	return busy
+}
+
+// takeErrors returns the errors of the runs finished since the previous call
+func (p *workerPool) takeErrors() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	errs := p.errs
+	p.errs = nil
+	return errors.Join(errs...)
+}
+
+// work runs step for each application the worker is woken up with, until it
+// is told to quit. Once ctx is canceled, the runs fail right away.
+func (p *workerPool) work(ctx context.Context, w *worker, step func(context.Context, *Application) error) {
+	for {
+		var app *Application
+		select {
+		case app = <-w.wake:
+		case <-w.quit:
+			// the select may pick quit over a wake that is still queued
+			select {
+			case <-w.wake:
+				w.busy.Store(false)
+				p.running.Done()
+			default:
+			}
+			return
+		}
+
+		var err error
+		select {
+		case p.slots <- struct{}{}:
+			err = step(ctx, app)
+			<-p.slots
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			p.mutex.Lock()
+			p.errs = append(p.errs, fmt.Errorf("application %s: %w", app.Name, err))
+			p.mutex.Unlock()
+		}
+		w.busy.Store(false)
+		p.running.Done()
+	}
+}
+
+// wait blocks until no application is being advanced, or timeout passes. It
+// reports whether they all finished.
+func (p *workerPool) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		p.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// close stops the idle workers, and the busy ones once they finish
+func (p *workerPool) close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for id, w := range p.workers {
+		close(w.quit)
+		delete(p.workers, id)
+	}
+}
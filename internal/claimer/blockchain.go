@@ -88,17 +88,19 @@ func (self *claimerBlockchain) submitClaimToBlockchain(
 		lastBlockNumber, *epoch.ClaimHash)
 	if err != nil {
 		self.logger.Error("submitClaimToBlockchain:failed",
-			"appContractAddress", application.IApplicationAddress,
-			"claimHash", *epoch.ClaimHash,
+			"application", application.Name,
+			"address", application.IApplicationAddress,
+			"claim_hash", *epoch.ClaimHash,
 			"last_block", epoch.LastBlock,
 			"error", err)
 	} else {
 		txHash = tx.Hash()
 		self.logger.Debug("submitClaimToBlockchain:success",
-			"appContractAddress", application.IApplicationAddress,
-			"claimHash", *epoch.ClaimHash,
+			"application", application.Name,
+			"address", application.IApplicationAddress,
+			"claim_hash", *epoch.ClaimHash,
 			"last_block", epoch.LastBlock,
-			"TxHash", txHash)
+			"tx_hash", txHash)
 	}
 	return txHash, err
 }
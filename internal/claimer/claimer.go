@@ -46,11 +46,16 @@ import (
 
 	"github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/pkg/contracts/iconsensus"
 
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/internal/claimer")
+
 var (
 	ErrClaimMismatch = fmt.Errorf("Claim and antecessor mismatch")
 	ErrEventMismatch = fmt.Errorf("Computed Claim mismatches ClaimSubmitted event")
@@ -89,13 +94,17 @@ type iclaimerRepository interface {
 		index uint64,
 	) error
 
-	UpdateApplicationState(
+	SetApplicationInoperable(
 		ctx context.Context,
 		appID int64,
-		state model.ApplicationState,
-		reason *string,
+		code model.ReasonCode,
+		reason string,
 	) error
 
+	// to apply the retention policy of the applications
+	GetExecutionParameters(ctx context.Context, applicationID int64) (*model.ExecutionParameters, error)
+	repository.PruneRepository
+
 	SaveNodeConfigRaw(ctx context.Context, key string, rawJSON []byte) error
 	LoadNodeConfigRaw(ctx context.Context, key string) (rawJSON []byte, createdAt, updatedAt time.Time, err error)
 }
@@ -109,14 +118,15 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 ) []error {
 	errs := []error{}
 	var err error
+	defer func() { claimsInFlightMetric.Set(float64(len(s.claimsInFlight))) }()
 
 	// check claims in flight
 	for key, txHash := range s.claimsInFlight {
 		ready, receipt, err := s.blockchain.pollTransaction(s.Context, txHash, endBlock)
 		if err != nil {
 			s.Logger.Warn("Claim submission failed, retrying.",
-				"txHash", txHash,
-				"err", err,
+				"tx_hash", txHash,
+				"error", err,
 			)
 			delete(s.claimsInFlight, key)
 			continue
@@ -135,22 +145,29 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 				errs = append(errs, err)
 				return errs
 			}
+			observeSubmission(apps[key], receipt)
 			s.Logger.Info("Claim submitted",
-				"app", apps[key].IApplicationAddress,
+				"application", apps[key].Name,
+				"address", apps[key].IApplicationAddress,
 				"receipt_block_number", receipt.BlockNumber,
 				"claim_hash", fmt.Sprintf("%x", computedEpoch.ClaimHash),
 				"last_block", computedEpoch.LastBlock,
-				"tx", txHash)
+				"tx_hash", txHash)
 			delete(computedEpochs, key)
 		} else {
 			s.Logger.Warn("expected claim in flight to be in currClaims.",
-				"tx", receipt.TxHash)
+				"tx_hash", receipt.TxHash)
 		}
 		delete(s.claimsInFlight, key)
 	}
 
 	// check computed epochs
 	for key, currEpoch := range computedEpochs {
+		// leave the remaining claims to the next run when stopping
+		if s.Stopping() {
+			break
+		}
+		s.Heartbeat()
 		var ic *iconsensus.IConsensus
 		var prevClaimSubmissionEvent *iconsensus.IConsensusClaimSubmitted
 		var currClaimSubmissionEvent *iconsensus.IConsensusClaimSubmitted
@@ -173,8 +190,8 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 			if err != nil {
 				err = s.setApplicationInoperable(
 					s.Context,
-					app.IApplicationAddress,
-					prevEpoch.ApplicationID,
+					app,
+					model.ReasonCode_InconsistentState,
 					"database mismatch on epochs. application: %v, epochs: %v (%v), %v (%v).",
 					app.IApplicationAddress,
 					prevEpoch.Index,
@@ -199,8 +216,8 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 			if prevClaimSubmissionEvent == nil {
 				err = s.setApplicationInoperable(
 					s.Context,
-					app.IApplicationAddress,
-					app.ID,
+					app,
+					model.ReasonCode_ClaimMismatch,
 					"epoch has no matching event. application: %v, epoch: %v (%v).",
 					app.IApplicationAddress,
 					prevEpoch.Index,
@@ -214,12 +231,12 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 				s.Logger.Error("event mismatch",
 					"claim", prevEpoch,
 					"event", prevClaimSubmissionEvent,
-					"err", ErrEventMismatch,
+					"error", ErrEventMismatch,
 				)
 				err = s.setApplicationInoperable(
 					s.Context,
-					app.IApplicationAddress,
-					app.ID,
+					app,
+					model.ReasonCode_ClaimMismatch,
 					"epoch has an invalid event: %v, epoch: %v (%v). event: %v",
 					currEpoch.Index,
 					prevEpoch.Index,
@@ -243,15 +260,16 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 
 		if currClaimSubmissionEvent != nil {
 			s.Logger.Debug("Found ClaimSubmitted Event",
-				"app", currClaimSubmissionEvent.AppContract,
+				"application", app.Name,
+				"address", currClaimSubmissionEvent.AppContract,
 				"claim_hash", fmt.Sprintf("%x", currClaimSubmissionEvent.OutputsMerkleRoot),
 				"last_block", currClaimSubmissionEvent.LastProcessedBlockNumber.Uint64(),
 			)
 			if !claimSubmittedEventMatches(app, currEpoch, currClaimSubmissionEvent) {
 				err = s.setApplicationInoperable(
 					s.Context,
-					app.IApplicationAddress,
-					app.ID,
+					app,
+					model.ReasonCode_ClaimMismatch,
 					"computed claim does not match event. computed_claim=%v, current_event=%v",
 					currEpoch, currClaimSubmissionEvent,
 				)
@@ -260,7 +278,8 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 				goto nextApp
 			}
 			s.Logger.Debug("Updating claim status to submitted",
-				"app", app.IApplicationAddress,
+				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"claim_hash", fmt.Sprintf("%x", currEpoch.ClaimHash),
 				"last_block", currEpoch.LastBlock,
 			)
@@ -278,7 +297,8 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 			}
 			delete(s.claimsInFlight, key)
 			s.Logger.Info("Claim previously submitted",
-				"app", app.IApplicationAddress,
+				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"event_block_number", currClaimSubmissionEvent.Raw.BlockNumber,
 				"claim_hash", fmt.Sprintf("%x", currEpoch.ClaimHash),
 				"last_block", currEpoch.LastBlock,
@@ -286,18 +306,20 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 		} else if s.submissionEnabled {
 			if prevEpoch != nil && prevEpoch.Status != model.EpochStatus_ClaimAccepted {
 				s.Logger.Debug("Waiting previous claim to be accepted before submitting new one. Previous:",
-					"app", app.IApplicationAddress,
+					"application", app.Name,
+					"address", app.IApplicationAddress,
 					"claim_hash", fmt.Sprintf("%x", prevEpoch.ClaimHash),
 					"last_block", prevEpoch.LastBlock,
 				)
 				goto nextApp
 			}
 			s.Logger.Debug("Submitting claim to blockchain",
-				"app", app.IApplicationAddress,
+				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"claim_hash", fmt.Sprintf("%x", currEpoch.ClaimHash),
 				"last_block", currEpoch.LastBlock,
 			)
-			txHash, err := s.blockchain.submitClaimToBlockchain(ic, app, currEpoch)
+			txHash, err := s.submitClaim(ic, app, currEpoch)
 			if err != nil {
 				delete(computedEpochs, key)
 				errs = append(errs, err)
@@ -306,7 +328,8 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 			s.claimsInFlight[key] = txHash
 		} else {
 			s.Logger.Debug("Claim submission disabled. Doing nothing",
-				"app", app.IApplicationAddress,
+				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"claim_hash", fmt.Sprintf("%x", currEpoch.ClaimHash),
 				"last_block", currEpoch.LastBlock,
 			)
@@ -317,6 +340,24 @@ func (s *Service) submitClaimsAndUpdateDatabase(
 	return errs
 }
 
+// submitClaim submits the claim of epoch to the consensus of app, returning
+// the hash of the transaction.
+func (s *Service) submitClaim(
+	ic *iconsensus.IConsensus,
+	app *model.Application,
+	epoch *model.Epoch,
+) (txHash common.Hash, err error) {
+	_, span := tracing.Start(s.Context, tracer, "SubmitClaim",
+		tracing.ApplicationAddress(app.IApplicationAddress),
+		tracing.EpochIndex(epoch.Index),
+		attribute.String("cartesi.claim.hash", epoch.ClaimHash.String()))
+	defer func() {
+		span.SetAttributes(attribute.String("cartesi.claim.transaction", txHash.String()))
+		tracing.End(span, err)
+	}()
+	return s.blockchain.submitClaimToBlockchain(ic, app, epoch)
+}
+
 /* transition claims from submitted to accepted */
 func (s *Service) acceptClaimsAndUpdateDatabase(
 	acceptedEpochs map[int64]*model.Epoch,
@@ -329,6 +370,11 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 
 	// check submitted claims
 	for key, submittedEpoch := range submittedEpochs {
+		// leave the remaining claims to the next run when stopping
+		if s.Stopping() {
+			break
+		}
+		s.Heartbeat()
 		var prevEvent *iconsensus.IConsensusClaimAccepted
 		var currEvent *iconsensus.IConsensusClaimAccepted
 
@@ -344,10 +390,11 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 			err := checkEpochSequenceConstraint(acceptedEpoch, submittedEpoch)
 			if err != nil {
 				s.Logger.Error("Database mismatch on epochs.",
-					"app", app.IApplicationAddress,
+					"application", app.Name,
+					"address", app.IApplicationAddress,
 					"previous_epoch_index", acceptedEpoch.Index,
 					"current_epoch_index", submittedEpoch.Index,
-					"err", err,
+					"error", err,
 				)
 				delete(submittedEpochs, key)
 				errs = append(errs, err)
@@ -364,9 +411,10 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 			}
 			if prevEvent == nil {
 				s.Logger.Error("Missing event",
-					"app", app.IApplicationAddress,
+					"application", app.Name,
+					"address", app.IApplicationAddress,
 					"claim", acceptedEpoch,
-					"err", ErrMissingEvent,
+					"error", ErrMissingEvent,
 				)
 				delete(submittedEpochs, key)
 				errs = append(errs, ErrMissingEvent)
@@ -374,10 +422,11 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 			}
 			if !claimAcceptedEventMatches(app, acceptedEpoch, prevEvent) {
 				s.Logger.Error("Event mismatch",
-					"app", app.IApplicationAddress,
+					"application", app.Name,
+					"address", app.IApplicationAddress,
 					"claim", acceptedEpoch,
 					"event", prevEvent,
-					"err", ErrEventMismatch,
+					"error", ErrEventMismatch,
 				)
 				delete(submittedEpochs, key)
 				errs = append(errs, ErrEventMismatch)
@@ -396,7 +445,8 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 
 		if currEvent != nil {
 			s.Logger.Debug("Found ClaimAccepted Event",
-				"app", currEvent.AppContract,
+				"application", app.Name,
+				"address", currEvent.AppContract,
 				"claim_hash", fmt.Sprintf("%x", currEvent.OutputsMerkleRoot),
 				"last_block", currEvent.LastProcessedBlockNumber.Uint64(),
 			)
@@ -404,14 +454,15 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 				s.Logger.Error("event mismatch",
 					"claim", submittedEpoch,
 					"event", currEvent,
-					"err", ErrEventMismatch,
+					"error", ErrEventMismatch,
 				)
 				delete(submittedEpochs, key)
 				errs = append(errs, ErrEventMismatch)
 				goto nextApp
 			}
 			s.Logger.Debug("Updating claim status to accepted",
-				"app", app.IApplicationAddress,
+				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"claim_hash", fmt.Sprintf("%x", submittedEpoch.ClaimHash),
 				"last_block", submittedEpoch.LastBlock,
 			)
@@ -422,38 +473,71 @@ func (s *Service) acceptClaimsAndUpdateDatabase(
 				errs = append(errs, err)
 				goto nextApp
 			}
+			claimsAcceptedMetric.WithLabelValues(app.Name).Inc()
 			s.Logger.Info("Claim accepted",
-				"app", currEvent.AppContract,
+				"application", app.Name,
+				"address", currEvent.AppContract,
 				"event_block_number", currEvent.Raw.BlockNumber,
 				"claim_hash", fmt.Sprintf("%x", currEvent.OutputsMerkleRoot),
 				"last_block", currEvent.LastProcessedBlockNumber.Uint64(),
-				"tx", txHash,
+				"tx_hash", txHash,
 			)
+			if err = s.pruneApplication(app); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	nextApp:
 	}
 	return errs
 }
 
-// setApplicationInoperable marks an application as inoperable with the given reason,
+// pruneApplication applies the retention policy of the application, now that
+// another of its claims was accepted
+func (s *Service) pruneApplication(app *model.Application) error {
+	ep, err := s.repository.GetExecutionParameters(s.Context, app.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get the execution parameters of application %s: %w", app.Name, err)
+	}
+	if ep == nil || ep.RetainedEpochs == 0 {
+		return nil
+	}
+
+	pruned := *app
+	pruned.ExecutionParameters = *ep
+	result, err := repository.PruneApplication(s.Context, s.repository, &pruned)
+	if err != nil {
+		return fmt.Errorf("failed to prune application %s: %w", app.Name, err)
+	}
+	if result != nil && result.Inputs+result.Reports > 0 {
+		s.Logger.Info("Pruned application",
+			"application", app.Name,
+			"epoch_index", result.EpochIndex,
+			"inputs", result.Inputs,
+			"reports", result.Reports,
+		)
+	}
+	return nil
+}
+
+// setApplicationInoperable marks an application as inoperable with the given code and reason,
 // logs any error that occurs during the update, and returns an error with the reason.
 func (s *Service) setApplicationInoperable(
 	ctx context.Context,
-	iApplicationAddress common.Address,
-	id int64,
+	app *model.Application,
+	code model.ReasonCode,
 	reasonFmt string,
 	args ...any,
 ) error {
 	reason := fmt.Sprintf(reasonFmt, args...)
-	appAddress := iApplicationAddress.String()
 
 	// Log the reason first
-	s.Logger.Error(reason, "application", appAddress)
+	s.Logger.Error(reason, "application", app.Name, "address", app.IApplicationAddress)
 
 	// Update application state
-	err := s.repository.UpdateApplicationState(ctx, id, model.ApplicationState_Inoperable, &reason)
+	err := s.repository.SetApplicationInoperable(ctx, app.ID, code, reason)
 	if err != nil {
-		s.Logger.Error("failed to update application state to inoperable", "app", appAddress, "err", err)
+		s.Logger.Error("failed to update application state to inoperable",
+			"application", app.Name, "address", app.IApplicationAddress, "error", err)
 	}
 
 	// Return the error with the reason
@@ -470,8 +554,8 @@ func (s *Service) checkConsensusForAddressChange(
 	if app.IConsensusAddress != newConsensusAddress {
 		err = s.setApplicationInoperable(
 			s.Context,
-			app.IApplicationAddress,
-			app.ID,
+			app,
+			model.ReasonCode_ConsensusChanged,
 			"consensus change detected. application: %v.",
 			app.IApplicationAddress,
 		)
@@ -5,6 +5,7 @@ package claimer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -12,11 +13,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
 	"github.com/cartesi/rollups-node/pkg/contracts/iconsensus"
 	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/lmittmann/tint"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -74,13 +77,13 @@ func (m *claimerRepositoryMock) UpdateEpochWithSubmittedClaim(
 	return args.Error(0)
 }
 
-func (m *claimerRepositoryMock) UpdateApplicationState(
+func (m *claimerRepositoryMock) SetApplicationInoperable(
 	ctx context.Context,
 	appID int64,
-	state model.ApplicationState,
-	reason *string,
+	code model.ReasonCode,
+	reason string,
 ) error {
-	args := m.Called(ctx, appID, state, reason)
+	args := m.Called(ctx, appID, code, reason)
 	return args.Error(0)
 }
 
@@ -93,6 +96,40 @@ func (m *claimerRepositoryMock) UpdateEpochWithAcceptedClaim(
 	return args.Error(0)
 }
 
+func (m *claimerRepositoryMock) GetExecutionParameters(
+	ctx context.Context,
+	applicationID int64,
+) (*model.ExecutionParameters, error) {
+	args := m.Called(ctx, applicationID)
+	return args.Get(0).(*model.ExecutionParameters), args.Error(1)
+}
+
+func (m *claimerRepositoryMock) ListEpochs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.EpochFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Epoch, uint64, error) {
+	args := m.Called(ctx, nameOrAddress, f, p, descending)
+	return args.Get(0).([]*model.Epoch), args.Get(1).(uint64), args.Error(2)
+}
+
+func (m *claimerRepositoryMock) ListSnapshots(ctx context.Context, nameOrAddress string) ([]*model.Input, error) {
+	args := m.Called(ctx, nameOrAddress)
+	return args.Get(0).([]*model.Input), args.Error(1)
+}
+
+func (m *claimerRepositoryMock) PruneEpochs(
+	ctx context.Context,
+	appID int64,
+	epochIndex uint64,
+	inputIndex uint64,
+) (uint64, uint64, error) {
+	args := m.Called(ctx, appID, epochIndex, inputIndex)
+	return args.Get(0).(uint64), args.Get(1).(uint64), args.Error(2)
+}
+
 func (m *claimerRepositoryMock) SaveNodeConfigRaw(
 	ctx context.Context,
 	key string,
@@ -314,6 +351,7 @@ func TestSubmitFirstClaim(t *testing.T) {
 	errs := m.submitClaimsAndUpdateDatabase(makeEpochMap(), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
 	assert.Equal(t, len(errs), 0)
 	assert.Equal(t, len(m.claimsInFlight), 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(claimsInFlightMetric))
 }
 
 func TestSubmitClaimWithAntecessor(t *testing.T) {
@@ -393,6 +431,7 @@ func TestInFlightCompleted(t *testing.T) {
 	txHash := common.HexToHash("0x10")
 	endBlock := big.NewInt(0)
 	app := makeApplication(0)
+	app.Name = "in-flight-completed"
 	currEpoch := makeComputedEpoch(app, 3)
 	currEpoch.ClaimTransactionHash = &txHash
 
@@ -400,8 +439,10 @@ func TestInFlightCompleted(t *testing.T) {
 
 	b.On("pollTransaction", mock.Anything, txHash, endBlock).
 		Return(true, &types.Receipt{
-			ContractAddress: app.IApplicationAddress,
-			TxHash:          *currEpoch.ClaimTransactionHash,
+			ContractAddress:   app.IApplicationAddress,
+			TxHash:            *currEpoch.ClaimTransactionHash,
+			GasUsed:           21000,
+			EffectiveGasPrice: big.NewInt(3),
 		}, nil).Once()
 	r.On("UpdateEpochWithSubmittedClaim", mock.Anything, app.ID, currEpoch.Index, txHash).
 		Return(nil).Once()
@@ -409,6 +450,10 @@ func TestInFlightCompleted(t *testing.T) {
 	errs := m.submitClaimsAndUpdateDatabase(makeEpochMap(), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
 	assert.Equal(t, len(errs), 0)
 	assert.Equal(t, len(m.claimsInFlight), 0)
+	assert.Equal(t, 0.0, testutil.ToFloat64(claimsInFlightMetric))
+	assert.Equal(t, 1.0, testutil.ToFloat64(claimsSubmittedMetric.WithLabelValues(app.Name)))
+	assert.Equal(t, 21000.0, testutil.ToFloat64(gasUsedMetric.WithLabelValues(app.Name)))
+	assert.Equal(t, 63000.0, testutil.ToFloat64(gasCostMetric.WithLabelValues(app.Name)))
 }
 
 func TestUpdateFirstClaim(t *testing.T) {
@@ -485,6 +530,7 @@ func TestAcceptClaimWithAntecessor(t *testing.T) {
 
 	endBlock := big.NewInt(0)
 	app := makeApplication(0)
+	app.Name = "accept-claim-with-antecessor"
 	prevEpoch := makeAcceptedEpoch(app, 1)
 	currEpoch := makeSubmittedEpoch(app, 3)
 	prevEvent := makeAcceptedEvent(app, prevEpoch)
@@ -496,6 +542,44 @@ func TestAcceptClaimWithAntecessor(t *testing.T) {
 		Return(&iconsensus.IConsensus{}, prevEvent, currEvent, nil).Once()
 	r.On("UpdateEpochWithAcceptedClaim", mock.Anything, app.ID, currEpoch.Index).
 		Return(nil).Once()
+	r.On("GetExecutionParameters", mock.Anything, app.ID).
+		Return(&model.ExecutionParameters{}, nil).Once()
+
+	errs := m.acceptClaimsAndUpdateDatabase(makeEpochMap(prevEpoch), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, 1.0, testutil.ToFloat64(claimsAcceptedMetric.WithLabelValues(app.Name)))
+}
+
+func TestAcceptClaimAppliesRetentionPolicy(t *testing.T) {
+	m, r, b := newServiceMock()
+	defer r.AssertExpectations(t)
+	defer b.AssertExpectations(t)
+
+	endBlock := big.NewInt(0)
+	app := makeApplication(0)
+	prevEpoch := makeAcceptedEpoch(app, 1)
+	currEpoch := makeSubmittedEpoch(app, 3)
+	prevEvent := makeAcceptedEvent(app, prevEpoch)
+	currEvent := makeAcceptedEvent(app, currEpoch)
+	nameOrAddress := app.IApplicationAddress.String()
+	status := model.EpochStatus_ClaimAccepted
+	snapshot := &model.Input{Index: 7}
+
+	b.On("getConsensusAddress", mock.Anything, app).
+		Return(app.IConsensusAddress, nil).Once()
+	b.On("findClaimAcceptedEventAndSucc", mock.Anything, app, prevEpoch, endBlock).
+		Return(&iconsensus.IConsensus{}, prevEvent, currEvent, nil).Once()
+	r.On("UpdateEpochWithAcceptedClaim", mock.Anything, app.ID, currEpoch.Index).
+		Return(nil).Once()
+	r.On("GetExecutionParameters", mock.Anything, app.ID).
+		Return(&model.ExecutionParameters{RetainedEpochs: 1}, nil).Once()
+	r.On("ListEpochs", mock.Anything, nameOrAddress, repository.EpochFilter{Status: &status},
+		repository.Pagination{Limit: 1, Offset: 0}, true).
+		Return([]*model.Epoch{currEpoch}, uint64(2), nil).Once()
+	r.On("ListSnapshots", mock.Anything, nameOrAddress).
+		Return([]*model.Input{snapshot}, nil).Once()
+	r.On("PruneEpochs", mock.Anything, app.ID, currEpoch.Index, snapshot.Index+1).
+		Return(uint64(4), uint64(2), nil).Once()
 
 	errs := m.acceptClaimsAndUpdateDatabase(makeEpochMap(prevEpoch), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
 	assert.Equal(t, len(errs), 0)
@@ -581,7 +665,7 @@ func TestSubmitClaimWithAntecessorMismatch(t *testing.T) {
 	b.On("findClaimSubmittedEventAndSucc", app, prevEpoch, endBlock).
 		Return(&iconsensus.IConsensus{}, prevEvent, currEvent, nil).
 		Once()
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_ClaimMismatch, mock.Anything).
 		Return(nil).
 		Once()
 
@@ -606,7 +690,7 @@ func TestSubmitClaimWithEventMismatch(t *testing.T) {
 		Return(app.IConsensusAddress, nil).Once()
 	b.On("findClaimSubmittedEventAndSucc", app, prevEpoch, endBlock).
 		Return(&iconsensus.IConsensus{}, prevEvent, wrongEvent, nil)
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_ClaimMismatch, mock.Anything).
 		Return(nil)
 
 	errs := m.submitClaimsAndUpdateDatabase(makeEpochMap(prevEpoch), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
@@ -625,7 +709,7 @@ func TestSubmitClaimWithAntecessorOutOfOrder(t *testing.T) {
 
 	b.On("getConsensusAddress", mock.Anything, app).
 		Return(app.IConsensusAddress, nil).Once()
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_InconsistentState, mock.Anything).
 		Return(nil)
 
 	errs := m.submitClaimsAndUpdateDatabase(makeEpochMap(prevEpoch), makeEpochMap(currEpoch), makeApplicationMap(app), big.NewInt(0))
@@ -648,7 +732,7 @@ func TestErrSubmittedMissingEvent(t *testing.T) {
 		Return(app.IConsensusAddress, nil).Once()
 	b.On("findClaimSubmittedEventAndSucc", app, prevEpoch, endBlock).
 		Return(&iconsensus.IConsensus{}, prevEvent, currEvent, nil).Once()
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_ClaimMismatch, mock.Anything).
 		Return(nil)
 
 	errs := m.submitClaimsAndUpdateDatabase(makeEpochMap(prevEpoch), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
@@ -669,7 +753,7 @@ func TestConsensusAddressChangedOnSubmittedClaims(t *testing.T) {
 	b.On("getConsensusAddress", mock.Anything, app).
 		Return(wrongConsensusAddress, nil).
 		Once()
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_ConsensusChanged, mock.Anything).
 		Return(nil).
 		Once()
 
@@ -851,10 +935,41 @@ func TestConsensusAddressChangedOnAcceptedClaims(t *testing.T) {
 	b.On("getConsensusAddress", mock.Anything, app).
 		Return(wrongConsensusAddress, nil).
 		Once()
-	r.On("UpdateApplicationState", nil, int64(0), model.ApplicationState_Inoperable, mock.Anything).
+	r.On("SetApplicationInoperable", nil, int64(0), model.ReasonCode_ConsensusChanged, mock.Anything).
 		Return(nil).
 		Once()
 
 	errs := m.acceptClaimsAndUpdateDatabase(makeEpochMap(), makeEpochMap(currEpoch), makeApplicationMap(app), endBlock)
 	assert.Equal(t, len(errs), 1)
 }
+
+func TestReloadOverridesClaimSubmission(t *testing.T) {
+	m, r, _ := newServiceMock()
+	defer r.AssertExpectations(t)
+
+	persisted := PersistentConfig{
+		DefaultBlock:           model.DefaultBlock_Finalized,
+		ClaimSubmissionEnabled: true,
+		ChainID:                1,
+	}
+	raw, err := json.Marshal(persisted)
+	assert.Nil(t, err)
+	persisted.ClaimSubmissionEnabled = false
+	overridden, err := json.Marshal(persisted)
+	assert.Nil(t, err)
+
+	r.On("LoadNodeConfigRaw", mock.Anything, ClaimerConfigKey).
+		Return(raw, time.Time{}, time.Time{}, nil).
+		Once()
+	r.On("SaveNodeConfigRaw", mock.Anything, ClaimerConfigKey, overridden).
+		Return(nil).
+		Once()
+
+	err = m.reloadPersistentConfig(context.Background(), &config.ClaimerConfig{
+		BlockchainDefaultBlock:        model.DefaultBlock_Finalized,
+		BlockchainId:                  1,
+		FeatureClaimSubmissionEnabled: false,
+	})
+	assert.Nil(t, err)
+	assert.False(t, m.submissionEnabled)
+}
@@ -0,0 +1,57 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package claimer
+
+import (
+	"math/big"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	claimsSubmittedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "claimer",
+		Name:      "claims_submitted_total",
+		Help:      "Claims submitted by the node, per application.",
+	}, []string{"application"})
+	claimsAcceptedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "claimer",
+		Name:      "claims_accepted_total",
+		Help:      "Claims accepted by the consensus, per application.",
+	}, []string{"application"})
+	gasUsedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "claimer",
+		Name:      "gas_used_total",
+		Help:      "Gas used by the claim submissions, per application.",
+	}, []string{"application"})
+	gasCostMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "claimer",
+		Name:      "gas_cost_wei_total",
+		Help:      "Fees paid for the claim submissions, in wei, per application.",
+	}, []string{"application"})
+	claimsInFlightMetric = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "claimer",
+		Name:      "claims_in_flight",
+		Help:      "Claim submissions waiting for their transaction to be mined.",
+	})
+)
+
+// observeSubmission records a claim submission of app mined with receipt.
+func observeSubmission(app *model.Application, receipt *types.Receipt) {
+	claimsSubmittedMetric.WithLabelValues(app.Name).Inc()
+	gasUsedMetric.WithLabelValues(app.Name).Add(float64(receipt.GasUsed))
+	if receipt.EffectiveGasPrice != nil {
+		cost := new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+		wei, _ := new(big.Float).SetInt(cost).Float64()
+		gasCostMetric.WithLabelValues(app.Name).Add(wei)
+	}
+}
@@ -38,10 +38,16 @@ type Service struct {
 	blockchain        iclaimerBlockchain
 	claimsInFlight    map[int64]common.Hash // application.ID -> txHash
 	submissionEnabled bool
+	// configuration in effect, updated by Reload
+	config config.ClaimerConfig
 }
 
 const ClaimerConfigKey = "claimer"
 
+// PersistentConfig is saved in the database the first time the claimer starts
+// and takes precedence over the configuration afterwards. On Reload, the
+// configured ClaimSubmissionEnabled overrides the persisted one; the other
+// fields never change.
 type PersistentConfig struct {
 	DefaultBlock           DefaultBlock
 	ClaimSubmissionEnabled bool
@@ -63,6 +69,12 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 	s := &Service{}
 	c.Impl = s
 
+	// wake up on computed claims instead of waiting for the next poll
+	if c.NotifyConnection == "" && c.Config.DatabaseConnection != nil {
+		c.NotifyConnection = c.Config.DatabaseConnection.String()
+		c.NotifyChannels = []string{repository.EpochClaimComputedChannel}
+	}
+
 	err = service.Create(ctx, &c.CreateInfo, &s.Service)
 	if err != nil {
 		return nil, err
@@ -85,6 +97,7 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		return nil, fmt.Errorf("NodeConfig chainId mismatch: network %d != config %d",
 			chainId.Uint64(), nodeConfig.ChainID)
 	}
+	s.config = c.Config
 	s.submissionEnabled = nodeConfig.ClaimSubmissionEnabled
 	s.claimsInFlight = map[int64]common.Hash{}
 
@@ -110,6 +123,9 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		},
 	}
 
+	s.AddHealthCheck("database", c.Repository.Ping)
+	s.AddHealthCheck("tick", s.TickCheck)
+
 	return s, nil
 }
 
@@ -118,10 +134,33 @@ func (s *Service) Alive() bool {
 }
 
 func (s *Service) Ready() bool {
-	return true
+	return s.Health(s.Context).Ready
 }
 
+// Reload applies the log level, the polling interval, the maximum block range
+// and whether claims are submitted
 func (s *Service) Reload() []error {
+	c, err := config.LoadClaimerConfig()
+	if err != nil {
+		return []error{err}
+	}
+	s.SetLogLevel(c.LogLevel)
+	s.SetPollInterval(c.ClaimerPollingInterval)
+	if blockchain, ok := s.blockchain.(*claimerBlockchain); ok {
+		blockchain.filter.MaxChunkSize = new(big.Int).SetUint64(c.BlockchainMaxBlockRange)
+	}
+	err = s.reloadPersistentConfig(s.Context, c)
+	if err != nil {
+		return []error{err}
+	}
+
+	s.config.LogLevel = c.LogLevel
+	s.config.ClaimerPollingInterval = c.ClaimerPollingInterval
+	s.config.BlockchainMaxBlockRange = c.BlockchainMaxBlockRange
+	s.config.FeatureClaimSubmissionEnabled = c.FeatureClaimSubmissionEnabled
+	s.config.BlockchainDefaultBlock = c.BlockchainDefaultBlock
+	s.config.BlockchainId = c.BlockchainId
+	s.ReportRestartRequired(config.Changed(&s.config, c))
 	return nil
 }
 
@@ -200,3 +239,35 @@ func setupPersistentConfig(
 	logger.Error("Could not retrieve persistent config from Database. %w", "error", err)
 	return nil, err
 }
+
+// reloadPersistentConfig overrides the persisted claim submission with the
+// configured one and applies it
+func (s *Service) reloadPersistentConfig(ctx context.Context, c *config.ClaimerConfig) error {
+	nc, err := repository.LoadNodeConfig[PersistentConfig](ctx, s.repository, ClaimerConfigKey)
+	if err != nil || nc == nil {
+		return err
+	}
+	if c.BlockchainDefaultBlock != nc.Value.DefaultBlock || c.BlockchainId != nc.Value.ChainID {
+		s.Logger.Warn("Configuration differs from the persistent config, which is kept",
+			"config", nc.Value)
+	}
+	if c.FeatureClaimSubmissionEnabled == nc.Value.ClaimSubmissionEnabled {
+		return nil
+	}
+
+	if blockchain, ok := s.blockchain.(*claimerBlockchain); ok &&
+		c.FeatureClaimSubmissionEnabled && blockchain.txOpts == nil {
+		blockchain.txOpts, err = auth.GetTransactOpts(new(big.Int).SetUint64(nc.Value.ChainID))
+		if err != nil {
+			return err
+		}
+	}
+	nc.Value.ClaimSubmissionEnabled = c.FeatureClaimSubmissionEnabled
+	err = repository.SaveNodeConfig(ctx, s.repository, nc)
+	if err != nil {
+		return err
+	}
+	s.Logger.Info("Overriding claimer persistent config", "config", nc.Value)
+	s.submissionEnabled = nc.Value.ClaimSubmissionEnabled
+	return nil
+}
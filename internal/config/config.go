@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
+	"github.com/cartesi/rollups-node/pkg/service"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -34,6 +36,7 @@ type (
 	URL             = *url.URL
 	Duration        = time.Duration
 	LogLevel        = slog.Level
+	LogFormat       = service.LogFormat
 	DefaultBlock    = model.DefaultBlock
 	RedactedString  = Redacted[string]
 	RedactedUint    = Redacted[uint32]
@@ -55,6 +58,66 @@ const (
 	AuthKindAWS
 )
 
+// ------------------------------------------------------------------------------------------------
+// Snapshot Retention
+// ------------------------------------------------------------------------------------------------
+
+type SnapshotRetention uint8
+
+const (
+	// SnapshotRetentionLast keeps the most recent snapshots.
+	SnapshotRetentionLast SnapshotRetention = iota
+	// SnapshotRetentionEpoch keeps the most recent snapshot and the last
+	// snapshot of the most recent accepted epochs.
+	SnapshotRetentionEpoch
+)
+
+// ------------------------------------------------------------------------------------------------
+// Restart Policy
+// ------------------------------------------------------------------------------------------------
+
+type RestartPolicy uint8
+
+const (
+	// RestartPolicyOnFailure restarts the services that fail.
+	RestartPolicyOnFailure RestartPolicy = iota
+	// RestartPolicyAlways restarts the services whenever they stop.
+	RestartPolicyAlways
+	// RestartPolicyNever leaves the services that stop down.
+	RestartPolicyNever
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartPolicyOnFailure:
+		return "on-failure"
+	case RestartPolicyAlways:
+		return "always"
+	case RestartPolicyNever:
+		return "never"
+	default:
+		return fmt.Sprintf("RestartPolicy(%d)", uint8(p))
+	}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Reload
+// ------------------------------------------------------------------------------------------------
+
+// Changed returns the variables whose values differ between two configurations of the same
+// service, given as pointers to their structs.
+func Changed(old, new any) []string {
+	oldValue := reflect.ValueOf(old).Elem()
+	newValue := reflect.ValueOf(new).Elem()
+	var variables []string
+	for i := range oldValue.NumField() {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			variables = append(variables, oldValue.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return variables
+}
+
 // ------------------------------------------------------------------------------------------------
 // Parsing functions
 // ------------------------------------------------------------------------------------------------
@@ -94,6 +157,10 @@ func ToLogLevelFromString(s string) (LogLevel, error) {
 	}
 }
 
+func ToLogFormatFromString(s string) (LogFormat, error) {
+	return service.ParseLogFormat(s)
+}
+
 func ToMachineLogLevelFromString(s string) (MachineLogLevel, error) {
 	return cartesimachine.ParseMachineLogLevel(s)
 }
@@ -166,6 +233,33 @@ func ToAuthKindFromString(s string) (AuthKind, error) {
 	}
 }
 
+func ToSnapshotRetentionFromString(s string) (SnapshotRetention, error) {
+	var m = map[string]SnapshotRetention{
+		"last":  SnapshotRetentionLast,
+		"epoch": SnapshotRetentionEpoch,
+	}
+	if v, ok := m[s]; ok {
+		return v, nil
+	} else {
+		var zeroValue SnapshotRetention
+		return zeroValue, fmt.Errorf("invalid snapshot retention '%s'", s)
+	}
+}
+
+func ToRestartPolicyFromString(s string) (RestartPolicy, error) {
+	var m = map[string]RestartPolicy{
+		"on-failure": RestartPolicyOnFailure,
+		"always":     RestartPolicyAlways,
+		"never":      RestartPolicyNever,
+	}
+	if v, ok := m[s]; ok {
+		return v, nil
+	} else {
+		var zeroValue RestartPolicy
+		return zeroValue, fmt.Errorf("invalid restart policy '%s'", s)
+	}
+}
+
 func ToRedactedStringFromString(s string) (RedactedString, error) {
 	return RedactedString{s}, nil
 }
@@ -185,31 +279,37 @@ func ToURLFromString(s string) (URL, error) {
 
 // Aliases to be used by the generated functions.
 var (
-	toBool            = strconv.ParseBool
-	toUint64          = ToUint64FromString
-	toString          = ToStringFromString
-	toDuration        = ToDurationFromSeconds
-	toLogLevel        = ToLogLevelFromString
-	toAuthKind        = ToAuthKindFromString
-	toDefaultBlock    = ToDefaultBlockFromString
-	toRedactedString  = ToRedactedStringFromString
-	toRedactedUint    = ToRedactedUint32FromString
-	toURL             = ToURLFromString
-	toMachineLogLevel = ToMachineLogLevelFromString
-	toAddress         = ToAddressFromString
+	toBool              = strconv.ParseBool
+	toUint64            = ToUint64FromString
+	toString            = ToStringFromString
+	toDuration          = ToDurationFromSeconds
+	toLogLevel          = ToLogLevelFromString
+	toLogFormat         = ToLogFormatFromString
+	toAuthKind          = ToAuthKindFromString
+	toSnapshotRetention = ToSnapshotRetentionFromString
+	toRestartPolicy     = ToRestartPolicyFromString
+	toDefaultBlock      = ToDefaultBlockFromString
+	toRedactedString    = ToRedactedStringFromString
+	toRedactedUint      = ToRedactedUint32FromString
+	toURL               = ToURLFromString
+	toMachineLogLevel   = ToMachineLogLevelFromString
+	toAddress           = ToAddressFromString
 )
 
 var (
-	notDefinedbool            = func() bool { return false }
-	notDefineduint64          = func() uint64 { return 0 }
-	notDefinedstring          = func() string { return "" }
-	notDefinedDuration        = func() time.Duration { return 0 }
-	notDefinedLogLevel        = func() slog.Level { return slog.LevelInfo }
-	notDefinedAuthKind        = func() AuthKind { return AuthKindMnemonicVar }
-	notDefinedDefaultBlock    = func() model.DefaultBlock { return model.DefaultBlock_Finalized }
-	notDefinedRedactedString  = func() RedactedString { return RedactedString{""} }
-	notDefinedRedactedUint    = func() RedactedUint { return RedactedUint{0} }
-	notDefinedURL             = func() URL { return &url.URL{} }
-	notDefinedMachineLogLevel = func() MachineLogLevel { return cartesimachine.MachineLogLevelInfo }
-	notDefinedAddress         = func() Address { return common.Address{} }
+	notDefinedbool              = func() bool { return false }
+	notDefineduint64            = func() uint64 { return 0 }
+	notDefinedstring            = func() string { return "" }
+	notDefinedDuration          = func() time.Duration { return 0 }
+	notDefinedLogLevel          = func() slog.Level { return slog.LevelInfo }
+	notDefinedLogFormat         = func() LogFormat { return service.LogFormatText }
+	notDefinedAuthKind          = func() AuthKind { return AuthKindMnemonicVar }
+	notDefinedSnapshotRetention = func() SnapshotRetention { return SnapshotRetentionLast }
+	notDefinedRestartPolicy     = func() RestartPolicy { return RestartPolicyOnFailure }
+	notDefinedDefaultBlock      = func() model.DefaultBlock { return model.DefaultBlock_Finalized }
+	notDefinedRedactedString    = func() RedactedString { return RedactedString{""} }
+	notDefinedRedactedUint      = func() RedactedUint { return RedactedUint{0} }
+	notDefinedURL               = func() URL { return &url.URL{} }
+	notDefinedMachineLogLevel   = func() MachineLogLevel { return cartesimachine.MachineLogLevelInfo }
+	notDefinedAddress           = func() Address { return common.Address{} }
 )
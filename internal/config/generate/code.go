@@ -117,12 +117,17 @@ package config
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/spf13/viper"
 )
 
 var ErrNotDefined = fmt.Errorf("variable not defined")
 
+// Serializes the Load functions, which may run concurrently when the
+// services of the node reload their configuration.
+var loadMutex sync.Mutex
+
 func init() {
 	// Automatically bind environment variables.
 	viper.AutomaticEnv()
@@ -162,6 +167,9 @@ type {{ capitalize $service }}Config struct {
 // Load{{ capitalize $service }}Config reads configuration from environment variables, a config file, and defaults.
 // Priority: command line flags > environment variables > config file > defaults.
 func Load{{ capitalize $service }}Config() (*{{ capitalize $service }}Config, error) {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+
 	SetDefaults()
 
 	// Load config file if specified via --config flag.
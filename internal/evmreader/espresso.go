@@ -0,0 +1,289 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/pkg/contracts/inputs"
+	"github.com/cartesi/rollups-node/pkg/espresso"
+	"github.com/cartesi/rollups-node/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// espressoSource is where the Espresso inputs of an application are read from.
+type espressoSource struct {
+	// Espresso block height to start reading from
+	fromBlock uint64
+	namespace uint32
+}
+
+// espressoCursor remembers the first Espresso block not read yet, so the next
+// read does not have to search for it.
+type espressoCursor struct {
+	l1Block uint64
+	height  uint64
+}
+
+// decodeEspressoDataAvailability decodes the arguments of an
+// InputBoxAndEspresso(address,uint256,uint32) data availability.
+func decodeEspressoDataAvailability(app *Application, data []byte) (*espressoSource, error) {
+	args, err := unpackDataAvailability("InputBoxAndEspresso", data)
+	if err != nil {
+		return nil, err
+	}
+	inputBoxAddress, ok := args[0].(common.Address)
+	if !ok || inputBoxAddress != app.IInputBoxAddress {
+		return nil, fmt.Errorf("InputBox %v does not match the application InputBox %v",
+			args[0], app.IInputBoxAddress)
+	}
+	fromBlock, ok := args[1].(*big.Int)
+	if !ok || !fromBlock.IsUint64() {
+		return nil, fmt.Errorf("invalid fromBlock %v", args[1])
+	}
+	namespace, ok := args[2].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("invalid namespaceId %v", args[2])
+	}
+	return &espressoSource{fromBlock: fromBlock.Uint64(), namespace: namespace}, nil
+}
+
+// espressoInputSource merges the transactions of an Espresso namespace with
+// the InputBox inputs.
+//
+// Espresso transactions are placed after the InputBox inputs of the finalized
+// L1 block referenced by their Espresso block header. As later Espresso blocks
+// may still reference the latest finalized L1 block, inputs are only read up
+// to the block before it.
+type espressoInputSource struct {
+	InputSourceAdapter
+	reader *Service
+	source *espressoSource
+	// number of Espresso blocks available when the read started
+	blockHeight uint64
+}
+
+// newEspressoInputSource is the InputSourceFactory of the InputBoxAndEspresso
+// data availability.
+func (r *Service) newEspressoInputSource(
+	app *Application,
+	dataAvailability []byte,
+	client *ethclient.Client,
+	filter ethutil.Filter,
+) (InputSourceAdapter, error) {
+	source, err := decodeEspressoDataAvailability(app, dataAvailability)
+	if err != nil {
+		return nil, err
+	}
+	if r.espresso == nil {
+		return nil, fmt.Errorf("no Espresso query service configured")
+	}
+	inputBox, err := NewInputSourceAdapter(app.IInputBoxAddress, client, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &espressoInputSource{InputSourceAdapter: inputBox, reader: r, source: source}, nil
+}
+
+func (s *espressoInputSource) ReadableBlock(ctx context.Context, mostRecentBlockNumber uint64) (uint64, error) {
+	height, err := s.reader.espresso.BlockHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve the Espresso block height: %w", err)
+	}
+	if height == 0 {
+		return 0, nil
+	}
+	latest, err := s.reader.espresso.Header(ctx, height-1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve Espresso header %d: %w", height-1, err)
+	}
+	if latest.L1FinalizedNumber() == 0 {
+		return 0, nil
+	}
+	s.blockHeight = height
+	return min(mostRecentBlockNumber, latest.L1FinalizedNumber()-1), nil
+}
+
+func (s *espressoInputSource) MergeInputs(
+	ctx context.Context,
+	app *Application,
+	startBlock, endBlock uint64,
+	inputBoxInputs []*Input,
+) ([]*Input, error) {
+	r := s.reader
+	espressoInputs, next, err := r.readEspressoInputs(ctx, app, s, startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make([]*Input, 0, len(inputBoxInputs)+len(espressoInputs))
+	for i, j := 0, 0; i < len(inputBoxInputs) || j < len(espressoInputs); {
+		if j == len(espressoInputs) ||
+			(i < len(inputBoxInputs) && inputBoxInputs[i].BlockNumber <= espressoInputs[j].BlockNumber) {
+			merged = append(merged, inputBoxInputs[i])
+			i++
+		} else {
+			merged = append(merged, espressoInputs[j])
+			j++
+		}
+	}
+
+	if r.espressoCursors == nil {
+		r.espressoCursors = make(map[common.Address]espressoCursor)
+	}
+	r.espressoCursors[app.IApplicationAddress] = espressoCursor{l1Block: endBlock, height: next}
+	return merged, nil
+}
+
+// readEspressoInputs reads the Espresso transactions of app sequenced in the
+// blocks referencing the L1 blocks from startBlock to endBlock, returning
+// them as inputs and the height of the first Espresso block not read.
+// Transactions that are not valid signed messages to app are skipped.
+func (r *Service) readEspressoInputs(
+	ctx context.Context,
+	app *Application,
+	s *espressoInputSource,
+	startBlock, endBlock uint64,
+) ([]*Input, uint64, error) {
+	address := app.IApplicationAddress
+	source := s.source
+	height, err := r.findEspressoHeight(ctx, address, source, s.blockHeight, startBlock)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*Input
+	l1Headers := map[uint64]*types.Header{}
+	references := map[common.Hash]bool{}
+	for ; height < s.blockHeight; height++ {
+		header, err := r.espresso.Header(ctx, height)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to retrieve Espresso header %d: %w", height, err)
+		}
+		l1Block := header.L1FinalizedNumber()
+		if l1Block > endBlock {
+			break
+		}
+		txs, err := r.espresso.NamespaceTransactions(ctx, height, source.namespace)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to retrieve Espresso block %d: %w", height, err)
+		}
+
+		for i, tx := range txs {
+			msg, err := espresso.DecodeSignedMessage(tx.Payload)
+			if err != nil {
+				r.Logger.Warn("Skipping Espresso transaction",
+					"height", height, "index", i, "error", err)
+				continue
+			}
+			if msg.Message.App != address {
+				continue // sent to another application in the namespace
+			}
+			sender, err := msg.Sender(r.chainId)
+			if err != nil {
+				r.Logger.Warn("Skipping Espresso transaction",
+					"height", height, "index", i, "error", err)
+				continue
+			}
+			ref := msg.Message.Reference(sender)
+			stored, err := r.repository.GetInputByTxReference(ctx, address.String(), &ref)
+			if err != nil {
+				return nil, 0, err
+			}
+			if stored != nil || references[ref] {
+				r.Logger.Warn("Skipping replayed Espresso transaction",
+					"height", height, "index", i,
+					"sender", sender, "nonce", msg.Message.Nonce)
+				continue
+			}
+			references[ref] = true
+
+			l1Header, ok := l1Headers[l1Block]
+			if !ok {
+				l1Header, err = r.client.HeaderByNumber(ctx, new(big.Int).SetUint64(l1Block))
+				if err != nil {
+					return nil, 0, fmt.Errorf("failed to retrieve header %d: %w", l1Block, err)
+				}
+				l1Headers[l1Block] = l1Header
+			}
+			rawData, err := encodeAdvance(r.chainId, address, sender, l1Header, msg.Message.Data)
+			if err != nil {
+				return nil, 0, err
+			}
+			blockHash := l1Header.Hash()
+			result = append(result, &Input{
+				Status:               InputCompletionStatus_None,
+				RawData:              rawData,
+				BlockNumber:          l1Block,
+				BlockHash:            &blockHash,
+				TransactionReference: ref,
+			})
+			r.Logger.Debug("Received Espresso input",
+				"address", address,
+				"height", height,
+				"sender", sender,
+				"nonce", msg.Message.Nonce)
+		}
+	}
+	return result, height, nil
+}
+
+// findEspressoHeight returns the first Espresso block, from the configured
+// one, referencing a finalized L1 block from startBlock on.
+func (r *Service) findEspressoHeight(
+	ctx context.Context,
+	address common.Address,
+	source *espressoSource,
+	blockHeight uint64,
+	startBlock uint64,
+) (uint64, error) {
+	if cursor, ok := r.espressoCursors[address]; ok &&
+		cursor.l1Block+1 == startBlock && cursor.height >= source.fromBlock {
+		return cursor.height, nil
+	}
+	// the finalized L1 block referenced by the Espresso headers never decreases
+	low, high := source.fromBlock, blockHeight
+	for low < high {
+		mid := low + (high-low)/2
+		header, err := r.espresso.Header(ctx, mid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve Espresso header %d: %w", mid, err)
+		}
+		if header.L1FinalizedNumber() < startBlock {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// encodeAdvance encodes an Espresso input as an EvmAdvance call, as the
+// InputBox does. Its index is set once merged with the InputBox inputs.
+func encodeAdvance(
+	chainId uint64,
+	app common.Address,
+	sender common.Address,
+	l1Header *types.Header,
+	payload []byte,
+) ([]byte, error) {
+	parsedAbi, err := inputs.InputsMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return parsedAbi.Pack("EvmAdvance",
+		new(big.Int).SetUint64(chainId),
+		app,
+		sender,
+		new(big.Int).Set(l1Header.Number),
+		new(big.Int).SetUint64(l1Header.Time),
+		new(big.Int).SetBytes(l1Header.MixDigest[:]),
+		new(big.Int),
+		payload)
+}
@@ -0,0 +1,310 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/pkg/contracts/dataavailability"
+	"github.com/cartesi/rollups-node/pkg/contracts/iinputbox"
+	"github.com/cartesi/rollups-node/pkg/contracts/inputs"
+	"github.com/cartesi/rollups-node/pkg/espresso"
+	"github.com/cartesi/rollups-node/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeQueryService serves Espresso blocks from memory, the block at height i
+// referencing the finalized L1 block l1Finalized[i].
+type fakeQueryService struct {
+	l1Finalized  []uint64
+	transactions map[uint64][]espresso.Transaction
+}
+
+func (f *fakeQueryService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var height uint64
+	var namespace uint32
+	var resp any
+	if r.URL.Path == "/v0/status/block-height" {
+		resp = len(f.l1Finalized)
+	} else if _, err := fmt.Sscanf(r.URL.Path, "/v0/availability/header/%d", &height); err == nil {
+		// headers are wrapped with their version, as newer query services do
+		resp = map[string]any{"fields": map[string]any{
+			"height":       height,
+			"l1_finalized": map[string]any{"number": f.l1Finalized[height]},
+		}}
+	} else if _, err := fmt.Sscanf(r.URL.Path, "/v0/availability/block/%d/namespace/%d",
+		&height, &namespace); err == nil {
+		txs, ok := f.transactions[height]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp = map[string]any{"transactions": txs}
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func signedMessage(key *ecdsa.PrivateKey, app common.Address, nonce uint64, data string) []byte {
+	msg := espresso.SignedMessage{
+		Message: espresso.Message{App: app, Nonce: nonce, Data: []byte(data)},
+	}
+	hash, err := msg.Message.Hash(1)
+	if err != nil {
+		panic(err)
+	}
+	msg.Signature, err = crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		panic(err)
+	}
+	msg.Signature[crypto.RecoveryIDOffset] += 27
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return payload
+}
+
+func inputAddedEvent(app common.Address, index uint64, blockNumber uint64, data string) iinputbox.IInputBoxInputAdded {
+	parsedAbi, err := inputs.InputsMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	rawData, err := parsedAbi.Pack("EvmAdvance", big.NewInt(1), app, common.HexToAddress("0xf00"),
+		new(big.Int).SetUint64(blockNumber), big.NewInt(0), big.NewInt(0),
+		new(big.Int).SetUint64(index), []byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return iinputbox.IInputBoxInputAdded{
+		AppContract: app,
+		Index:       new(big.Int).SetUint64(index),
+		Input:       rawData,
+		Raw:         types.Log{BlockNumber: blockNumber},
+	}
+}
+
+func (s *EvmReaderSuite) TestItMergesEspressoInputs() {
+	appAddress := common.HexToAddress("0x2E663fe9aE92275242406A185AA4fC8174339D3E")
+	key, err := crypto.GenerateKey()
+	s.Require().Nil(err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	valid := signedMessage(key, appAddress, 0, "first")
+	server := httptest.NewServer(&fakeQueryService{
+		l1Finalized: []uint64{0x05, 0x10, 0x11, 0x12, 0x14},
+		transactions: map[uint64][]espresso.Transaction{
+			1: {{Namespace: 7, Payload: signedMessage(key, appAddress, 9, "too early")}},
+			2: {{Namespace: 7, Payload: valid}},
+			3: {
+				{Namespace: 7, Payload: []byte("not a message")},
+				{Namespace: 7, Payload: signedMessage(key, common.HexToAddress("0xbeef"), 0, "other app")},
+				{Namespace: 7, Payload: valid},
+				{Namespace: 7, Payload: signedMessage(key, appAddress, 1, "second")},
+			},
+			4: {{Namespace: 7, Payload: signedMessage(key, appAddress, 2, "too late")}},
+		},
+	})
+	defer server.Close()
+
+	s.evmReader.chainId = 1
+	s.evmReader.espresso, err = espresso.NewClient(server.URL)
+	s.Require().Nil(err)
+
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	s.Require().Nil(err)
+	da, err := parsedAbi.Pack("InputBoxAndEspresso",
+		common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"), big.NewInt(1), uint32(7))
+	s.Require().Nil(err)
+
+	inputBox := newMockInputBox()
+	inputBox.Unset("RetrieveInputs")
+	inputBox.On("RetrieveInputs", mock.Anything, mock.Anything, mock.Anything).Return(
+		[]iinputbox.IInputBoxInputAdded{
+			inputAddedEvent(appAddress, 0, 0x11, "inputbox 0"),
+			inputAddedEvent(appAddress, 1, 0x12, "inputbox 1"),
+		}, nil)
+
+	s.repository.Unset("ListInputs")
+	s.repository.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		false,
+	).Return([]*Input{}, uint64(5), nil)
+
+	var stored []*Input
+	s.repository.Unset("CreateEpochsAndInputs")
+	s.repository.On("CreateEpochsAndInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		uint64(0x13),
+	).Once().Run(func(arguments mock.Arguments) {
+		epochInputMap, ok := arguments.Get(2).(map[*Epoch][]*Input)
+		s.Require().True(ok)
+		s.Require().Equal(1, len(epochInputMap))
+		for epoch, inputs := range epochInputMap {
+			s.Equal(uint64(1), epoch.Index)
+			stored = inputs
+		}
+	}).Return(nil)
+
+	app := &Application{
+		ID:                  1,
+		IApplicationAddress: appAddress,
+		IInputBoxAddress:    common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"),
+		DataAvailability:    da,
+		IInputBoxBlock:      0x10,
+		EpochLength:         10,
+		LastInputCheckBlock: 0x10,
+	}
+	source, err := decodeEspressoDataAvailability(app, da)
+	s.Require().Nil(err)
+	apps := []appContracts{{
+		application: app,
+		inputSource: &espressoInputSource{
+			InputSourceAdapter: inputBox,
+			reader:             s.evmReader,
+			source:             source,
+		},
+	}}
+	s.evmReader.checkForNewInputs(s.ctx, apps, 0x20)
+	s.repository.AssertNumberOfCalls(s.T(), "CreateEpochsAndInputs", 1)
+
+	// Espresso inputs are read up to the block before the latest finalized L1
+	// block Espresso has seen, after the InputBox inputs of the same block
+	ioAbi, err := inputs.InputsMetaData.GetAbi()
+	s.Require().Nil(err)
+	expected := []struct {
+		blockNumber uint64
+		sender      common.Address
+		payload     string
+	}{
+		{0x11, common.HexToAddress("0xf00"), "inputbox 0"},
+		{0x11, sender, "first"},
+		{0x12, common.HexToAddress("0xf00"), "inputbox 1"},
+		{0x12, sender, "second"},
+	}
+	s.Require().Equal(len(expected), len(stored))
+	for i, input := range stored {
+		s.Equal(uint64(5+i), input.Index)
+		s.Equal(expected[i].blockNumber, input.BlockNumber)
+		args, err := ioAbi.Methods["EvmAdvance"].Inputs.Unpack(input.RawData[4:])
+		s.Require().Nil(err)
+		s.Equal(appAddress, args[1])
+		s.Equal(expected[i].sender, args[2])
+		s.Equal(new(big.Int).SetUint64(input.Index), args[6])
+		s.Equal([]byte(expected[i].payload), args[7])
+	}
+	s.Equal(common.BigToHash(big.NewInt(1)), stored[2].TransactionReference)
+	s.Equal(espresso.Message{Nonce: 1}.Reference(sender), stored[3].TransactionReference)
+}
+
+func (s *EvmReaderSuite) TestItMarksInoperableOnInvalidMergedInput() {
+	appAddress := common.HexToAddress("0x2E663fe9aE92275242406A185AA4fC8174339D3E")
+	server := httptest.NewServer(&fakeQueryService{
+		l1Finalized: []uint64{0x05, 0x10, 0x11, 0x12, 0x14},
+	})
+	defer server.Close()
+
+	var err error
+	s.evmReader.chainId = 1
+	s.evmReader.espresso, err = espresso.NewClient(server.URL)
+	s.Require().Nil(err)
+
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	s.Require().Nil(err)
+	da, err := parsedAbi.Pack("InputBoxAndEspresso",
+		common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"), big.NewInt(1), uint32(7))
+	s.Require().Nil(err)
+
+	invalid := inputAddedEvent(appAddress, 0, 0x11, "inputbox 0")
+	invalid.Input = []byte("not an EvmAdvance call")
+	inputBox := newMockInputBox()
+	inputBox.Unset("RetrieveInputs")
+	inputBox.On("RetrieveInputs", mock.Anything, mock.Anything, mock.Anything).Return(
+		[]iinputbox.IInputBoxInputAdded{invalid}, nil)
+
+	s.repository.Unset("ListInputs")
+	s.repository.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		false,
+	).Return([]*Input{}, uint64(0), nil)
+	s.repository.On("SetApplicationInoperable",
+		mock.Anything,
+		int64(1),
+		ReasonCode_InvalidInput,
+		mock.Anything,
+	).Once().Return(nil)
+
+	app := &Application{
+		ID:                  1,
+		IApplicationAddress: appAddress,
+		IInputBoxAddress:    common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"),
+		DataAvailability:    da,
+		IInputBoxBlock:      0x10,
+		EpochLength:         10,
+		LastInputCheckBlock: 0x10,
+	}
+	source, err := decodeEspressoDataAvailability(app, da)
+	s.Require().Nil(err)
+	apps := []appContracts{{
+		application: app,
+		inputSource: &espressoInputSource{
+			InputSourceAdapter: inputBox,
+			reader:             s.evmReader,
+			source:             source,
+		},
+	}}
+	s.evmReader.checkForNewInputs(s.ctx, apps, 0x20)
+	s.repository.AssertNumberOfCalls(s.T(), "CreateEpochsAndInputs", 0)
+	s.repository.AssertNumberOfCalls(s.T(), "SetApplicationInoperable", 1)
+}
+
+func (s *EvmReaderSuite) TestItRequiresEspressoQueryService() {
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	s.Require().Nil(err)
+	da, err := parsedAbi.Pack("InputBoxAndEspresso", common.Address{}, big.NewInt(0), uint32(7))
+	s.Require().Nil(err)
+
+	app := &Application{DataAvailability: da}
+	_, err = s.evmReader.inputSources.Create(app, nil, ethutil.Filter{})
+	s.ErrorContains(err, "no Espresso query service configured")
+}
+
+func (s *EvmReaderSuite) TestDecodeEspressoDataAvailability() {
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	s.Require().Nil(err)
+	s.Equal(parsedAbi.Methods["InputBoxAndEspresso"].ID, DataAvailability_InputBoxAndEspresso[:])
+
+	da, err := parsedAbi.Pack("InputBoxAndEspresso", common.HexToAddress("0x01"), big.NewInt(42), uint32(7))
+	s.Require().Nil(err)
+	app := &Application{IInputBoxAddress: common.HexToAddress("0x01")}
+	source, err := decodeEspressoDataAvailability(app, da)
+	s.Require().Nil(err)
+	s.Equal(uint64(42), source.fromBlock)
+	s.Equal(uint32(7), source.namespace)
+
+	_, err = decodeEspressoDataAvailability(&Application{}, da)
+	s.ErrorContains(err, "does not match the application InputBox")
+
+	_, err = decodeEspressoDataAvailability(app, DataAvailability_InputBox[:])
+	s.NotNil(err)
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -22,12 +23,15 @@ import (
 	"github.com/cartesi/rollups-node/pkg/contracts/iapplication"
 	"github.com/cartesi/rollups-node/pkg/contracts/iinputbox"
 	"github.com/cartesi/rollups-node/pkg/ethutil"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/internal/evmreader")
+
 // Interface for the node repository
 type EvmReaderRepository interface {
 	ListApplications(ctx context.Context, f repository.ApplicationFilter, p repository.Pagination, descending bool) ([]*Application, uint64, error)
-	UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error
+	SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error
 	UpdateEventLastCheckBlock(ctx context.Context, appIDs []int64, event MonitoredEvent, blockNumber uint64) error
 
 	SaveNodeConfigRaw(ctx context.Context, key string, rawJSON []byte) error
@@ -40,6 +44,11 @@ type EvmReaderRepository interface {
 	) error
 	GetEpoch(ctx context.Context, nameOrAddress string, index uint64) (*Epoch, error)
 	ListEpochs(ctx context.Context, nameOrAddress string, f repository.EpochFilter, p repository.Pagination, descending bool) ([]*Epoch, uint64, error)
+	ListInputs(ctx context.Context, nameOrAddress string, f repository.InputFilter, p repository.Pagination, descending bool) ([]*Input, uint64, error)
+	GetInputByTxReference(ctx context.Context, nameOrAddress string, ref *common.Hash) (*Input, error)
+
+	// Reorganization handling
+	RevertBlocks(ctx context.Context, appID int64, blockNumber uint64) (uint64, error)
 
 	// Output execution monitor
 	GetOutput(ctx context.Context, nameOrAddress string, indexKey uint64) (*Output, error)
@@ -67,6 +76,27 @@ type InputSourceAdapter interface {
 	) ([]iinputbox.IInputBoxInputAdded, error)
 }
 
+// Interface for input sources that add inputs from outside the InputBox to
+// the InputBox ones, like the Espresso sequencer
+type InputMergerAdapter interface {
+	InputSourceAdapter
+
+	// ReadableBlock returns the latest block, up to mostRecentBlockNumber,
+	// whose inputs can all be read.
+	ReadableBlock(ctx context.Context, mostRecentBlockNumber uint64) (uint64, error)
+
+	// MergeInputs reads the inputs of app referencing the blocks from
+	// startBlock to endBlock and merges them with the InputBox inputs read
+	// from the same blocks, returning all of them in order. They are indexed
+	// by the reader.
+	MergeInputs(
+		ctx context.Context,
+		app *Application,
+		startBlock, endBlock uint64,
+		inputBoxInputs []*Input,
+	) ([]*Input, error)
+}
+
 type SubscriptionError struct {
 	Cause error
 }
@@ -90,6 +120,7 @@ func (r *Service) Run(ctx context.Context, ready chan struct{}) error {
 		if _, ok := err.(*SubscriptionError); !ok {
 			return err
 		}
+		rpcErrorsMetric.WithLabelValues("subscribe").Inc()
 		r.Logger.Error(err.Error())
 		r.Logger.Info("Restarting subscription")
 	}
@@ -111,6 +142,8 @@ func (r *Service) watchForNewBlocks(ctx context.Context, ready chan<- struct{})
 		return fmt.Errorf("could not start subscription: %v", err)
 	}
 	r.Logger.Info("Subscribed to new block events")
+	r.subscribed.Store(true)
+	defer r.subscribed.Store(false)
 	ready <- struct{}{}
 	defer sub.Unsubscribe()
 
@@ -118,12 +151,16 @@ func (r *Service) watchForNewBlocks(ctx context.Context, ready chan<- struct{})
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-r.stop:
+			return nil
 		case err := <-sub.Err():
 			return &SubscriptionError{Cause: err}
 		case header := <-headers:
 
 			// Every time a new block arrives
 			r.Logger.Debug("New block header received", "blockNumber", header.Number, "blockHash", header.Hash())
+			r.headBlock = header.Number.Uint64()
+			headBlockMetric.Set(float64(r.headBlock))
 
 			r.Logger.Debug("Retrieving enabled applications")
 			runningApps, _, err := getAllRunningApplications(ctx, r.repository)
@@ -150,10 +187,21 @@ func (r *Service) watchForNewBlocks(ctx context.Context, ready chan<- struct{})
 			// Build Contracts
 			var apps []appContracts
 			for _, app := range runningApps {
+				if err := r.inputSources.Check(app); err != nil {
+					r.Logger.Error("Application data availability is not supported",
+						"application", app.Name,
+						"error", err)
+					err := r.repository.SetApplicationInoperable(ctx, app.ID,
+						ReasonCode_UnsupportedDataAvailability, err.Error())
+					if err != nil {
+						r.Logger.Error("failed to update application state to inoperable", "application", app.Name, "error", err)
+					}
+					continue
+				}
 				applicationContract, inputSource, err := r.adapterFactory.CreateAdapters(app, r.client)
 
 				if err != nil {
-					r.Logger.Error("Error retrieving application contracts", "app", app, "error", err)
+					r.Logger.Error("Error retrieving application contracts", "application", app.Name, "error", err)
 					continue
 				}
 				aContracts := appContracts{
@@ -170,9 +218,9 @@ func (r *Service) watchForNewBlocks(ctx context.Context, ready chan<- struct{})
 				continue
 			}
 
-			blockNumber := header.Number.Uint64()
+			mostRecentHeader := header
 			if r.defaultBlock != DefaultBlock_Latest {
-				mostRecentHeader, err := r.fetchMostRecentHeader(
+				mostRecentHeader, err = r.fetchMostRecentHeader(
 					ctx,
 					r.defaultBlock,
 				)
@@ -182,11 +230,23 @@ func (r *Service) watchForNewBlocks(ctx context.Context, ready chan<- struct{})
 						"error", err)
 					continue
 				}
-				blockNumber = mostRecentHeader.Number.Uint64()
 
 				r.Logger.Debug(fmt.Sprintf("Using block %d and not %d because of commitment policy: %s",
 					mostRecentHeader.Number.Uint64(), header.Number.Uint64(), r.defaultBlock))
 			}
+			blockNumber := mostRecentHeader.Number.Uint64()
+
+			reverted, err := r.checkForReorg(ctx, apps, mostRecentHeader)
+			if err != nil {
+				r.Logger.Error("Error checking for chain reorganizations",
+					"block_number", blockNumber,
+					"error", err)
+				continue
+			}
+			if reverted {
+				// read the reverted applications again on the next block
+				continue
+			}
 
 			r.checkForNewInputs(ctx, apps, blockNumber)
 
@@ -222,6 +282,7 @@ func (r *Service) fetchMostRecentHeader(
 			ctx,
 			new(big.Int).SetInt64(defaultBlockNumber))
 	if err != nil {
+		rpcErrorsMetric.WithLabelValues("header").Inc()
 		return nil, fmt.Errorf("failed to retrieve header. %v", err)
 	}
 
@@ -236,7 +297,19 @@ type AdapterFactory interface {
 }
 
 type DefaultAdapterFactory struct {
-	Filter ethutil.Filter
+	Filter       ethutil.Filter
+	InputSources *InputSourceRegistry
+
+	// guards Filter, which may change while adapters are created
+	filterMutex sync.Mutex
+}
+
+// SetMaxBlockRange changes the maximum block range of the queries of the
+// adapters created afterwards. Zero means unlimited.
+func (f *DefaultAdapterFactory) SetMaxBlockRange(maxBlockRange uint64) {
+	f.filterMutex.Lock()
+	defer f.filterMutex.Unlock()
+	f.Filter.MaxChunkSize = new(big.Int).SetUint64(maxBlockRange)
 }
 
 func (f *DefaultAdapterFactory) CreateAdapters(app *Application, client EthClientInterface) (ApplicationContractAdapter, InputSourceAdapter, error) {
@@ -250,7 +323,11 @@ func (f *DefaultAdapterFactory) CreateAdapters(app *Application, client EthClien
 		return nil, nil, fmt.Errorf("client is not an *ethclient.Client, cannot create adapters")
 	}
 
-	applicationContract, err := NewApplicationContractAdapter(app.IApplicationAddress, ethClient, f.Filter)
+	f.filterMutex.Lock()
+	filter := f.Filter
+	f.filterMutex.Unlock()
+
+	applicationContract, err := NewApplicationContractAdapter(app.IApplicationAddress, ethClient, filter)
 	if err != nil {
 		return nil, nil, errors.Join(
 			fmt.Errorf("error building application contract"),
@@ -258,10 +335,10 @@ func (f *DefaultAdapterFactory) CreateAdapters(app *Application, client EthClien
 		)
 	}
 
-	inputSource, err := NewInputSourceAdapter(app.IInputBoxAddress, ethClient, f.Filter)
+	inputSource, err := f.InputSources.Create(app, ethClient, filter)
 	if err != nil {
 		return nil, nil, errors.Join(
-			fmt.Errorf("error building inputbox contract"),
+			fmt.Errorf("error building input source"),
 			err,
 		)
 	}
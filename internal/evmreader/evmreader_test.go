@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"slices"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -122,6 +124,7 @@ func (me *EvmReaderSuite) SetupTest() {
 		hasEnabledApps:     true,
 		inputReaderEnabled: true,
 	}
+	me.evmReader.inputSources = me.evmReader.newInputSourceRegistry()
 	serviceArgs := &service.CreateInfo{Name: "evm-reader", Impl: me.evmReader}
 	err := service.Create(context.Background(), serviceArgs, &me.evmReader.Service)
 	me.Require().Nil(err)
@@ -155,6 +158,31 @@ func (s *EvmReaderSuite) TestItEventuallyBecomesReady() {
 	}
 }
 
+func (s *EvmReaderSuite) TestItReportsSubscriptionHealth() {
+	s.evmReader.AddHealthCheck("subscription", s.evmReader.checkSubscription)
+	s.Require().False(s.evmReader.Ready())
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	ready := make(chan struct{}, 1)
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- s.evmReader.Run(ctx, ready)
+	}()
+
+	select {
+	case <-ready:
+	case err := <-errChannel:
+		s.FailNow("unexpected failure", err)
+	}
+	s.Require().True(s.evmReader.Ready())
+
+	cancel()
+	s.Require().Equal(context.Canceled, <-errChannel)
+	health := s.evmReader.Health(s.ctx)
+	s.Require().False(health.Ready)
+	s.Require().Equal("not subscribed to new blocks", health.Checks[0].Error)
+}
+
 func (s *EvmReaderSuite) TestItFailsToSubscribeForNewInputsOnStart() {
 	s.client.Unset("SubscribeNewHead")
 	emptySubscription := &MockSubscription{}
@@ -170,6 +198,27 @@ func (s *EvmReaderSuite) TestItFailsToSubscribeForNewInputsOnStart() {
 	s.client.AssertNumberOfCalls(s.T(), "SubscribeNewHead", 1)
 }
 
+func (s *EvmReaderSuite) TestObserveReadBlock() {
+	app := &Application{Name: "observe-read-block"}
+	lastRead := lastReadBlockMetric.WithLabelValues(app.Name)
+	lag := lagMetric.WithLabelValues(app.Name)
+
+	s.evmReader.headBlock = 0x20
+	s.evmReader.observeReadBlock(app, 0x11)
+	s.Equal(float64(0x11), testutil.ToFloat64(lastRead))
+	s.Equal(float64(0x20-0x11), testutil.ToFloat64(lag))
+
+	s.evmReader.observeReadBlock(app, 0x20)
+	s.Equal(float64(0x20), testutil.ToFloat64(lastRead))
+	s.Equal(0.0, testutil.ToFloat64(lag))
+
+	// a head that was not received yet leaves the lag as it was
+	s.evmReader.headBlock = 0x10
+	s.evmReader.observeReadBlock(app, 0x21)
+	s.Equal(float64(0x21), testutil.ToFloat64(lastRead))
+	s.Equal(0.0, testutil.ToFloat64(lag))
+}
+
 func (s *EvmReaderSuite) TestIndexApps() {
 
 	s.Run("Ok", func() {
@@ -433,9 +482,30 @@ func newMockRepository() *MockRepository {
 		mock.Anything,
 		mock.Anything,
 		mock.Anything,
-		false,
+		mock.Anything,
 	).Return([]*Epoch{}, uint64(0), nil)
 
+	repo.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return([]*Input{}, uint64(0), nil)
+
+	repo.On("SetApplicationInoperable",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+
+	repo.On("GetInputByTxReference",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(nil, nil)
+
 	repo.On("UpdateOutputsExecution",
 		mock.Anything,
 		mock.Anything,
@@ -462,8 +532,15 @@ func newMockRepository() *MockRepository {
 }
 
 func (m *MockRepository) Unset(methodName string) {
+	// call.Unset removes the calls from m.ExpectedCalls, so they are collected first
+	var calls []*mock.Call
 	for _, call := range m.ExpectedCalls {
 		if call.Method == methodName {
+			calls = append(calls, call)
+		}
+	}
+	for _, call := range calls {
+		if slices.Contains(m.ExpectedCalls, call) {
 			call.Unset()
 		}
 	}
@@ -522,6 +599,27 @@ func (m *MockRepository) ListEpochs(ctx context.Context, nameOrAddress string,
 	return args.Get(0).([]*Epoch), args.Get(1).(uint64), args.Error(2)
 }
 
+func (m *MockRepository) ListInputs(ctx context.Context, nameOrAddress string,
+	f repository.InputFilter, p repository.Pagination, descending bool) ([]*Input, uint64, error) {
+	args := m.Called(ctx, nameOrAddress, f, p, descending)
+	return args.Get(0).([]*Input), args.Get(1).(uint64), args.Error(2)
+}
+
+func (m *MockRepository) GetInputByTxReference(ctx context.Context, nameOrAddress string,
+	ref *common.Hash) (*Input, error) {
+	args := m.Called(ctx, nameOrAddress, ref)
+	obj := args.Get(0)
+	if obj == nil {
+		return nil, args.Error(1)
+	}
+	return obj.(*Input), args.Error(1)
+}
+
+func (m *MockRepository) RevertBlocks(ctx context.Context, appID int64, blockNumber uint64) (uint64, error) {
+	args := m.Called(ctx, appID, blockNumber)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 func (m *MockRepository) GetOutput(ctx context.Context, nameOrAddress string, indexKey uint64) (*Output, error) {
 	args := m.Called(ctx, nameOrAddress, indexKey)
 	obj := args.Get(0)
@@ -537,8 +635,8 @@ func (m *MockRepository) UpdateOutputsExecution(ctx context.Context, nameOrAddre
 	return args.Error(0)
 }
 
-func (m *MockRepository) UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error {
-	args := m.Called(ctx, appID, state, reason)
+func (m *MockRepository) SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error {
+	args := m.Called(ctx, appID, code, reason)
 	return args.Error(0)
 }
 
@@ -4,15 +4,27 @@
 package evmreader
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
 
 	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/tracing"
+	"github.com/cartesi/rollups-node/pkg/contracts/inputs"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// ErrInvalidInput is returned for inputs that can not be decoded, so reading
+// them again would fail the same way
+var ErrInvalidInput = errors.New("invalid input")
+
 // checkForNewInputs checks if is there new Inputs for all running Applications
 func (r *Service) checkForNewInputs(
 	ctx context.Context,
@@ -27,7 +39,8 @@ func (r *Service) checkForNewInputs(
 
 	appsByInputBox := map[common.Address][]appContracts{}
 	for _, app := range applications {
-		if !app.application.HasDataAvailabilitySelector(DataAvailability_InputBox) {
+		if _, ok := app.inputSource.(InputMergerAdapter); ok {
+			r.checkForNewMergedInputs(ctx, app, mostRecentBlockNumber)
 			continue
 		}
 		key := app.application.IInputBoxAddress
@@ -91,6 +104,56 @@ func (r *Service) checkForNewInputs(
 	}
 }
 
+// checkForNewMergedInputs checks if there are new inputs for an application
+// whose input source merges other inputs with the InputBox ones. It is only
+// read up to the block the source can provide all inputs for. Failures are
+// retried on the next block, except for invalid inputs, which make the
+// application inoperable.
+func (r *Service) checkForNewMergedInputs(
+	ctx context.Context,
+	app appContracts,
+	mostRecentBlockNumber uint64,
+) {
+	merger := app.inputSource.(InputMergerAdapter)
+	readableBlock, err := merger.ReadableBlock(ctx, mostRecentBlockNumber)
+	if err != nil {
+		r.Logger.Error("Error checking the readable blocks",
+			"application", app.application.Name,
+			"error", err)
+		return
+	}
+
+	lastProcessedBlock := app.application.LastInputCheckBlock
+	if lastProcessedBlock < app.application.IInputBoxBlock {
+		lastProcessedBlock = app.application.IInputBoxBlock - 1
+	}
+	if readableBlock <= lastProcessedBlock {
+		r.Logger.Debug("Not reading inputs: waiting for the input source to reach the next blocks",
+			"application", app.application.Name,
+			"last_processed_block", lastProcessedBlock,
+			"readable_block", readableBlock,
+			"most_recent_block", mostRecentBlockNumber)
+		return
+	}
+
+	err = r.readAndStoreInputs(ctx, lastProcessedBlock, readableBlock, []appContracts{app})
+	if err != nil {
+		r.Logger.Error("Error reading inputs",
+			"application", app.application.Name,
+			"last_processed_block", lastProcessedBlock,
+			"most_recent_block", readableBlock,
+			"error", err)
+	}
+	if errors.Is(err, ErrInvalidInput) {
+		err := r.repository.SetApplicationInoperable(ctx, app.application.ID,
+			ReasonCode_InvalidInput, err.Error())
+		if err != nil {
+			r.Logger.Error("failed to update application state to inoperable",
+				"application", app.application.Name, "error", err)
+		}
+	}
+}
+
 // readAndStoreInputs reads, inputs from the InputSource given specific filter options, indexes
 // them into epochs and store the indexed inputs and epochs
 func (r *Service) readAndStoreInputs(
@@ -98,13 +161,23 @@ func (r *Service) readAndStoreInputs(
 	lastProcessedBlock uint64,
 	mostRecentBlockNumber uint64,
 	apps []appContracts,
-) error {
+) (err error) {
 
 	if len(apps) == 0 {
 		r.Logger.Warn("No valid running applications")
 		return nil
 	}
 
+	addresses := make([]string, 0, len(apps))
+	for _, app := range apps {
+		addresses = append(addresses, app.application.IApplicationAddress.String())
+	}
+	_, span := tracing.Start(ctx, tracer, "ReadInputs",
+		tracing.ApplicationAddressKey.StringSlice(addresses),
+		attribute.Int64("cartesi.block.start", int64(lastProcessedBlock+1)), // nolint: gosec
+		attribute.Int64("cartesi.block.end", int64(mostRecentBlockNumber)))  // nolint: gosec
+	defer func() { tracing.End(span, err) }()
+
 	// Retrieve Inputs from blockchain
 	nextSearchBlock := lastProcessedBlock + 1
 	appInputsMap, err := r.readInputsFromBlockchain(ctx, apps, nextSearchBlock, mostRecentBlockNumber)
@@ -115,6 +188,23 @@ func (r *Service) readAndStoreInputs(
 			err)
 	}
 
+	// Merge the inputs of the applications that have other input sources
+	for _, app := range apps {
+		merger, ok := app.inputSource.(InputMergerAdapter)
+		if !ok {
+			continue
+		}
+		address := app.application.IApplicationAddress
+		appInputsMap[address], err = r.mergeInputs(ctx, app.application, merger,
+			nextSearchBlock, mostRecentBlockNumber, appInputsMap[address])
+		if err != nil {
+			return fmt.Errorf("failed to merge inputs from block %v to block %v. %w",
+				nextSearchBlock,
+				mostRecentBlockNumber,
+				err)
+		}
+	}
+
 	addrToApp := mapAddressToApp(apps)
 
 	// Index Inputs into epochs and handle epoch finalization
@@ -165,15 +255,18 @@ func (r *Service) readAndStoreInputs(
 							"epoch_index", currentEpoch.Index,
 							"status", currentEpoch.Status,
 						)
-						err := r.repository.UpdateApplicationState(ctx, app.application.ID, ApplicationState_Inoperable, &reason)
+						err := r.repository.SetApplicationInoperable(ctx, app.application.ID,
+							ReasonCode_InconsistentState, reason)
 						if err != nil {
-							r.Logger.Error("failed to update application state to inoperable", "application", app.application.Name, "err", err)
+							r.Logger.Error("failed to update application state to inoperable", "application", app.application.Name, "error", err)
 						}
 						return errors.New(reason)
 					}
 				} else {
 					if currentEpoch.Status == EpochStatus_Open {
-						currentEpoch.Status = EpochStatus_Closed
+						if err := r.closeEpoch(ctx, currentEpoch); err != nil {
+							return err
+						}
 						r.Logger.Info("Closing epoch",
 							"application", app.application.Name,
 							"address", address,
@@ -201,7 +294,7 @@ func (r *Service) readAndStoreInputs(
 			r.Logger.Info("Found new Input",
 				"application", app.application.Name,
 				"address", address,
-				"index", input.Index,
+				"input_index", input.Index,
 				"block", input.BlockNumber,
 				"epoch_index", inputEpochIndex)
 
@@ -216,7 +309,9 @@ func (r *Service) readAndStoreInputs(
 		// Indexed all inputs. Check if it is time to close the last epoch
 		if currentEpoch != nil && currentEpoch.Status == EpochStatus_Open &&
 			mostRecentBlockNumber >= currentEpoch.LastBlock {
-			currentEpoch.Status = EpochStatus_Closed
+			if err := r.closeEpoch(ctx, currentEpoch); err != nil {
+				return err
+			}
 			r.Logger.Info("Closing epoch",
 				"application", app.application.Name,
 				"address", address,
@@ -230,12 +325,7 @@ func (r *Service) readAndStoreInputs(
 			}
 		}
 
-		err = r.repository.CreateEpochsAndInputs(
-			ctx,
-			address.String(),
-			epochInputMap,
-			mostRecentBlockNumber,
-		)
+		err = r.createEpochsAndInputs(ctx, address, epochInputMap, inputs, mostRecentBlockNumber)
 		if err != nil {
 			r.Logger.Error("Error storing inputs and epochs",
 				"application", app.application.Name,
@@ -244,6 +334,8 @@ func (r *Service) readAndStoreInputs(
 			)
 			continue
 		}
+		inputsReadMetric.WithLabelValues(app.application.Name).Add(float64(len(inputs)))
+		r.observeReadBlock(app.application, mostRecentBlockNumber)
 
 		// Store everything
 		if len(epochInputMap) > 0 {
@@ -264,12 +356,14 @@ func (r *Service) readAndStoreInputs(
 	// Update LastInputCheckBlock for applications that didn't have any inputs
 	// (for apps with inputs, LastInputCheckBlock is already updated in CreateEpochsAndInputs)
 	appsToUpdate := []int64{}
+	appsWithoutInputs := []*Application{}
 	// Find applications that didn't have any inputs in appInputsMap
 	for _, app := range apps {
 		appAddress := app.application.IApplicationAddress
 		// If the app doesn't have any inputs in the map or has an empty slice
 		if inputs, exists := appInputsMap[appAddress]; !exists || len(inputs) == 0 {
 			appsToUpdate = append(appsToUpdate, app.application.ID)
+			appsWithoutInputs = append(appsWithoutInputs, app.application)
 		}
 	}
 	// Update LastInputCheckBlock for applications without inputs
@@ -288,12 +382,50 @@ func (r *Service) readAndStoreInputs(
 				"app_ids", appsToUpdate,
 				"block_number", mostRecentBlockNumber,
 			)
+			for _, app := range appsWithoutInputs {
+				r.observeReadBlock(app, mostRecentBlockNumber)
+			}
 		}
 	}
 
 	return nil
 }
 
+// createEpochsAndInputs stores the epochs and inputs of the application at address.
+func (r *Service) createEpochsAndInputs(
+	ctx context.Context,
+	address common.Address,
+	epochInputMap map[*Epoch][]*Input,
+	inputs []*Input,
+	blockNumber uint64,
+) (err error) {
+	attributes := []attribute.KeyValue{
+		tracing.ApplicationAddress(address),
+		attribute.Int("cartesi.epochs.count", len(epochInputMap)),
+		attribute.Int("cartesi.inputs.count", len(inputs)),
+	}
+	if len(inputs) > 0 {
+		attributes = append(attributes,
+			tracing.InputIndex(inputs[0].Index),
+			attribute.Int64("cartesi.input.last_index", int64(inputs[len(inputs)-1].Index))) // nolint: gosec
+	}
+	ctx, span := tracing.Start(ctx, tracer, "CreateEpochsAndInputs", attributes...)
+	defer func() { tracing.End(span, err) }()
+	return r.repository.CreateEpochsAndInputs(ctx, address.String(), epochInputMap, blockNumber)
+}
+
+// closeEpoch marks the epoch as closed, recording the hash of its last block
+// so a later chain reorganization of that block can be detected.
+func (r *Service) closeEpoch(ctx context.Context, epoch *Epoch) error {
+	hash, err := r.blockHash(ctx, epoch.LastBlock)
+	if err != nil {
+		return fmt.Errorf("failed to close epoch %d: %w", epoch.Index, err)
+	}
+	epoch.Status = EpochStatus_Closed
+	epoch.BlockHash = &hash
+	return nil
+}
+
 // readInputsFromBlockchain read the inputs from the blockchain ordered by Input index
 func (r *Service) readInputsFromBlockchain(
 	ctx context.Context,
@@ -317,6 +449,7 @@ func (r *Service) readInputsFromBlockchain(
 	}
 	inputsEvents, err := inputSource.RetrieveInputs(&opts, appsAddresses, nil)
 	if err != nil {
+		rpcErrorsMetric.WithLabelValues("inputs").Inc()
 		return nil, err
 	}
 
@@ -324,13 +457,15 @@ func (r *Service) readInputsFromBlockchain(
 	for _, event := range inputsEvents {
 		r.Logger.Debug("Received input",
 			"address", event.AppContract,
-			"index", event.Index,
+			"input_index", event.Index,
 			"block", event.Raw.BlockNumber)
+		blockHash := event.Raw.BlockHash
 		input := &Input{
 			Index:                event.Index.Uint64(),
 			Status:               InputCompletionStatus_None,
 			RawData:              event.Input,
 			BlockNumber:          event.Raw.BlockNumber,
+			BlockHash:            &blockHash,
 			TransactionReference: common.BigToHash(event.Index),
 		}
 
@@ -345,3 +480,50 @@ func (r *Service) readInputsFromBlockchain(
 func byLastInputCheckBlock(app appContracts) uint64 {
 	return app.application.LastInputCheckBlock
 }
+
+// mergeInputs merges the inputs of the other input sources of app with its
+// InputBox inputs, indexing all of them after the stored inputs.
+func (r *Service) mergeInputs(
+	ctx context.Context,
+	app *Application,
+	merger InputMergerAdapter,
+	startBlock, endBlock uint64,
+	inputBoxInputs []*Input,
+) ([]*Input, error) {
+	_, nextIndex, err := r.repository.ListInputs(ctx, app.IApplicationAddress.String(),
+		repository.InputFilter{}, repository.Pagination{Limit: 1}, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the inputs: %w", err)
+	}
+	merged, err := merger.MergeInputs(ctx, app, startBlock, endBlock, inputBoxInputs)
+	if err != nil {
+		return nil, err
+	}
+	parsedAbi, err := inputs.InputsMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	for k, input := range merged {
+		input.Index = nextIndex + uint64(k)
+		input.RawData, err = setAdvanceIndex(parsedAbi, input.RawData, input.Index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index input from block %d: %w", input.BlockNumber, err)
+		}
+	}
+	return merged, nil
+}
+
+// setAdvanceIndex replaces the input index encoded in an EvmAdvance call.
+func setAdvanceIndex(parsedAbi *abi.ABI, rawData []byte, index uint64) ([]byte, error) {
+	method := parsedAbi.Methods["EvmAdvance"]
+	if len(rawData) < len(method.ID) || !bytes.Equal(rawData[:len(method.ID)], method.ID) {
+		return nil, fmt.Errorf("%w: not an EvmAdvance call: 0x%s", ErrInvalidInput,
+			hex.EncodeToString(rawData[:min(len(rawData), len(method.ID))]))
+	}
+	args, err := method.Inputs.Unpack(rawData[len(method.ID):])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+	args[6] = new(big.Int).SetUint64(index)
+	return parsedAbi.Pack("EvmAdvance", args...)
+}
@@ -491,3 +491,38 @@ func (s *EvmReaderSuite) TestItStartsWhenLasProcessedBlockIsTheMostRecentBlock()
 		0,
 	)
 }
+
+func (s *EvmReaderSuite) TestItMarksInoperableOnInputsForClosedEpoch() {
+	s.repository.Unset("GetEpoch")
+	s.repository.On("GetEpoch",
+		mock.Anything,
+		mock.Anything,
+		uint64(1),
+	).Return(&Epoch{
+		Index:      1,
+		FirstBlock: 10,
+		LastBlock:  19,
+		Status:     EpochStatus_Closed,
+	}, nil)
+	s.repository.Unset("SetApplicationInoperable")
+	s.repository.On("SetApplicationInoperable",
+		mock.Anything,
+		int64(1),
+		ReasonCode_InconsistentState,
+		mock.Anything,
+	).Once().Return(nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	inputBox.Unset("RetrieveInputs")
+	inputBox.On("RetrieveInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return([]iinputbox.IInputBoxInputAdded{inputAddedEvent2}, nil)
+
+	wsClient.fireNewHead(&header2)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "SetApplicationInoperable", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "CreateEpochsAndInputs", 0)
+}
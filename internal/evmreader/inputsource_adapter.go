@@ -4,6 +4,7 @@
 package evmreader
 
 import (
+	"fmt"
 	"math/big"
 
 	. "github.com/cartesi/rollups-node/internal/model"
@@ -110,3 +111,23 @@ func (i *InputSourceAdapterImpl) RetrieveInputs(
 	}
 	return events, nil
 }
+
+// NewInputBoxInputSource is the InputSourceFactory of the InputBox(address)
+// data availability.
+func NewInputBoxInputSource(
+	app *Application,
+	dataAvailability []byte,
+	client *ethclient.Client,
+	filter ethutil.Filter,
+) (InputSourceAdapter, error) {
+	args, err := unpackDataAvailability("InputBox", dataAvailability)
+	if err != nil {
+		return nil, err
+	}
+	inputBoxAddress, ok := args[0].(common.Address)
+	if !ok || inputBoxAddress != app.IInputBoxAddress {
+		return nil, fmt.Errorf("InputBox %v does not match the application InputBox %v",
+			args[0], app.IInputBoxAddress)
+	}
+	return NewInputSourceAdapter(inputBoxAddress, client, filter)
+}
@@ -0,0 +1,119 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/pkg/contracts/dataavailability"
+	"github.com/cartesi/rollups-node/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var ErrUnsupportedDataAvailability = errors.New("unsupported data availability")
+
+// InputSourceFactory decodes the data availability of app, selector
+// included, and creates the adapter its inputs are read from.
+type InputSourceFactory func(
+	app *Application,
+	dataAvailability []byte,
+	client *ethclient.Client,
+	filter ethutil.Filter,
+) (InputSourceAdapter, error)
+
+type inputSourceEntry struct {
+	name    string
+	factory InputSourceFactory
+}
+
+// InputSourceRegistry maps each supported data availability selector to the
+// factory of the adapter its inputs are read from.
+type InputSourceRegistry struct {
+	entries map[DataAvailabilitySelector]inputSourceEntry
+}
+
+func NewInputSourceRegistry() *InputSourceRegistry {
+	return &InputSourceRegistry{entries: map[DataAvailabilitySelector]inputSourceEntry{}}
+}
+
+// Register makes the applications with the selector read from the adapters
+// created by factory, replacing any previous registration.
+func (r *InputSourceRegistry) Register(
+	selector DataAvailabilitySelector,
+	name string,
+	factory InputSourceFactory,
+) {
+	r.entries[selector] = inputSourceEntry{name: name, factory: factory}
+}
+
+// Names returns the names of the registered data availabilities, sorted.
+func (r *InputSourceRegistry) Names() []string {
+	names := make([]string, 0, len(r.entries))
+	for _, entry := range r.entries {
+		names = append(names, entry.name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (r *InputSourceRegistry) lookup(app *Application) (inputSourceEntry, error) {
+	if len(app.DataAvailability) >= DATA_AVAILABILITY_SELECTOR_SIZE {
+		var selector DataAvailabilitySelector
+		copy(selector[:], app.DataAvailability)
+		if entry, ok := r.entries[selector]; ok {
+			return entry, nil
+		}
+	}
+	return inputSourceEntry{}, fmt.Errorf("%w %s, expected one of: %s",
+		ErrUnsupportedDataAvailability,
+		hexutil.Encode(app.DataAvailability[:min(len(app.DataAvailability), DATA_AVAILABILITY_SELECTOR_SIZE)]),
+		strings.Join(r.Names(), ", "))
+}
+
+// Check returns an error wrapping ErrUnsupportedDataAvailability if the data
+// availability of app has no registered input source.
+func (r *InputSourceRegistry) Check(app *Application) error {
+	_, err := r.lookup(app)
+	return err
+}
+
+// Create creates the adapter the inputs of app are read from.
+func (r *InputSourceRegistry) Create(
+	app *Application,
+	client *ethclient.Client,
+	filter ethutil.Filter,
+) (InputSourceAdapter, error) {
+	entry, err := r.lookup(app)
+	if err != nil {
+		return nil, err
+	}
+	source, err := entry.factory(app, app.DataAvailability, client, filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s data availability: %w", entry.name, err)
+	}
+	return source, nil
+}
+
+// unpackDataAvailability decodes the arguments of a method of the
+// DataAvailability interface.
+func unpackDataAvailability(method string, data []byte) ([]any, error) {
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	m, ok := parsedAbi.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown data availability method %s", method)
+	}
+	if len(data) < DATA_AVAILABILITY_SELECTOR_SIZE ||
+		!DataAvailabilitySelector(m.ID).MatchesBytes(data) {
+		return nil, fmt.Errorf("data availability is not %s", method)
+	}
+	return m.Inputs.Unpack(data[DATA_AVAILABILITY_SELECTOR_SIZE:])
+}
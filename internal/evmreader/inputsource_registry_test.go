@@ -0,0 +1,106 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/pkg/contracts/dataavailability"
+	"github.com/cartesi/rollups-node/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *EvmReaderSuite) TestInputSourceRegistry() {
+	registry := NewInputSourceRegistry()
+	selector := DataAvailabilitySelector{0x01, 0x02, 0x03, 0x04}
+	inputBox := newMockInputBox()
+	registry.Register(selector, "Custom", func(
+		app *Application,
+		dataAvailability []byte,
+		client *ethclient.Client,
+		filter ethutil.Filter,
+	) (InputSourceAdapter, error) {
+		s.Equal([]byte{0x01, 0x02, 0x03, 0x04, 0xff}, dataAvailability)
+		return inputBox, nil
+	})
+	registry.Register(DataAvailability_InputBox, "InputBox", NewInputBoxInputSource)
+	s.Equal([]string{"Custom", "InputBox"}, registry.Names())
+
+	source, err := registry.Create(&Application{DataAvailability: []byte{0x01, 0x02, 0x03, 0x04, 0xff}},
+		nil, ethutil.Filter{})
+	s.Require().Nil(err)
+	s.Equal(inputBox, source)
+
+	app := &Application{DataAvailability: []byte{0x04, 0x03, 0x02, 0x01}}
+	s.ErrorIs(registry.Check(app), ErrUnsupportedDataAvailability)
+	_, err = registry.Create(app, nil, ethutil.Filter{})
+	s.ErrorIs(err, ErrUnsupportedDataAvailability)
+	s.ErrorContains(err, "0x04030201, expected one of: Custom, InputBox")
+	s.ErrorIs(registry.Check(&Application{}), ErrUnsupportedDataAvailability)
+
+	// each source decodes its own arguments
+	parsedAbi, err := dataavailability.DataAvailabilityMetaData.GetAbi()
+	s.Require().Nil(err)
+	da, err := parsedAbi.Pack("InputBox", common.HexToAddress("0x01"))
+	s.Require().Nil(err)
+	_, err = registry.Create(&Application{DataAvailability: da}, nil, ethutil.Filter{})
+	s.ErrorContains(err, "invalid InputBox data availability")
+	_, err = registry.Create(&Application{DataAvailability: DataAvailability_InputBox[:]}, nil, ethutil.Filter{})
+	s.ErrorContains(err, "invalid InputBox data availability")
+}
+
+func (s *EvmReaderSuite) TestItMarksUnsupportedDataAvailabilityInoperable() {
+	wsClient := FakeWSEhtClient{}
+	s.evmReader.wsClient = &wsClient
+
+	otherDA := DataAvailability_InputBox
+	otherDA[0]++
+
+	s.repository.Unset("ListApplications")
+	s.repository.On(
+		"ListApplications",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		false,
+	).Return([]*Application{{
+		ID:                  7,
+		IApplicationAddress: common.HexToAddress("0x2E663fe9aE92275242406A185AA4fC8174339D3E"),
+		IInputBoxAddress:    common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"),
+		DataAvailability:    otherDA[:],
+		IInputBoxBlock:      0x10,
+		EpochLength:         10,
+	}}, uint64(1), nil).Once()
+
+	s.repository.Unset("SetApplicationInoperable")
+	s.repository.On("SetApplicationInoperable",
+		mock.Anything,
+		int64(7),
+		ReasonCode_UnsupportedDataAvailability,
+		mock.MatchedBy(func(reason string) bool {
+			return strings.HasPrefix(reason, "unsupported data availability 0xb22c9ede")
+		}),
+	).Once().Return(nil)
+
+	ready := make(chan struct{}, 1)
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- s.evmReader.Run(s.ctx, ready)
+	}()
+	select {
+	case <-ready:
+	case err := <-errChannel:
+		s.FailNow("unexpected error signal", err)
+	}
+
+	wsClient.fireNewHead(&header0)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "SetApplicationInoperable", 1)
+	s.contractFactory.AssertNumberOfCalls(s.T(), "CreateAdapters", 0)
+}
@@ -0,0 +1,51 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	headBlockMetric = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "evmreader",
+		Name:      "head_block",
+		Help:      "Number of the most recent block received from the chain.",
+	})
+	lastReadBlockMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "evmreader",
+		Name:      "last_read_block",
+		Help:      "Last block whose inputs were read, per application.",
+	}, []string{"application"})
+	lagMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "evmreader",
+		Name:      "lag_blocks",
+		Help:      "Blocks between the head of the chain and the last block read, per application.",
+	}, []string{"application"})
+	inputsReadMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "evmreader",
+		Name:      "inputs_read_total",
+		Help:      "Inputs read and stored, per application.",
+	}, []string{"application"})
+	rpcErrorsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "evmreader",
+		Name:      "rpc_errors_total",
+		Help:      "Failed requests to the blockchain node, per operation.",
+	}, []string{"operation"})
+)
+
+// observeReadBlock records that the inputs of app were read up to block.
+func (r *Service) observeReadBlock(app *Application, block uint64) {
+	lastReadBlockMetric.WithLabelValues(app.Name).Set(float64(block))
+	if r.headBlock >= block {
+		lagMetric.WithLabelValues(app.Name).Set(float64(r.headBlock - block))
+	}
+}
@@ -67,6 +67,7 @@ func (r *Service) readAndUpdateOutputs(
 
 	outputExecutedEvents, err := contract.RetrieveOutputExecutionEvents(opts)
 	if err != nil {
+		rpcErrorsMetric.WithLabelValues("outputs").Inc()
 		r.Logger.Error("Error reading output events",
 			"application", app.application.Name, "address", app.application.IApplicationAddress,
 			"error", err)
@@ -104,7 +105,7 @@ func (r *Service) readAndUpdateOutputs(
 		if err != nil {
 			r.Logger.Error("Error retrieving output",
 				"application", app.application.Name, "address", app.application.IApplicationAddress,
-				"index", event.OutputIndex,
+				"output_index", event.OutputIndex,
 				"error", err)
 			return
 		}
@@ -112,27 +113,27 @@ func (r *Service) readAndUpdateOutputs(
 		if output == nil {
 			r.Logger.Warn("Found OutputExecuted event but output does not exist in the database yet",
 				"application", app.application.Name, "address", app.application.IApplicationAddress,
-				"index", event.OutputIndex)
+				"output_index", event.OutputIndex)
 			return
 		}
 
 		if !bytes.Equal(output.RawData, event.Output) {
 			r.Logger.Debug("Output mismatch",
 				"application", app.application.Name, "address", app.application.IApplicationAddress,
-				"index", event.OutputIndex,
+				"output_index", event.OutputIndex,
 				"actual", output.RawData,
 				"event's", event.Output)
 
 			r.Logger.Error("Output mismatch. Application is in an invalid state",
 				"application", app.application.Name, "address", app.application.IApplicationAddress,
-				"index", event.OutputIndex)
+				"output_index", event.OutputIndex)
 
 			return
 		}
 
 		r.Logger.Info("Output executed",
 			"application", app.application.Name, "address", app.application.IApplicationAddress,
-			"index", event.OutputIndex)
+			"output_index", event.OutputIndex)
 		output.ExecutionTransactionHash = &event.Raw.TxHash
 		executedOutputs = append(executedOutputs, output)
 	}
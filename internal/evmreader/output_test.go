@@ -179,6 +179,7 @@ func (s *EvmReaderSuite) TestReadOutputExecution() {
 		IApplicationAddress:  appAddress,
 		IConsensusAddress:    common.HexToAddress("0xdeadbeef"),
 		IInputBoxAddress:     common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"),
+		DataAvailability:     DataAvailability_InputBox[:],
 		IInputBoxBlock:       0x10,
 		EpochLength:          10,
 		LastOutputCheckBlock: 0x10,
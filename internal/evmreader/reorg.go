@@ -0,0 +1,271 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxReorgDepth is how many of the latest blocks read are remembered, and how
+// many of the latest inputs and epochs are checked, when looking for the
+// point where a chain reorganization forked.
+const maxReorgDepth = 64
+
+// remember records header as the latest block read.
+func (r *Service) remember(header *types.Header) {
+	if r.blockHashes == nil {
+		r.blockHashes = make(map[uint64]common.Hash)
+	}
+	number := header.Number.Uint64()
+	r.blockHashes[number] = header.Hash()
+	for n := range r.blockHashes {
+		if n+maxReorgDepth <= number {
+			delete(r.blockHashes, n)
+		}
+	}
+	if r.lastHeader == nil || number >= r.lastHeader.Number.Uint64() {
+		r.lastHeader = header
+	}
+}
+
+// forget drops the remembered blocks after blockNumber, which were orphaned.
+func (r *Service) forget(blockNumber uint64) {
+	for n := range r.blockHashes {
+		if n > blockNumber {
+			delete(r.blockHashes, n)
+		}
+	}
+	r.lastHeader = nil
+}
+
+// blockHash returns the hash of a block, avoiding a request if it was read recently.
+func (r *Service) blockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	if hash, ok := r.blockHashes[number]; ok {
+		return hash, nil
+	}
+	header, err := r.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to retrieve header %d: %w", number, err)
+	}
+	return header.Hash(), nil
+}
+
+// detectReorg reports whether the chain was reorganized since the last block
+// read, by checking the parent hash of header or, when blocks were skipped,
+// the current hash of the last block read.
+func (r *Service) detectReorg(ctx context.Context, header *types.Header) (bool, error) {
+	last := r.lastHeader
+	number := header.Number.Uint64()
+	lastNumber := last.Number.Uint64()
+	switch {
+	case number == lastNumber+1:
+		return header.ParentHash != last.Hash(), nil
+	case number <= lastNumber:
+		hash, ok := r.blockHashes[number]
+		return ok && hash != header.Hash(), nil
+	}
+	canonical, err := r.client.HeaderByNumber(ctx, last.Number)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve header %d: %w", lastNumber, err)
+	}
+	return canonical.Hash() != last.Hash(), nil
+}
+
+// findForkPoint returns the latest remembered block that is still part of the
+// chain. When none is, the reorganization is deeper than what is remembered
+// and the block before the oldest one is returned.
+func (r *Service) findForkPoint(ctx context.Context) (uint64, error) {
+	numbers := make([]uint64, 0, len(r.blockHashes))
+	for n := range r.blockHashes {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+	for i := len(numbers) - 1; i >= 0; i-- {
+		header, err := r.client.HeaderByNumber(ctx, new(big.Int).SetUint64(numbers[i]))
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve header %d: %w", numbers[i], err)
+		}
+		if header.Hash() == r.blockHashes[numbers[i]] {
+			return numbers[i], nil
+		}
+	}
+	if len(numbers) == 0 || numbers[0] == 0 {
+		return 0, nil
+	}
+	r.Logger.Warn("Chain reorganization is deeper than the blocks remembered",
+		"oldest_block", numbers[0])
+	return numbers[0] - 1, nil
+}
+
+// findOrphanedBlock compares the block hashes recorded for the latest inputs
+// and closed epochs of app with the chain, returning the oldest block found
+// orphaned, if any.
+func (r *Service) findOrphanedBlock(ctx context.Context, app *Application) (uint64, bool, error) {
+	hashes := map[uint64]common.Hash{}
+	orphaned := func(number uint64, hash common.Hash) (bool, error) {
+		canonical, ok := hashes[number]
+		if !ok {
+			header, err := r.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return false, fmt.Errorf("failed to retrieve header %d: %w", number, err)
+			}
+			canonical = header.Hash()
+			hashes[number] = canonical
+		}
+		return canonical != hash, nil
+	}
+
+	var block uint64
+	found := false
+	p := repository.Pagination{Limit: maxReorgDepth}
+	inputs, _, err := r.repository.ListInputs(ctx, app.IApplicationAddress.String(),
+		repository.InputFilter{}, p, true)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, input := range inputs {
+		if input.BlockHash == nil {
+			break // read before block hashes were recorded
+		}
+		bad, err := orphaned(input.BlockNumber, *input.BlockHash)
+		if err != nil {
+			return 0, false, err
+		}
+		if !bad {
+			break
+		}
+		block, found = input.BlockNumber, true
+	}
+
+	epochs, _, err := r.repository.ListEpochs(ctx, app.IApplicationAddress.String(),
+		repository.EpochFilter{}, p, true)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, epoch := range epochs {
+		if epoch.BlockHash == nil {
+			if epoch.Status == EpochStatus_Open {
+				continue
+			}
+			break // closed before block hashes were recorded
+		}
+		bad, err := orphaned(epoch.LastBlock, *epoch.BlockHash)
+		if err != nil {
+			return 0, false, err
+		}
+		if !bad {
+			break
+		}
+		if !found || epoch.LastBlock < block {
+			block, found = epoch.LastBlock, true
+		}
+	}
+	return block, found, nil
+}
+
+// checkForReorg looks for a chain reorganization before reading up to header,
+// and reverts the applications to the block where the chain forked. The
+// stored block hashes are also verified on the first block read, in case the
+// chain was reorganized while the node was down. It reports whether any
+// application was reverted, in which case they must be read again.
+func (r *Service) checkForReorg(
+	ctx context.Context,
+	apps []appContracts,
+	header *types.Header,
+) (bool, error) {
+	if r.defaultBlock == DefaultBlock_Finalized {
+		r.remember(header)
+		return false, nil
+	}
+
+	var reorged bool
+	fork := uint64(math.MaxUint64)
+	if r.lastHeader != nil {
+		var err error
+		reorged, err = r.detectReorg(ctx, header)
+		if err != nil {
+			return false, err
+		}
+		if !reorged {
+			r.remember(header)
+			return false, nil
+		}
+		fork, err = r.findForkPoint(ctx)
+		if err != nil {
+			return false, err
+		}
+		r.Logger.Warn("Chain reorganization detected",
+			"block_number", header.Number,
+			"block_hash", header.Hash(),
+			"fork_block", fork)
+	}
+
+	reverted := false
+	for _, app := range apps {
+		revertTo := uint64(math.MaxUint64)
+		if reorged && fork < max(app.application.LastInputCheckBlock, app.application.LastOutputCheckBlock) {
+			revertTo = fork
+		}
+		block, found, err := r.findOrphanedBlock(ctx, app.application)
+		if err != nil {
+			return false, err
+		}
+		if found && block == 0 {
+			// the genesis block was replaced, so nothing read from the chain is
+			// kept: there are no inputs in block 0 to keep before it
+			revertTo = 0
+		} else if found {
+			revertTo = min(revertTo, block-1)
+		}
+		if revertTo == math.MaxUint64 {
+			continue
+		}
+
+		inputs, err := r.repository.RevertBlocks(ctx, app.application.ID, revertTo)
+		if errors.Is(err, repository.ErrClaimedEpochReverted) {
+			// the claim on chain no longer matches the inputs, which needs an
+			// operator to decide what to do
+			reason := fmt.Sprintf("chain reorganization after block %d reverted a claimed epoch: %v",
+				revertTo, err)
+			r.Logger.Error("Refusing to revert application, marking it inoperable",
+				"application", app.application.Name,
+				"block_number", revertTo,
+				"error", err)
+			err = r.repository.SetApplicationInoperable(ctx, app.application.ID,
+				ReasonCode_ClaimedEpochReverted, reason)
+			if err != nil {
+				return false, fmt.Errorf("failed to mark application %s inoperable: %w",
+					app.application.Name, err)
+			}
+			// the block is read again without it
+			reverted = true
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to revert application %s to block %d: %w",
+				app.application.Name, revertTo, err)
+		}
+		r.Logger.Warn("Reverted application to the block before the chain reorganization",
+			"application", app.application.Name,
+			"block_number", revertTo,
+			"reverted_inputs", inputs)
+		reverted = true
+	}
+
+	if reorged {
+		r.forget(fork)
+	}
+	r.remember(header)
+	return reverted, nil
+}
@@ -0,0 +1,223 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package evmreader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/pkg/contracts/iapplication"
+	"github.com/cartesi/rollups-node/pkg/contracts/iinputbox"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *EvmReaderSuite) prepareReorgTest(lastInputCheckBlock uint64) (*FakeWSEhtClient, *MockInputBox) {
+	wsClient := FakeWSEhtClient{}
+	s.evmReader.wsClient = &wsClient
+
+	inputBox := newMockInputBox()
+	applicationContract := &MockApplicationContract{}
+	applicationContract.On("RetrieveOutputExecutionEvents",
+		mock.Anything,
+	).Return([]*iapplication.IApplicationOutputExecuted{}, nil)
+	s.contractFactory.Unset("CreateAdapters")
+	s.contractFactory.On("CreateAdapters",
+		mock.Anything,
+		mock.Anything,
+	).Return(applicationContract, inputBox, nil)
+
+	s.repository.Unset("ListApplications")
+	s.repository.On(
+		"ListApplications",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		false,
+	).Return([]*Application{{
+		ID:                  1,
+		IApplicationAddress: common.HexToAddress("0x2E663fe9aE92275242406A185AA4fC8174339D3E"),
+		IConsensusAddress:   common.HexToAddress("0xdeadbeef"),
+		IInputBoxAddress:    common.HexToAddress("0xBa3Cf8fB82E43D370117A0b7296f91ED674E94e3"),
+		DataAvailability:    DataAvailability_InputBox[:],
+		IInputBoxBlock:      0x10,
+		EpochLength:         10,
+		LastInputCheckBlock: lastInputCheckBlock,
+	}}, uint64(1), nil)
+	s.repository.On("UpdateEventLastCheckBlock",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+
+	ready := make(chan struct{}, 1)
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- s.evmReader.Run(s.ctx, ready)
+	}()
+	select {
+	case <-ready:
+	case err := <-errChannel:
+		s.FailNow("unexpected error signal", err)
+	}
+	return &wsClient, inputBox
+}
+
+func (s *EvmReaderSuite) TestItRecordsBlockHashes() {
+	s.repository.Unset("CreateEpochsAndInputs")
+	s.repository.On(
+		"CreateEpochsAndInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Once().Run(func(arguments mock.Arguments) {
+		epochInputMap, ok := arguments.Get(2).(map[*Epoch][]*Input)
+		s.Require().True(ok)
+		s.Require().Equal(1, len(epochInputMap))
+		for epoch, inputs := range epochInputMap {
+			s.Equal(EpochStatus_Closed, epoch.Status)
+			s.Equal(header2.Hash(), *epoch.BlockHash)
+			s.Require().Equal(1, len(inputs))
+			s.Equal(inputAddedEvent2.Raw.BlockHash, *inputs[0].BlockHash)
+		}
+	}).Return(nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	inputBox.Unset("RetrieveInputs")
+	inputBox.On("RetrieveInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return([]iinputbox.IInputBoxInputAdded{inputAddedEvent2}, nil)
+
+	wsClient.fireNewHead(&header2)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "CreateEpochsAndInputs", 1)
+}
+
+func (s *EvmReaderSuite) TestItRevertsOrphanedInputsOnStart() {
+	orphanedHash := common.HexToHash("0xdead")
+	s.repository.Unset("ListInputs")
+	s.repository.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		true,
+	).Return([]*Input{{Index: 1, BlockNumber: 0x12, BlockHash: &orphanedHash}}, uint64(1), nil)
+	s.repository.On("RevertBlocks",
+		mock.Anything,
+		int64(1),
+		uint64(0x11),
+	).Once().Return(uint64(1), nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	wsClient.fireNewHead(&header2)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "RevertBlocks", 1)
+	inputBox.AssertNumberOfCalls(s.T(), "RetrieveInputs", 0)
+}
+
+func (s *EvmReaderSuite) TestItRevertsEverythingWhenTheGenesisIsOrphaned() {
+	orphanedHash := common.HexToHash("0xdead")
+	s.repository.Unset("ListInputs")
+	s.repository.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		true,
+	).Return([]*Input{{Index: 0, BlockNumber: 0, BlockHash: &orphanedHash}}, uint64(1), nil)
+	s.repository.On("RevertBlocks",
+		mock.Anything,
+		int64(1),
+		uint64(0),
+	).Once().Return(uint64(1), nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	wsClient.fireNewHead(&header2)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "RevertBlocks", 1)
+	inputBox.AssertNumberOfCalls(s.T(), "RetrieveInputs", 0)
+}
+
+func (s *EvmReaderSuite) TestItRefusesToRevertClaimedEpochs() {
+	orphanedHash := common.HexToHash("0xdead")
+	s.repository.Unset("ListInputs")
+	s.repository.On("ListInputs",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		true,
+	).Return([]*Input{{Index: 1, BlockNumber: 0x12, BlockHash: &orphanedHash}}, uint64(1), nil)
+	s.repository.On("RevertBlocks",
+		mock.Anything,
+		int64(1),
+		uint64(0x11),
+	).Once().Return(uint64(0), fmt.Errorf("%w: epoch 1 is CLAIM_SUBMITTED", repository.ErrClaimedEpochReverted))
+	s.repository.Unset("SetApplicationInoperable")
+	s.repository.On("SetApplicationInoperable",
+		mock.Anything,
+		int64(1),
+		ReasonCode_ClaimedEpochReverted,
+		mock.MatchedBy(func(reason string) bool {
+			return strings.Contains(reason, "reverted a claimed epoch")
+		}),
+	).Once().Return(nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	wsClient.fireNewHead(&header2)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "RevertBlocks", 1)
+	s.repository.AssertNumberOfCalls(s.T(), "SetApplicationInoperable", 1)
+	inputBox.AssertNumberOfCalls(s.T(), "RetrieveInputs", 0)
+}
+
+func (s *EvmReaderSuite) TestItRevertsOnParentHashMismatch() {
+	// the chain forked before block 0x12, so it was replaced as well
+	replaced := types.CopyHeader(&header1)
+	replaced.ParentHash = common.HexToHash("0x01")
+	forked := types.CopyHeader(&header2)
+	forked.ParentHash = replaced.Hash()
+
+	s.client.Unset("HeaderByNumber")
+	s.client.On("HeaderByNumber",
+		mock.Anything,
+		mock.Anything,
+	).Return(replaced, nil)
+	s.repository.On("RevertBlocks",
+		mock.Anything,
+		int64(1),
+		uint64(0x11),
+	).Once().Return(uint64(0), nil)
+
+	wsClient, inputBox := s.prepareReorgTest(0x12)
+	wsClient.fireNewHead(&header1)
+	wsClient.fireNewHead(forked)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "RevertBlocks", 1)
+	inputBox.AssertNumberOfCalls(s.T(), "RetrieveInputs", 0)
+
+	// the next block builds on the new chain
+	next := types.CopyHeader(&header2)
+	next.Number.SetUint64(0x14)
+	next.ParentHash = forked.Hash()
+	wsClient.fireNewHead(next)
+	time.Sleep(time.Second)
+
+	s.repository.AssertNumberOfCalls(s.T(), "RevertBlocks", 1)
+	inputBox.AssertNumberOfCalls(s.T(), "RetrieveInputs", 1)
+}
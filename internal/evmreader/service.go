@@ -8,12 +8,17 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
+	"time"
 
 	"github.com/cartesi/rollups-node/internal/config"
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/pkg/espresso"
 	"github.com/cartesi/rollups-node/pkg/ethutil"
 	"github.com/cartesi/rollups-node/pkg/service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type CreateInfo struct {
@@ -30,18 +35,40 @@ type CreateInfo struct {
 type Service struct {
 	service.Service
 
-	client             EthClientInterface
-	wsClient           EthClientInterface
-	adapterFactory     AdapterFactory
-	repository         EvmReaderRepository
-	chainId            uint64
-	defaultBlock       DefaultBlock
-	hasEnabledApps     bool
+	client         EthClientInterface
+	wsClient       EthClientInterface
+	adapterFactory AdapterFactory
+	inputSources   *InputSourceRegistry
+	repository     EvmReaderRepository
+	chainId        uint64
+	defaultBlock   DefaultBlock
+	hasEnabledApps bool
+	// number of the most recent block received
+	headBlock          uint64
 	inputReaderEnabled bool
+	// whether new block events are being received
+	subscribed atomic.Bool
+	// configuration in effect, updated by Reload
+	config config.EvmreaderConfig
+	// closed by Stop to make Run return after the block in progress
+	stop chan struct{}
+	// closed when Run returns
+	runDone chan struct{}
+
+	// latest blocks read, to detect chain reorganizations
+	lastHeader  *types.Header
+	blockHashes map[uint64]common.Hash
+
+	// Espresso query service, if configured
+	espresso        *espresso.Client
+	espressoCursors map[common.Address]espressoCursor
 }
 
 const EvmReaderConfigKey = "evm-reader"
 
+// PersistentConfig is saved in the database the first time the evm-reader
+// starts and takes precedence over the configuration afterwards. Its fields
+// never change, not even on Reload.
 type PersistentConfig struct {
 	DefaultBlock       DefaultBlock
 	InputReaderEnabled bool
@@ -54,7 +81,7 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		return nil, err // This returns context.Canceled or context.DeadlineExceeded.
 	}
 
-	s := &Service{}
+	s := &Service{stop: make(chan struct{})}
 	c.Impl = s
 
 	err = service.Create(ctx, &c.CreateInfo, &s.Service)
@@ -100,6 +127,7 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 			chainId.Uint64(), nodeConfig.ChainID)
 	}
 
+	s.config = c.Config
 	s.client = c.EthClient
 	s.wsClient = c.EthWsClient
 
@@ -107,41 +135,129 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 	s.defaultBlock = nodeConfig.DefaultBlock
 	s.inputReaderEnabled = nodeConfig.InputReaderEnabled
 	s.hasEnabledApps = true
+
+	espressoURL, err := config.GetEspressoBaseUrl()
+	if err != nil && !errors.Is(err, config.ErrNotDefined) {
+		return nil, err
+	} else if err == nil {
+		s.espresso, err = espresso.NewClient(espressoURL.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.inputSources = s.newInputSourceRegistry()
 	s.adapterFactory = &DefaultAdapterFactory{
 		Filter: ethutil.Filter{
 			MinChunkSize: ethutil.DefaultMinChunkSize,
 			MaxChunkSize: new(big.Int).SetUint64(c.Config.BlockchainMaxBlockRange),
 			Logger:       s.Logger,
 		},
+		InputSources: s.inputSources,
 	}
 
+	s.AddHealthCheck("database", c.Repository.Ping)
+	s.AddHealthCheck("subscription", s.checkSubscription)
+
 	return s, nil
 }
 
+// checkSubscription fails while new block events are not being received
+func (s *Service) checkSubscription(ctx context.Context) error {
+	if !s.subscribed.Load() {
+		return errors.New("not subscribed to new blocks")
+	}
+	return nil
+}
+
+// newInputSourceRegistry registers the data availabilities the node reads inputs from.
+func (s *Service) newInputSourceRegistry() *InputSourceRegistry {
+	registry := NewInputSourceRegistry()
+	registry.Register(DataAvailability_InputBox, "InputBox", NewInputBoxInputSource)
+	registry.Register(DataAvailability_InputBoxAndEspresso, "InputBoxAndEspresso", s.newEspressoInputSource)
+	return registry
+}
+
 func (s *Service) Alive() bool {
 	return true
 }
 
 func (s *Service) Ready() bool {
-	return true
+	return s.Health(s.Context).Ready
 }
 
+// Reload applies the log level and the maximum block range
 func (s *Service) Reload() []error {
+	c, err := config.LoadEvmreaderConfig()
+	if err != nil {
+		return []error{err}
+	}
+	s.SetLogLevel(c.LogLevel)
+	if factory, ok := s.adapterFactory.(*DefaultAdapterFactory); ok {
+		factory.SetMaxBlockRange(c.BlockchainMaxBlockRange)
+	}
+	if c.BlockchainDefaultBlock != s.defaultBlock ||
+		c.FeatureInputReaderEnabled != s.inputReaderEnabled ||
+		c.BlockchainId != s.chainId {
+		s.Logger.Warn("Configuration differs from the persistent config, which is kept",
+			"config", PersistentConfig{
+				DefaultBlock:       s.defaultBlock,
+				InputReaderEnabled: s.inputReaderEnabled,
+				ChainID:            s.chainId,
+			})
+	}
+
+	s.config.LogLevel = c.LogLevel
+	s.config.BlockchainMaxBlockRange = c.BlockchainMaxBlockRange
+	s.config.BlockchainDefaultBlock = c.BlockchainDefaultBlock
+	s.config.FeatureInputReaderEnabled = c.FeatureInputReaderEnabled
+	s.config.BlockchainId = c.BlockchainId
+	s.ReportRestartRequired(config.Changed(&s.config, c))
 	return nil
 }
 
+// Stop makes Run return once the block in progress was read, waiting up to
+// ShutdownTimeout for it
 func (s *Service) Stop(bool) []error {
-	return nil
+	if s.runDone == nil {
+		return nil
+	}
+	s.Logger.Info("Stopping the block subscription")
+	close(s.stop)
+	select {
+	case <-s.runDone:
+		return nil
+	case <-time.After(s.ShutdownTimeout):
+		return []error{errors.New("timed out waiting for the block in progress")}
+	}
 }
 
 func (s *Service) Tick() []error {
 	return []error{}
 }
 
+// Serve reads the blocks until the service stops. When reading fails for
+// good, the service stops and Serve returns the error.
 func (s *Service) Serve() error {
 	ready := make(chan struct{}, 1)
-	go s.Run(s.Context, ready)
-	return s.Service.Serve()
+	s.runDone = make(chan struct{})
+	var runErr error
+	go func() {
+		defer close(s.runDone)
+		err := s.Run(s.Context, ready)
+		if err != nil && !s.Stopping() {
+			s.Logger.Error("Stopped reading blocks", "error", err)
+			runErr = err
+			s.Cancel()
+		}
+	}()
+	err := s.Service.Serve()
+	select {
+	case <-s.runDone:
+		return errors.Join(err, runErr)
+	default:
+		return err
+	}
 }
 
 func (s *Service) String() string {
@@ -17,7 +17,6 @@ import (
 	"github.com/cartesi/rollups-node/internal/manager"
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/services"
-	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
@@ -56,11 +55,8 @@ func NewInspector(
 	repo InspectRepository,
 	machines IInspectMachines,
 	address string,
-	logLevel slog.Level,
-	logPretty bool,
+	logger *slog.Logger,
 ) (*Inspector, *http.Server, func() error) {
-	logger := service.NewLogger(slog.Level(logLevel), logPretty)
-	logger = logger.With("service", "inspect")
 	inspector := &Inspector{
 		IInspectMachines: machines,
 		repository:       repo,
@@ -79,7 +75,7 @@ func NewInspector(
 	return inspector, server, func() error {
 		maxRetries := 3                  // FIXME: should go to config
 		retryInterval := 5 * time.Second // FIXME: should go to config
-		inspector.Logger.Info("Create", "LogLevel", logLevel, "pid", os.Getpid())
+		inspector.Logger.Info("Create", "pid", os.Getpid())
 		inspector.Logger.Info("Listening", "address", address)
 		var err error = nil
 		for retry := 0; retry <= maxRetries; retry++ {
@@ -111,7 +107,7 @@ func (inspect *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if r.PathValue("dapp") == "" {
 		inspect.Logger.Info("Bad request",
-			"err", "Missing application address")
+			"error", "Missing application address")
 		http.Error(w, "Missing application address", http.StatusBadRequest)
 		return
 	}
@@ -120,7 +116,7 @@ func (inspect *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "POST" {
 		payload, err = io.ReadAll(r.Body)
 		if err != nil {
-			inspect.Logger.Info("Bad request", "err", err)
+			inspect.Logger.Info("Bad request", "error", err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -134,11 +130,11 @@ func (inspect *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	result, err := inspect.process(r.Context(), dapp, payload)
 	if err != nil {
 		if errors.Is(err, ErrNoApp) {
-			inspect.Logger.Error("Application not found", "application", dapp, "err", err)
+			inspect.Logger.Error("Application not found", "application", dapp, "error", err)
 			http.Error(w, "Application not found", http.StatusNotFound)
 			return
 		}
-		inspect.Logger.Error("Internal server error", "err", err)
+		inspect.Logger.Error("Internal server error", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -169,7 +165,7 @@ func (inspect *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
 		inspect.Logger.Error("Internal server error",
-			"err", err)
+			"error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -197,7 +193,15 @@ func (inspect *Inspector) process(
 		return nil, fmt.Errorf("%w %s", ErrNoApp, nameOrAddress)
 	}
 
+	// requests in flight beyond the concurrency limit are waiting for the machine
+	concurrencyLimitMetric.WithLabelValues(app.Name).Set(
+		float64(app.ExecutionParameters.MaxConcurrentInspects))
+	inFlight := inFlightMetric.WithLabelValues(app.Name)
+	inFlight.Inc()
+	start := time.Now()
 	res, err := machine.Inspect(ctx, query)
+	durationMetric.WithLabelValues(app.Name).Observe(time.Since(start).Seconds())
+	inFlight.Dec()
 	if err != nil {
 		return nil, err
 	}
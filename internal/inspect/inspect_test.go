@@ -238,6 +238,26 @@ func (mock *MockMachine) CreateSnapshot(ctx context.Context, processedInputs uin
 	return nil
 }
 
+func (mock *MockMachine) ProcessedInputs() uint64 {
+	// Not used in inspect tests, but needed to satisfy the interface
+	return 0
+}
+
+func (mock *MockMachine) Hash(ctx context.Context) (common.Hash, error) {
+	// Not used in inspect tests, but needed to satisfy the interface
+	return common.Hash{}, nil
+}
+
+func (mock *MockMachine) Ping(ctx context.Context) error {
+	// Not used in inspect tests, but needed to satisfy the interface
+	return nil
+}
+
+func (mock *MockMachine) SetMaxConcurrentInspects(limit uint32) error {
+	// Not used in inspect tests, but needed to satisfy the interface
+	return nil
+}
+
 func (mock *MockMachine) Close() error {
 	// Not used in inspect tests, but needed to satisfy the interface
 	return nil
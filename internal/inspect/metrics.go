@@ -0,0 +1,31 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package inspect
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	inFlightMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "inspect",
+		Name:      "in_flight",
+		Help:      "Inspect requests running or waiting for the machine, per application.",
+	}, []string{"application"})
+	concurrencyLimitMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cartesi",
+		Subsystem: "inspect",
+		Name:      "concurrency_limit",
+		Help:      "Inspect requests the machine of each application runs at the same time.",
+	}, []string{"application"})
+	durationMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cartesi",
+		Subsystem: "inspect",
+		Name:      "duration_seconds",
+		Help:      "Time taken to serve an inspect request, waiting included, per application.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"application"})
+)
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/evmreader"
@@ -59,6 +60,9 @@ func (s *Service) handleRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s.Logger.Info(fmt.Sprintf("Received RPC request: %s", req.Method))
+	start := time.Now()
+	method := req.Method
+	defer func() { observeRequest(method, time.Since(start)) }()
 	switch req.Method {
 	case "rpc.discover":
 		s.handleDiscover(w, r, req)
@@ -91,6 +95,7 @@ func (s *Service) handleRPC(w http.ResponseWriter, r *http.Request) {
 	case "cartesi_getNodeVersion":
 		s.handleGetNodeVersion(w, req)
 	default:
+		method = "unknown"
 		s.Logger.Info(fmt.Sprintf("RPC method not found: %s", req.Method))
 		writeRPCError(w, req.ID, JSONRPC_METHOD_NOT_FOUND, "Method not found", nil)
 	}
@@ -104,13 +109,13 @@ func (s *Service) handleRPC(w http.ResponseWriter, r *http.Request) {
 func (s *Service) handleDiscover(w http.ResponseWriter, _ *http.Request, req RPCRequest) {
 	data, err := discoverSpec.ReadFile("jsonrpc-discover.json")
 	if err != nil {
-		s.Logger.Error("Unable to read jsonrpc-discover content", "err", err)
+		s.Logger.Error("Unable to read jsonrpc-discover content", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
 	var spec any
 	if err := json.Unmarshal(data, &spec); err != nil {
-		s.Logger.Error("Unable to unmarshal discovery spec JSON", "err", err)
+		s.Logger.Error("Unable to unmarshal discovery spec JSON", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -120,7 +125,7 @@ func (s *Service) handleDiscover(w http.ResponseWriter, _ *http.Request, req RPC
 func (s *Service) handleListApplications(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params ListApplicationsParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -138,7 +143,7 @@ func (s *Service) handleListApplications(w http.ResponseWriter, r *http.Request,
 		Offset: params.Offset,
 	}, params.Descending)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve applications from repository", "err", err)
+		s.Logger.Error("Unable to retrieve applications from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -173,7 +178,7 @@ func (s *Service) handleListApplications(w http.ResponseWriter, r *http.Request,
 func (s *Service) handleGetApplication(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetApplicationParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -186,7 +191,7 @@ func (s *Service) handleGetApplication(w http.ResponseWriter, r *http.Request, r
 
 	app, err := s.repository.GetApplication(r.Context(), params.Application)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve application from repository", "err", err)
+		s.Logger.Error("Unable to retrieve application from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -208,7 +213,7 @@ func (s *Service) handleGetApplication(w http.ResponseWriter, r *http.Request, r
 func (s *Service) handleListEpochs(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params ListEpochsParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -238,12 +243,20 @@ func (s *Service) handleListEpochs(w http.ResponseWriter, r *http.Request, req R
 		epochFilter.Status = &status
 	}
 
+	// Resume after the cursor index if provided
+	after, err := parseCursor(params.After)
+	if err != nil {
+		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
 	epochs, total, err := s.repository.ListEpochs(r.Context(), params.Application, epochFilter, repository.Pagination{
 		Limit:  params.Limit,
 		Offset: params.Offset,
+		After:  after,
 	}, params.Descending)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve epochs from repository", "err", err)
+		s.Logger.Error("Unable to retrieve epochs from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -287,10 +300,22 @@ func parseIndex(indexString string, field string) (uint64, error) {
 	return index, nil
 }
 
+// parseCursor parses the optional keyset cursor of the list methods.
+func parseCursor(after *string) (*uint64, error) {
+	if after == nil {
+		return nil, nil
+	}
+	index, err := parseIndex(*after, "after")
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
 func (s *Service) handleGetEpoch(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetEpochParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -309,7 +334,7 @@ func (s *Service) handleGetEpoch(w http.ResponseWriter, r *http.Request, req RPC
 
 	epoch, err := s.repository.GetEpoch(r.Context(), params.Application, index)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve epoch from repository", "err", err)
+		s.Logger.Error("Unable to retrieve epoch from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -331,7 +356,7 @@ func (s *Service) handleGetEpoch(w http.ResponseWriter, r *http.Request, req RPC
 func (s *Service) handleGetLastAcceptedEpochIndex(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetLastAcceptedEpochIndexParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -348,7 +373,7 @@ func (s *Service) handleGetLastAcceptedEpochIndex(w http.ResponseWriter, r *http
 		return
 	}
 	if err != nil {
-		s.Logger.Error("Unable to retrieve epoch from repository", "err", err)
+		s.Logger.Error("Unable to retrieve epoch from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -366,7 +391,7 @@ func (s *Service) handleGetLastAcceptedEpochIndex(w http.ResponseWriter, r *http
 func (s *Service) handleListInputs(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params ListInputsParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -407,12 +432,20 @@ func (s *Service) handleListInputs(w http.ResponseWriter, r *http.Request, req R
 		inputFilter.Sender = &sender
 	}
 
+	// Resume after the cursor index if provided
+	after, err := parseCursor(params.After)
+	if err != nil {
+		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
 	inputs, total, err := s.repository.ListInputs(r.Context(), params.Application, inputFilter, repository.Pagination{
 		Limit:  params.Limit,
 		Offset: params.Offset,
+		After:  after,
 	}, params.Descending)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve inputs from repository", "err", err)
+		s.Logger.Error("Unable to retrieve inputs from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -421,7 +454,7 @@ func (s *Service) handleListInputs(w http.ResponseWriter, r *http.Request, req R
 	for _, in := range inputs {
 		decoded, err := DecodeInput(in, s.inputABI)
 		if err != nil {
-			s.Logger.Error("Unable to decode Input", "app", params.Application, "index", in.Index, "err", err)
+			s.Logger.Error("Unable to decode Input", "application", params.Application, "input_index", in.Index, "error", err)
 		}
 		resultInputs = append(resultInputs, decoded)
 	}
@@ -456,7 +489,7 @@ func (s *Service) handleListInputs(w http.ResponseWriter, r *http.Request, req R
 func (s *Service) handleGetInput(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetInputParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -475,7 +508,7 @@ func (s *Service) handleGetInput(w http.ResponseWriter, r *http.Request, req RPC
 
 	input, err := s.repository.GetInput(r.Context(), params.Application, index)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve input from repository", "err", err)
+		s.Logger.Error("Unable to retrieve input from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -486,7 +519,7 @@ func (s *Service) handleGetInput(w http.ResponseWriter, r *http.Request, req RPC
 
 	decoded, err := DecodeInput(input, s.inputABI)
 	if err != nil {
-		s.Logger.Error("Unable to decode Input", "app", params.Application, "index", input.Index, "err", err)
+		s.Logger.Error("Unable to decode Input", "application", params.Application, "input_index", input.Index, "error", err)
 	}
 
 	// Format response according to spec
@@ -502,7 +535,7 @@ func (s *Service) handleGetInput(w http.ResponseWriter, r *http.Request, req RPC
 func (s *Service) handleGetProcessedInputCount(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetApplicationParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -519,7 +552,7 @@ func (s *Service) handleGetProcessedInputCount(w http.ResponseWriter, r *http.Re
 		return
 	}
 	if err != nil {
-		s.Logger.Error("Unable to retrieve application from repository", "err", err)
+		s.Logger.Error("Unable to retrieve application from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -552,7 +585,7 @@ func ParseOutputType(s string) ([]byte, error) {
 func (s *Service) handleListOutputs(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params ListOutputsParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -612,12 +645,20 @@ func (s *Service) handleListOutputs(w http.ResponseWriter, r *http.Request, req
 		outputFilter.VoucherAddress = &voucherAddress
 	}
 
+	// Resume after the cursor index if provided
+	after, err := parseCursor(params.After)
+	if err != nil {
+		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
 	outputs, total, err := s.repository.ListOutputs(r.Context(), params.Application, outputFilter, repository.Pagination{
 		Limit:  params.Limit,
 		Offset: params.Offset,
+		After:  after,
 	}, params.Descending)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve outputs from repository", "err", err)
+		s.Logger.Error("Unable to retrieve outputs from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -626,7 +667,7 @@ func (s *Service) handleListOutputs(w http.ResponseWriter, r *http.Request, req
 	for _, out := range outputs {
 		decoded, err := DecodeOutput(out, s.outputABI)
 		if err != nil {
-			s.Logger.Error("Unable to decode Output", "app", params.Application, "index", out.Index, "err", err)
+			s.Logger.Error("Unable to decode Output", "application", params.Application, "output_index", out.Index, "error", err)
 		}
 		resultOutputs = append(resultOutputs, decoded)
 	}
@@ -661,7 +702,7 @@ func (s *Service) handleListOutputs(w http.ResponseWriter, r *http.Request, req
 func (s *Service) handleGetOutput(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetOutputParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -680,7 +721,7 @@ func (s *Service) handleGetOutput(w http.ResponseWriter, r *http.Request, req RP
 
 	output, err := s.repository.GetOutput(r.Context(), params.Application, index)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve output from repository", "err", err)
+		s.Logger.Error("Unable to retrieve output from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -691,7 +732,7 @@ func (s *Service) handleGetOutput(w http.ResponseWriter, r *http.Request, req RP
 
 	decoded, err := DecodeOutput(output, s.outputABI)
 	if err != nil {
-		s.Logger.Error("Unable to decode Output", "app", params.Application, "index", output.Index, "err", err)
+		s.Logger.Error("Unable to decode Output", "application", params.Application, "output_index", output.Index, "error", err)
 	}
 
 	// Format response according to spec
@@ -707,7 +748,7 @@ func (s *Service) handleGetOutput(w http.ResponseWriter, r *http.Request, req RP
 func (s *Service) handleListReports(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params ListReportsParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -747,12 +788,20 @@ func (s *Service) handleListReports(w http.ResponseWriter, r *http.Request, req
 		reportFilter.InputIndex = &inputIndex
 	}
 
+	// Resume after the cursor index if provided
+	after, err := parseCursor(params.After)
+	if err != nil {
+		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
 	reports, total, err := s.repository.ListReports(r.Context(), params.Application, reportFilter, repository.Pagination{
 		Limit:  params.Limit,
 		Offset: params.Offset,
+		After:  after,
 	}, params.Descending)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve reports from repository", "err", err)
+		s.Logger.Error("Unable to retrieve reports from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -787,7 +836,7 @@ func (s *Service) handleListReports(w http.ResponseWriter, r *http.Request, req
 func (s *Service) handleGetReport(w http.ResponseWriter, r *http.Request, req RPCRequest) {
 	var params GetReportParams
 	if err := UnmarshalParams(req.Params, &params); err != nil {
-		s.Logger.Debug("Invalid parameters", "err", err)
+		s.Logger.Debug("Invalid parameters", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INVALID_PARAMS, "Invalid parameters", nil)
 		return
 	}
@@ -806,7 +855,7 @@ func (s *Service) handleGetReport(w http.ResponseWriter, r *http.Request, req RP
 
 	report, err := s.repository.GetReport(r.Context(), params.Application, index)
 	if err != nil {
-		s.Logger.Error("Unable to retrieve report from repository", "err", err)
+		s.Logger.Error("Unable to retrieve report from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
@@ -833,7 +882,7 @@ func (s *Service) handleGetChainId(w http.ResponseWriter, r *http.Request, req R
 		return
 	}
 	if err != nil {
-		s.Logger.Error("Unable to retrieve evmreader config from repository", "err", err)
+		s.Logger.Error("Unable to retrieve evmreader config from repository", "error", err)
 		writeRPCError(w, req.ID, JSONRPC_INTERNAL_ERROR, "Internal server error", nil)
 		return
 	}
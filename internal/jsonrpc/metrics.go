@@ -0,0 +1,34 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package jsonrpc
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cartesi",
+		Subsystem: "jsonrpc",
+		Name:      "requests_total",
+		Help:      "JSON-RPC requests served, per method.",
+	}, []string{"method"})
+	requestDurationMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cartesi",
+		Subsystem: "jsonrpc",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve a JSON-RPC request, per method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// observeRequest records a request to method served in duration. Unknown
+// methods are recorded together, so clients cannot create arbitrary series.
+func observeRequest(method string, duration time.Duration) {
+	requestsMetric.WithLabelValues(method).Inc()
+	requestDurationMetric.WithLabelValues(method).Observe(duration.Seconds())
+}
@@ -5,9 +5,9 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/cartesi/rollups-node/internal/config"
 	"github.com/cartesi/rollups-node/internal/repository"
@@ -30,6 +30,9 @@ type Service struct {
 	server     *http.Server
 	inputABI   *abi.ABI
 	outputABI  *abi.ABI
+
+	// configuration in effect, updated by Reload
+	config config.JsonrpcConfig
 }
 
 type CreateInfo struct {
@@ -54,6 +57,7 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		return nil, err
 	}
 
+	s.config = c.Config
 	s.repository = c.Repository
 	if s.repository == nil {
 		return nil, fmt.Errorf("repository on validator service Create is nil")
@@ -76,6 +80,8 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		Handler: services.CorsMiddleware(mux), // FIXME: add proper cors config
 	}
 
+	s.AddHealthCheck("database", c.Repository.Ping)
+
 	return s, nil
 }
 
@@ -84,10 +90,18 @@ func (s *Service) Alive() bool {
 }
 
 func (s *Service) Ready() bool {
-	return true
+	return s.Health(s.Context).Ready
 }
 
+// Reload applies the log level
 func (s *Service) Reload() []error {
+	c, err := config.LoadJsonrpcConfig()
+	if err != nil {
+		return []error{err}
+	}
+	s.SetLogLevel(c.LogLevel)
+	s.config.LogLevel = c.LogLevel
+	s.ReportRestartRequired(config.Changed(&s.config, c))
 	return nil
 }
 
@@ -98,7 +112,8 @@ func (s *Service) Tick() []error {
 
 func (s *Service) Stop(force bool) []error {
 	var errs []error
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	s.Logger.Info("Stopping the HTTP server")
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
 	defer cancel()
 	if err := s.server.Shutdown(ctx); err != nil {
 		errs = append(errs, err)
@@ -111,6 +126,15 @@ func (s *Service) String() string {
 }
 
 func (s *Service) Serve() error {
+	// handles the reloads and stops the server
+	go s.Service.Serve()
+
 	s.Logger.Info("Listening", "addr", s.server.Addr)
-	return s.server.ListenAndServe()
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		// wait for the requests in progress and the rest of the shutdown
+		<-s.Context.Done()
+		return nil
+	}
+	return err
 }
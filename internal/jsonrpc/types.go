@@ -163,6 +163,7 @@ type ListEpochsParams struct {
 	Limit       uint64  `json:"limit"`
 	Offset      uint64  `json:"offset"`
 	Descending  bool    `json:"descending,omitempty"`
+	After       *string `json:"after,omitempty"`
 }
 
 // GetEpochParams aligns with the OpenRPC specification
@@ -184,6 +185,7 @@ type ListInputsParams struct {
 	Limit       uint64  `json:"limit"`
 	Offset      uint64  `json:"offset"`
 	Descending  bool    `json:"descending,omitempty"`
+	After       *string `json:"after,omitempty"`
 }
 
 // GetInputParams aligns with the OpenRPC specification
@@ -207,6 +209,7 @@ type ListOutputsParams struct {
 	Limit          uint64  `json:"limit"`
 	Offset         uint64  `json:"offset"`
 	Descending     bool    `json:"descending,omitempty"`
+	After          *string `json:"after,omitempty"`
 }
 
 // GetOutputParams aligns with the OpenRPC specification
@@ -223,6 +226,7 @@ type ListReportsParams struct {
 	Limit       uint64  `json:"limit"`
 	Offset      uint64  `json:"offset"`
 	Descending  bool    `json:"descending,omitempty"`
+	After       *string `json:"after,omitempty"`
 }
 
 // GetReportParams aligns with the OpenRPC specification
@@ -252,6 +256,9 @@ type DecodedInput struct {
 }
 
 func DecodeInput(input *model.Input, parsedAbi *abi.ABI) (*DecodedInput, error) {
+	if len(input.RawData) < 4 { // pruned inputs have no payload
+		return &DecodedInput{Input: input}, fmt.Errorf("raw data too short")
+	}
 	decoded := make(map[string]any)
 	err := parsedAbi.Methods["EvmAdvance"].Inputs.UnpackIntoMap(decoded, input.RawData[4:])
 	if err != nil {
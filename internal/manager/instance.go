@@ -7,19 +7,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/cartesi/rollups-node/internal/manager/pmutex"
 	. "github.com/cartesi/rollups-node/internal/model"
-	"github.com/cartesi/rollups-node/pkg/emulator"
+	"github.com/cartesi/rollups-node/internal/tracing"
+	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/semaphore"
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/internal/manager")
+
 var (
 	ErrMachineClosed          = errors.New("machine is closed")
 	ErrInvalidInputIndex      = errors.New("invalid input index")
@@ -47,6 +55,8 @@ type MachineInstanceImpl struct {
 	mutex                 *pmutex.PMutex
 	advanceMutex          sync.Mutex
 	inspectSemaphore      *semaphore.Weighted
+	// guards the inspect limit, which may change while the machine runs
+	inspectMutex sync.RWMutex
 
 	// Factory for creating machine runtimes
 	runtimeFactory MachineRuntimeFactory
@@ -81,10 +91,7 @@ func NewMachineInstanceFromSnapshot(
 	machineHash *common.Hash,
 	inputIndex uint64,
 ) (MachineInstance, error) {
-	factory := &SnapshotMachineRuntimeFactory{
-		SnapshotPath: snapshotPath,
-		MachineHash:  machineHash,
-	}
+	factory := newSnapshotFactory(snapshotPath, machineHash, inputIndex)
 	return NewMachineInstanceWithFactory(ctx, verbosity, app, inputIndex+1, logger, checkHash, factory)
 }
 
@@ -123,7 +130,7 @@ func NewMachineInstanceWithFactory(
 	// Create the machine server and runtime
 	runtime, err := factory.CreateMachineRuntime(ctx, verbosity, app, logger, checkHash)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrMachineCreation, err)
+		return nil, fmt.Errorf("%w: %w", ErrMachineCreation, err)
 	}
 
 	// Create the machine instance
@@ -154,27 +161,30 @@ func (m *MachineInstanceImpl) Synchronize(ctx context.Context, repo MachineRepos
 		"address", appAddress,
 		"processed_inputs", m.application.ProcessedInputs)
 
-	// Get all processed inputs for this application
-	inputs, _, err := getProcessedInputs(ctx, repo, appAddress)
+	// Verify that the number of inputs matches what's expected
+	count, err := countProcessedInputs(ctx, repo, appAddress)
 	if err != nil {
 		return err
 	}
-
-	// Verify that the number of inputs matches what's expected
-	if uint64(len(inputs)) != m.application.ProcessedInputs {
+	if count != m.application.ProcessedInputs {
 		errorMsg := fmt.Sprintf("processed inputs count mismatch: expected %d, got %d",
-			m.application.ProcessedInputs, len(inputs))
+			m.application.ProcessedInputs, count)
 		m.logger.Error(errorMsg, "address", appAddress)
 		return fmt.Errorf("%w: %s", ErrMachineSynchronization, errorMsg)
 	}
 
-	if len(inputs) == 0 {
+	if count == 0 {
 		m.logger.Info("No previous processed inputs to synchronize", "address", appAddress)
 		return nil
 	}
 
-	// Process each input to bring the machine to the current state
-	for _, input := range inputs {
+	// Process each input to bring the machine to the current state,
+	// fetching them from the repository one page at a time
+	for input, err := range streamProcessedInputs(ctx, repo, appAddress, nil) {
+		if err != nil {
+			return err
+		}
+
 		m.logger.Info("Replaying input during synchronization",
 			"address", appAddress,
 			"epoch_index", input.EpochIndex,
@@ -210,13 +220,17 @@ func (m *MachineInstanceImpl) forkForAdvance(ctx context.Context, index uint64)
 }
 
 // Advance processes an input and advances the machine state
-func (m *MachineInstanceImpl) Advance(ctx context.Context, input []byte, index uint64) (*AdvanceResult, error) {
+func (m *MachineInstanceImpl) Advance(ctx context.Context, input []byte, index uint64) (_ *AdvanceResult, err error) {
+	ctx, span := tracing.Start(ctx, tracer, "Advance",
+		tracing.ApplicationAddress(m.application.IApplicationAddress),
+		tracing.InputIndex(index))
+	defer func() { tracing.End(span, err) }()
+
 	// Only one advance can be active at a time
 	m.advanceMutex.Lock()
 	defer m.advanceMutex.Unlock()
 
 	var fork rollupsmachine.RollupsMachine
-	var err error
 
 	// Fork the machine
 	fork, err = m.forkForAdvance(ctx, index)
@@ -235,24 +249,39 @@ func (m *MachineInstanceImpl) Advance(ctx context.Context, input []byte, index u
 		return nil, errors.Join(err, fork.Close(ctx))
 	}
 
+	prevCycle, err := fork.Cycle(ctx)
+	if err != nil {
+		return nil, errors.Join(err, fork.Close(ctx))
+	}
+
 	// Create a timeout context for the advance operation
 	advanceCtx, cancel := context.WithTimeout(ctx, m.advanceTimeout)
 	defer cancel()
 
 	// Process the input
+	start := time.Now()
 	accepted, outputs, reports, outputsHash, err := fork.Advance(advanceCtx, input)
+	executionTime := time.Since(start)
 	status, err := toInputStatus(accepted, err)
 	if err != nil {
 		return nil, errors.Join(err, fork.Close(ctx))
 	}
 
+	cycle, err := fork.Cycle(ctx)
+	if err != nil {
+		return nil, errors.Join(err, fork.Close(ctx))
+	}
+	span.SetAttributes(attribute.Int64("cartesi.machine.cycles", int64(cycle-prevCycle))) // nolint: gosec
+
 	// Create the result
 	result := &AdvanceResult{
-		InputIndex:  index,
-		Status:      status,
-		Outputs:     outputs,
-		Reports:     reports,
-		OutputsHash: outputsHash,
+		InputIndex:    index,
+		Status:        status,
+		Outputs:       outputs,
+		Reports:       reports,
+		OutputsHash:   outputsHash,
+		Cycles:        cycle - prevCycle,
+		ExecutionTime: executionTime,
 	}
 
 	// If the input was accepted, update the machine state
@@ -290,6 +319,61 @@ func (m *MachineInstanceImpl) Advance(ctx context.Context, input []byte, index u
 	return result, err
 }
 
+// ProcessedInputs returns how many inputs were processed by the machine
+func (m *MachineInstanceImpl) ProcessedInputs() uint64 {
+	m.mutex.LLock()
+	defer m.mutex.Unlock()
+	return m.processedInputs
+}
+
+// Ping checks the machine server is responsive. A machine busy advancing or
+// storing a snapshot is reported healthy, instead of waiting for it; those
+// operations have their own deadlines.
+func (m *MachineInstanceImpl) Ping(ctx context.Context) error {
+	if !m.mutex.TryLLock() {
+		return nil
+	}
+	defer m.mutex.Unlock()
+
+	if m.runtime == nil {
+		return ErrMachineClosed
+	}
+	_, err := m.runtime.Cycle(ctx)
+	return err
+}
+
+// Hash returns the hash of the current state of the machine
+func (m *MachineInstanceImpl) Hash(ctx context.Context) (common.Hash, error) {
+	m.mutex.LLock()
+	defer m.mutex.Unlock()
+
+	if m.runtime == nil {
+		return common.Hash{}, ErrMachineClosed
+	}
+	hash, err := m.runtime.Hash(ctx)
+	return common.Hash(hash), err
+}
+
+// SetMaxConcurrentInspects changes how many inspects the machine runs at the
+// same time. The inspects in progress, or already waiting, are counted by the
+// previous limit until they finish.
+func (m *MachineInstanceImpl) SetMaxConcurrentInspects(limit uint32) error {
+	if limit == 0 {
+		return ErrInvalidConcurrentLimit
+	}
+	m.inspectMutex.Lock()
+	defer m.inspectMutex.Unlock()
+	if limit == m.maxConcurrentInspects {
+		return nil
+	}
+
+	m.inspectSemaphore = semaphore.NewWeighted(int64(limit))
+	m.logger.Info("Changed the limit of concurrent inspects",
+		"from", m.maxConcurrentInspects, "to", limit)
+	m.maxConcurrentInspects = limit
+	return nil
+}
+
 // forkForInspect creates a copy of the machine for inspect operations
 // It returns the forked machine and the current processed inputs count
 func (m *MachineInstanceImpl) forkForInspect(ctx context.Context) (rollupsmachine.RollupsMachine, uint64, error) {
@@ -312,11 +396,13 @@ func (m *MachineInstanceImpl) forkForInspect(ctx context.Context) (rollupsmachin
 // Inspect queries the machine state without modifying it
 func (m *MachineInstanceImpl) Inspect(ctx context.Context, query []byte) (*InspectResult, error) {
 	// Limit concurrent inspects
-	err := m.inspectSemaphore.Acquire(ctx, 1)
-	if err != nil {
+	m.inspectMutex.RLock()
+	inspectSemaphore := m.inspectSemaphore
+	m.inspectMutex.RUnlock()
+	if err := inspectSemaphore.Acquire(ctx, 1); err != nil {
 		return nil, err
 	}
-	defer m.inspectSemaphore.Release(1)
+	defer inspectSemaphore.Release(1)
 
 	// Fork the machine (without index validation)
 	fork, processedInputs, err := m.forkForInspect(ctx)
@@ -353,7 +439,9 @@ func (m *MachineInstanceImpl) Inspect(ctx context.Context, query []byte) (*Inspe
 	return result, nil
 }
 
-// CreateSnapshot creates a snapshot of the machine's current state
+// CreateSnapshot creates a snapshot of the machine's current state, with its
+// manifest. The snapshot is written to a temporary directory and moved to
+// path once complete, so path never holds a partial snapshot.
 func (m *MachineInstanceImpl) CreateSnapshot(ctx context.Context, processedInputs uint64, path string) error {
 	// Acquire the advance mutex to ensure no advance operations are in progress
 	m.advanceMutex.Lock()
@@ -368,7 +456,7 @@ func (m *MachineInstanceImpl) CreateSnapshot(ctx context.Context, processedInput
 	}
 
 	// Verify processed inputs
-	if m.processedInputs != processedInputs {
+	if m.processedInputs != processedInputs || processedInputs == 0 {
 		return fmt.Errorf("%w: machine processed inputs is %d and expected is %d", ErrInvalidSnapshotPoint, m.processedInputs, processedInputs)
 	}
 
@@ -378,13 +466,49 @@ func (m *MachineInstanceImpl) CreateSnapshot(ctx context.Context, processedInput
 	storeCtx, cancel := context.WithTimeout(ctx, m.application.ExecutionParameters.StoreDeadline)
 	defer cancel()
 
-	// Store the machine state to the specified path
-	err := m.runtime.Store(storeCtx, path)
+	machineHash, err := m.runtime.Hash(storeCtx)
+	if err != nil {
+		return err
+	}
+	outputsHash, err := m.runtime.OutputsHash(storeCtx)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "snapshot")
+
+	// Store the machine state to the temporary path
+	err = m.runtime.Store(storeCtx, tmpPath)
 	if err != nil {
 		m.logger.Error("Failed to create snapshot", "path", path, "error", err)
 		return err
 	}
 
+	err = writeSnapshotManifest(tmpPath, &SnapshotManifest{
+		Application: m.application.IApplicationAddress,
+		InputIndex:  processedInputs - 1,
+		MachineHash: common.Hash(machineHash),
+		OutputsHash: common.Hash(outputsHash),
+		NodeVersion: version.BuildVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+
+	// Replace the snapshot left at path, by a retake after a crash for instance
+	err = os.Rename(path, filepath.Join(tmpDir, "previous"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to move previous snapshot away: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to move snapshot into place: %w", err)
+	}
+
 	m.logger.Debug("Snapshot created successfully", "path", path)
 	return nil
 }
@@ -396,6 +520,8 @@ func (m *MachineInstanceImpl) Close() error {
 	defer m.advanceMutex.Unlock()
 
 	ctx := context.Background()
+	m.inspectMutex.Lock()
+	defer m.inspectMutex.Unlock()
 	for range int(m.maxConcurrentInspects) {
 		_ = m.inspectSemaphore.Acquire(ctx, 1)
 		defer m.inspectSemaphore.Release(1)
@@ -446,35 +572,22 @@ func createMachineRuntimeCommon(
 		"address", appAddress,
 		"path", machinePath)
 
-	// Start the machine server
-	// TODO(mpolitzer): this needs a refactoring to:
-	// - store the `server` state itself (no need to reconnect via address).
-	// - store the server `pid` may be needed to kill misbehaving servers.
-	// - fit the call in the right abstraction layer `emulator` vs `cartesimachine`.
-	server, address, pid, err := emulator.SpawnServer("127.0.0.1:0", app.ExecutionParameters.FastDeadline)
+	// Start the machine server, logging its output, and load the machine
+	serverLogger := logger.With("application", app.Name)
+	machine, err := cartesimachine.Spawn(ctx, machinePath, nil, &app.ExecutionParameters,
+		logger, verbosity,
+		serverLogWriter{logger: serverLogger, stream: "stdout"},
+		serverLogWriter{logger: serverLogger, stream: "stderr"})
 	if err != nil {
 		return nil, err
 	}
-	_ = server
-	_ = pid
-
-	// Load the machine
-	logger.Info(fmt.Sprintf("Loading machine from %s", sourceType),
-		"application", app.Name,
-		"address", appAddress,
-		"remote-machine", address,
-		"path", machinePath)
-
-	// Create the machine
-	machine, err := cartesimachine.Load(ctx, machinePath, address, nil, &app.ExecutionParameters)
-	if err != nil {
-		return nil, errors.Join(err, cartesimachine.StopServer(address, logger, &app.ExecutionParameters))
-	}
+	address := machine.Address()
 
 	logger.Debug(fmt.Sprintf("Machine loaded from %s", sourceType),
 		"application", app.Name,
 		"address", appAddress,
 		"remote-machine", address,
+		"pid", machine.Pid(),
 		"path", machinePath)
 
 	// Verify the machine hash if required
@@ -485,7 +598,7 @@ func createMachineRuntimeCommon(
 
 		machineHash, err := machine.ReadHash(ctx)
 		if err != nil {
-			return nil, errors.Join(err, cartesimachine.StopServer(address, logger, &app.ExecutionParameters))
+			return nil, errors.Join(err, machine.Close(ctx))
 		}
 
 		if machineHash != expectedHash {
@@ -512,7 +625,7 @@ func createMachineRuntimeCommon(
 		return nil, errors.Join(err, machine.Close(ctx))
 	}
 
-	return runtime, nil
+	return newTrackedMachine(runtime, machineServers, app.Name, address), nil
 }
 
 // DefaultMachineRuntimeFactory is the standard implementation of MachineRuntimeFactory
@@ -542,6 +655,7 @@ func (f *DefaultMachineRuntimeFactory) CreateMachineRuntime(
 type SnapshotMachineRuntimeFactory struct {
 	SnapshotPath string
 	MachineHash  *common.Hash // The hash to check against (from the input's machine_hash)
+	InputIndex   uint64       // The last input processed by the machine in the snapshot
 }
 
 // CreateMachineRuntime creates a new machine runtime from a snapshot
@@ -552,6 +666,18 @@ func (f *SnapshotMachineRuntimeFactory) CreateMachineRuntime(
 	logger *slog.Logger,
 	checkHash bool,
 ) (rollupsmachine.RollupsMachine, error) {
+	// Refuse snapshots that are incomplete, corrupt or from another point.
+	// The ones taken before manifests were written are only loaded if the
+	// machine has the hash stored with their input.
+	manifest, err := ReadSnapshotManifest(f.SnapshotPath)
+	if errors.Is(err, ErrMissingManifest) && f.MachineHash != nil {
+		checkHash = true
+	} else if err != nil {
+		return nil, err
+	} else if err = manifest.Validate(f.SnapshotPath, app, f.InputIndex, f.MachineHash); err != nil {
+		return nil, err
+	}
+
 	// Determine which hash to check against
 	expectedHash := app.TemplateHash
 	if f.MachineHash != nil {
@@ -573,6 +699,15 @@ func (f *SnapshotMachineRuntimeFactory) CreateMachineRuntime(
 // Default factory instance
 var defaultFactory MachineRuntimeFactory = &DefaultMachineRuntimeFactory{}
 
+// Creates the factory of the machine runtimes loaded from a snapshot
+var newSnapshotFactory = func(snapshotPath string, machineHash *common.Hash, inputIndex uint64) MachineRuntimeFactory {
+	return &SnapshotMachineRuntimeFactory{
+		SnapshotPath: snapshotPath,
+		MachineHash:  machineHash,
+		InputIndex:   inputIndex,
+	}
+}
+
 // Helper function to convert machine response to input status
 func toInputStatus(accepted bool, err error) (status InputCompletionStatus, _ error) {
 	if err == nil {
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -217,6 +219,8 @@ func (s *MachineInstanceSuite) TestAdvance() {
 		s.Run("Accept", func() {
 			require := s.Require()
 			_, fork, machine := s.setupAdvance()
+			fork.CycleReturn = 1000
+			fork.AdvanceCyclesReturn = 42
 
 			res, err := machine.Advance(context.Background(), []byte{}, 5)
 			require.Nil(err)
@@ -228,6 +232,7 @@ func (s *MachineInstanceSuite) TestAdvance() {
 			require.Equal(expectedReports1, res.Reports)
 			require.Equal(newHash(1), res.OutputsHash)
 			require.Equal(newHash(2), *res.MachineHash)
+			require.Equal(uint64(42), res.Cycles)
 			require.Equal(uint64(6), machine.processedInputs)
 		})
 
@@ -538,9 +543,49 @@ func (s *MachineInstanceSuite) TestCreateSnapshot() {
 		require := s.Require()
 		inner, _, machine := s.setupAdvance()
 		inner.CloseError = nil
+		inner.HashReturn = newHash(2)
+		machine.application.IApplicationAddress = common.HexToAddress("0x1")
 
-		err := machine.CreateSnapshot(context.Background(), 5, "/tmp/snapshot")
+		dir := s.T().TempDir()
+		snapshotPath := filepath.Join(dir, "snapshot")
+		err := machine.CreateSnapshot(context.Background(), 5, snapshotPath)
+		require.Nil(err)
+
+		manifest, err := ReadSnapshotManifest(snapshotPath)
+		require.Nil(err)
+		require.Equal(common.HexToAddress("0x1"), manifest.Application)
+		require.Equal(uint64(4), manifest.InputIndex)
+		require.Equal(common.Hash(newHash(2)), manifest.MachineHash)
+		require.Contains(manifest.Files, "config.json")
+		hash := common.Hash(newHash(2))
+		require.Nil(manifest.Validate(snapshotPath, machine.application, 4, &hash))
+
+		// only the snapshot is left
+		entries, err := os.ReadDir(dir)
+		require.Nil(err)
+		require.Len(entries, 1)
+	})
+
+	s.Run("ReplacesExisting", func() {
+		require := s.Require()
+		inner, _, machine := s.setupAdvance()
+		inner.CloseError = nil
+		inner.HashReturn = newHash(2)
+
+		dir := s.T().TempDir()
+		snapshotPath := filepath.Join(dir, "snapshot")
+		require.Nil(machine.CreateSnapshot(context.Background(), 5, snapshotPath))
+
+		// retaken after a crash, before the snapshot was recorded
+		inner.HashReturn = newHash(3)
+		require.Nil(machine.CreateSnapshot(context.Background(), 5, snapshotPath))
+
+		manifest, err := ReadSnapshotManifest(snapshotPath)
+		require.Nil(err)
+		require.Equal(common.Hash(newHash(3)), manifest.MachineHash)
+		entries, err := os.ReadDir(dir)
 		require.Nil(err)
+		require.Len(entries, 1)
 	})
 
 	s.Run("Error", func() {
@@ -573,6 +618,81 @@ func (s *MachineInstanceSuite) TestCreateSnapshot() {
 	})
 }
 
+func (s *MachineInstanceSuite) TestSetMaxConcurrentInspects() {
+	s.Run("Ok", func() {
+		require := s.Require()
+		_, _, machine := s.setupInspect()
+
+		err := machine.SetMaxConcurrentInspects(1)
+		require.Nil(err)
+		require.Equal(uint32(1), machine.maxConcurrentInspects)
+
+		// the new limit is enforced
+		require.True(machine.inspectSemaphore.TryAcquire(1))
+		ctx, cancel := context.WithTimeout(context.Background(), centisecond)
+		defer cancel()
+		_, err = machine.Inspect(ctx, []byte{})
+		require.ErrorIs(err, context.DeadlineExceeded)
+	})
+
+	s.Run("DoesNotWaitForInspects", func() {
+		require := s.Require()
+		_, _, machine := s.setupInspect()
+
+		// every inspect slot is taken, and another inspect waits for one
+		previous := machine.inspectSemaphore
+		require.True(previous.TryAcquire(3))
+		waiting := make(chan error)
+		go func() {
+			_, err := machine.Inspect(context.Background(), []byte{})
+			waiting <- err
+		}()
+		time.Sleep(centisecond)
+
+		require.Nil(machine.SetMaxConcurrentInspects(1))
+		require.Equal(uint32(1), machine.maxConcurrentInspects)
+		require.NotSame(previous, machine.inspectSemaphore)
+
+		// the inspects in progress release the previous limit
+		previous.Release(3)
+		require.Nil(<-waiting)
+		require.True(machine.inspectSemaphore.TryAcquire(1))
+	})
+
+	s.Run("ErrInvalidConcurrentLimit", func() {
+		require := s.Require()
+		_, _, machine := s.setupInspect()
+
+		err := machine.SetMaxConcurrentInspects(0)
+		require.ErrorIs(err, ErrInvalidConcurrentLimit)
+		require.Equal(uint32(3), machine.maxConcurrentInspects)
+	})
+}
+
+func (s *MachineInstanceSuite) TestPing() {
+	s.Run("Ok", func() {
+		_, _, machine := s.setupAdvance()
+		s.Require().Nil(machine.Ping(context.Background()))
+	})
+
+	s.Run("Busy", func() {
+		require := s.Require()
+		_, _, machine := s.setupAdvance()
+		machine.runtime = nil
+
+		// an advance or a snapshot in progress
+		machine.mutex.HLock()
+		defer machine.mutex.Unlock()
+		require.Nil(machine.Ping(context.Background()))
+	})
+
+	s.Run("MachineClosed", func() {
+		_, _, machine := s.setupAdvance()
+		machine.runtime = nil
+		s.Require().ErrorIs(machine.Ping(context.Background()), ErrMachineClosed)
+	})
+}
+
 func (s *MachineInstanceSuite) TestClose() {
 	s.Run("Ok", func() {
 		require := s.Require()
@@ -632,7 +752,8 @@ func (s *MachineInstanceSuite) TestClose() {
 
 // MockMachineInstance implements the MachineInstance interface for testing
 type MockMachineInstance struct {
-	application *model.Application
+	application     *model.Application
+	processedInputs uint64
 }
 
 func (m *MockMachineInstance) Application() *model.Application {
@@ -655,6 +776,22 @@ func (m *MockMachineInstance) CreateSnapshot(ctx context.Context, processedInput
 	return nil
 }
 
+func (m *MockMachineInstance) ProcessedInputs() uint64 {
+	return m.processedInputs
+}
+
+func (m *MockMachineInstance) Hash(ctx context.Context) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+func (m *MockMachineInstance) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *MockMachineInstance) SetMaxConcurrentInspects(limit uint32) error {
+	return nil
+}
+
 func (m *MockMachineInstance) Close() error {
 	return nil
 }
@@ -815,8 +952,11 @@ type MockRollupsMachine struct {
 	AdvanceOutputsReturn  []rollupsmachine.Output
 	AdvanceReportsReturn  []rollupsmachine.Report
 	AdvanceHashReturn     rollupsmachine.Hash
+	AdvanceCyclesReturn   rollupsmachine.Cycle
 	AdvanceError          error
 
+	CycleReturn rollupsmachine.Cycle
+
 	InspectAcceptedReturn bool
 	InspectReportsReturn  []rollupsmachine.Report
 	InspectError          error
@@ -838,9 +978,14 @@ func (machine *MockRollupsMachine) OutputsHash(_ context.Context) (rollupsmachin
 	return machine.AdvanceHashReturn, machine.HashError
 }
 
+func (machine *MockRollupsMachine) Cycle(_ context.Context) (rollupsmachine.Cycle, error) {
+	return machine.CycleReturn, nil
+}
+
 func (machine *MockRollupsMachine) Advance(_ context.Context, input []byte) (
 	bool, []rollupsmachine.Output, []rollupsmachine.Report, rollupsmachine.Hash, error,
 ) {
+	machine.CycleReturn += machine.AdvanceCyclesReturn
 	return machine.AdvanceAcceptedReturn,
 		machine.AdvanceOutputsReturn,
 		machine.AdvanceReportsReturn,
@@ -854,10 +999,20 @@ func (machine *MockRollupsMachine) Inspect(_ context.Context,
 	return machine.InspectAcceptedReturn, machine.InspectReportsReturn, machine.InspectError
 }
 
-func (machine *MockRollupsMachine) Store(_ context.Context, _ string) error {
-	return machine.StoreError
+func (machine *MockRollupsMachine) Store(_ context.Context, path string) error {
+	if machine.StoreError != nil {
+		return machine.StoreError
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(path, "config.json"), []byte("{}"), 0644)
 }
 
 func (machine *MockRollupsMachine) Close(_ context.Context) error {
 	return machine.CloseError
 }
+
+func (machine *MockRollupsMachine) Pid() uint32 {
+	return 0
+}
@@ -7,18 +7,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"sync"
 
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/snapshot"
 )
 
 var (
 	ErrApplicationNotFound    = errors.New("application not found")
 	ErrMachineCreation        = errors.New("failed to create machine")
 	ErrMachineSynchronization = errors.New("failed to synchronize machine")
+	ErrMachineHashMismatch    = errors.New("machine hash mismatch")
 )
 
 // MachineRepository defines the repository interface needed by the MachineManager
@@ -29,8 +33,8 @@ type MachineRepository interface {
 	// ListInputs retrieves inputs based on filter criteria
 	ListInputs(ctx context.Context, nameOrAddress string, f repository.InputFilter, p repository.Pagination, descending bool) ([]*Input, uint64, error)
 
-	// GetLastSnapshot retrieves the most recent input with a snapshot for the given application
-	GetLastSnapshot(ctx context.Context, nameOrAddress string) (*Input, error)
+	// ListSnapshots retrieves the inputs with a snapshot for the given application, the most recent first
+	ListSnapshots(ctx context.Context, nameOrAddress string) ([]*Input, error)
 }
 
 // MachineManager manages the lifecycle of machine instances for applications
@@ -41,6 +45,9 @@ type MachineManager struct {
 	verbosity  MachineLogLevel
 	checkHash  bool
 	logger     *slog.Logger
+	// remote snapshots are downloaded from snapshotStorage to snapshotsDir
+	snapshotStorage snapshot.Storage
+	snapshotsDir    string
 }
 
 // NewMachineManager creates a new machine manager
@@ -60,8 +67,28 @@ func NewMachineManager(
 	}
 }
 
-// UpdateMachines refreshes the list of machines based on enabled applications
-func (m *MachineManager) UpdateMachines(ctx context.Context) error {
+// SetSnapshotStorage makes the manager load the snapshots with remote URIs
+// from storage, keeping a copy of each in the cache directory
+func (m *MachineManager) SetSnapshotStorage(storage snapshot.Storage, cacheDir string) {
+	m.snapshotStorage = storage
+	m.snapshotsDir = cacheDir
+}
+
+// SetServersDir makes the manager keep a pidfile of each machine server in
+// dir, and kills the orphan servers left there by a previous run
+func (m *MachineManager) SetServersDir(dir string) error {
+	return machineServers.setDir(dir, m.logger)
+}
+
+// Servers lists the machine servers that are running
+func (m *MachineManager) Servers() []MachineServer {
+	return machineServers.list()
+}
+
+// UpdateMachines refreshes the list of machines based on enabled applications.
+// The machines of the applications in busy are neither rebuilt nor removed,
+// they are left to a later update.
+func (m *MachineManager) UpdateMachines(ctx context.Context, busy map[int64]struct{}) error {
 	// Get all enabled applications
 	apps, _, err := getEnabledApplications(ctx, m.repository)
 	if err != nil {
@@ -70,116 +97,268 @@ func (m *MachineManager) UpdateMachines(ctx context.Context) error {
 
 	// Create machines for new applications
 	for _, app := range apps {
-		if m.HasMachine(app.ID) {
+		if _, ok := busy[app.ID]; ok {
 			continue
 		}
+		if machine, exists := m.GetMachine(app.ID); exists {
+			if machine.ProcessedInputs() <= app.ProcessedInputs {
+				continue
+			}
+			// The inputs were reverted by a chain reorganization after the
+			// machine processed them, so it is rebuilt from an earlier snapshot
+			m.logger.Warn("Machine is ahead of the processed inputs, rebuilding it",
+				"application", app.Name,
+				"machine_processed_inputs", machine.ProcessedInputs(),
+				"processed_inputs", app.ProcessedInputs)
+			m.removeMachine(app.ID)
+		}
 
 		m.logger.Info("Creating new machine instance",
 			"application", app.Name,
 			"address", app.IApplicationAddress.String())
 
-		// Check if we have a snapshot to load from
-		var instance MachineInstance
-		var err error
-
-		// Find the latest snapshot for this application
-		snapshot, err := m.repository.GetLastSnapshot(ctx, app.IApplicationAddress.String())
+		instance, err := m.createMachine(ctx, app)
 		if err != nil {
-			m.logger.Error("Failed to find latest snapshot",
+			m.logger.Error("Failed to create machine instance",
 				"application", app.Name,
+				"address", app.IApplicationAddress,
 				"error", err)
-			// Continue with template-based initialization
+			continue
 		}
 
-		if snapshot != nil && snapshot.SnapshotURI != nil {
-			// Create a machine instance from the snapshot
-			m.logger.Info("Creating machine instance from snapshot",
-				"application", app.Name,
-				"snapshot", *snapshot.SnapshotURI)
-
-			// Verify the snapshot path exists
-			if _, err := os.Stat(*snapshot.SnapshotURI); os.IsNotExist(err) {
-				m.logger.Error("Snapshot path does not exist",
-					"application", app.Name,
-					"snapshot", *snapshot.SnapshotURI,
-					"error", err)
-				// Fall back to template-based initialization
-			} else {
-				// Create a factory with the snapshot path and machine hash
-				instance, err = NewMachineInstanceFromSnapshot(
-					ctx, m.verbosity, app, m.logger, m.checkHash, *snapshot.SnapshotURI, snapshot.MachineHash, snapshot.Index)
-
-				if err != nil {
-					m.logger.Error("Failed to create machine instance from snapshot",
-						"application", app.Name,
-						"snapshot", *snapshot.SnapshotURI,
-						"error", err)
-					// Fall back to template-based initialization
-				} else {
-					// If we loaded from a snapshot, we need to synchronize from the snapshot point
-					// Get the inputs after the snapshot
-					inputsAfterSnapshot, err := getInputsAfterSnapshot(ctx, m.repository, app, snapshot.Index)
-					if err != nil {
-						m.logger.Error("Failed to get inputs after snapshot",
-							"application", app.Name,
-							"snapshot_input_index", snapshot.Index,
-							"error", err)
-						instance.Close()
-						continue
-					}
-
-					// Process each input to bring the machine to the current state
-					for _, input := range inputsAfterSnapshot {
-						m.logger.Info("Replaying input after snapshot",
-							"application", app.Name,
-							"epoch_index", input.EpochIndex,
-							"input_index", input.Index)
-
-						_, err := instance.Advance(ctx, input.RawData, input.Index)
-						if err != nil {
-							m.logger.Error("Failed to replay input after snapshot",
-								"application", app.Name,
-								"input_index", input.Index,
-								"error", err)
-							instance.Close()
-							continue
-						}
-					}
-
-					// Add the machine to the manager
-					m.addMachine(app.ID, instance)
-					continue
-				}
-			}
+		// Add the machine to the manager
+		if !m.addMachine(app.ID, instance) {
+			instance.Close()
 		}
+	}
+
+	// Remove machines for disabled applications
+	m.removeMachines(apps, busy)
+
+	return nil
+}
+
+// RecoverMachine rebuilds the machine of app, which is not enabled yet, and
+// keeps it once its hash matches the one after the last processed input, or
+// the template hash if there is none. The machine is removed by the next
+// update if app is not enabled by then.
+func (m *MachineManager) RecoverMachine(ctx context.Context, app *Application) error {
+	m.removeMachine(app.ID)
+
+	m.logger.Info("Recovering machine instance",
+		"application", app.Name,
+		"address", app.IApplicationAddress.String())
+
+	instance, err := m.createMachine(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	expected := app.TemplateHash
+	inputs, _, err := m.repository.ListInputs(ctx, app.IApplicationAddress.String(),
+		repository.InputFilter{NotStatus: Pointer(InputCompletionStatus_None)},
+		repository.Pagination{Limit: 1}, true)
+	if err != nil {
+		instance.Close()
+		return err
+	}
+	if len(inputs) > 0 && inputs[0].MachineHash != nil {
+		expected = *inputs[0].MachineHash
+	}
 
-		// If we didn't load from a snapshot, create a new machine instance from the template
-		instance, err = NewMachineInstance(ctx, m.verbosity, app, m.logger, m.checkHash)
+	hash, err := instance.Hash(ctx)
+	if err != nil {
+		instance.Close()
+		return err
+	}
+	if hash != expected {
+		instance.Close()
+		return fmt.Errorf("%w: expected %v, got %v", ErrMachineHashMismatch, expected, hash)
+	}
+
+	if !m.addMachine(app.ID, instance) {
+		// another machine was created meanwhile
+		instance.Close()
+	}
+	return nil
+}
+
+// createMachine creates the machine of app from its most recent snapshot that
+// works or, failing that, from the template, and replays the inputs it has
+// not processed
+func (m *MachineManager) createMachine(ctx context.Context, app *Application) (MachineInstance, error) {
+	// Load the machine from the most recent snapshot that works
+	instance := m.loadFromSnapshots(ctx, app)
+	if instance != nil {
+		return instance, nil
+	}
+
+	// If we didn't load from a snapshot, create a new machine instance from the template
+	instance, err := NewMachineInstance(ctx, m.verbosity, app, m.logger, m.checkHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Synchronize the machine with processed inputs
+	err = instance.Synchronize(ctx, m.repository)
+	if err != nil {
+		instance.Close()
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Reload applies the execution parameters of the enabled applications that
+// may change while their machines run: the limit of concurrent inspects
+func (m *MachineManager) Reload(ctx context.Context) error {
+	apps, _, err := getEnabledApplications(ctx, m.repository)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, app := range apps {
+		machine, exists := m.GetMachine(app.ID)
+		if !exists {
+			continue
+		}
+		err := machine.SetMaxConcurrentInspects(app.ExecutionParameters.MaxConcurrentInspects)
 		if err != nil {
-			m.logger.Error("Failed to create machine instance",
-				"application", app.IApplicationAddress,
+			errs = append(errs, fmt.Errorf("application %v: %w", app.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadFromSnapshots creates the machine of app from its most recent snapshot
+// and replays the inputs processed after it. If that fails, it falls back to
+// the older snapshots in order. It returns nil if no snapshot could be loaded.
+func (m *MachineManager) loadFromSnapshots(ctx context.Context, app *Application) MachineInstance {
+	snapshots, err := m.repository.ListSnapshots(ctx, app.IApplicationAddress.String())
+	if err != nil {
+		m.logger.Error("Failed to list snapshots",
+			"application", app.Name,
+			"error", err)
+		// Continue with template-based initialization
+		return nil
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotURI == nil {
+			continue
+		}
+		instance, err := m.loadFromSnapshot(ctx, app, snapshot)
+		if err != nil {
+			m.logger.Error("Failed to create machine instance from snapshot",
+				"application", app.Name,
+				"snapshot", *snapshot.SnapshotURI,
 				"error", err)
+			// Fall back to an older snapshot
 			continue
 		}
+		return instance
+	}
+	return nil
+}
+
+// loadFromSnapshot creates the machine of app from snapshot and replays the
+// inputs processed after it
+func (m *MachineManager) loadFromSnapshot(
+	ctx context.Context,
+	app *Application,
+	snapshot *Input,
+) (MachineInstance, error) {
+	m.logger.Info("Creating machine instance from snapshot",
+		"application", app.Name,
+		"snapshot", *snapshot.SnapshotURI)
+
+	snapshotPath, cached, err := m.localSnapshot(ctx, *snapshot.SnapshotURI)
+	if err != nil {
+		return nil, err
+	}
 
-		// Synchronize the machine with processed inputs
-		err = instance.Synchronize(ctx, m.repository)
+	instance, err := NewMachineInstanceFromSnapshot(
+		ctx, m.verbosity, app, m.logger, m.checkHash, snapshotPath, snapshot.MachineHash, snapshot.Index)
+	if errors.Is(err, ErrInvalidSnapshot) && cached {
+		// The cached copy is damaged, so it is downloaded again
+		m.logger.Warn("Cached snapshot is invalid, downloading it again",
+			"application", app.Name,
+			"path", snapshotPath,
+			"error", err)
+		if err := os.RemoveAll(snapshotPath); err != nil {
+			return nil, err
+		}
+		if snapshotPath, _, err = m.localSnapshot(ctx, *snapshot.SnapshotURI); err != nil {
+			return nil, err
+		}
+		instance, err = NewMachineInstanceFromSnapshot(
+			ctx, m.verbosity, app, m.logger, m.checkHash, snapshotPath, snapshot.MachineHash, snapshot.Index)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the inputs processed after the snapshot, one page at a time
+	inputs := streamProcessedInputs(ctx, m.repository, app.IApplicationAddress.String(), &snapshot.Index)
+	for input, err := range inputs {
 		if err != nil {
-			m.logger.Error("Failed to synchronize machine",
-				"application", app.IApplicationAddress,
-				"error", err)
 			instance.Close()
-			continue
+			return nil, fmt.Errorf("failed to get inputs after snapshot: %w", err)
 		}
 
-		// Add the machine to the manager
-		m.addMachine(app.ID, instance)
+		m.logger.Info("Replaying input after snapshot",
+			"application", app.Name,
+			"epoch_index", input.EpochIndex,
+			"input_index", input.Index)
+
+		_, err := instance.Advance(ctx, input.RawData, input.Index)
+		if err != nil {
+			instance.Close()
+			return nil, fmt.Errorf("failed to replay input %d after snapshot: %w", input.Index, err)
+		}
 	}
+	return instance, nil
+}
 
-	// Remove machines for disabled applications
-	m.removeMachines(apps)
+// localSnapshot returns the local path of the snapshot at uri and whether it
+// is a copy of a remote snapshot found in the cache. Remote snapshots missing
+// from the cache are downloaded to it first.
+func (m *MachineManager) localSnapshot(ctx context.Context, uri string) (string, bool, error) {
+	if !snapshot.IsRemote(uri) {
+		// Verify the snapshot path exists
+		if _, err := os.Stat(uri); err != nil {
+			return "", false, fmt.Errorf("snapshot path is not accessible: %w", err)
+		}
+		return uri, false, nil
+	}
+	if m.snapshotStorage == nil {
+		return "", false, fmt.Errorf("no remote snapshot storage configured for %s", uri)
+	}
 
-	return nil
+	cachePath := filepath.Join(m.snapshotsDir, snapshot.Name(uri))
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, true, nil
+	}
+
+	m.logger.Info("Downloading snapshot", "uri", uri, "path", cachePath)
+	if err := os.MkdirAll(m.snapshotsDir, 0755); err != nil { // nolint: mnd
+		return "", false, fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+	// Download to a temporary directory first, so an interrupted download is
+	// never taken for a cached snapshot
+	tmpDir, err := os.MkdirTemp(m.snapshotsDir, filepath.Base(cachePath)+".tmp-*")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "snapshot")
+	if err := m.snapshotStorage.Download(ctx, uri, tmpPath); err != nil {
+		return "", false, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		return "", false, err
+	}
+	return cachePath, false, nil
 }
 
 // GetMachine retrieves a machine instance for an application
@@ -212,7 +391,7 @@ func (m *MachineManager) addMachine(appID int64, machine MachineInstance) bool {
 }
 
 // RemoveMachines removes machines for applications not in the provided list
-func (m *MachineManager) removeMachines(apps []*Application) {
+func (m *MachineManager) removeMachines(apps []*Application, busy map[int64]struct{}) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -224,6 +403,9 @@ func (m *MachineManager) removeMachines(apps []*Application) {
 
 	// Remove machines for applications not in the active list
 	for id, machine := range m.machines {
+		if _, ok := busy[id]; ok {
+			continue
+		}
 		if _, present := activeApps[id]; !present {
 			if m.logger != nil {
 				m.logger.Info("Application was disabled, shutting down machine",
@@ -235,6 +417,17 @@ func (m *MachineManager) removeMachines(apps []*Application) {
 	}
 }
 
+// removeMachine shuts down and removes the machine of an application
+func (m *MachineManager) removeMachine(appID int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if machine, exists := m.machines[appID]; exists {
+		machine.Close()
+		delete(m.machines, appID)
+	}
+}
+
 // Applications returns the list of applications with active machines
 func (m *MachineManager) Applications() []*Application {
 	m.mutex.RLock()
@@ -260,6 +453,11 @@ func (m *MachineManager) Close() error {
 		delete(m.machines, id)
 	}
 
+	// kill the servers of forks that were not closed
+	if err := machineServers.killAll(); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -272,25 +470,33 @@ func getEnabledApplications(ctx context.Context, repo MachineRepository) ([]*App
 	return repo.ListApplications(ctx, f, repository.Pagination{}, false)
 }
 
-// Helper function to get processed inputs
-func getProcessedInputs(ctx context.Context, repo MachineRepository, appAddress string) ([]*Input, uint64, error) {
+// Helper function to count the processed inputs
+func countProcessedInputs(ctx context.Context, repo MachineRepository, appAddress string) (uint64, error) {
 	f := repository.InputFilter{NotStatus: Pointer(InputCompletionStatus_None)}
-	return repo.ListInputs(ctx, appAddress, f, repository.Pagination{}, false)
+	_, total, err := repo.ListInputs(ctx, appAddress, f, repository.Pagination{Limit: 1}, false)
+	return total, err
 }
 
-// Helper function to get inputs after a specific index
-func getInputsAfterSnapshot(ctx context.Context, repo MachineRepository, app *Application, snapshotInputIndex uint64) ([]*Input, error) {
-	// Get all processed inputs for this application
-	inputs, _, err := getProcessedInputs(ctx, repo, app.IApplicationAddress.String())
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter inputs to only include those after the snapshot
-	for i, input := range inputs {
-		if input.Index > snapshotInputIndex {
-			return inputs[i:], nil
+// Helper function to stream the processed inputs, optionally after a specific index.
+// A pruned input can not be replayed, so it ends the stream with ErrMachineSynchronization.
+func streamProcessedInputs(
+	ctx context.Context,
+	repo MachineRepository,
+	appAddress string,
+	after *uint64,
+) iter.Seq2[*Input, error] {
+	f := repository.InputFilter{NotStatus: Pointer(InputCompletionStatus_None)}
+	inputs := repository.StreamInputs(ctx, repo, appAddress, f, repository.Pagination{After: after}, false)
+	return func(yield func(*Input, error) bool) {
+		for input, err := range inputs {
+			if err == nil && len(input.RawData) == 0 {
+				err = fmt.Errorf("%w: the payload of input %d was pruned",
+					ErrMachineSynchronization, input.Index)
+				input = nil
+			}
+			if !yield(input, err) || err != nil {
+				return
+			}
 		}
 	}
-	return []*Input{}, nil
 }
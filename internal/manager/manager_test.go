@@ -5,12 +5,18 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
+	"math/big"
+	"os"
 	"testing"
 
 	"github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/memory"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/mock"
@@ -61,7 +67,7 @@ func (s *MachineManagerSuite) TestUpdateMachines() {
 			Return([]*model.Input{}, uint64(0), nil)
 
 		// Mock GetLastSnapshot to return nil (no snapshot available)
-		repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
 			Return(nil, nil)
 
 		// Create manager with a test logger
@@ -81,18 +87,174 @@ func (s *MachineManagerSuite) TestUpdateMachines() {
 		defer func() { defaultFactory = originalFactory }()
 
 		// This test should now succeed with our mock
-		err := manager.UpdateMachines(context.Background())
+		err := manager.UpdateMachines(context.Background(), nil)
 		require.NoError(err)
 
 		repo.AssertCalled(s.T(), "ListApplications", mock.Anything, mock.Anything, mock.Anything, false)
 	})
 
+	s.Run("RebuildRevertedMachines", func() {
+		require := s.Require()
+
+		repo := &MockMachineRepository{}
+		app1 := &model.Application{
+			ID:                  1,
+			Name:                "App1",
+			IApplicationAddress: common.HexToAddress("0x1"),
+			State:               model.ApplicationState_Enabled,
+			ProcessedInputs:     0,
+			ExecutionParameters: model.ExecutionParameters{
+				AdvanceMaxDeadline:    100,
+				InspectMaxDeadline:    100,
+				MaxConcurrentInspects: 3,
+			},
+		}
+		repo.On("ListApplications", mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Application{app1}, uint64(1), nil)
+		repo.On("ListInputs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Input{}, uint64(0), nil)
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
+			Return(nil, nil)
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+
+		originalFactory := defaultFactory
+		defaultFactory = &MockMachineRuntimeFactory{RuntimeToReturn: &MockRollupsMachine{}}
+		defer func() { defaultFactory = originalFactory }()
+
+		// the machine processed inputs that were reverted by a chain reorganization
+		reverted := &MockMachineInstance{application: app1, processedInputs: 2}
+		manager.addMachine(1, reverted)
+
+		err := manager.UpdateMachines(context.Background(), nil)
+		require.NoError(err)
+
+		machine, exists := manager.GetMachine(1)
+		require.True(exists)
+		require.NotSame(reverted, machine)
+		require.Equal(uint64(0), machine.ProcessedInputs())
+	})
+
+	s.Run("FallBackToOlderSnapshots", func() {
+		require := s.Require()
+
+		repo := &MockMachineRepository{}
+		app1 := &model.Application{
+			ID:                  1,
+			Name:                "App1",
+			IApplicationAddress: common.HexToAddress("0x1"),
+			State:               model.ApplicationState_Enabled,
+			ProcessedInputs:     8,
+			ExecutionParameters: model.ExecutionParameters{
+				AdvanceMaxDeadline:    100,
+				InspectMaxDeadline:    100,
+				MaxConcurrentInspects: 3,
+			},
+		}
+		dir := s.T().TempDir()
+		corrupt, older, missing := dir+"/corrupt", dir+"/older", dir+"/missing"
+		require.Nil(os.Mkdir(corrupt, 0755))
+		require.Nil(os.Mkdir(older, 0755))
+
+		repo.On("ListApplications", mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Application{app1}, uint64(1), nil)
+		repo.On("ListInputs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Input{}, uint64(0), nil)
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
+			Return([]*model.Input{
+				{Index: 7, SnapshotURI: &missing},
+				{Index: 6, SnapshotURI: &corrupt},
+				{Index: 3, SnapshotURI: &older},
+			}, nil)
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+
+		var loaded []string
+		originalSnapshotFactory := newSnapshotFactory
+		newSnapshotFactory = func(snapshotPath string, _ *common.Hash, _ uint64) MachineRuntimeFactory {
+			loaded = append(loaded, snapshotPath)
+			if snapshotPath == corrupt {
+				return &MockMachineRuntimeFactory{ErrorToReturn: errors.New("corrupt snapshot")}
+			}
+			return &MockMachineRuntimeFactory{RuntimeToReturn: &MockRollupsMachine{}}
+		}
+		defer func() { newSnapshotFactory = originalSnapshotFactory }()
+
+		err := manager.UpdateMachines(context.Background(), nil)
+		require.NoError(err)
+
+		require.Equal([]string{corrupt, older}, loaded)
+		machine, exists := manager.GetMachine(1)
+		require.True(exists)
+		require.Equal(uint64(4), machine.ProcessedInputs())
+	})
+
+	s.Run("DownloadRemoteSnapshots", func() {
+		require := s.Require()
+
+		repo := &MockMachineRepository{}
+		app1 := &model.Application{
+			ID:                  1,
+			Name:                "App1",
+			IApplicationAddress: common.HexToAddress("0x1"),
+			State:               model.ApplicationState_Enabled,
+			ProcessedInputs:     4,
+			ExecutionParameters: model.ExecutionParameters{
+				AdvanceMaxDeadline:    100,
+				InspectMaxDeadline:    100,
+				MaxConcurrentInspects: 3,
+			},
+		}
+		cacheDir, remoteDir := s.T().TempDir(), s.T().TempDir()
+		require.Nil(os.MkdirAll(remoteDir+"/App1_epoch0_input3", 0755))
+		require.Nil(os.WriteFile(remoteDir+"/App1_epoch0_input3/config.json", []byte("{}"), 0644))
+		storage, err := snapshot.NewFileStorage(remoteDir)
+		require.Nil(err)
+		uri := "file://" + remoteDir + "/App1_epoch0_input3"
+
+		repo.On("ListApplications", mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Application{app1}, uint64(1), nil)
+		repo.On("ListInputs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Input{}, uint64(0), nil)
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
+			Return([]*model.Input{{Index: 3, SnapshotURI: &uri}}, nil)
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+		manager.SetSnapshotStorage(storage, cacheDir)
+
+		// the cached copy is downloaded again when it is invalid
+		cached := cacheDir + "/App1_epoch0_input3"
+		require.Nil(os.Mkdir(cached, 0755))
+		var loaded []string
+		originalSnapshotFactory := newSnapshotFactory
+		newSnapshotFactory = func(snapshotPath string, _ *common.Hash, _ uint64) MachineRuntimeFactory {
+			loaded = append(loaded, snapshotPath)
+			if _, err := os.Stat(snapshotPath + "/config.json"); err != nil {
+				return &MockMachineRuntimeFactory{ErrorToReturn: ErrInvalidSnapshot}
+			}
+			return &MockMachineRuntimeFactory{RuntimeToReturn: &MockRollupsMachine{}}
+		}
+		defer func() { newSnapshotFactory = originalSnapshotFactory }()
+
+		err = manager.UpdateMachines(context.Background(), nil)
+		require.NoError(err)
+
+		require.Equal([]string{cached, cached}, loaded)
+		require.FileExists(cached + "/config.json")
+		machine, exists := manager.GetMachine(1)
+		require.True(exists)
+		require.Equal(uint64(4), machine.ProcessedInputs())
+	})
+
 	s.Run("RemoveDisabledMachines", func() {
 		require := s.Require()
 
 		// Create a mock repository
 		repo := &MockMachineRepository{}
-		repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
 			Return(nil, nil)
 
 		// Create a test logger
@@ -113,7 +275,7 @@ func (s *MachineManagerSuite) TestUpdateMachines() {
 		manager.addMachine(3, mockMachine3)
 
 		// Remove machines not in the active list
-		manager.removeMachines([]*model.Application{app1, app3})
+		manager.removeMachines([]*model.Application{app1, app3}, nil)
 
 		// Verify machine2 was removed
 		require.Len(manager.machines, 2)
@@ -121,13 +283,163 @@ func (s *MachineManagerSuite) TestUpdateMachines() {
 		require.False(manager.HasMachine(2))
 		require.True(manager.HasMachine(3))
 	})
+
+	s.Run("SkipBusyMachines", func() {
+		require := s.Require()
+
+		app1 := &model.Application{ID: 1, Name: "App1", State: model.ApplicationState_Enabled}
+		app2 := &model.Application{ID: 2, Name: "App2"}
+		repo := &MockMachineRepository{}
+		repo.On("ListApplications", mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Application{app1}, uint64(1), nil)
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+
+		// the machine of app1 is ahead while it advances, app2 was disabled
+		machine1 := &MockMachineInstance{application: app1, processedInputs: 5}
+		manager.addMachine(1, machine1)
+		manager.addMachine(2, &MockMachineInstance{application: app2})
+
+		err := manager.UpdateMachines(context.Background(), map[int64]struct{}{1: {}, 2: {}})
+		require.NoError(err)
+		machine, exists := manager.GetMachine(1)
+		require.True(exists)
+		require.Same(machine1, machine)
+		require.True(manager.HasMachine(2))
+	})
+
+	s.Run("RefusePrunedInputs", func() {
+		require := s.Require()
+		ctx := context.Background()
+
+		repo := memory.NewMemoryRepository()
+		app := &model.Application{
+			Name:                "app1",
+			IApplicationAddress: common.HexToAddress("0x1"),
+			DataAvailability:    model.DataAvailability_InputBox[:],
+			EpochLength:         10,
+			State:               model.ApplicationState_Enabled,
+		}
+		var err error
+		app.ID, err = repo.CreateApplication(ctx, app)
+		require.NoError(err)
+
+		machineHash := common.HexToHash("0xff")
+		for i := range uint64(2) {
+			epoch := &model.Epoch{
+				ApplicationID: app.ID,
+				Index:         i,
+				VirtualIndex:  i,
+				FirstBlock:    i * app.EpochLength,
+				LastBlock:     i*app.EpochLength + app.EpochLength - 1,
+				Status:        model.EpochStatus_ClaimAccepted,
+			}
+			input := &model.Input{
+				EpochIndex:           i,
+				Index:                i,
+				BlockNumber:          epoch.FirstBlock,
+				Status:               model.InputCompletionStatus_None,
+				RawData:              []byte("input"),
+				TransactionReference: common.BigToHash(new(big.Int).SetUint64(i)),
+			}
+			err = repo.CreateEpochsAndInputs(ctx, app.Name,
+				map[*model.Epoch][]*model.Input{epoch: {input}}, epoch.LastBlock)
+			require.NoError(err)
+			err = repo.StoreAdvanceResult(ctx, app.ID, &model.AdvanceResult{
+				InputIndex:  i,
+				Status:      model.InputCompletionStatus_Accepted,
+				OutputsHash: common.HexToHash("0xee"),
+				MachineHash: &machineHash,
+			})
+			require.NoError(err)
+		}
+
+		// prune the first epoch, then lose the snapshot that was replayed on
+		require.NoError(repo.UpdateInputSnapshotURI(ctx, app.ID, 1, "/snapshots/1"))
+		app, err = repo.GetApplication(ctx, app.Name)
+		require.NoError(err)
+		app.ExecutionParameters.RetainedEpochs = 1
+		result, err := repository.PruneApplication(ctx, repo, app)
+		require.NoError(err)
+		require.Equal(uint64(1), result.Inputs)
+		require.NoError(repo.ClearInputSnapshotURI(ctx, app.ID, 1))
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(ctx, repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+
+		originalFactory := defaultFactory
+		defaultFactory = &MockMachineRuntimeFactory{RuntimeToReturn: &MockRollupsMachine{}}
+		defer func() { defaultFactory = originalFactory }()
+
+		// the template can not be brought up to date without the pruned input
+		require.NoError(manager.UpdateMachines(ctx, nil))
+		require.False(manager.HasMachine(app.ID))
+
+		err = manager.RecoverMachine(ctx, app)
+		require.ErrorIs(err, ErrMachineSynchronization)
+		require.False(manager.HasMachine(app.ID))
+	})
+}
+
+func (s *MachineManagerSuite) TestRecoverMachine() {
+	newRecovery := func(machineHash common.Hash) (*MachineManager, *model.Application) {
+		repo := &MockMachineRepository{}
+		app := &model.Application{
+			ID:                  1,
+			Name:                "App1",
+			IApplicationAddress: common.HexToAddress("0x1"),
+			State:               model.ApplicationState_Enabled,
+			ExecutionParameters: model.ExecutionParameters{
+				AdvanceMaxDeadline:    100,
+				InspectMaxDeadline:    100,
+				MaxConcurrentInspects: 3,
+			},
+		}
+		repo.On("ListSnapshots", mock.Anything, mock.Anything).
+			Return(nil, nil)
+		repo.On("ListInputs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false).
+			Return([]*model.Input{}, uint64(0), nil)
+		// the last processed input
+		repo.On("ListInputs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true).
+			Return([]*model.Input{{Index: 0, MachineHash: &machineHash}}, uint64(1), nil)
+
+		testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, testLogger, false)
+
+		originalFactory := defaultFactory
+		defaultFactory = &MockMachineRuntimeFactory{RuntimeToReturn: &MockRollupsMachine{HashReturn: rollupsmachine.Hash{1}}}
+		s.T().Cleanup(func() { defaultFactory = originalFactory })
+		return manager, app
+	}
+
+	s.Run("KeepsMatchingMachine", func() {
+		require := s.Require()
+		manager, app := newRecovery(common.Hash{1})
+		broken := &MockMachineInstance{application: app}
+		manager.addMachine(app.ID, broken)
+
+		require.NoError(manager.RecoverMachine(context.Background(), app))
+		machine, exists := manager.GetMachine(app.ID)
+		require.True(exists)
+		require.NotSame(broken, machine)
+	})
+
+	s.Run("RejectsHashMismatch", func() {
+		require := s.Require()
+		manager, app := newRecovery(common.Hash{2})
+
+		err := manager.RecoverMachine(context.Background(), app)
+		require.ErrorIs(err, ErrMachineHashMismatch)
+		require.False(manager.HasMachine(app.ID))
+	})
 }
 
 func (s *MachineManagerSuite) TestGetMachine() {
 	require := s.Require()
 
 	repo := &MockMachineRepository{}
-	repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+	repo.On("ListSnapshots", mock.Anything, mock.Anything).
 		Return(nil, nil)
 
 	manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, nil, false)
@@ -150,7 +462,7 @@ func (s *MachineManagerSuite) TestHasMachine() {
 	require := s.Require()
 
 	repo := &MockMachineRepository{}
-	repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+	repo.On("ListSnapshots", mock.Anything, mock.Anything).
 		Return(nil, nil)
 
 	manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, nil, false)
@@ -170,7 +482,7 @@ func (s *MachineManagerSuite) TestAddMachine() {
 	require := s.Require()
 
 	repo := &MockMachineRepository{}
-	repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+	repo.On("ListSnapshots", mock.Anything, mock.Anything).
 		Return(nil, nil)
 
 	manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, nil, false)
@@ -212,7 +524,7 @@ func (s *MachineManagerSuite) TestRemoveDisabledMachines() {
 	manager.addMachine(3, machine3)
 
 	// Remove machines not in the active list
-	manager.removeMachines([]*model.Application{app1, app3})
+	manager.removeMachines([]*model.Application{app1, app3}, nil)
 
 	// Verify machine2 was removed
 	require.Len(manager.machines, 2)
@@ -225,7 +537,7 @@ func (s *MachineManagerSuite) TestApplications() {
 	require := s.Require()
 
 	repo := &MockMachineRepository{}
-	repo.On("GetLastSnapshot", mock.Anything, mock.Anything).
+	repo.On("ListSnapshots", mock.Anything, mock.Anything).
 		Return(nil, nil)
 
 	manager := NewMachineManager(context.Background(), repo, cartesimachine.MachineLogLevelInfo, nil, false)
@@ -282,12 +594,12 @@ func (m *MockMachineRepository) ListInputs(
 	return args.Get(0).([]*model.Input), args.Get(1).(uint64), args.Error(2)
 }
 
-func (m *MockMachineRepository) GetLastSnapshot(
+func (m *MockMachineRepository) ListSnapshots(
 	ctx context.Context,
-	nameOrAddress string) (*model.Input, error) {
+	nameOrAddress string) ([]*model.Input, error) {
 	args := m.Called(ctx, nameOrAddress)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.Input), args.Error(1)
+	return args.Get(0).([]*model.Input), args.Error(1)
 }
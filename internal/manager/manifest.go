@@ -0,0 +1,152 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	// ErrMissingManifest is returned for incomplete snapshots, and for the
+	// ones taken before manifests were written
+	ErrMissingManifest = fmt.Errorf("%w: no manifest", ErrInvalidSnapshot)
+)
+
+// SnapshotManifest describes a snapshot, so it can be validated before it is
+// loaded. It is written last, so a snapshot without one is incomplete.
+type SnapshotManifest struct {
+	Application common.Address `json:"application"`
+	// Index of the last input processed by the machine in the snapshot
+	InputIndex  uint64      `json:"input_index"`
+	MachineHash common.Hash `json:"machine_hash"`
+	OutputsHash common.Hash `json:"outputs_hash"`
+	NodeVersion string      `json:"node_version"`
+	// SHA-256 checksum of every file of the snapshot, by path relative to it
+	Files map[string]string `json:"files"`
+}
+
+// ReadSnapshotManifest reads the manifest of the snapshot in dir
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshot.ManifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w in %s", ErrMissingManifest, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: malformed manifest: %w", ErrInvalidSnapshot, err)
+	}
+	return &manifest, nil
+}
+
+// writeSnapshotManifest computes the checksums of the files of the snapshot
+// in dir and writes the manifest next to them
+func writeSnapshotManifest(dir string, manifest *SnapshotManifest) error {
+	files, err := checksumSnapshotFiles(dir)
+	if err != nil {
+		return fmt.Errorf("failed to compute snapshot checksums: %w", err)
+	}
+	manifest.Files = files
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, snapshot.ManifestFileName), data, 0644) // nolint: mnd
+}
+
+// Validate checks the manifest belongs to the snapshot of app after the input
+// with inputIndex, with machineHash if not nil, and that the files in dir
+// match their checksums
+func (m *SnapshotManifest) Validate(
+	dir string,
+	app *Application,
+	inputIndex uint64,
+	machineHash *common.Hash,
+) error {
+	if m.Application != app.IApplicationAddress {
+		return fmt.Errorf("%w: snapshot of application %s, expected %s",
+			ErrInvalidSnapshot, m.Application, app.IApplicationAddress)
+	}
+	if m.InputIndex != inputIndex {
+		return fmt.Errorf("%w: snapshot after input %d, expected %d",
+			ErrInvalidSnapshot, m.InputIndex, inputIndex)
+	}
+	if machineHash != nil && m.MachineHash != *machineHash {
+		return fmt.Errorf("%w: snapshot machine hash %s, expected %s",
+			ErrInvalidSnapshot, m.MachineHash, machineHash)
+	}
+
+	files, err := checksumSnapshotFiles(dir)
+	if err != nil {
+		return fmt.Errorf("failed to compute snapshot checksums: %w", err)
+	}
+	for name, checksum := range m.Files {
+		actual, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%w: missing file %s", ErrInvalidSnapshot, name)
+		}
+		if actual != checksum {
+			return fmt.Errorf("%w: checksum mismatch on file %s", ErrInvalidSnapshot, name)
+		}
+	}
+	for name := range files {
+		if _, ok := m.Files[name]; !ok {
+			return fmt.Errorf("%w: unexpected file %s", ErrInvalidSnapshot, name)
+		}
+	}
+	return nil
+}
+
+// checksumSnapshotFiles returns the SHA-256 checksum of every regular file in
+// dir, except the manifest, by path relative to dir
+func checksumSnapshotFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name == snapshot.ManifestFileName {
+			return nil
+		}
+		checksum, err := checksumFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(name)] = checksum
+		return nil
+	})
+	return files, err
+}
+
+func checksumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,96 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/snapshot"
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSnapshotManifest(t *testing.T) {
+	suite.Run(t, new(SnapshotManifestSuite))
+}
+
+type SnapshotManifestSuite struct {
+	suite.Suite
+	dir  string
+	app  *model.Application
+	hash common.Hash
+}
+
+func (s *SnapshotManifestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.app = &model.Application{IApplicationAddress: common.HexToAddress("0x1")}
+	s.hash = common.HexToHash("0x2")
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "config.json"), []byte("{}"), 0644))
+	s.Require().Nil(os.Mkdir(filepath.Join(s.dir, "ram"), 0755))
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "ram", "0000"), []byte("ram"), 0644))
+	s.Require().Nil(writeSnapshotManifest(s.dir, &SnapshotManifest{
+		Application: s.app.IApplicationAddress,
+		InputIndex:  7,
+		MachineHash: s.hash,
+	}))
+}
+
+func (s *SnapshotManifestSuite) validate() error {
+	manifest, err := ReadSnapshotManifest(s.dir)
+	if err != nil {
+		return err
+	}
+	return manifest.Validate(s.dir, s.app, 7, &s.hash)
+}
+
+func (s *SnapshotManifestSuite) TestValid() {
+	manifest, err := ReadSnapshotManifest(s.dir)
+	s.Require().Nil(err)
+	s.Len(manifest.Files, 2)
+	s.Contains(manifest.Files, "ram/0000")
+	s.Nil(s.validate())
+	s.Nil(manifest.Validate(s.dir, s.app, 7, nil))
+}
+
+func (s *SnapshotManifestSuite) TestMismatch() {
+	manifest, err := ReadSnapshotManifest(s.dir)
+	s.Require().Nil(err)
+	other := common.HexToHash("0x3")
+	s.ErrorIs(manifest.Validate(s.dir, s.app, 7, &other), ErrInvalidSnapshot)
+	s.ErrorIs(manifest.Validate(s.dir, s.app, 8, &s.hash), ErrInvalidSnapshot)
+	s.ErrorIs(manifest.Validate(s.dir, &model.Application{}, 7, &s.hash), ErrInvalidSnapshot)
+}
+
+func (s *SnapshotManifestSuite) TestCorruptFile() {
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "ram", "0000"), []byte("RAM"), 0644))
+	s.ErrorContains(s.validate(), "checksum mismatch on file ram/0000")
+}
+
+func (s *SnapshotManifestSuite) TestMissingFile() {
+	s.Require().Nil(os.Remove(filepath.Join(s.dir, "config.json")))
+	s.ErrorContains(s.validate(), "missing file config.json")
+}
+
+func (s *SnapshotManifestSuite) TestUnexpectedFile() {
+	s.Require().Nil(os.WriteFile(filepath.Join(s.dir, "extra"), nil, 0644))
+	s.ErrorContains(s.validate(), "unexpected file extra")
+}
+
+func (s *SnapshotManifestSuite) TestMissingManifest() {
+	s.Require().Nil(os.Remove(filepath.Join(s.dir, snapshot.ManifestFileName)))
+	s.ErrorIs(s.validate(), ErrInvalidSnapshot)
+
+	factory := &SnapshotMachineRuntimeFactory{SnapshotPath: s.dir, InputIndex: 7}
+	_, err := factory.CreateMachineRuntime(s.T().Context(), cartesimachine.MachineLogLevelInfo, s.app, nil, false)
+	s.ErrorIs(err, ErrMissingManifest)
+
+	// a legacy snapshot is loaded, and checked against the input machine hash
+	factory.MachineHash = &s.hash
+	_, err = factory.CreateMachineRuntime(s.T().Context(), cartesimachine.MachineLogLevelInfo, s.app, nil, false)
+	s.ErrorIs(err, ErrInvalidLogger)
+}
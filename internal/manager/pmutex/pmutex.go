@@ -54,6 +54,19 @@ func (pmutex *PMutex) LLock() {
 }
 
 // Unlock releases the mutex for both types of threads.
+// TryLLock acquires the lock with low priority if it is free and no
+// high-priority thread is waiting for it, and reports whether it did.
+func (pmutex *PMutex) TryLLock() bool {
+	if !pmutex.mutex.TryLock() {
+		return false
+	}
+	if pmutex.waitingHigh.Load() != 0 {
+		pmutex.Unlock()
+		return false
+	}
+	return true
+}
+
 func (pmutex *PMutex) Unlock() {
 	pmutex.waitingLow.Broadcast()
 	pmutex.mutex.Unlock()
@@ -52,6 +52,18 @@ func (s *PMutexSuite) TestContestedLLock() {
 	never(require, func() bool { s.mutex.LLock(); return true })
 }
 
+func (s *PMutexSuite) TestTryLLock() {
+	require := s.Require()
+	require.True(s.mutex.TryLLock())
+	require.False(s.mutex.TryLLock())
+	s.mutex.Unlock()
+
+	s.mutex.HLock()
+	require.False(s.mutex.TryLLock())
+	s.mutex.Unlock()
+	require.True(s.mutex.TryLLock())
+}
+
 func (s *PMutexSuite) TestPriority() {
 	require := s.Require()
 	release := make(chan struct{})
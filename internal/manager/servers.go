@@ -0,0 +1,281 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cartesi/rollups-node/pkg/rollupsmachine"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Name of the executable of the machine servers, to tell them from processes
+// that reused the pid of an orphan
+const machineServerCommand = "cartesi-jsonrpc-machine"
+
+// The pid and address of each server are in the logs and pidfiles instead, as
+// every advance forks a server of its own.
+var serversMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "cartesi",
+	Subsystem: "machine",
+	Name:      "servers",
+	Help:      "Running machine servers, forks included, by application.",
+}, []string{"application"})
+
+// MachineServer is a machine server process spawned by the node, either for
+// an application or as a fork of one of its machines.
+type MachineServer struct {
+	Pid         uint32    `json:"pid"`
+	Address     string    `json:"address"`
+	Application string    `json:"application"`
+	StartedAt   time.Time `json:"started_at"`
+	// process id of the node that spawned the server
+	Owner int `json:"owner"`
+}
+
+// serverRegistry tracks the machine servers spawned by this process. When it
+// has a directory, each server also has a pidfile there, so the servers left
+// behind by a node that crashed can be killed when it starts again.
+type serverRegistry struct {
+	mutex   sync.Mutex
+	dir     string
+	servers map[uint32]MachineServer
+	logger  *slog.Logger
+}
+
+// The servers spawned by the machines of every manager of this process
+var machineServers = &serverRegistry{
+	servers: map[uint32]MachineServer{},
+	logger:  slog.Default(),
+}
+
+// setDir makes the registry write the pidfiles to dir and kills the orphan
+// servers of the previous runs listed there.
+func (r *serverRegistry) setDir(dir string, logger *slog.Logger) error {
+	if err := os.MkdirAll(dir, 0755); err != nil { // nolint: mnd
+		return fmt.Errorf("failed to create the machine servers directory: %w", err)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.dir = dir
+	r.logger = logger
+	return r.reapOrphans()
+}
+
+// reapOrphans kills the servers in the pidfiles of dir that no live node owns.
+func (r *serverRegistry) reapOrphans() error {
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			r.logger.Warn("Failed to read machine server pidfile", "path", path, "error", err)
+			continue
+		}
+		var server MachineServer
+		if err := json.Unmarshal(data, &server); err != nil {
+			r.logger.Warn("Removing malformed machine server pidfile", "path", path, "error", err)
+			_ = os.Remove(path)
+			continue
+		}
+		if _, tracked := r.servers[server.Pid]; tracked {
+			continue
+		}
+		// another node sharing the directory is still running
+		if server.Owner != os.Getpid() && processAlive(server.Owner) {
+			continue
+		}
+		if isMachineServer(server.Pid) {
+			r.logger.Warn("Killing orphan machine server",
+				"pid", server.Pid,
+				"address", server.Address,
+				"application", server.Application,
+				"started_at", server.StartedAt)
+			if err := syscall.Kill(int(server.Pid), syscall.SIGKILL); err != nil {
+				r.logger.Error("Failed to kill orphan machine server", "pid", server.Pid, "error", err)
+				continue
+			}
+		}
+		_ = os.Remove(path)
+	}
+	return nil
+}
+
+// add tracks a server spawned for application.
+func (r *serverRegistry) add(pid uint32, address string, application string) {
+	if pid == 0 {
+		return
+	}
+	server := MachineServer{
+		Pid:         pid,
+		Address:     address,
+		Application: application,
+		StartedAt:   time.Now(),
+		Owner:       os.Getpid(),
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.servers[pid]; !ok {
+		serversMetric.WithLabelValues(application).Inc()
+	}
+	r.servers[pid] = server
+	r.logger.Debug("Tracking machine server", "pid", pid, "address", address, "application", application)
+	if r.dir == "" {
+		return
+	}
+	data, err := json.Marshal(server)
+	if err == nil {
+		err = os.WriteFile(r.pidfile(pid), data, 0644) // nolint: mnd
+	}
+	if err != nil {
+		r.logger.Warn("Failed to write machine server pidfile", "pid", pid, "error", err)
+	}
+}
+
+// remove stops tracking a server that exited.
+func (r *serverRegistry) remove(pid uint32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	server, ok := r.servers[pid]
+	if !ok {
+		return
+	}
+	delete(r.servers, pid)
+	serversMetric.WithLabelValues(server.Application).Dec()
+	if r.dir != "" {
+		_ = os.Remove(r.pidfile(pid))
+	}
+}
+
+// kill kills a server that did not shut down when asked to, and stops
+// tracking it.
+func (r *serverRegistry) kill(pid uint32) error {
+	var err error
+	if isMachineServer(pid) {
+		r.logger.Warn("Killing machine server", "pid", pid)
+		err = syscall.Kill(int(pid), syscall.SIGKILL)
+	}
+	r.remove(pid)
+	return err
+}
+
+// killAll kills the servers still running, after their machines were closed.
+func (r *serverRegistry) killAll() error {
+	var errs []error
+	for _, server := range r.list() {
+		errs = append(errs, r.kill(server.Pid))
+	}
+	return errors.Join(errs...)
+}
+
+// list returns the tracked servers, the oldest first.
+func (r *serverRegistry) list() []MachineServer {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	servers := make([]MachineServer, 0, len(r.servers))
+	for _, server := range r.servers {
+		servers = append(servers, server)
+	}
+	slices.SortFunc(servers, func(a, b MachineServer) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return servers
+}
+
+func (r *serverRegistry) pidfile(pid uint32) string {
+	return filepath.Join(r.dir, fmt.Sprintf("%d.json", pid))
+}
+
+// processAlive reports whether a process with pid exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// isMachineServer reports whether pid is a running machine server. Without
+// /proc it can not tell, and reports false.
+func isMachineServer(pid uint32) bool {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return false
+	}
+	command, _, _ := bytes.Cut(cmdline, []byte{0})
+	return strings.HasSuffix(string(command), machineServerCommand)
+}
+
+// serverLogWriter logs each line a machine server writes to one of its
+// output streams. It expects to be wrapped by a linewriter.LineWriter.
+type serverLogWriter struct {
+	logger *slog.Logger
+	stream string
+}
+
+func (w serverLogWriter) Write(p []byte) (int, error) {
+	w.logger.Info(strings.TrimRight(string(p), "\r\n"), "stream", w.stream)
+	return len(p), nil
+}
+
+// trackedMachine is a machine whose server, and the servers of its forks,
+// are tracked by the registry and killed if they fail to shut down.
+type trackedMachine struct {
+	rollupsmachine.RollupsMachine
+	registry    *serverRegistry
+	application string
+	address     string
+}
+
+func newTrackedMachine(
+	machine rollupsmachine.RollupsMachine,
+	registry *serverRegistry,
+	application string,
+	address string,
+) *trackedMachine {
+	registry.add(machine.Pid(), address, application)
+	return &trackedMachine{
+		RollupsMachine: machine,
+		registry:       registry,
+		application:    application,
+		address:        address,
+	}
+}
+
+func (m *trackedMachine) Fork(ctx context.Context) (rollupsmachine.RollupsMachine, error) {
+	fork, err := m.RollupsMachine.Fork(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newTrackedMachine(fork, m.registry, m.application, ""), nil
+}
+
+func (m *trackedMachine) Close(ctx context.Context) error {
+	pid := m.Pid()
+	err := m.RollupsMachine.Close(ctx)
+	if pid == 0 {
+		return err
+	}
+	if err != nil {
+		return errors.Join(err, m.registry.kill(pid))
+	}
+	m.registry.remove(pid)
+	return nil
+}
@@ -0,0 +1,149 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/services/linewriter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestServerRegistry(t *testing.T) {
+	suite.Run(t, new(ServerRegistrySuite))
+}
+
+type ServerRegistrySuite struct {
+	suite.Suite
+	dir      string
+	registry *serverRegistry
+}
+
+func (s *ServerRegistrySuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.registry = &serverRegistry{servers: map[uint32]MachineServer{}, logger: slog.Default()}
+	s.Require().Nil(s.registry.setDir(s.dir, slog.Default()))
+}
+
+// startFakeServer runs a process whose command is named like a machine server
+func (s *ServerRegistrySuite) startFakeServer() *exec.Cmd {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		s.T().Skip("sleep is not available")
+	}
+	data, err := os.ReadFile(sleep)
+	s.Require().Nil(err)
+	command := filepath.Join(s.T().TempDir(), machineServerCommand)
+	s.Require().Nil(os.WriteFile(command, data, 0755))
+
+	cmd := exec.Command(command, "60")
+	if err := cmd.Start(); err != nil {
+		s.T().Skipf("cannot run the fake server: %v", err)
+	}
+	s.T().Cleanup(func() { _ = cmd.Process.Kill() })
+	return cmd
+}
+
+func (s *ServerRegistrySuite) TestPidfiles() {
+	s.registry.add(1234, "127.0.0.1:5000", "app")
+	s.FileExists(filepath.Join(s.dir, "1234.json"))
+	s.Require().Len(s.registry.list(), 1)
+	s.Equal("127.0.0.1:5000", s.registry.list()[0].Address)
+
+	s.registry.remove(1234)
+	s.NoFileExists(filepath.Join(s.dir, "1234.json"))
+	s.Empty(s.registry.list())
+}
+
+func (s *ServerRegistrySuite) TestServersMetric() {
+	servers := serversMetric.WithLabelValues("metric-app")
+	s.registry.add(2001, "127.0.0.1:5000", "metric-app")
+	s.registry.add(2002, "", "metric-app") // a fork
+	s.registry.add(2002, "", "metric-app")
+	s.Equal(2.0, testutil.ToFloat64(servers))
+
+	s.registry.remove(2002)
+	s.registry.remove(2002)
+	s.Equal(1.0, testutil.ToFloat64(servers))
+	s.registry.remove(2001)
+	s.Equal(0.0, testutil.ToFloat64(servers))
+}
+
+func (s *ServerRegistrySuite) TestReapOrphans() {
+	cmd := s.startFakeServer()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// left by a node that is no longer running
+	data, err := json.Marshal(MachineServer{Pid: uint32(cmd.Process.Pid), Owner: -1}) // nolint: gosec
+	s.Require().Nil(err)
+	pidfile := filepath.Join(s.dir, "orphan.json")
+	s.Require().Nil(os.WriteFile(pidfile, data, 0644))
+
+	// left by a node that is still running
+	data, err = json.Marshal(MachineServer{Pid: 1, Owner: os.Getppid()})
+	s.Require().Nil(err)
+	other := filepath.Join(s.dir, "other.json")
+	s.Require().Nil(os.WriteFile(other, data, 0644))
+
+	s.Require().Nil(s.registry.setDir(s.dir, slog.Default()))
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		s.Fail("the orphan server was not killed")
+	}
+	s.NoFileExists(pidfile)
+	s.FileExists(other)
+}
+
+func (s *ServerRegistrySuite) TestKillOnFailedClose() {
+	cmd := s.startFakeServer()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	pid := uint32(cmd.Process.Pid) // nolint: gosec
+	runtime := &MockRollupsMachine{CloseError: errors.New("server does not respond")}
+	machine := newTrackedMachine(&pidMachine{MockRollupsMachine: runtime, pid: pid}, s.registry, "app", "")
+	s.Len(s.registry.list(), 1)
+
+	s.Error(machine.Close(context.Background()))
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		s.Fail("the server was not killed")
+	}
+	s.Empty(s.registry.list())
+}
+
+func (s *ServerRegistrySuite) TestServerLogWriter() {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&output, nil)).With("application", "app")
+	writer := linewriter.New(serverLogWriter{logger: logger, stream: "stderr"})
+
+	_, err := writer.Write([]byte("remote machine server bound to 127.0.0.1:5000\nhalf"))
+	s.Require().Nil(err)
+
+	var record map[string]any
+	s.Require().Nil(json.Unmarshal(output.Bytes(), &record))
+	s.Equal("remote machine server bound to 127.0.0.1:5000", record["msg"])
+	s.Equal("app", record["application"])
+	s.Equal("stderr", record["stream"])
+}
+
+type pidMachine struct {
+	*MockRollupsMachine
+	pid uint32
+}
+
+func (m *pidMachine) Pid() uint32 { return m.pid }
@@ -6,6 +6,8 @@ package manager
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/common"
+
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
 )
@@ -20,6 +22,10 @@ type MachineInstance interface {
 	Inspect(ctx context.Context, query []byte) (*InspectResult, error)
 	Synchronize(ctx context.Context, repo MachineRepository) error
 	CreateSnapshot(ctx context.Context, processedInputs uint64, path string) error
+	ProcessedInputs() uint64
+	Hash(ctx context.Context) (common.Hash, error)
+	Ping(ctx context.Context) error
+	SetMaxConcurrentInspects(limit uint32) error
 	Close() error
 }
 
@@ -31,9 +37,21 @@ type MachineProvider interface {
 	// Applications returns the list of applications with active machines
 	Applications() []*Application
 
-	// UpdateMachines refreshes the list of machines
-	UpdateMachines(ctx context.Context) error
+	// UpdateMachines refreshes the list of machines, leaving alone those of
+	// the applications in busy, which are being advanced
+	UpdateMachines(ctx context.Context, busy map[int64]struct{}) error
+
+	// Reload applies the execution parameters that may change while the
+	// machines run
+	Reload(ctx context.Context) error
 
 	// HasMachine checks if a machine exists for the given application ID
 	HasMachine(appID int64) bool
+
+	// RecoverMachine rebuilds the machine of an application and checks its
+	// hash before keeping it
+	RecoverMachine(ctx context.Context, app *Application) error
+
+	// Close shuts down the machines
+	Close() error
 }
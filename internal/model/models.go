@@ -27,6 +27,7 @@ type Application struct {
 	DataAvailability     []byte              `json:"data_availability"`
 	State                ApplicationState    `json:"state"`
 	Reason               *string             `json:"reason"`
+	ReasonCode           *ReasonCode         `json:"reason_code"`
 	IInputBoxBlock       uint64              `json:"iinputbox_block"`
 	LastInputCheckBlock  uint64              `json:"last_input_check_block"`
 	LastOutputCheckBlock uint64              `json:"last_output_check_block"`
@@ -109,6 +110,92 @@ func (e ApplicationState) String() string {
 	return string(e)
 }
 
+// ReasonCode classifies the reason of an inoperable application, so the node
+// can act on it without parsing the reason itself
+type ReasonCode string
+
+const (
+	// The machine server, or the connection to it, failed. The machine may
+	// work again once rebuilt.
+	ReasonCode_MachineServerFailure ReasonCode = "MACHINE_SERVER_FAILURE"
+	// The machine, or the application running on it, failed.
+	ReasonCode_MachineFailure ReasonCode = "MACHINE_FAILURE"
+	// The claim computed by the node does not match the one on chain or the
+	// one computed by the machine.
+	ReasonCode_ClaimMismatch ReasonCode = "CLAIM_MISMATCH"
+	// The consensus of the application was replaced on chain.
+	ReasonCode_ConsensusChanged ReasonCode = "CONSENSUS_CHANGED"
+	// The epochs, inputs or outputs stored by the node are inconsistent.
+	ReasonCode_InconsistentState ReasonCode = "INCONSISTENT_STATE"
+	// The node can not read inputs from the data availability of the application.
+	ReasonCode_UnsupportedDataAvailability ReasonCode = "UNSUPPORTED_DATA_AVAILABILITY"
+	// A chain reorganization reverted the inputs of an epoch already claimed.
+	ReasonCode_ClaimedEpochReverted ReasonCode = "CLAIMED_EPOCH_REVERTED"
+	// An input read from the chain can not be decoded.
+	ReasonCode_InvalidInput ReasonCode = "INVALID_INPUT"
+)
+
+var ReasonCodeAllValues = []ReasonCode{
+	ReasonCode_MachineServerFailure,
+	ReasonCode_MachineFailure,
+	ReasonCode_ClaimMismatch,
+	ReasonCode_ConsensusChanged,
+	ReasonCode_InconsistentState,
+	ReasonCode_UnsupportedDataAvailability,
+	ReasonCode_ClaimedEpochReverted,
+	ReasonCode_InvalidInput,
+}
+
+func (e *ReasonCode) Scan(value any) error {
+	var enumValue string
+	switch val := value.(type) {
+	case string:
+		enumValue = val
+	case []byte:
+		enumValue = string(val)
+	default:
+		return errors.New("invalid value for ReasonCode enum. Enum value has to be of type string or []byte")
+	}
+
+	switch enumValue {
+	case "MACHINE_SERVER_FAILURE":
+		*e = ReasonCode_MachineServerFailure
+	case "MACHINE_FAILURE":
+		*e = ReasonCode_MachineFailure
+	case "CLAIM_MISMATCH":
+		*e = ReasonCode_ClaimMismatch
+	case "CONSENSUS_CHANGED":
+		*e = ReasonCode_ConsensusChanged
+	case "INCONSISTENT_STATE":
+		*e = ReasonCode_InconsistentState
+	case "UNSUPPORTED_DATA_AVAILABILITY":
+		*e = ReasonCode_UnsupportedDataAvailability
+	case "CLAIMED_EPOCH_REVERTED":
+		*e = ReasonCode_ClaimedEpochReverted
+	case "INVALID_INPUT":
+		*e = ReasonCode_InvalidInput
+	default:
+		return errors.New("invalid value '" + enumValue + "' for ReasonCode enum")
+	}
+
+	return nil
+}
+
+func (e ReasonCode) String() string {
+	return string(e)
+}
+
+// ApplicationStateChange records a change of the state or reason of an
+// application.
+type ApplicationStateChange struct {
+	ID            int64            `sql:"primary_key" json:"-"`
+	ApplicationID int64            `json:"-"`
+	PreviousState ApplicationState `json:"previous_state"`
+	State         ApplicationState `json:"state"`
+	Reason        *string          `json:"reason"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
 const DATA_AVAILABILITY_SELECTOR_SIZE = 4
 
 type DataAvailabilitySelector [DATA_AVAILABILITY_SELECTOR_SIZE]byte
@@ -117,6 +204,8 @@ type DataAvailabilitySelector [DATA_AVAILABILITY_SELECTOR_SIZE]byte
 var (
 	// ABI encoded "InputBox(address)"
 	DataAvailability_InputBox = DataAvailabilitySelector{0xb1, 0x2c, 0x9e, 0xde}
+	// ABI encoded "InputBoxAndEspresso(address,uint256,uint32)"
+	DataAvailability_InputBoxAndEspresso = DataAvailabilitySelector{0x85, 0x79, 0xfd, 0x0c}
 )
 
 func (d *DataAvailabilitySelector) MarshalJSON() ([]byte, error) {
@@ -156,15 +245,21 @@ func (d *DataAvailabilitySelector) Scan(value any) error {
 type SnapshotPolicy string
 
 const (
-	SnapshotPolicy_None       SnapshotPolicy = "NONE"
-	SnapshotPolicy_EveryInput SnapshotPolicy = "EVERY_INPUT"
-	SnapshotPolicy_EveryEpoch SnapshotPolicy = "EVERY_EPOCH"
+	SnapshotPolicy_None          SnapshotPolicy = "NONE"
+	SnapshotPolicy_EveryInput    SnapshotPolicy = "EVERY_INPUT"
+	SnapshotPolicy_EveryEpoch    SnapshotPolicy = "EVERY_EPOCH"
+	SnapshotPolicy_EveryNInputs  SnapshotPolicy = "EVERY_N_INPUTS"
+	SnapshotPolicy_EveryDuration SnapshotPolicy = "EVERY_DURATION"
+	SnapshotPolicy_EveryNCycles  SnapshotPolicy = "EVERY_N_CYCLES"
 )
 
 var SnapshotPolicyAllValues = []SnapshotPolicy{
 	SnapshotPolicy_None,
 	SnapshotPolicy_EveryInput,
 	SnapshotPolicy_EveryEpoch,
+	SnapshotPolicy_EveryNInputs,
+	SnapshotPolicy_EveryDuration,
+	SnapshotPolicy_EveryNCycles,
 }
 
 func (e *SnapshotPolicy) Scan(value any) error {
@@ -185,6 +280,12 @@ func (e *SnapshotPolicy) Scan(value any) error {
 		*e = SnapshotPolicy_EveryInput
 	case "EVERY_EPOCH":
 		*e = SnapshotPolicy_EveryEpoch
+	case "EVERY_N_INPUTS":
+		*e = SnapshotPolicy_EveryNInputs
+	case "EVERY_DURATION":
+		*e = SnapshotPolicy_EveryDuration
+	case "EVERY_N_CYCLES":
+		*e = SnapshotPolicy_EveryNCycles
 	default:
 		return errors.New("invalid scan value '" + enumValue + "' for SnapshotPolicy enum")
 	}
@@ -211,6 +312,10 @@ type ExecutionParameters struct {
 	StoreDeadline         time.Duration  `json:"store_deadline"`
 	FastDeadline          time.Duration  `json:"fast_deadline"`
 	MaxConcurrentInspects uint32         `json:"max_concurrent_inspects"`
+	RetainedEpochs        uint64         `json:"retained_epochs"`
+	SnapshotInputInterval uint64         `json:"snapshot_input_interval"`
+	SnapshotTimeInterval  time.Duration  `json:"snapshot_time_interval"`
+	SnapshotCycleInterval uint64         `json:"snapshot_cycle_interval"`
 	CreatedAt             time.Time      `json:"created_at"`
 	UpdatedAt             time.Time      `json:"updated_at"`
 }
@@ -220,31 +325,35 @@ func (e *ExecutionParameters) MarshalJSON() ([]byte, error) {
 	type Alias ExecutionParameters
 	// Define a new structure that embeds the alias but overrides the hex fields.
 	aux := &struct {
-		AdvanceIncCycles   string `json:"advance_inc_cycles"`
-		AdvanceMaxCycles   string `json:"advance_max_cycles"`
-		InspectIncCycles   string `json:"inspect_inc_cycles"`
-		InspectMaxCycles   string `json:"inspect_max_cycles"`
-		AdvanceIncDeadline string `json:"advance_inc_deadline"`
-		AdvanceMaxDeadline string `json:"advance_max_deadline"`
-		InspectIncDeadline string `json:"inspect_inc_deadline"`
-		InspectMaxDeadline string `json:"inspect_max_deadline"`
-		LoadDeadline       string `json:"load_deadline"`
-		StoreDeadline      string `json:"store_deadline"`
-		FastDeadline       string `json:"fast_deadline"`
+		AdvanceIncCycles      string `json:"advance_inc_cycles"`
+		AdvanceMaxCycles      string `json:"advance_max_cycles"`
+		InspectIncCycles      string `json:"inspect_inc_cycles"`
+		InspectMaxCycles      string `json:"inspect_max_cycles"`
+		AdvanceIncDeadline    string `json:"advance_inc_deadline"`
+		AdvanceMaxDeadline    string `json:"advance_max_deadline"`
+		InspectIncDeadline    string `json:"inspect_inc_deadline"`
+		InspectMaxDeadline    string `json:"inspect_max_deadline"`
+		LoadDeadline          string `json:"load_deadline"`
+		StoreDeadline         string `json:"store_deadline"`
+		FastDeadline          string `json:"fast_deadline"`
+		SnapshotTimeInterval  string `json:"snapshot_time_interval"`
+		SnapshotCycleInterval string `json:"snapshot_cycle_interval"`
 		*Alias
 	}{
-		AdvanceIncCycles:   fmt.Sprintf("0x%x", e.AdvanceIncCycles),
-		AdvanceMaxCycles:   fmt.Sprintf("0x%x", e.AdvanceMaxCycles),
-		InspectIncCycles:   fmt.Sprintf("0x%x", e.InspectIncCycles),
-		InspectMaxCycles:   fmt.Sprintf("0x%x", e.InspectMaxCycles),
-		AdvanceIncDeadline: fmt.Sprintf("0x%x", uint64(e.AdvanceIncDeadline)),
-		AdvanceMaxDeadline: fmt.Sprintf("0x%x", uint64(e.AdvanceMaxDeadline)),
-		InspectIncDeadline: fmt.Sprintf("0x%x", uint64(e.InspectIncDeadline)),
-		InspectMaxDeadline: fmt.Sprintf("0x%x", uint64(e.InspectMaxDeadline)),
-		LoadDeadline:       fmt.Sprintf("0x%x", uint64(e.LoadDeadline)),
-		StoreDeadline:      fmt.Sprintf("0x%x", uint64(e.StoreDeadline)),
-		FastDeadline:       fmt.Sprintf("0x%x", uint64(e.FastDeadline)),
-		Alias:              (*Alias)(e),
+		AdvanceIncCycles:      fmt.Sprintf("0x%x", e.AdvanceIncCycles),
+		AdvanceMaxCycles:      fmt.Sprintf("0x%x", e.AdvanceMaxCycles),
+		InspectIncCycles:      fmt.Sprintf("0x%x", e.InspectIncCycles),
+		InspectMaxCycles:      fmt.Sprintf("0x%x", e.InspectMaxCycles),
+		AdvanceIncDeadline:    fmt.Sprintf("0x%x", uint64(e.AdvanceIncDeadline)),
+		AdvanceMaxDeadline:    fmt.Sprintf("0x%x", uint64(e.AdvanceMaxDeadline)),
+		InspectIncDeadline:    fmt.Sprintf("0x%x", uint64(e.InspectIncDeadline)),
+		InspectMaxDeadline:    fmt.Sprintf("0x%x", uint64(e.InspectMaxDeadline)),
+		LoadDeadline:          fmt.Sprintf("0x%x", uint64(e.LoadDeadline)),
+		StoreDeadline:         fmt.Sprintf("0x%x", uint64(e.StoreDeadline)),
+		FastDeadline:          fmt.Sprintf("0x%x", uint64(e.FastDeadline)),
+		SnapshotTimeInterval:  fmt.Sprintf("0x%x", uint64(e.SnapshotTimeInterval)),
+		SnapshotCycleInterval: fmt.Sprintf("0x%x", e.SnapshotCycleInterval),
+		Alias:                 (*Alias)(e),
 	}
 	return json.Marshal(aux)
 }
@@ -254,17 +363,19 @@ func (e *ExecutionParameters) UnmarshalJSON(data []byte) error {
 	type Alias ExecutionParameters
 	// Define a new structure that embeds the alias but overrides the hex fields.
 	aux := &struct {
-		AdvanceIncCycles   string `json:"advance_inc_cycles"`
-		AdvanceMaxCycles   string `json:"advance_max_cycles"`
-		InspectIncCycles   string `json:"inspect_inc_cycles"`
-		InspectMaxCycles   string `json:"inspect_max_cycles"`
-		AdvanceIncDeadline string `json:"advance_inc_deadline"`
-		AdvanceMaxDeadline string `json:"advance_max_deadline"`
-		InspectIncDeadline string `json:"inspect_inc_deadline"`
-		InspectMaxDeadline string `json:"inspect_max_deadline"`
-		LoadDeadline       string `json:"load_deadline"`
-		StoreDeadline      string `json:"store_deadline"`
-		FastDeadline       string `json:"fast_deadline"`
+		AdvanceIncCycles      string `json:"advance_inc_cycles"`
+		AdvanceMaxCycles      string `json:"advance_max_cycles"`
+		InspectIncCycles      string `json:"inspect_inc_cycles"`
+		InspectMaxCycles      string `json:"inspect_max_cycles"`
+		AdvanceIncDeadline    string `json:"advance_inc_deadline"`
+		AdvanceMaxDeadline    string `json:"advance_max_deadline"`
+		InspectIncDeadline    string `json:"inspect_inc_deadline"`
+		InspectMaxDeadline    string `json:"inspect_max_deadline"`
+		LoadDeadline          string `json:"load_deadline"`
+		StoreDeadline         string `json:"store_deadline"`
+		FastDeadline          string `json:"fast_deadline"`
+		SnapshotTimeInterval  string `json:"snapshot_time_interval"`
+		SnapshotCycleInterval string `json:"snapshot_cycle_interval"`
 		*Alias
 	}{
 		Alias: (*Alias)(e),
@@ -362,6 +473,22 @@ func (e *ExecutionParameters) UnmarshalJSON(data []byte) error {
 		e.FastDeadline = val
 	}
 
+	if aux.SnapshotTimeInterval != "" {
+		val, err := ParseHexDuration(aux.SnapshotTimeInterval)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot_time_interval: %w", err)
+		}
+		e.SnapshotTimeInterval = val
+	}
+
+	if aux.SnapshotCycleInterval != "" {
+		val, err := ParseHexUint64(aux.SnapshotCycleInterval)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot_cycle_interval: %w", err)
+		}
+		e.SnapshotCycleInterval = val
+	}
+
 	return nil
 }
 
@@ -385,6 +512,7 @@ type Epoch struct {
 	Index                uint64       `sql:"primary_key" json:"index"`
 	FirstBlock           uint64       `json:"first_block"`
 	LastBlock            uint64       `json:"last_block"`
+	BlockHash            *common.Hash `json:"-"`
 	ClaimHash            *common.Hash `json:"claim_hash"`
 	ClaimTransactionHash *common.Hash `json:"claim_transaction_hash"`
 	Status               EpochStatus  `json:"status"`
@@ -477,12 +605,19 @@ type Input struct {
 	EpochIndex           uint64                `json:"epoch_index"`
 	Index                uint64                `sql:"primary_key" json:"index"`
 	BlockNumber          uint64                `json:"block_number"`
+	BlockHash            *common.Hash          `json:"-"`
 	RawData              []byte                `json:"raw_data"`
 	Status               InputCompletionStatus `json:"status"`
 	MachineHash          *common.Hash          `json:"machine_hash"`
 	OutputsHash          *common.Hash          `json:"outputs_hash"`
 	TransactionReference common.Hash           `json:"transaction_reference"`
 	SnapshotURI          *string               `json:"-"`
+	Cycles               *uint64               `json:"cycles"` // execution metrics, nil until the input is processed
+	ExecutionTime        *time.Duration        `json:"execution_time"`
+	OutputsCount         *uint64               `json:"outputs_count"`
+	OutputsSize          *uint64               `json:"outputs_size"`
+	ReportsCount         *uint64               `json:"reports_count"`
+	ReportsSize          *uint64               `json:"reports_size"`
 	CreatedAt            time.Time             `json:"created_at"`
 	UpdatedAt            time.Time             `json:"updated_at"`
 }
@@ -492,21 +627,42 @@ func (i *Input) MarshalJSON() ([]byte, error) {
 	type Alias Input
 	// Define a new structure that embeds the alias but overrides the hex fields.
 	aux := &struct {
-		EpochIndex  string `json:"epoch_index"`
-		Index       string `json:"index"`
-		BlockNumber string `json:"block_number"`
-		RawData     string `json:"raw_data"`
+		EpochIndex    string  `json:"epoch_index"`
+		Index         string  `json:"index"`
+		BlockNumber   string  `json:"block_number"`
+		RawData       string  `json:"raw_data"`
+		Cycles        *string `json:"cycles"`
+		ExecutionTime *string `json:"execution_time"`
+		OutputsCount  *string `json:"outputs_count"`
+		OutputsSize   *string `json:"outputs_size"`
+		ReportsCount  *string `json:"reports_count"`
+		ReportsSize   *string `json:"reports_size"`
 		*Alias
 	}{
-		EpochIndex:  fmt.Sprintf("0x%x", i.EpochIndex),
-		Index:       fmt.Sprintf("0x%x", i.Index),
-		BlockNumber: fmt.Sprintf("0x%x", i.BlockNumber),
-		RawData:     "0x" + hex.EncodeToString(i.RawData),
-		Alias:       (*Alias)(i),
+		EpochIndex:    fmt.Sprintf("0x%x", i.EpochIndex),
+		Index:         fmt.Sprintf("0x%x", i.Index),
+		BlockNumber:   fmt.Sprintf("0x%x", i.BlockNumber),
+		RawData:       "0x" + hex.EncodeToString(i.RawData),
+		Cycles:        hexOrNil(i.Cycles),
+		ExecutionTime: hexOrNil(i.ExecutionTime),
+		OutputsCount:  hexOrNil(i.OutputsCount),
+		OutputsSize:   hexOrNil(i.OutputsSize),
+		ReportsCount:  hexOrNil(i.ReportsCount),
+		ReportsSize:   hexOrNil(i.ReportsSize),
+		Alias:         (*Alias)(i),
 	}
 	return json.Marshal(aux)
 }
 
+// hexOrNil encodes an optional quantity as a hex string, nil when missing.
+func hexOrNil[T ~int64 | ~uint64](value *T) *string {
+	if value == nil {
+		return nil
+	}
+	s := fmt.Sprintf("0x%x", uint64(*value))
+	return &s
+}
+
 type InputCompletionStatus string
 
 const (
@@ -648,6 +804,31 @@ type AdvanceResult struct {
 	Reports     [][]byte
 	OutputsHash common.Hash
 	MachineHash *common.Hash
+	// Machine cycles the input took to process
+	Cycles uint64
+	// Wall-clock time the input took to process
+	ExecutionTime time.Duration
+	// Block of the input, to tell it from an input read again at the same
+	// index after a chain reorganization. Checked when set.
+	InputBlockHash *common.Hash
+}
+
+// OutputsSize returns the total size of the outputs, in bytes.
+func (r *AdvanceResult) OutputsSize() uint64 {
+	return totalSize(r.Outputs)
+}
+
+// ReportsSize returns the total size of the reports, in bytes.
+func (r *AdvanceResult) ReportsSize() uint64 {
+	return totalSize(r.Reports)
+}
+
+func totalSize(data [][]byte) uint64 {
+	var size uint64
+	for _, d := range data {
+		size += uint64(len(d))
+	}
+	return size
 }
 
 type InspectResult struct {
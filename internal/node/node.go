@@ -5,8 +5,6 @@ package node
 
 import (
 	"context"
-	"fmt"
-	"os"
 
 	"github.com/cartesi/rollups-node/pkg/service"
 
@@ -35,7 +33,7 @@ type CreateInfo struct {
 type Service struct {
 	service.Service
 
-	Children   []service.IService
+	Supervisor *Supervisor
 	Client     *ethclient.Client
 	Repository repository.Repository
 }
@@ -57,102 +55,98 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 
 	s.Logger.Debug("Creating services", "config", c.Config)
 
-	err = createServices(ctx, c, s)
-	if err != nil {
-		s.Logger.Error(fmt.Sprint(err))
-		return nil, err
+	s.Supervisor = &Supervisor{
+		Logger:         s.Logger,
+		Policy:         c.Config.RestartPolicy,
+		MinBackoff:     c.Config.RestartMinBackoff,
+		MaxBackoff:     c.Config.RestartMaxBackoff,
+		StartupTimeout: c.Config.MaxStartupTime,
 	}
+	addServices(c, s)
 	return s, nil
 }
 
-func createServices(ctx context.Context, c *CreateInfo, s *Service) error {
-	ch := make(chan service.IService)
-	numChildren := 0
-
-	numChildren++
-	go func() {
-		ch <- newEVMReader(ctx, c, s)
-	}()
-
-	numChildren++
-	go func() {
-		ch <- newAdvancer(ctx, c, s)
-	}()
-
-	numChildren++
-	go func() {
-		ch <- newValidator(ctx, c, s)
-	}()
-
-	numChildren++
-	go func() {
-		ch <- newClaimer(ctx, c, s)
-	}()
-
+// addServices registers the children on the supervisor, which creates them
+// when the node is served
+func addServices(c *CreateInfo, s *Service) {
+	s.Supervisor.Add("evm-reader", func(ctx context.Context) (service.IService, error) {
+		return newEVMReader(ctx, c, s)
+	})
+	s.Supervisor.Add("advancer", func(ctx context.Context) (service.IService, error) {
+		return newAdvancer(ctx, c, s)
+	})
+	s.Supervisor.Add("validator", func(ctx context.Context) (service.IService, error) {
+		return newValidator(ctx, c, s)
+	})
+	s.Supervisor.Add("claimer", func(ctx context.Context) (service.IService, error) {
+		return newClaimer(ctx, c, s)
+	})
 	if c.Config.FeatureJsonrpcApiEnabled {
-		numChildren++
-		go func() {
-			ch <- newJsonrpc(ctx, c, s)
-		}()
+		s.Supervisor.Add("jsonrpc", func(ctx context.Context) (service.IService, error) {
+			return newJsonrpc(ctx, c, s)
+		})
 	}
-
-	for range numChildren {
-		select {
-		case child := <-ch:
-			s.Children = append(s.Children, child)
-		case <-ctx.Done():
-			err := ctx.Err()
-			s.Logger.Error("Failed to create services. Time limit exceeded",
-				"err", err)
-			return fmt.Errorf("failed to create services. Time limit exceeded")
-		}
-	}
-	return nil
 }
 
 func (me *Service) Alive() bool {
-	allAlive := true
-	for _, s := range me.Children {
-		allAlive = allAlive && s.Alive()
-	}
-	return allAlive
+	return me.Supervisor.Alive()
 }
 
 func (me *Service) Ready() bool {
-	allReady := true
-	for _, s := range me.Children {
-		allReady = allReady && s.Ready()
+	return me.Health(me.Context).Ready
+}
+
+// Health details the health of each child service. The node is ready when
+// all of them are running and ready.
+func (me *Service) Health(ctx context.Context) service.Health {
+	health := me.Service.Health(ctx)
+	for _, child := range me.Supervisor.Health(ctx) {
+		health.Ready = health.Ready && child.Ready
+		health.Services = append(health.Services, child)
 	}
-	return allReady
+	return health
 }
 
-func (s *Service) Reload() []error { return nil }
-func (s *Service) Tick() []error   { return nil }
-func (me *Service) Stop(force bool) []error {
-	errs := []error{}
-	for _, s := range me.Children {
-		errs = append(errs, s.Stop(force)...)
+// Reload applies the log level and makes the children reload their own
+// configuration
+func (me *Service) Reload() []error {
+	c, err := config.LoadNodeConfig()
+	if err != nil {
+		return []error{err}
+	}
+	me.SetLogLevel(c.LogLevel)
+	for _, s := range me.Supervisor.Services() {
+		s.RequestReload()
 	}
-	return errs
+	return nil
+}
+
+func (s *Service) Tick() []error { return nil }
+
+// Stop shuts the children down concurrently, each one waiting for its own
+// work in progress, and collects their errors
+func (me *Service) Stop(force bool) []error {
+	return me.Supervisor.Stop(force)
 }
 
 func (me *Service) Serve() error {
-	for _, s := range me.Children {
-		go s.Serve()
-	}
+	me.Supervisor.Start()
 	return me.Service.Serve()
 }
 
 // services creation
 
-func newEVMReader(ctx context.Context, c *CreateInfo, s *Service) service.IService {
+func newEVMReader(ctx context.Context, c *CreateInfo, s *Service) (service.IService, error) {
 	readerArgs := evmreader.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 "evm-reader",
 			LogLevel:             c.Config.LogLevel,
 			LogColor:             c.Config.LogColor,
+			LogFormat:            c.Config.LogFormat,
+			LogOutput:            c.LogOutput,
 			EnableSignalHandling: false,
 			TelemetryCreate:      false,
+			ShutdownTimeout:      c.Config.MaxShutdownTime,
 			ServeMux:             s.ServeMux,
 		},
 		EthClient:   c.ReaderClient,
@@ -163,20 +157,22 @@ func newEVMReader(ctx context.Context, c *CreateInfo, s *Service) service.IServi
 
 	readerService, err := evmreader.Create(ctx, &readerArgs)
 	if err != nil {
-		s.Logger.Error("Fatal", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	return readerService
+	return readerService, nil
 }
 
-func newAdvancer(ctx context.Context, c *CreateInfo, s *Service) service.IService {
+func newAdvancer(ctx context.Context, c *CreateInfo, s *Service) (service.IService, error) {
 	advancerArgs := advancer.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 "advancer",
 			LogLevel:             c.Config.LogLevel,
 			LogColor:             c.Config.LogColor,
+			LogFormat:            c.Config.LogFormat,
+			LogOutput:            c.LogOutput,
 			EnableSignalHandling: false,
 			TelemetryCreate:      false,
+			ShutdownTimeout:      c.Config.MaxShutdownTime,
 			PollInterval:         c.Config.AdvancerPollingInterval,
 			ServeMux:             s.ServeMux,
 		},
@@ -186,20 +182,22 @@ func newAdvancer(ctx context.Context, c *CreateInfo, s *Service) service.IServic
 
 	advancerService, err := advancer.Create(ctx, &advancerArgs)
 	if err != nil {
-		s.Logger.Error("Fatal", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	return advancerService
+	return advancerService, nil
 }
 
-func newValidator(ctx context.Context, c *CreateInfo, s *Service) service.IService {
+func newValidator(ctx context.Context, c *CreateInfo, s *Service) (service.IService, error) {
 	validatorArgs := validator.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 "validator",
 			LogLevel:             c.Config.LogLevel,
 			LogColor:             c.Config.LogColor,
+			LogFormat:            c.Config.LogFormat,
+			LogOutput:            c.LogOutput,
 			EnableSignalHandling: false,
 			TelemetryCreate:      false,
+			ShutdownTimeout:      c.Config.MaxShutdownTime,
 			PollInterval:         c.Config.ValidatorPollingInterval,
 			ServeMux:             s.ServeMux,
 		},
@@ -209,20 +207,22 @@ func newValidator(ctx context.Context, c *CreateInfo, s *Service) service.IServi
 
 	validatorService, err := validator.Create(ctx, &validatorArgs)
 	if err != nil {
-		s.Logger.Error("Fatal", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	return validatorService
+	return validatorService, nil
 }
 
-func newClaimer(ctx context.Context, c *CreateInfo, s *Service) service.IService {
+func newClaimer(ctx context.Context, c *CreateInfo, s *Service) (service.IService, error) {
 	claimerArgs := claimer.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 "claimer",
 			LogLevel:             c.Config.LogLevel,
 			LogColor:             c.Config.LogColor,
+			LogFormat:            c.Config.LogFormat,
+			LogOutput:            c.LogOutput,
 			EnableSignalHandling: false,
 			TelemetryCreate:      false,
+			ShutdownTimeout:      c.Config.MaxShutdownTime,
 			PollInterval:         c.Config.ClaimerPollingInterval,
 			ServeMux:             s.ServeMux,
 		},
@@ -233,20 +233,22 @@ func newClaimer(ctx context.Context, c *CreateInfo, s *Service) service.IService
 
 	claimerService, err := claimer.Create(ctx, &claimerArgs)
 	if err != nil {
-		s.Logger.Error("Fatal", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	return claimerService
+	return claimerService, nil
 }
 
-func newJsonrpc(ctx context.Context, c *CreateInfo, s *Service) service.IService {
+func newJsonrpc(ctx context.Context, c *CreateInfo, s *Service) (service.IService, error) {
 	jsonrpcArgs := jsonrpc.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 "jsonrpc",
 			LogLevel:             c.Config.LogLevel,
 			LogColor:             c.Config.LogColor,
+			LogFormat:            c.Config.LogFormat,
+			LogOutput:            c.LogOutput,
 			EnableSignalHandling: false,
 			TelemetryCreate:      false,
+			ShutdownTimeout:      c.Config.MaxShutdownTime,
 			ServeMux:             s.ServeMux,
 		},
 		Repository: c.Repository,
@@ -255,8 +257,7 @@ func newJsonrpc(ctx context.Context, c *CreateInfo, s *Service) service.IService
 
 	jsonrpcService, err := jsonrpc.Create(ctx, &jsonrpcArgs)
 	if err != nil {
-		s.Logger.Error("Fatal", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	return jsonrpcService
+	return jsonrpcService, nil
 }
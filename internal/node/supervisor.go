@@ -0,0 +1,260 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/pkg/service"
+)
+
+// CreateFunc creates a child service. The context bounds the creation only.
+type CreateFunc func(ctx context.Context) (service.IService, error)
+
+// ChildState is the lifecycle stage of a supervised service.
+type ChildState string
+
+const (
+	ChildStarting   ChildState = "starting"
+	ChildRunning    ChildState = "running"
+	ChildRestarting ChildState = "restarting"
+	ChildFailed     ChildState = "failed"
+	ChildStopped    ChildState = "stopped"
+)
+
+// Supervisor creates the child services, serves them and, according to the
+// restart policy, creates them again with an exponential backoff when they
+// fail to start, stop or panic.
+type Supervisor struct {
+	Logger         *slog.Logger
+	Policy         config.RestartPolicy
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+	StartupTimeout time.Duration
+
+	children []*child
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type child struct {
+	name   string
+	create CreateFunc
+
+	mutex    sync.Mutex
+	service  service.IService // nil while it is not running
+	state    ChildState
+	restarts uint64
+	lastErr  error
+}
+
+// Add registers a child service, to be created by Start.
+func (s *Supervisor) Add(name string, create CreateFunc) {
+	s.children = append(s.children, &child{name: name, create: create, state: ChildStarting})
+}
+
+// Start creates and serves each child in its own goroutine.
+func (s *Supervisor) Start() {
+	s.stop = make(chan struct{})
+	for _, c := range s.children {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.supervise(c)
+		}()
+	}
+}
+
+// Stop shuts the running children down concurrently, keeps the others from
+// being restarted and waits for their goroutines to return.
+func (s *Supervisor) Stop(force bool) []error {
+	if s.stop == nil {
+		return nil // not started
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	errs := []error{}
+	for _, c := range s.children {
+		svc := c.current()
+		if svc == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			childErrs := svc.Shutdown(force)
+			mutex.Lock()
+			errs = append(errs, childErrs...)
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+	s.wg.Wait()
+	return errs
+}
+
+// Services returns the children that are running.
+func (s *Supervisor) Services() []service.IService {
+	services := []service.IService{}
+	for _, c := range s.children {
+		if svc := c.current(); svc != nil {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// Alive reports whether no child failed for good and the running ones are
+// alive. Children waiting to be restarted only make the node not ready.
+func (s *Supervisor) Alive() bool {
+	for _, c := range s.children {
+		c.mutex.Lock()
+		svc, state := c.service, c.state
+		c.mutex.Unlock()
+		if state == ChildFailed || (svc != nil && !svc.Alive()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Health details the health of each child. Those that are not running are
+// not ready, with the reason in their "supervisor" check.
+func (s *Supervisor) Health(ctx context.Context) []service.Health {
+	healths := []service.Health{}
+	for _, c := range s.children {
+		c.mutex.Lock()
+		svc, state, restarts, lastErr := c.service, c.state, c.restarts, c.lastErr
+		c.mutex.Unlock()
+
+		if svc == nil {
+			check := service.Check{Name: "supervisor", Error: fmt.Sprintf("%v after %d restarts", state, restarts)}
+			if lastErr != nil {
+				check.Error = fmt.Sprintf("%s: %v", check.Error, lastErr)
+			}
+			healths = append(healths, service.Health{
+				Service: c.name,
+				Checks:  []service.Check{check},
+			})
+			continue
+		}
+		if reporter, ok := svc.(service.HealthReporter); ok {
+			healths = append(healths, reporter.Health(ctx))
+		} else {
+			healths = append(healths, service.Health{Service: svc.String(), Ready: svc.Ready()})
+		}
+	}
+	return healths
+}
+
+func (c *child) current() service.IService {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.service
+}
+
+func (c *child) setState(state ChildState, svc service.IService, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.state = state
+	c.service = svc
+	if err != nil {
+		c.lastErr = err
+	}
+}
+
+// supervise runs a child until the supervisor stops or the restart policy
+// leaves it down.
+func (s *Supervisor) supervise(c *child) {
+	backoff := s.MinBackoff
+	for {
+		start := time.Now()
+		err := s.run(c)
+		if s.stopping() {
+			c.setState(ChildStopped, nil, nil)
+			return
+		}
+
+		if err == nil && s.Policy != config.RestartPolicyAlways ||
+			err != nil && s.Policy == config.RestartPolicyNever {
+			s.Logger.Error("Service is down and will not be restarted",
+				"child", c.name,
+				"policy", s.Policy,
+				"error", err)
+			c.setState(ChildFailed, nil, err)
+			return
+		}
+
+		// a service that ran for a while before stopping starts over
+		if time.Since(start) > s.MaxBackoff {
+			backoff = s.MinBackoff
+		}
+		c.mutex.Lock()
+		c.restarts++
+		c.mutex.Unlock()
+		c.setState(ChildRestarting, nil, err)
+		s.Logger.Warn("Restarting service",
+			"child", c.name,
+			"backoff", backoff,
+			"error", err)
+
+		select {
+		case <-s.stop:
+			c.setState(ChildStopped, nil, nil)
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, s.MaxBackoff)
+	}
+}
+
+// run creates the child and serves it until it returns. Panics are
+// recovered and returned as errors, after the child is shut down.
+func (s *Supervisor) run(c *child) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.StartupTimeout)
+	svc, err := c.create(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to create: %w", err)
+	}
+	if svc == nil {
+		return errors.New("failed to create")
+	}
+
+	c.setState(ChildRunning, svc, nil)
+	defer c.setState(ChildStarting, nil, nil)
+
+	// Stop may have run before the child was stored, missing it
+	if s.stopping() {
+		svc.Shutdown(true)
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		// release what the child holds when it returned by itself
+		svc.Shutdown(true)
+	}()
+	return svc.Serve()
+}
+
+func (s *Supervisor) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,135 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package node
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/pkg/service"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSupervisor(t *testing.T) {
+	suite.Run(t, new(SupervisorSuite))
+}
+
+type SupervisorSuite struct{ suite.Suite }
+
+type fakeService struct {
+	service.Service
+	serve func() error // defaults to serving until stopped
+}
+
+func (s *fakeService) Alive() bool     { return true }
+func (s *fakeService) Ready() bool     { return true }
+func (s *fakeService) Reload() []error { return nil }
+func (s *fakeService) Tick() []error   { return nil }
+func (s *fakeService) Stop(bool) []error {
+	return nil
+}
+func (s *fakeService) Serve() error {
+	if s.serve != nil {
+		return s.serve()
+	}
+	return s.Service.Serve()
+}
+
+func newFakeService(ctx context.Context, serve func() error) (service.IService, error) {
+	s := &fakeService{serve: serve}
+	err := service.Create(ctx, &service.CreateInfo{Name: "fake", Impl: s}, &s.Service)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newSupervisor(policy config.RestartPolicy) *Supervisor {
+	return &Supervisor{
+		Logger:         service.NewLogger(slog.LevelError, false),
+		Policy:         policy,
+		MinBackoff:     time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		StartupTimeout: time.Second,
+	}
+}
+
+func (s *SupervisorSuite) TestRetriesCreation() {
+	require := s.Require()
+	supervisor := newSupervisor(config.RestartPolicyOnFailure)
+
+	var attempts atomic.Int32
+	supervisor.Add("fake", func(ctx context.Context) (service.IService, error) {
+		if attempts.Add(1) < 3 {
+			return nil, errors.New("database is down")
+		}
+		return newFakeService(ctx, nil)
+	})
+	supervisor.Start()
+
+	require.Eventually(func() bool {
+		return len(supervisor.Services()) == 1
+	}, time.Second, time.Millisecond)
+	require.EqualValues(3, attempts.Load())
+	require.True(supervisor.Alive())
+	require.True(supervisor.Health(context.Background())[0].Ready)
+
+	require.Empty(supervisor.Stop(false))
+	require.Empty(supervisor.Services())
+}
+
+func (s *SupervisorSuite) TestRestartsOnFailure() {
+	require := s.Require()
+	supervisor := newSupervisor(config.RestartPolicyOnFailure)
+
+	var runs atomic.Int32
+	supervisor.Add("failing", func(ctx context.Context) (service.IService, error) {
+		return newFakeService(ctx, func() error {
+			if runs.Add(1) == 1 {
+				panic("unexpected")
+			}
+			return errors.New("subscription lost")
+		})
+	})
+	supervisor.Add("exiting", func(ctx context.Context) (service.IService, error) {
+		return newFakeService(ctx, func() error { return nil })
+	})
+	supervisor.Start()
+
+	require.Eventually(func() bool { return runs.Load() > 3 }, time.Second, time.Millisecond)
+	require.Eventually(func() bool { return !supervisor.Alive() }, time.Second, time.Millisecond)
+
+	healths := supervisor.Health(context.Background())
+	require.Len(healths, 2)
+	require.Equal("exiting", healths[1].Service)
+	require.False(healths[1].Ready)
+	require.Contains(healths[1].Checks[0].Error, "failed after 0 restarts")
+
+	require.Empty(supervisor.Stop(false))
+}
+
+func (s *SupervisorSuite) TestNeverRestarts() {
+	require := s.Require()
+	supervisor := newSupervisor(config.RestartPolicyNever)
+
+	var runs atomic.Int32
+	supervisor.Add("failing", func(ctx context.Context) (service.IService, error) {
+		runs.Add(1)
+		return newFakeService(ctx, func() error { return errors.New("subscription lost") })
+	})
+	supervisor.Start()
+
+	require.Eventually(func() bool { return !supervisor.Alive() }, time.Second, time.Millisecond)
+	require.EqualValues(1, runs.Load())
+	health := supervisor.Health(context.Background())[0]
+	require.False(health.Ready)
+	require.Contains(health.Checks[0].Error, "subscription lost")
+
+	require.Empty(supervisor.Stop(false))
+}
@@ -0,0 +1,72 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package blob implements content-addressed stores for payloads that are too
+// large to be kept inline in the database.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrNotFound  = errors.New("blob not found")
+	ErrCorrupted = errors.New("blob does not match its key")
+)
+
+// DefaultThreshold is the payload size, in bytes, above which payloads are
+// offloaded when no other threshold is configured.
+const DefaultThreshold = 64 * 1024 //nolint:mnd
+
+// Store keeps payloads keyed by their keccak256 hash.
+type Store interface {
+	// Put stores data under key. Storing the same key twice is not an error.
+	Put(ctx context.Context, key common.Hash, data []byte) error
+	// Get retrieves the data stored under key, or ErrNotFound. It fails with
+	// ErrCorrupted when the data no longer hashes to key.
+	Get(ctx context.Context, key common.Hash) ([]byte, error)
+	// Delete removes the data stored under key, if any.
+	Delete(ctx context.Context, key common.Hash) error
+}
+
+// Key returns the key under which data is stored.
+func Key(data []byte) common.Hash {
+	return crypto.Keccak256Hash(data)
+}
+
+// verify returns data if it is stored under the right key
+func verify(key common.Hash, data []byte) ([]byte, error) {
+	if actual := Key(data); actual != key {
+		return nil, fmt.Errorf("%w: %s has hash %s", ErrCorrupted, key, actual)
+	}
+	return data, nil
+}
+
+// New chooses the store implementation based on the URI. For instance:
+//   - "file:///var/lib/cartesi-rollups-node/blobs" => filesystem directory
+//   - "s3://bucket/prefix" => S3-compatible object storage
+//
+// S3 endpoints other than AWS, like MinIO, can be set with the "endpoint" query
+// parameter, and the region with "region". Credentials are taken from the
+// standard AWS environment variables and configuration files.
+func New(ctx context.Context, uri string) (Store, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob store URI: %w", err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "file":
+		return NewFileStore(u.Host + u.Path)
+	case "s3":
+		return NewS3Store(ctx, u)
+	default:
+		return nil, fmt.Errorf("unrecognized blob store URI format: %s", uri)
+	}
+}
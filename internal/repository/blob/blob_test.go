@@ -0,0 +1,68 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package blob
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cartesi/rollups-node/test/tooling/fakes3"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	data := []byte("some large payload")
+	key := Key(data)
+
+	_, err := s.Get(ctx, key)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.Nil(t, s.Put(ctx, key, data))
+	require.Nil(t, s.Put(ctx, key, data))
+
+	stored, err := s.Get(ctx, key)
+	require.Nil(t, err)
+	require.Equal(t, data, stored)
+
+	require.Nil(t, s.Delete(ctx, key))
+	_, err = s.Get(ctx, key)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Nil(t, s.Delete(ctx, key))
+
+	// data that does not hash to its key is never returned
+	require.Nil(t, s.Put(ctx, key, data[:len(data)-1]))
+	_, err = s.Get(ctx, key)
+	require.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestFileStore(t *testing.T) {
+	s, err := New(context.Background(), "file://"+t.TempDir())
+	require.Nil(t, err)
+	require.IsType(t, &FileStore{}, s)
+	testStore(t, s)
+}
+
+func TestS3Store(t *testing.T) {
+	fakes3.SetCredentials(t)
+	fake := fakes3.New()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := New(context.Background(), "s3://bucket/blobs?region=us-east-1&endpoint="+server.URL)
+	require.Nil(t, err)
+	require.IsType(t, &S3Store{}, s)
+	testStore(t, s)
+
+	// objects are stored path-style under the prefix
+	data := []byte("payload")
+	require.Nil(t, s.Put(context.Background(), Key(data), data))
+	require.Contains(t, fake.Keys(), "/bucket/blobs/"+Key(data).Hex()[2:])
+}
+
+func TestNewRejectsUnknownSchemes(t *testing.T) {
+	_, err := New(context.Background(), "ftp://somewhere")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,92 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FileStore keeps each blob in its own file, spread over subdirectories named
+// after the first byte of the key to avoid huge directories.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("blob store directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:mnd
+		return nil, fmt.Errorf("failed to create blob store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(key common.Hash) string {
+	name := common.Bytes2Hex(key[:])
+	return filepath.Join(s.dir, name[:2], name)
+}
+
+func (s *FileStore) Put(ctx context.Context, key common.Hash, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := s.path(key)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:mnd
+		return err
+	}
+
+	// Write to a temporary file first so readers never see partial blobs.
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	err = errors.Join(err, f.Close())
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(f.Name()))
+	}
+	return nil
+}
+
+func (s *FileStore) Get(ctx context.Context, key common.Hash) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return verify(key, data)
+}
+
+func (s *FileStore) Delete(ctx context.Context, key common.Hash) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
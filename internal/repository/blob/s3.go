@@ -0,0 +1,120 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_cfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// S3Store keeps each blob as an object under a bucket prefix.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates a store from a "s3://bucket/prefix" URL.
+func NewS3Store(ctx context.Context, u *url.URL) (*S3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing bucket in blob store URI: %s", u.Redacted())
+	}
+
+	client, err := NewS3Client(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Store{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+// NewS3Client creates a client for the S3-compatible server selected by the
+// "endpoint" and "region" query parameters of a "s3://" URL.
+func NewS3Client(ctx context.Context, u *url.URL) (*s3.Client, error) {
+	var opts []func(*aws_cfg.LoadOptions) error
+	if region := u.Query().Get("region"); region != "" {
+		opts = append(opts, aws_cfg.WithRegion(region))
+	}
+	awsc, err := aws_cfg.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	endpoint := u.Query().Get("endpoint")
+	client := s3.NewFromConfig(awsc, func(o *s3.Options) {
+		if endpoint != "" {
+			// Self-hosted S3-compatible servers rarely support virtual-hosted
+			// buckets or the newer default integrity checksums.
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+			o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+		}
+	})
+	return client, nil
+}
+
+func (s *S3Store) key(key common.Hash) string {
+	return path.Join(s.prefix, common.Bytes2Hex(key[:]))
+}
+
+func (s *S3Store) Put(ctx context.Context, key common.Hash, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(s.key(key)),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put blob %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key common.Hash) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to get blob %s: %w", key, err)
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob %s: %w", key, err)
+	}
+	return verify(key, data)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key common.Hash) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,484 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+// ------------------------ ApplicationRepository Methods ------------------------ //
+
+// defaultExecutionParameters mirrors the column defaults of the execution_parameters table.
+func defaultExecutionParameters(appID int64, now time.Time) model.ExecutionParameters {
+	return model.ExecutionParameters{
+		ApplicationID:         appID,
+		SnapshotPolicy:        model.SnapshotPolicy_None,
+		AdvanceIncCycles:      1 << 22,   // nolint: mnd
+		AdvanceMaxCycles:      1<<62 - 1, // nolint: mnd
+		InspectIncCycles:      1 << 22,   // nolint: mnd
+		InspectMaxCycles:      1<<62 - 1, // nolint: mnd
+		AdvanceIncDeadline:    10 * time.Second,
+		AdvanceMaxDeadline:    180 * time.Second,
+		InspectIncDeadline:    10 * time.Second,
+		InspectMaxDeadline:    180 * time.Second,
+		LoadDeadline:          300 * time.Second,
+		StoreDeadline:         180 * time.Second,
+		FastDeadline:          5 * time.Second,
+		MaxConcurrentInspects: 10,  // nolint: mnd
+		SnapshotInputInterval: 100, // nolint: mnd
+		SnapshotTimeInterval:  time.Hour,
+		SnapshotCycleInterval: 1 << 40, // nolint: mnd
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+}
+
+func cloneApplication(app *model.Application) *model.Application {
+	c := *app
+	c.DataAvailability = slices.Clone(app.DataAvailability)
+	c.Reason = cloneString(app.Reason)
+	if app.ReasonCode != nil {
+		code := *app.ReasonCode
+		c.ReasonCode = &code
+	}
+	return &c
+}
+
+// checkApplication enforces the constraints of the application table.
+func (r *MemoryRepository) checkApplication(app *model.Application) error {
+	if len(app.Name) > maxVarcharLength || !applicationNameRegex.MatchString(app.Name) {
+		return fmt.Errorf("invalid application name: %q", app.Name)
+	}
+	if len(app.TemplateURI) > maxVarcharLength {
+		return fmt.Errorf("application template URI is too long")
+	}
+	if len(app.DataAvailability) < model.DATA_AVAILABILITY_SELECTOR_SIZE {
+		return fmt.Errorf("invalid application data availability: %x", app.DataAvailability)
+	}
+	if !slices.Contains(model.ApplicationStateAllValues, app.State) {
+		return fmt.Errorf("invalid application state: %q", app.State)
+	}
+	if app.State == model.ApplicationState_Inoperable && (app.Reason == nil || len(*app.Reason) == 0) {
+		return fmt.Errorf("a reason is required for inoperable application %q", app.Name)
+	}
+	if app.Reason != nil && len(*app.Reason) > maxVarcharLength {
+		return fmt.Errorf("application reason is too long")
+	}
+	if app.ReasonCode != nil && !slices.Contains(model.ReasonCodeAllValues, *app.ReasonCode) {
+		return fmt.Errorf("invalid application reason code: %q", *app.ReasonCode)
+	}
+	for _, other := range r.applications {
+		if other.ID == app.ID {
+			continue
+		}
+		if other.Name == app.Name {
+			return fmt.Errorf("application name %q already exists", app.Name)
+		}
+		if other.IApplicationAddress == app.IApplicationAddress {
+			return fmt.Errorf("application address %s already exists", app.IApplicationAddress)
+		}
+	}
+	return nil
+}
+
+func checkExecutionParameters(ep *model.ExecutionParameters) error {
+	if !slices.Contains(model.SnapshotPolicyAllValues, ep.SnapshotPolicy) {
+		return fmt.Errorf("invalid snapshot policy: %q", ep.SnapshotPolicy)
+	}
+	// The table stores these values in signed BIGINT columns.
+	for name, value := range map[string]uint64{
+		"advance_inc_cycles":      ep.AdvanceIncCycles,
+		"advance_max_cycles":      ep.AdvanceMaxCycles,
+		"inspect_inc_cycles":      ep.InspectIncCycles,
+		"inspect_max_cycles":      ep.InspectMaxCycles,
+		"snapshot_input_interval": ep.SnapshotInputInterval,
+		"snapshot_cycle_interval": ep.SnapshotCycleInterval,
+	} {
+		if value == 0 || value > 1<<63-1 {
+			return fmt.Errorf("invalid execution parameter %s: %d", name, value)
+		}
+	}
+	for name, value := range map[string]time.Duration{
+		"advance_inc_deadline":   ep.AdvanceIncDeadline,
+		"advance_max_deadline":   ep.AdvanceMaxDeadline,
+		"inspect_inc_deadline":   ep.InspectIncDeadline,
+		"inspect_max_deadline":   ep.InspectMaxDeadline,
+		"load_deadline":          ep.LoadDeadline,
+		"store_deadline":         ep.StoreDeadline,
+		"fast_deadline":          ep.FastDeadline,
+		"snapshot_time_interval": ep.SnapshotTimeInterval,
+	} {
+		if value <= 0 {
+			return fmt.Errorf("invalid execution parameter %s: %v", name, value)
+		}
+	}
+	if ep.MaxConcurrentInspects == 0 || ep.MaxConcurrentInspects > 1<<31-1 {
+		return fmt.Errorf("invalid execution parameter max_concurrent_inspects: %d", ep.MaxConcurrentInspects)
+	}
+	if ep.RetainedEpochs > 1<<63-1 {
+		return fmt.Errorf("invalid execution parameter retained_epochs: %d", ep.RetainedEpochs)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateApplication(
+	ctx context.Context,
+	app *model.Application,
+) (int64, error) {
+
+	var newID int64
+	err := r.update(ctx, func(t *tx) error {
+		now := time.Now()
+		row := &application{
+			Application:    *cloneApplication(app),
+			virtualIndexes: map[uint64]uint64{},
+			txReferences:   map[common.Hash]uint64{},
+			senders:        map[uint64][]byte{},
+		}
+		row.ID = r.nextAppID
+		row.CreatedAt = now
+		row.UpdatedAt = now
+		row.ExecutionParameters = defaultExecutionParameters(row.ID, now)
+		if err := r.checkApplication(&row.Application); err != nil {
+			return fmt.Errorf("unable to create database application: %w", err)
+		}
+
+		r.applications[row.ID] = row
+		r.nextAppID++
+		newID = row.ID
+		return nil
+	})
+	return newID, err
+}
+
+// GetApplication retrieves one application by name or address, including its execution parameters.
+func (r *MemoryRepository) GetApplication(
+	ctx context.Context,
+	nameOrAddress string,
+) (*model.Application, error) {
+
+	var app *model.Application
+	err := r.view(ctx, func() error {
+		if row := r.findApplication(nameOrAddress); row != nil {
+			app = cloneApplication(&row.Application)
+		}
+		return nil
+	})
+	return app, err
+}
+
+// GetProcessedInputs retrieves the ProcessedInputs field from a application by Name or address.
+func (r *MemoryRepository) GetProcessedInputs(
+	ctx context.Context,
+	nameOrAddress string,
+) (uint64, error) {
+
+	var processedInputs uint64
+	err := r.view(ctx, func() error {
+		row := r.findApplication(nameOrAddress)
+		if row == nil {
+			return repository.ErrNotFound
+		}
+		processedInputs = row.ProcessedInputs
+		return nil
+	})
+	return processedInputs, err
+}
+
+// UpdateApplication updates an existing application row.
+func (r *MemoryRepository) UpdateApplication(
+	ctx context.Context,
+	app *model.Application,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		row, ok := r.applications[app.ID]
+		if !ok {
+			return nil
+		}
+		updated := *cloneApplication(app)
+		updated.ExecutionParameters = row.ExecutionParameters
+		updated.CreatedAt = row.CreatedAt
+		updated.UpdatedAt = time.Now()
+		if err := r.checkApplication(&updated); err != nil {
+			return err
+		}
+		updateRow(t, &row.Application, func(a *model.Application) { *a = updated })
+		return nil
+	})
+}
+
+func (r *MemoryRepository) UpdateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state model.ApplicationState,
+	reason *string,
+) error {
+	return r.updateApplicationState(ctx, appID, state, reason, nil)
+}
+
+func (r *MemoryRepository) SetApplicationInoperable(
+	ctx context.Context,
+	appID int64,
+	code model.ReasonCode,
+	reason string,
+) error {
+	return r.updateApplicationState(ctx, appID, model.ApplicationState_Inoperable, &reason, &code)
+}
+
+func (r *MemoryRepository) updateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state model.ApplicationState,
+	reason *string,
+	code *model.ReasonCode,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		row, ok := r.applications[appID]
+		if !ok {
+			return nil
+		}
+		updated := *cloneApplication(&row.Application)
+		updated.State = state
+		updated.Reason = cloneString(reason)
+		updated.ReasonCode = code
+		updated.UpdatedAt = time.Now()
+		if err := r.checkApplication(&updated); err != nil {
+			return err
+		}
+		if row.State != state || !equalReasons(row.Reason, reason) {
+			change := &model.ApplicationStateChange{
+				ID:            r.nextStateChangeID,
+				ApplicationID: appID,
+				PreviousState: row.State,
+				State:         state,
+				Reason:        cloneString(reason),
+				CreatedAt:     updated.UpdatedAt,
+			}
+			err := insertRow(t, &row.stateHistory, uint64(change.ID), change, "application_state_history")
+			if err != nil {
+				return err
+			}
+			r.nextStateChangeID++
+		}
+		updateRow(t, &row.Application, func(a *model.Application) { *a = updated })
+		return nil
+	})
+}
+
+func (r *MemoryRepository) ListApplicationStateHistory(
+	ctx context.Context,
+	nameOrAddress string,
+) ([]*model.ApplicationStateChange, error) {
+
+	var changes []*model.ApplicationStateChange
+	err := r.view(ctx, func() error {
+		row := r.findApplication(nameOrAddress)
+		if row == nil {
+			return nil
+		}
+		for _, change := range row.stateHistory.vals {
+			c := *change
+			c.Reason = cloneString(change.Reason)
+			changes = append(changes, &c)
+		}
+		return nil
+	})
+	return changes, err
+}
+
+func (r *MemoryRepository) UpdateEventLastCheckBlock(
+	ctx context.Context,
+	appIDs []int64,
+	event model.MonitoredEvent,
+	blockNumber uint64,
+) error {
+
+	var set func(app *model.Application)
+	switch event {
+	case model.MonitoredEvent_InputAdded:
+		set = func(app *model.Application) { app.LastInputCheckBlock = blockNumber }
+	case model.MonitoredEvent_OutputExecuted:
+		set = func(app *model.Application) { app.LastOutputCheckBlock = blockNumber }
+	case model.MonitoredEvent_ClaimSubmitted:
+		fallthrough
+	case model.MonitoredEvent_ClaimAccepted:
+		fallthrough
+	default:
+		return fmt.Errorf("invalid monitored event type: %v", event)
+	}
+	if len(appIDs) == 0 {
+		return nil
+	}
+
+	return r.update(ctx, func(t *tx) error {
+		now := time.Now()
+		for _, id := range appIDs {
+			if row, ok := r.applications[id]; ok {
+				updateRow(t, &row.Application, func(app *model.Application) {
+					set(app)
+					app.UpdatedAt = now
+				})
+			}
+		}
+		return nil
+	})
+}
+
+// GetLastSnapshot retrieves the most recent input with a snapshot for the given application
+func (r *MemoryRepository) GetLastSnapshot(ctx context.Context, nameOrAddress string) (*model.Input, error) {
+	var input *model.Input
+	err := r.view(ctx, func() error {
+		row := r.findApplication(nameOrAddress)
+		if row == nil {
+			return nil
+		}
+		matches := filterRows(&row.inputs, repository.Pagination{}, true, func(in *model.Input) bool {
+			return in.Status == model.InputCompletionStatus_Accepted && in.SnapshotURI != nil
+		})
+		if len(matches) > 0 {
+			input = cloneInput(matches[0])
+		}
+		return nil
+	})
+	return input, err
+}
+
+// ListSnapshots retrieves the accepted inputs with a snapshot for the given
+// application, the most recent first, without their payloads
+func (r *MemoryRepository) ListSnapshots(ctx context.Context, nameOrAddress string) ([]*model.Input, error) {
+	var inputs []*model.Input
+	err := r.view(ctx, func() error {
+		row := r.findApplication(nameOrAddress)
+		if row == nil {
+			return nil
+		}
+		matches := filterRows(&row.inputs, repository.Pagination{}, true, func(in *model.Input) bool {
+			return in.Status == model.InputCompletionStatus_Accepted && in.SnapshotURI != nil
+		})
+		for _, in := range matches {
+			input := cloneInput(in)
+			input.RawData = nil
+			inputs = append(inputs, input)
+		}
+		return nil
+	})
+	return inputs, err
+}
+
+// ListSnapshotURIs retrieves the snapshot URIs referenced by the inputs of every application
+func (r *MemoryRepository) ListSnapshotURIs(ctx context.Context) ([]string, error) {
+	var uris []string
+	err := r.view(ctx, func() error {
+		for _, app := range r.applications {
+			for _, in := range app.inputs.vals {
+				if in.SnapshotURI != nil {
+					uris = append(uris, *in.SnapshotURI)
+				}
+			}
+		}
+		return nil
+	})
+	return uris, err
+}
+
+// DeleteApplication removes the application and, by cascade, everything that references it.
+func (r *MemoryRepository) DeleteApplication(
+	ctx context.Context,
+	id int64,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		row, ok := r.applications[id]
+		if !ok {
+			return fmt.Errorf("application with ID %d not found", id)
+		}
+		delete(r.applications, id)
+		t.onRollback(func() { r.applications[id] = row })
+		return nil
+	})
+}
+
+// ListApplications queries multiple apps with optional filters & pagination.
+func (r *MemoryRepository) ListApplications(
+	ctx context.Context,
+	f repository.ApplicationFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Application, uint64, error) {
+
+	var apps []*model.Application
+	var total uint64
+	err := r.view(ctx, func() error {
+		var all []*model.Application
+		for _, row := range r.applications {
+			if f.State != nil && row.State != *f.State {
+				continue
+			}
+			if f.DataAvailability != nil && !f.DataAvailability.MatchesBytes(row.DataAvailability) {
+				continue
+			}
+			all = append(all, &row.Application)
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if descending {
+				return all[i].Name > all[j].Name
+			}
+			return all[i].Name < all[j].Name
+		})
+		apps, total = paginate(all, p, cloneApplication)
+		return nil
+	})
+	return apps, total, err
+}
+
+func (r *MemoryRepository) GetExecutionParameters(
+	ctx context.Context,
+	applicationID int64,
+) (*model.ExecutionParameters, error) {
+
+	var ep *model.ExecutionParameters
+	err := r.view(ctx, func() error {
+		if row, ok := r.applications[applicationID]; ok {
+			c := row.ExecutionParameters
+			ep = &c
+		}
+		return nil
+	})
+	return ep, err
+}
+
+func (r *MemoryRepository) UpdateExecutionParameters(
+	ctx context.Context,
+	ep *model.ExecutionParameters,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		row, ok := r.applications[ep.ApplicationID]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		if err := checkExecutionParameters(ep); err != nil {
+			return err
+		}
+		updateRow(t, &row.ExecutionParameters, func(params *model.ExecutionParameters) {
+			createdAt := params.CreatedAt
+			*params = *ep
+			params.CreatedAt = createdAt
+			params.UpdatedAt = time.Now()
+		})
+		return nil
+	})
+}
@@ -0,0 +1,366 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+// getOutputNextIndex follows the same rule as the SQL backends: outputs are
+// numbered after the last output of an accepted input.
+func getOutputNextIndex(app *application) uint64 {
+	for i := app.outputs.len() - 1; i >= 0; i-- {
+		o := app.outputs.vals[i]
+		if app.inputs.get(o.InputIndex).Status == model.InputCompletionStatus_Accepted {
+			return o.Index + 1
+		}
+	}
+	return 0
+}
+
+func getReportNextIndex(app *application) uint64 {
+	if n := app.reports.len(); n > 0 {
+		return app.reports.keys[n-1] + 1
+	}
+	return 0
+}
+
+func getInputForInsert(app *application, inputIndex uint64, table string) (*model.Input, error) {
+	input := app.inputs.get(inputIndex)
+	if input == nil {
+		return nil, fmt.Errorf(
+			"insert on table \"%s\" violates foreign key constraint \"%s_input_id_fkey\": input %d",
+			table, table, inputIndex)
+	}
+	return input, nil
+}
+
+func insertOutputs(
+	t *tx,
+	app *application,
+	inputIndex uint64,
+	dataArray [][]byte,
+	now time.Time,
+) error {
+	if len(dataArray) < 1 {
+		return nil
+	}
+
+	input, err := getInputForInsert(app, inputIndex, "output")
+	if err != nil {
+		return err
+	}
+
+	nextIndex := getOutputNextIndex(app)
+	for i, data := range dataArray {
+		output := &model.Output{
+			InputEpochApplicationID: app.ID,
+			EpochIndex:              input.EpochIndex,
+			InputIndex:              inputIndex,
+			Index:                   nextIndex + uint64(i),
+			RawData:                 slices.Clone(data),
+			CreatedAt:               now,
+			UpdatedAt:               now,
+		}
+		if err := insertRow(t, &app.outputs, output.Index, output, "output"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertReports(
+	t *tx,
+	app *application,
+	inputIndex uint64,
+	dataArray [][]byte,
+	now time.Time,
+) error {
+	if len(dataArray) < 1 {
+		return nil
+	}
+
+	input, err := getInputForInsert(app, inputIndex, "report")
+	if err != nil {
+		return err
+	}
+
+	nextIndex := getReportNextIndex(app)
+	for i, data := range dataArray {
+		report := &model.Report{
+			InputEpochApplicationID: app.ID,
+			EpochIndex:              input.EpochIndex,
+			InputIndex:              inputIndex,
+			Index:                   nextIndex + uint64(i),
+			RawData:                 slices.Clone(data),
+			CreatedAt:               now,
+			UpdatedAt:               now,
+		}
+		if err := insertRow(t, &app.reports, report.Index, report, "report"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) StoreAdvanceResult(
+	ctx context.Context,
+	appID int64,
+	res *model.AdvanceResult,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		app, ok := r.applications[appID]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		now := time.Now()
+
+		input := app.inputs.get(res.InputIndex)
+		if res.InputBlockHash != nil &&
+			(input == nil || input.BlockHash == nil || *input.BlockHash != *res.InputBlockHash) {
+			return repository.ErrInputReverted
+		}
+		if input == nil {
+			return sql.ErrNoRows
+		}
+		if !slices.Contains(model.InputCompletionStatusAllValues, res.Status) {
+			return fmt.Errorf("invalid input status: %q", res.Status)
+		}
+
+		err := insertOutputs(t, app, res.InputIndex, res.Outputs, now)
+		if err != nil {
+			return err
+		}
+
+		err = insertReports(t, app, res.InputIndex, res.Reports, now)
+		if err != nil {
+			return err
+		}
+		machineHash := *res.MachineHash
+		outputsHash := res.OutputsHash
+		cycles := res.Cycles
+		executionTime := res.ExecutionTime
+		outputsCount, outputsSize := uint64(len(res.Outputs)), res.OutputsSize()
+		reportsCount, reportsSize := uint64(len(res.Reports)), res.ReportsSize()
+		updateRow(t, input, func(in *model.Input) {
+			in.Status = res.Status
+			in.MachineHash = &machineHash
+			in.OutputsHash = &outputsHash
+			in.Cycles = &cycles
+			in.ExecutionTime = &executionTime
+			in.OutputsCount = &outputsCount
+			in.OutputsSize = &outputsSize
+			in.ReportsCount = &reportsCount
+			in.ReportsSize = &reportsSize
+			in.UpdatedAt = now
+		})
+
+		updateRow(t, &app.Application, func(a *model.Application) {
+			a.ProcessedInputs = res.InputIndex + 1
+			a.UpdatedAt = now
+		})
+		return nil
+	})
+}
+
+func (r *MemoryRepository) StoreClaimAndProofs(ctx context.Context, epoch *model.Epoch, outputs []*model.Output) error {
+	return r.update(ctx, func(t *tx) error {
+		now := time.Now()
+
+		var row *model.Epoch
+		if app, ok := r.applications[epoch.ApplicationID]; ok {
+			row = app.epochs.get(epoch.Index)
+		}
+		if row == nil {
+			return fmt.Errorf("failed to update application %d epoch %d: no rows affected", epoch.ApplicationID, epoch.Index)
+		}
+		updateRow(t, row, func(e *model.Epoch) {
+			e.ClaimHash = cloneHash(epoch.ClaimHash)
+			e.Status = model.EpochStatus_ClaimComputed
+			e.UpdatedAt = now
+		})
+
+		for _, output := range outputs {
+			var o *model.Output
+			if app, ok := r.applications[output.InputEpochApplicationID]; ok {
+				o = app.outputs.get(output.Index)
+			}
+			if o == nil {
+				return fmt.Errorf("failed to insert proof for output '%d'. No rows affected", output.Index)
+			}
+			updateRow(t, o, func(out *model.Output) {
+				out.Hash = cloneHash(output.Hash)
+				out.OutputHashesSiblings = slices.Clone(output.OutputHashesSiblings)
+				out.UpdatedAt = now
+			})
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepository) UpdateInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64, snapshotURI string) error {
+	return r.update(ctx, func(t *tx) error {
+		var input *model.Input
+		if app, ok := r.applications[appId]; ok {
+			input = app.inputs.get(inputIndex)
+		}
+		if input == nil {
+			return fmt.Errorf("no input found with appId %d and index %d", appId, inputIndex)
+		}
+		if len(snapshotURI) > maxVarcharLength {
+			return fmt.Errorf("snapshot URI is too long")
+		}
+		updateRow(t, input, func(in *model.Input) {
+			in.SnapshotURI = &snapshotURI
+			in.UpdatedAt = time.Now()
+		})
+		return nil
+	})
+}
+
+func (r *MemoryRepository) ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error {
+	return r.update(ctx, func(t *tx) error {
+		if app, ok := r.applications[appId]; ok {
+			if input := app.inputs.get(inputIndex); input != nil {
+				updateRow(t, input, func(in *model.Input) {
+					in.SnapshotURI = nil
+					in.UpdatedAt = time.Now()
+				})
+			}
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepository) PruneEpochs(
+	ctx context.Context,
+	appID int64,
+	epochIndex uint64,
+	inputIndex uint64,
+) (uint64, uint64, error) {
+	var inputs, reports uint64
+	err := r.update(ctx, func(t *tx) error {
+		app, ok := r.applications[appID]
+		if !ok {
+			return nil
+		}
+		// only the accepted epochs before epochIndex are pruned
+		prunedEpoch := func(index uint64) bool {
+			e := app.epochs.get(index)
+			return e != nil && e.Status == model.EpochStatus_ClaimAccepted && e.Index < epochIndex
+		}
+		now := time.Now()
+
+		for _, report := range app.reports.vals {
+			if len(report.RawData) > 0 && prunedEpoch(report.EpochIndex) {
+				updateRow(t, report, func(rep *model.Report) {
+					rep.RawData = []byte{}
+					rep.UpdatedAt = now
+				})
+				reports++
+			}
+		}
+		for _, input := range app.inputs.vals {
+			if input.Index < inputIndex && len(input.RawData) > 0 && prunedEpoch(input.EpochIndex) {
+				updateRow(t, input, func(in *model.Input) {
+					in.RawData = []byte{}
+					in.UpdatedAt = now
+				})
+				inputs++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return inputs, reports, nil
+}
+
+func (r *MemoryRepository) RevertBlocks(
+	ctx context.Context,
+	appID int64,
+	blockNumber uint64,
+) (uint64, error) {
+	var inputs uint64
+	err := r.update(ctx, func(t *tx) error {
+		app, ok := r.applications[appID]
+		if !ok {
+			return nil
+		}
+		now := time.Now()
+
+		// the epochs with a claim on chain can not be reverted
+		for _, epoch := range app.epochs.vals {
+			if epoch.LastBlock > blockNumber && (epoch.Status == model.EpochStatus_ClaimSubmitted ||
+				epoch.Status == model.EpochStatus_ClaimAccepted) {
+				return fmt.Errorf("%w: epoch %d is %s", repository.ErrClaimedEpochReverted, epoch.Index, epoch.Status)
+			}
+		}
+
+		// the outputs and reports of the reverted inputs go with them, like
+		// the foreign keys of the SQL schemas
+		reverted := func(index uint64) bool {
+			in := app.inputs.get(index)
+			return in != nil && in.BlockNumber > blockNumber
+		}
+		deleteRows(t, &app.outputs, func(o *model.Output) bool { return reverted(o.InputIndex) })
+		deleteRows(t, &app.reports, func(rep *model.Report) bool { return reverted(rep.InputIndex) })
+		inputs = deleteRows(t, &app.inputs, func(in *model.Input) bool {
+			if in.BlockNumber <= blockNumber {
+				return false
+			}
+			sender := app.senders[in.Index]
+			delete(app.txReferences, in.TransactionReference)
+			delete(app.senders, in.Index)
+			t.onRollback(func() {
+				app.txReferences[in.TransactionReference] = in.Index
+				app.senders[in.Index] = sender
+			})
+			return true
+		})
+		deleteRows(t, &app.epochs, func(e *model.Epoch) bool {
+			if e.FirstBlock <= blockNumber {
+				return false
+			}
+			delete(app.virtualIndexes, e.VirtualIndex)
+			t.onRollback(func() { app.virtualIndexes[e.VirtualIndex] = e.Index })
+			return true
+		})
+
+		for _, epoch := range app.epochs.vals {
+			if epoch.FirstBlock <= blockNumber && epoch.LastBlock > blockNumber &&
+				epoch.Status != model.EpochStatus_Open {
+				updateRow(t, epoch, func(e *model.Epoch) {
+					e.Status = model.EpochStatus_Open
+					e.ClaimHash = nil
+					e.ClaimTransactionHash = nil
+					e.BlockHash = nil
+					e.UpdatedAt = now
+				})
+			}
+		}
+
+		remaining := uint64(app.inputs.len())
+		updateRow(t, &app.Application, func(a *model.Application) {
+			a.ProcessedInputs = min(a.ProcessedInputs, remaining)
+			a.LastInputCheckBlock = min(a.LastInputCheckBlock, blockNumber)
+			a.LastOutputCheckBlock = min(a.LastOutputCheckBlock, blockNumber)
+			a.UpdatedAt = now
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inputs, nil
+}
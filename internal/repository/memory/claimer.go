@@ -0,0 +1,146 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cartesi/rollups-node/internal/model"
+)
+
+// Retrieve the claim of each enabled application with the smallest index.
+// The query may return either 0 or 1 entries per application.
+func (r *MemoryRepository) selectOldestClaimPerApp(
+	epochStatus model.EpochStatus,
+) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+) {
+	epochs := map[int64]*model.Epoch{}
+	applications := map[int64]*model.Application{}
+	for id, app := range r.applications {
+		if app.State != model.ApplicationState_Enabled {
+			continue
+		}
+		for _, e := range app.epochs.vals {
+			if e.Status == epochStatus {
+				epochs[id] = cloneEpoch(e)
+				applications[id] = cloneApplication(&app.Application)
+				break
+			}
+		}
+	}
+	return epochs, applications
+}
+
+// Retrieve the newest accepted claim of each enabled application
+func (r *MemoryRepository) selectNewestAcceptedClaimPerApp(
+	includeSubmitted bool,
+) map[int64]*model.Epoch {
+	epochs := map[int64]*model.Epoch{}
+	for id, app := range r.applications {
+		if app.State != model.ApplicationState_Enabled {
+			continue
+		}
+		for i := app.epochs.len() - 1; i >= 0; i-- {
+			e := app.epochs.vals[i]
+			if e.Status == model.EpochStatus_ClaimAccepted ||
+				(includeSubmitted && e.Status == model.EpochStatus_ClaimSubmitted) {
+				epochs[id] = cloneEpoch(e)
+				break
+			}
+		}
+	}
+	return epochs
+}
+
+func (r *MemoryRepository) SelectSubmittedClaimPairsPerApp(ctx context.Context) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+	error,
+) {
+	var computed, acceptedOrSubmitted map[int64]*model.Epoch
+	var applications map[int64]*model.Application
+	err := r.view(ctx, func() error {
+		computed, applications = r.selectOldestClaimPerApp(model.EpochStatus_ClaimComputed)
+		acceptedOrSubmitted = r.selectNewestAcceptedClaimPerApp(true)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return acceptedOrSubmitted, computed, applications, nil
+}
+
+func (r *MemoryRepository) SelectAcceptedClaimPairsPerApp(ctx context.Context) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+	error,
+) {
+	var submitted, accepted map[int64]*model.Epoch
+	var applications map[int64]*model.Application
+	err := r.view(ctx, func() error {
+		submitted, applications = r.selectOldestClaimPerApp(model.EpochStatus_ClaimSubmitted)
+		accepted = r.selectNewestAcceptedClaimPerApp(false)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return accepted, submitted, applications, nil
+}
+
+// updateClaimStatus moves an epoch from one claim status to the next one.
+func (r *MemoryRepository) updateClaimStatus(
+	ctx context.Context,
+	applicationID int64,
+	index uint64,
+	from model.EpochStatus,
+	f func(e *model.Epoch),
+) error {
+	return r.update(ctx, func(t *tx) error {
+		app, ok := r.applications[applicationID]
+		if !ok {
+			return ErrNoUpdate
+		}
+		row := app.epochs.get(index)
+		if row == nil || row.Status != from {
+			return ErrNoUpdate
+		}
+		updateRow(t, row, func(e *model.Epoch) {
+			f(e)
+			e.UpdatedAt = time.Now()
+		})
+		return nil
+	})
+}
+
+func (r *MemoryRepository) UpdateEpochWithSubmittedClaim(
+	ctx context.Context,
+	application_id int64,
+	index uint64,
+	transaction_hash common.Hash,
+) error {
+	return r.updateClaimStatus(ctx, application_id, index, model.EpochStatus_ClaimComputed,
+		func(e *model.Epoch) {
+			e.ClaimTransactionHash = &transaction_hash
+			e.Status = model.EpochStatus_ClaimSubmitted
+		})
+}
+
+func (r *MemoryRepository) UpdateEpochWithAcceptedClaim(
+	ctx context.Context,
+	application_id int64,
+	index uint64,
+) error {
+	return r.updateClaimStatus(ctx, application_id, index, model.EpochStatus_ClaimSubmitted,
+		func(e *model.Epoch) {
+			e.Status = model.EpochStatus_ClaimAccepted
+		})
+}
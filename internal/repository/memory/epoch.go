@@ -0,0 +1,335 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+func cloneEpoch(e *model.Epoch) *model.Epoch {
+	c := *e
+	c.ClaimHash = cloneHash(e.ClaimHash)
+	c.ClaimTransactionHash = cloneHash(e.ClaimTransactionHash)
+	c.BlockHash = cloneHash(e.BlockHash)
+	return &c
+}
+
+// insertEpoch adds an epoch row, enforcing the epoch table constraints.
+func insertEpoch(t *tx, app *application, e *model.Epoch) error {
+	if !slices.Contains(model.EpochStatusAllValues, e.Status) {
+		return fmt.Errorf("invalid epoch status: %q", e.Status)
+	}
+	if _, ok := app.virtualIndexes[e.VirtualIndex]; ok {
+		return fmt.Errorf(
+			"duplicate key value violates unique constraint \"epoch_application_id_virtual_index_unique\": virtual index %d",
+			e.VirtualIndex)
+	}
+	if err := insertRow(t, &app.epochs, e.Index, e, "epoch"); err != nil {
+		return err
+	}
+	app.virtualIndexes[e.VirtualIndex] = e.Index
+	t.onRollback(func() { delete(app.virtualIndexes, e.VirtualIndex) })
+	return nil
+}
+
+// insertInput adds an input row, enforcing the input table constraints.
+func insertInput(t *tx, app *application, in *model.Input) error {
+	if !slices.Contains(model.InputCompletionStatusAllValues, in.Status) {
+		return fmt.Errorf("invalid input status: %q", in.Status)
+	}
+	if app.epochs.get(in.EpochIndex) == nil {
+		return fmt.Errorf(
+			"insert on table \"input\" violates foreign key constraint \"input_epoch_id_fkey\": epoch %d",
+			in.EpochIndex)
+	}
+	if _, ok := app.txReferences[in.TransactionReference]; ok {
+		return fmt.Errorf(
+			"duplicate key value violates unique constraint \"input_application_id_tx_reference_unique\": %s",
+			in.TransactionReference)
+	}
+	if err := insertRow(t, &app.inputs, in.Index, in, "input"); err != nil {
+		return err
+	}
+	app.txReferences[in.TransactionReference] = in.Index
+	app.senders[in.Index] = slices.Clone(repository.InputSender(in.RawData))
+	t.onRollback(func() {
+		delete(app.txReferences, in.TransactionReference)
+		delete(app.senders, in.Index)
+	})
+	return nil
+}
+
+func (r *MemoryRepository) CreateEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	e *model.Epoch,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		now := time.Now()
+		row := cloneEpoch(e)
+		row.ApplicationID = app.ID
+		row.CreatedAt = now
+		row.UpdatedAt = now
+		return insertEpoch(t, app, row)
+	})
+}
+
+func getEpochNextVirtualIndex(app *application) uint64 {
+	var next uint64
+	for virtualIndex := range app.virtualIndexes {
+		next = max(next, virtualIndex+1)
+	}
+	return next
+}
+
+func orderEpochs(epochInputsMap map[*model.Epoch][]*model.Input) []*model.Epoch {
+	epochs := make([]*model.Epoch, 0, len(epochInputsMap))
+	for e := range epochInputsMap {
+		epochs = append(epochs, e)
+	}
+
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i].FirstBlock < epochs[j].FirstBlock
+	})
+
+	return epochs
+}
+
+func (r *MemoryRepository) CreateEpochsAndInputs(
+	ctx context.Context,
+	nameOrAddress string,
+	epochInputsMap map[*model.Epoch][]*model.Input,
+	blockNumber uint64,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		now := time.Now()
+
+		epochs := orderEpochs(epochInputsMap)
+		for _, epoch := range epochs {
+			if existing := app.epochs.get(epoch.Index); existing != nil {
+				// the epoch is already known, just refresh its status
+				if !slices.Contains(model.EpochStatusAllValues, epoch.Status) {
+					return fmt.Errorf("invalid epoch status: %q", epoch.Status)
+				}
+				updateRow(t, existing, func(e *model.Epoch) {
+					e.Status = epoch.Status
+					e.BlockHash = cloneHash(epoch.BlockHash)
+					e.UpdatedAt = now
+				})
+			} else {
+				err := insertEpoch(t, app, &model.Epoch{
+					ApplicationID: app.ID,
+					Index:         epoch.Index,
+					FirstBlock:    epoch.FirstBlock,
+					LastBlock:     epoch.LastBlock,
+					BlockHash:     cloneHash(epoch.BlockHash),
+					Status:        epoch.Status,
+					VirtualIndex:  getEpochNextVirtualIndex(app),
+					CreatedAt:     now,
+					UpdatedAt:     now,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, input := range epochInputsMap[epoch] {
+				err := insertInput(t, app, &model.Input{
+					EpochApplicationID:   app.ID,
+					EpochIndex:           epoch.Index,
+					Index:                input.Index,
+					BlockNumber:          input.BlockNumber,
+					BlockHash:            cloneHash(input.BlockHash),
+					RawData:              slices.Clone(input.RawData),
+					Status:               input.Status,
+					TransactionReference: input.TransactionReference,
+					CreatedAt:            now,
+					UpdatedAt:            now,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Update last processed block
+		updateRow(t, &app.Application, func(a *model.Application) {
+			a.LastInputCheckBlock = blockNumber
+			a.UpdatedAt = now
+		})
+		return nil
+	})
+}
+
+func (r *MemoryRepository) GetEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	index uint64,
+) (*model.Epoch, error) {
+
+	var epoch *model.Epoch
+	err := r.view(ctx, func() error {
+		if app := r.findApplication(nameOrAddress); app != nil {
+			if e := app.epochs.get(index); e != nil {
+				epoch = cloneEpoch(e)
+			}
+		}
+		return nil
+	})
+	return epoch, err
+}
+
+func (r *MemoryRepository) GetLastAcceptedEpochIndex(
+	ctx context.Context,
+	nameOrAddress string,
+) (uint64, error) {
+
+	var index uint64
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return repository.ErrNotFound
+		}
+		accepted := filterRows(&app.epochs, repository.Pagination{}, true, func(e *model.Epoch) bool {
+			return e.Status == model.EpochStatus_ClaimAccepted
+		})
+		if len(accepted) == 0 {
+			return repository.ErrNotFound
+		}
+		index = accepted[0].Index
+		return nil
+	})
+	return index, err
+}
+
+func (r *MemoryRepository) GetEpochByVirtualIndex(
+	ctx context.Context,
+	nameOrAddress string,
+	index uint64,
+) (*model.Epoch, error) {
+
+	var epoch *model.Epoch
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		if epochIndex, ok := app.virtualIndexes[index]; ok {
+			epoch = cloneEpoch(app.epochs.get(epochIndex))
+		}
+		return nil
+	})
+	return epoch, err
+}
+
+func (r *MemoryRepository) UpdateEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	e *model.Epoch,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return sql.ErrNoRows
+		}
+		row := app.epochs.get(e.Index)
+		if row == nil {
+			return sql.ErrNoRows
+		}
+		if !slices.Contains(model.EpochStatusAllValues, e.Status) {
+			return fmt.Errorf("invalid epoch status: %q", e.Status)
+		}
+		updateRow(t, row, func(epoch *model.Epoch) {
+			epoch.ClaimHash = cloneHash(e.ClaimHash)
+			epoch.ClaimTransactionHash = cloneHash(e.ClaimTransactionHash)
+			epoch.Status = e.Status
+			epoch.UpdatedAt = time.Now()
+		})
+		return nil
+	})
+}
+
+func (r *MemoryRepository) UpdateEpochsInputsProcessed(
+	ctx context.Context,
+	nameOrAddress string,
+) (int64, error) {
+
+	var count int64
+	err := r.update(ctx, func(t *tx) error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+
+		pending := map[uint64]bool{}
+		for _, in := range app.inputs.vals {
+			if in.Status == model.InputCompletionStatus_None {
+				pending[in.EpochIndex] = true
+			}
+		}
+
+		now := time.Now()
+		for _, epoch := range app.epochs.vals {
+			if epoch.Status != model.EpochStatus_Closed || pending[epoch.Index] {
+				continue
+			}
+			updateRow(t, epoch, func(e *model.Epoch) {
+				e.Status = model.EpochStatus_InputsProcessed
+				e.UpdatedAt = now
+			})
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (r *MemoryRepository) ListEpochs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.EpochFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Epoch, uint64, error) {
+
+	var epochs []*model.Epoch
+	var total uint64
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		all := filterRows(&app.epochs, p, descending, func(e *model.Epoch) bool {
+			if f.Status != nil && e.Status != *f.Status {
+				return false
+			}
+			if f.BeforeBlock != nil && e.LastBlock >= *f.BeforeBlock {
+				return false
+			}
+			return true
+		})
+		epochs, total = paginate(all, p, cloneEpoch)
+		return nil
+	})
+	return epochs, total, err
+}
@@ -0,0 +1,153 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+func cloneInput(in *model.Input) *model.Input {
+	c := *in
+	c.RawData = slices.Clone(in.RawData)
+	c.BlockHash = cloneHash(in.BlockHash)
+	c.MachineHash = cloneHash(in.MachineHash)
+	c.OutputsHash = cloneHash(in.OutputsHash)
+	c.SnapshotURI = cloneString(in.SnapshotURI)
+	c.Cycles = cloneUint64(in.Cycles)
+	c.ExecutionTime = cloneDuration(in.ExecutionTime)
+	c.OutputsCount = cloneUint64(in.OutputsCount)
+	c.OutputsSize = cloneUint64(in.OutputsSize)
+	c.ReportsCount = cloneUint64(in.ReportsCount)
+	c.ReportsSize = cloneUint64(in.ReportsSize)
+	return &c
+}
+
+func (r *MemoryRepository) GetInput(
+	ctx context.Context,
+	nameOrAddress string,
+	inputIndex uint64,
+) (*model.Input, error) {
+
+	var input *model.Input
+	err := r.view(ctx, func() error {
+		if app := r.findApplication(nameOrAddress); app != nil {
+			if in := app.inputs.get(inputIndex); in != nil {
+				input = cloneInput(in)
+			}
+		}
+		return nil
+	})
+	return input, err
+}
+
+func (r *MemoryRepository) GetInputByTxReference(
+	ctx context.Context,
+	nameOrAddress string,
+	ref *common.Hash,
+) (*model.Input, error) {
+
+	if ref == nil {
+		return nil, fmt.Errorf("tx reference is nil")
+	}
+
+	var input *model.Input
+	err := r.view(ctx, func() error {
+		if app := r.findApplication(nameOrAddress); app != nil {
+			if index, ok := app.txReferences[*ref]; ok {
+				input = cloneInput(app.inputs.get(index))
+			}
+		}
+		return nil
+	})
+	return input, err
+}
+
+// lastInput returns the input with the highest index accepted by match.
+func (r *MemoryRepository) lastInput(
+	ctx context.Context,
+	nameOrAddress string,
+	match func(in *model.Input) bool,
+) (*model.Input, error) {
+
+	var input *model.Input
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		for i := app.inputs.len() - 1; i >= 0; i-- {
+			if in := app.inputs.vals[i]; match(in) {
+				input = cloneInput(in)
+				break
+			}
+		}
+		return nil
+	})
+	return input, err
+}
+
+func (r *MemoryRepository) GetLastInput(
+	ctx context.Context,
+	nameOrAddress string,
+	epochIndex uint64,
+) (*model.Input, error) {
+
+	return r.lastInput(ctx, nameOrAddress, func(in *model.Input) bool {
+		return in.EpochIndex == epochIndex
+	})
+}
+
+func (r *MemoryRepository) GetLastProcessedInput(
+	ctx context.Context,
+	nameOrAddress string,
+) (*model.Input, error) {
+
+	return r.lastInput(ctx, nameOrAddress, func(in *model.Input) bool {
+		return in.Status != model.InputCompletionStatus_None
+	})
+}
+
+func (r *MemoryRepository) ListInputs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.InputFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Input, uint64, error) {
+
+	var inputs []*model.Input
+	var total uint64
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		all := filterRows(&app.inputs, p, descending, func(in *model.Input) bool {
+			if f.EpochIndex != nil && in.EpochIndex != *f.EpochIndex {
+				return false
+			}
+			if f.Status != nil && in.Status != *f.Status {
+				return false
+			}
+			if f.NotStatus != nil && in.Status == *f.NotStatus {
+				return false
+			}
+			if f.Sender != nil && !bytes.Equal(app.senders[in.Index], f.Sender.Bytes()) {
+				return false
+			}
+			return true
+		})
+		inputs, total = paginate(all, p, cloneInput)
+		return nil
+	})
+	return inputs, total, err
+}
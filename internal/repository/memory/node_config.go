@@ -0,0 +1,66 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+type nodeConfigRow struct {
+	value     []byte
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+const maxNodeConfigKeyLength = 255
+
+func (r *MemoryRepository) SaveNodeConfigRaw(ctx context.Context, key string, rawJSON []byte) error {
+	if len(key) > maxNodeConfigKeyLength {
+		return fmt.Errorf("node config key is too long: %q", key)
+	}
+	if !json.Valid(rawJSON) {
+		return fmt.Errorf("invalid JSON value for node config key %q", key)
+	}
+
+	return r.update(ctx, func(t *tx) error {
+		now := time.Now()
+		if row, ok := r.nodeConfig[key]; ok {
+			updateRow(t, row, func(nc *nodeConfigRow) {
+				nc.value = slices.Clone(rawJSON)
+				nc.updatedAt = now
+			})
+			return nil
+		}
+		r.nodeConfig[key] = &nodeConfigRow{
+			value:     slices.Clone(rawJSON),
+			createdAt: now,
+			updatedAt: now,
+		}
+		t.onRollback(func() { delete(r.nodeConfig, key) })
+		return nil
+	})
+}
+
+func (r *MemoryRepository) LoadNodeConfigRaw(ctx context.Context, key string) ([]byte, time.Time, time.Time, error) {
+	var row nodeConfigRow
+	err := r.view(ctx, func() error {
+		nc, ok := r.nodeConfig[key]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		row = *nc
+		row.value = slices.Clone(nc.value)
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	return row.value, row.createdAt, row.updatedAt, nil
+}
@@ -0,0 +1,148 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+func cloneOutput(o *model.Output) *model.Output {
+	c := *o
+	c.RawData = slices.Clone(o.RawData)
+	c.Hash = cloneHash(o.Hash)
+	c.OutputHashesSiblings = slices.Clone(o.OutputHashesSiblings)
+	c.ExecutionTransactionHash = cloneHash(o.ExecutionTransactionHash)
+	return &c
+}
+
+func (r *MemoryRepository) GetOutput(
+	ctx context.Context,
+	nameOrAddress string,
+	outputIndex uint64,
+) (*model.Output, error) {
+
+	var output *model.Output
+	err := r.view(ctx, func() error {
+		if app := r.findApplication(nameOrAddress); app != nil {
+			if o := app.outputs.get(outputIndex); o != nil {
+				output = cloneOutput(o)
+			}
+		}
+		return nil
+	})
+	return output, err
+}
+
+func (r *MemoryRepository) UpdateOutputsExecution(
+	ctx context.Context,
+	nameOrAddress string,
+	outputs []*model.Output,
+	lastOutputCheckBlock uint64,
+) error {
+
+	return r.update(ctx, func(t *tx) error {
+		app := r.findApplication(nameOrAddress)
+		now := time.Now()
+
+		for _, o := range outputs {
+			if o.ExecutionTransactionHash == nil {
+				return fmt.Errorf("output ExecutionTransactionHash must be not nil when updating app %s output %d", nameOrAddress, o.Index)
+			}
+			var row *model.Output
+			if app != nil {
+				row = app.outputs.get(o.Index)
+			}
+			if row == nil {
+				return fmt.Errorf("no row affected when updating app %s epoch %d", nameOrAddress, o.Index)
+			}
+			updateRow(t, row, func(out *model.Output) {
+				out.ExecutionTransactionHash = cloneHash(o.ExecutionTransactionHash)
+				out.UpdatedAt = now
+			})
+		}
+
+		// Update last claim check block
+		if app != nil {
+			updateRow(t, &app.Application, func(a *model.Application) {
+				a.LastOutputCheckBlock = lastOutputCheckBlock
+				a.UpdatedAt = now
+			})
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepository) ListOutputs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.OutputFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Output, uint64, error) {
+
+	var outputs []*model.Output
+	var total uint64
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		all := filterRows(&app.outputs, p, descending, func(o *model.Output) bool {
+			input := app.inputs.get(o.InputIndex)
+			accepted := input.Status == model.InputCompletionStatus_Accepted
+			if f.BlockRange != nil {
+				if input.BlockNumber < f.BlockRange.Start || input.BlockNumber > f.BlockRange.End || !accepted {
+					return false
+				}
+			}
+			if f.EpochIndex != nil && (input.EpochIndex != *f.EpochIndex || !accepted) {
+				return false
+			}
+			if f.InputIndex != nil && o.InputIndex != *f.InputIndex {
+				return false
+			}
+			if f.OutputType != nil && !substrEquals(o.RawData, 1, 4, *f.OutputType) { // nolint: mnd
+				return false
+			}
+			if f.VoucherAddress != nil && !substrEquals(o.RawData, 17, 20, f.VoucherAddress.Bytes()) { // nolint: mnd
+				return false
+			}
+			return true
+		})
+		outputs, total = paginate(all, p, cloneOutput)
+		return nil
+	})
+	return outputs, total, err
+}
+
+func (r *MemoryRepository) GetLastOutputBeforeBlock(
+	ctx context.Context,
+	nameOrAddress string,
+	block uint64,
+) (*model.Output, error) {
+
+	var output *model.Output
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		for i := app.outputs.len() - 1; i >= 0; i-- {
+			o := app.outputs.vals[i]
+			input := app.inputs.get(o.InputIndex)
+			if input.BlockNumber < block && input.Status == model.InputCompletionStatus_Accepted {
+				output = cloneOutput(o)
+				break
+			}
+		}
+		return nil
+	})
+	return output, err
+}
@@ -0,0 +1,69 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+func cloneReport(rp *model.Report) *model.Report {
+	c := *rp
+	c.RawData = slices.Clone(rp.RawData)
+	return &c
+}
+
+func (r *MemoryRepository) GetReport(
+	ctx context.Context,
+	nameOrAddress string,
+	reportIndex uint64,
+) (*model.Report, error) {
+
+	var report *model.Report
+	err := r.view(ctx, func() error {
+		if app := r.findApplication(nameOrAddress); app != nil {
+			if rp := app.reports.get(reportIndex); rp != nil {
+				report = cloneReport(rp)
+			}
+		}
+		return nil
+	})
+	return report, err
+}
+
+func (r *MemoryRepository) ListReports(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.ReportFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Report, uint64, error) {
+
+	var reports []*model.Report
+	var total uint64
+	err := r.view(ctx, func() error {
+		app := r.findApplication(nameOrAddress)
+		if app == nil {
+			return nil
+		}
+		all := filterRows(&app.reports, p, descending, func(rp *model.Report) bool {
+			if f.InputIndex != nil && rp.InputIndex != *f.InputIndex {
+				return false
+			}
+			if f.EpochIndex != nil {
+				input := app.inputs.get(rp.InputIndex)
+				if input.EpochIndex != *f.EpochIndex || input.Status != model.InputCompletionStatus_Accepted {
+					return false
+				}
+			}
+			return true
+		})
+		reports, total = paginate(all, p, cloneReport)
+		return nil
+	})
+	return reports, total, err
+}
@@ -0,0 +1,160 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+const connectionPrefix = "memory://"
+
+var (
+	ErrClosed   = errors.New("memory repository is closed")
+	ErrNoUpdate = fmt.Errorf("update did not take effect")
+)
+
+// MemoryRepository is the concrete type that implements the repository.Repository interface
+// keeping every table in process memory. Data lives as long as the repository itself.
+//
+// Each method behaves as a single database transaction: it either applies all of its
+// changes or, on error, none of them.
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	closed bool
+
+	nextAppID         int64
+	nextStateChangeID int64
+	applications      map[int64]*application
+	nodeConfig        map[string]*nodeConfigRow
+}
+
+// application holds one application row together with every row that references it,
+// so deleting it cascades like the foreign keys of the SQL schemas.
+type application struct {
+	model.Application
+
+	epochs  rows[model.Epoch]
+	inputs  rows[model.Input]
+	outputs rows[model.Output]
+	reports rows[model.Report]
+
+	stateHistory rows[model.ApplicationStateChange]
+
+	virtualIndexes map[uint64]uint64
+	txReferences   map[common.Hash]uint64
+	// senders mirrors the sender column of the inputs, which outlives their
+	// pruned payloads
+	senders map[uint64][]byte
+}
+
+// IsConnectionString reports whether conn selects the in-memory backend.
+func IsConnectionString(conn string) bool {
+	return strings.HasPrefix(strings.ToLower(conn), connectionPrefix)
+}
+
+// NewMemoryRepository creates an empty repository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		nextAppID:         1,
+		nextStateChangeID: 1,
+		applications:      map[int64]*application{},
+		nodeConfig:        map[string]*nodeConfigRow{},
+	}
+}
+
+// NewMemoryRepositoryFromConnectionString creates an empty repository for a "memory://"
+// connection string. Anything after the prefix is ignored.
+func NewMemoryRepositoryFromConnectionString(conn string) (repository.Repository, error) {
+	if !IsConnectionString(conn) {
+		return nil, fmt.Errorf("invalid memory connection string: %s", conn)
+	}
+	return NewMemoryRepository(), nil
+}
+
+func (r *MemoryRepository) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.applications = nil
+	r.nodeConfig = nil
+}
+
+func (r *MemoryRepository) Ping(ctx context.Context) error {
+	return r.view(ctx, func() error { return nil })
+}
+
+// tx records how to revert the changes of a write operation.
+type tx struct {
+	undo []func()
+}
+
+func (t *tx) onRollback(f func()) {
+	t.undo = append(t.undo, f)
+}
+
+func (t *tx) rollback() {
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.undo = nil
+}
+
+// view runs f holding the read lock.
+func (r *MemoryRepository) view(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return ErrClosed
+	}
+	return f()
+}
+
+// update runs f holding the write lock and reverts its changes if it fails.
+func (r *MemoryRepository) update(ctx context.Context, f func(t *tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	t := &tx{}
+	if err := f(t); err != nil {
+		t.rollback()
+		return err
+	}
+	return nil
+}
+
+// findApplication returns the application matching a name or a hex address, or nil.
+func (r *MemoryRepository) findApplication(nameOrAddress string) *application {
+	if isHexAddress(nameOrAddress) {
+		address := common.HexToAddress(nameOrAddress)
+		for _, app := range r.applications {
+			if app.IApplicationAddress == address {
+				return app
+			}
+		}
+		return nil
+	}
+	for _, app := range r.applications {
+		if app.Name == nameOrAddress {
+			return app
+		}
+	}
+	return nil
+}
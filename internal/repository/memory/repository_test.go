@@ -0,0 +1,21 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/test/tooling/repotest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	suite.Run(t, &repotest.RepositorySuite{
+		NewRepository: func(t *testing.T) repository.Repository {
+			return NewMemoryRepository()
+		},
+	})
+}
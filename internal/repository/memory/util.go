@@ -0,0 +1,194 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+var hexAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+var applicationNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+const maxVarcharLength = 4096
+
+func isHexAddress(s string) bool {
+	return hexAddressRegex.MatchString(s)
+}
+
+// rows keeps the rows of a table sorted by their index, the second half of
+// every primary key in the schema.
+type rows[T any] struct {
+	keys []uint64
+	vals []*T
+}
+
+func (r *rows[T]) get(key uint64) *T {
+	i, found := slices.BinarySearch(r.keys, key)
+	if !found {
+		return nil
+	}
+	return r.vals[i]
+}
+
+// insert adds a row, returning false if the key is already taken.
+func (r *rows[T]) insert(key uint64, val *T) bool {
+	i, found := slices.BinarySearch(r.keys, key)
+	if found {
+		return false
+	}
+	r.keys = slices.Insert(r.keys, i, key)
+	r.vals = slices.Insert(r.vals, i, val)
+	return true
+}
+
+func (r *rows[T]) delete(key uint64) {
+	i, found := slices.BinarySearch(r.keys, key)
+	if found {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.vals = slices.Delete(r.vals, i, i+1)
+	}
+}
+
+func (r *rows[T]) len() int {
+	return len(r.keys)
+}
+
+// insertRow adds a row inside a transaction.
+func insertRow[T any](t *tx, r *rows[T], key uint64, val *T, table string) error {
+	if !r.insert(key, val) {
+		return fmt.Errorf("duplicate key value violates unique constraint \"%s_pkey\": index %d", table, key)
+	}
+	t.onRollback(func() { r.delete(key) })
+	return nil
+}
+
+// updateRow lets f modify a row inside a transaction.
+func updateRow[T any](t *tx, row *T, f func(row *T)) {
+	prev := *row
+	t.onRollback(func() { *row = prev })
+	f(row)
+}
+
+// deleteRows removes the rows accepted by match inside a transaction,
+// returning how many were removed.
+func deleteRows[T any](t *tx, r *rows[T], match func(row *T) bool) uint64 {
+	prevKeys, prevVals := slices.Clone(r.keys), slices.Clone(r.vals)
+	var keys []uint64
+	var vals []*T
+	for i, row := range r.vals {
+		if !match(row) {
+			keys = append(keys, r.keys[i])
+			vals = append(vals, row)
+		}
+	}
+	removed := uint64(len(r.keys) - len(keys))
+	if removed > 0 {
+		t.onRollback(func() { r.keys, r.vals = prevKeys, prevVals })
+		r.keys, r.vals = keys, vals
+	}
+	return removed
+}
+
+// filterRows returns the rows after the pagination cursor that are accepted
+// by match, ordered by index.
+func filterRows[T any](
+	r *rows[T],
+	p repository.Pagination,
+	descending bool,
+	match func(row *T) bool,
+) []*T {
+	var result []*T
+	for i, row := range r.vals {
+		if p.After != nil {
+			if descending && r.keys[i] >= *p.After || !descending && r.keys[i] <= *p.After {
+				continue
+			}
+		}
+		if match(row) {
+			result = append(result, row)
+		}
+	}
+	if descending {
+		slices.Reverse(result)
+	}
+	return result
+}
+
+// paginate applies p to the already filtered and ordered rows, returning a copy
+// of the selected page and the total number of rows.
+func paginate[T any](all []*T, p repository.Pagination, clone func(*T) *T) ([]*T, uint64) {
+	total := uint64(len(all))
+	start := min(p.Offset, total)
+	end := total
+	if p.Limit > 0 {
+		end = min(start+p.Limit, total)
+	}
+	var page []*T
+	for _, row := range all[start:end] {
+		page = append(page, clone(row))
+	}
+	return page, total
+}
+
+func cloneHash(h *common.Hash) *common.Hash {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	return &c
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func cloneUint64(u *uint64) *uint64 {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	return &c
+}
+
+func cloneDuration(d *time.Duration) *time.Duration {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	return &c
+}
+
+// substr mirrors SQL SUBSTRING with a 1-based start.
+func substr(data []byte, start, length int) []byte {
+	start--
+	if start >= len(data) {
+		return nil
+	}
+	return data[start:min(start+length, len(data))]
+}
+
+func substrEquals(data []byte, start, length int, value []byte) bool {
+	return bytes.Equal(substr(data, start, length), value)
+}
+
+// equalReasons reports whether two nullable reasons are the same.
+func equalReasons(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
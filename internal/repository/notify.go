@@ -0,0 +1,14 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package repository
+
+// Channels notified by the Postgres backend when rows reach the status a
+// service waits for. The payload is the ID of the application that owns the
+// row.
+const (
+	InputAddedChannel           = "input_added"            // inputs added, for the advancer
+	EpochClosedChannel          = "epoch_closed"           // epochs closed, for the advancer
+	EpochInputsProcessedChannel = "epoch_inputs_processed" // epochs with all inputs processed, for the validator
+	EpochClaimComputedChannel   = "epoch_claim_computed"   // epochs with a computed claim, for the claimer
+)
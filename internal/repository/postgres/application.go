@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-jet/jet/v2/postgres"
 
 	"github.com/cartesi/rollups-node/internal/model"
@@ -112,6 +113,7 @@ func (r *PostgresRepository) GetApplication(
 			table.Application.DataAvailability,
 			table.Application.State,
 			table.Application.Reason,
+			table.Application.ReasonCode,
 			table.Application.IinputboxBlock,
 			table.Application.LastInputCheckBlock,
 			table.Application.LastOutputCheckBlock,
@@ -132,6 +134,10 @@ func (r *PostgresRepository) GetApplication(
 			table.ExecutionParameters.StoreDeadline,
 			table.ExecutionParameters.FastDeadline,
 			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
 			table.ExecutionParameters.CreatedAt,
 			table.ExecutionParameters.UpdatedAt,
 		).
@@ -159,6 +165,7 @@ func (r *PostgresRepository) GetApplication(
 		&app.DataAvailability,
 		&app.State,
 		&app.Reason,
+		&app.ReasonCode,
 		&app.IInputBoxBlock,
 		&app.LastInputCheckBlock,
 		&app.LastOutputCheckBlock,
@@ -179,6 +186,10 @@ func (r *PostgresRepository) GetApplication(
 		&app.ExecutionParameters.StoreDeadline,
 		&app.ExecutionParameters.FastDeadline,
 		&app.ExecutionParameters.MaxConcurrentInspects,
+		&app.ExecutionParameters.RetainedEpochs,
+		&app.ExecutionParameters.SnapshotInputInterval,
+		&app.ExecutionParameters.SnapshotTimeInterval,
+		&app.ExecutionParameters.SnapshotCycleInterval,
 		&app.ExecutionParameters.CreatedAt,
 		&app.ExecutionParameters.UpdatedAt,
 	)
@@ -242,6 +253,7 @@ func (r *PostgresRepository) UpdateApplication(
 			table.Application.DataAvailability,
 			table.Application.State,
 			table.Application.Reason,
+			table.Application.ReasonCode,
 			table.Application.IinputboxBlock,
 			table.Application.LastInputCheckBlock,
 			table.Application.LastOutputCheckBlock,
@@ -258,6 +270,7 @@ func (r *PostgresRepository) UpdateApplication(
 			app.DataAvailability[:],
 			app.State,
 			app.Reason,
+			app.ReasonCode,
 			app.IInputBoxBlock,
 			app.LastInputCheckBlock,
 			app.LastOutputCheckBlock,
@@ -276,21 +289,149 @@ func (r *PostgresRepository) UpdateApplicationState(
 	state model.ApplicationState,
 	reason *string,
 ) error {
+	return r.updateApplicationState(ctx, appID, state, reason, nil)
+}
 
-	updateStmt := table.Application.
+func (r *PostgresRepository) SetApplicationInoperable(
+	ctx context.Context,
+	appID int64,
+	code model.ReasonCode,
+	reason string,
+) error {
+	return r.updateApplicationState(ctx, appID, model.ApplicationState_Inoperable, &reason, &code)
+}
+
+func (r *PostgresRepository) updateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state model.ApplicationState,
+	reason *string,
+	code *model.ReasonCode,
+) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	sqlStr, args := table.Application.
+		SELECT(
+			table.Application.State,
+			table.Application.Reason,
+		).
+		WHERE(table.Application.ID.EQ(postgres.Int(appID))).
+		FOR(postgres.UPDATE()).
+		Sql()
+
+	var previousState model.ApplicationState
+	var previousReason *string
+	err = tx.QueryRow(ctx, sqlStr, args...).Scan(&previousState, &previousReason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx.Rollback(ctx)
+	}
+	if err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+
+	sqlStr, args = table.Application.
 		UPDATE(
 			table.Application.State,
 			table.Application.Reason,
+			table.Application.ReasonCode,
 		).
 		SET(
 			state,
 			reason,
+			code,
 		).
-		WHERE(table.Application.ID.EQ(postgres.Int(appID)))
+		WHERE(table.Application.ID.EQ(postgres.Int(appID))).
+		Sql()
 
-	sqlStr, args := updateStmt.Sql()
-	_, err := r.db.Exec(ctx, sqlStr, args...)
-	return err
+	_, err = tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+
+	if previousState != state || !equalReasons(previousReason, reason) {
+		sqlStr, args = table.ApplicationStateHistory.
+			INSERT(
+				table.ApplicationStateHistory.ApplicationID,
+				table.ApplicationStateHistory.PreviousState,
+				table.ApplicationStateHistory.State,
+				table.ApplicationStateHistory.Reason,
+			).
+			VALUES(
+				appID,
+				previousState,
+				state,
+				reason,
+			).Sql()
+
+		_, err = tx.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return errors.Join(err, tx.Rollback(ctx))
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListApplicationStateHistory(
+	ctx context.Context,
+	nameOrAddress string,
+) ([]*model.ApplicationStateChange, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.ApplicationStateHistory.
+		SELECT(
+			table.ApplicationStateHistory.ID,
+			table.ApplicationStateHistory.ApplicationID,
+			table.ApplicationStateHistory.PreviousState,
+			table.ApplicationStateHistory.State,
+			table.ApplicationStateHistory.Reason,
+			table.ApplicationStateHistory.CreatedAt,
+		).
+		FROM(
+			table.ApplicationStateHistory.
+				INNER_JOIN(table.Application,
+					table.ApplicationStateHistory.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(whereClause).
+		ORDER_BY(table.ApplicationStateHistory.ID.ASC())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.ApplicationStateChange
+	for rows.Next() {
+		var change model.ApplicationStateChange
+		err := rows.Scan(
+			&change.ID,
+			&change.ApplicationID,
+			&change.PreviousState,
+			&change.State,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+	return changes, rows.Err()
 }
 
 func (r *PostgresRepository) UpdateEventLastCheckBlock(
@@ -348,7 +489,9 @@ func (r *PostgresRepository) GetLastSnapshot(ctx context.Context, nameOrAddress
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
@@ -375,12 +518,15 @@ func (r *PostgresRepository) GetLastSnapshot(ctx context.Context, nameOrAddress
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var inp model.Input
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&inp.EpochApplicationID,
 		&inp.EpochIndex,
 		&inp.Index,
 		&inp.BlockNumber,
+		&inp.BlockHash,
 		&inp.RawData,
+		&rawDataHash,
 		&inp.Status,
 		&inp.MachineHash,
 		&inp.OutputsHash,
@@ -395,9 +541,104 @@ func (r *PostgresRepository) GetLastSnapshot(ctx context.Context, nameOrAddress
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &inp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &inp, nil
 }
 
+// ListSnapshots retrieves the accepted inputs with a snapshot for the given
+// application, the most recent first, without their payloads
+func (r *PostgresRepository) ListSnapshots(ctx context.Context, nameOrAddress string) ([]*model.Input, error) {
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.Status.EQ(postgres.NewEnumValue(model.InputCompletionStatus_Accepted.String()))).
+				AND(table.Input.SnapshotURI.IS_NOT_NULL()),
+		).
+		ORDER_BY(table.Input.Index.DESC())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inputs []*model.Input
+	for rows.Next() {
+		var inp model.Input
+		err := rows.Scan(
+			&inp.EpochApplicationID,
+			&inp.EpochIndex,
+			&inp.Index,
+			&inp.BlockNumber,
+			&inp.BlockHash,
+			&inp.Status,
+			&inp.MachineHash,
+			&inp.OutputsHash,
+			&inp.TransactionReference,
+			&inp.SnapshotURI,
+			&inp.CreatedAt,
+			&inp.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, &inp)
+	}
+	return inputs, rows.Err()
+}
+
+// ListSnapshotURIs retrieves the snapshot URIs referenced by the inputs of every application
+func (r *PostgresRepository) ListSnapshotURIs(ctx context.Context) ([]string, error) {
+	sel := table.Input.
+		SELECT(table.Input.SnapshotURI).
+		WHERE(table.Input.SnapshotURI.IS_NOT_NULL())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uris []string
+	for rows.Next() {
+		var uri string
+		if err := rows.Scan(&uri); err != nil {
+			return nil, err
+		}
+		uris = append(uris, uri)
+	}
+	return uris, rows.Err()
+}
+
 // DeleteApplication removes the row from "application" by ID.
 func (r *PostgresRepository) DeleteApplication(
 	ctx context.Context,
@@ -439,6 +680,7 @@ func (r *PostgresRepository) ListApplications(
 			table.Application.DataAvailability,
 			table.Application.State,
 			table.Application.Reason,
+			table.Application.ReasonCode,
 			table.Application.IinputboxBlock,
 			table.Application.LastInputCheckBlock,
 			table.Application.LastOutputCheckBlock,
@@ -459,6 +701,10 @@ func (r *PostgresRepository) ListApplications(
 			table.ExecutionParameters.StoreDeadline,
 			table.ExecutionParameters.FastDeadline,
 			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
 			table.ExecutionParameters.CreatedAt,
 			table.ExecutionParameters.UpdatedAt,
 			postgres.COUNT(postgres.STAR).OVER().AS("total_count"),
@@ -522,6 +768,7 @@ func (r *PostgresRepository) ListApplications(
 			&app.DataAvailability,
 			&app.State,
 			&app.Reason,
+			&app.ReasonCode,
 			&app.IInputBoxBlock,
 			&app.LastInputCheckBlock,
 			&app.LastOutputCheckBlock,
@@ -542,6 +789,10 @@ func (r *PostgresRepository) ListApplications(
 			&app.ExecutionParameters.StoreDeadline,
 			&app.ExecutionParameters.FastDeadline,
 			&app.ExecutionParameters.MaxConcurrentInspects,
+			&app.ExecutionParameters.RetainedEpochs,
+			&app.ExecutionParameters.SnapshotInputInterval,
+			&app.ExecutionParameters.SnapshotTimeInterval,
+			&app.ExecutionParameters.SnapshotCycleInterval,
 			&app.ExecutionParameters.CreatedAt,
 			&app.ExecutionParameters.UpdatedAt,
 			&total,
@@ -576,6 +827,10 @@ func (r *PostgresRepository) GetExecutionParameters(
 			table.ExecutionParameters.StoreDeadline,
 			table.ExecutionParameters.FastDeadline,
 			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
 			table.ExecutionParameters.CreatedAt,
 			table.ExecutionParameters.UpdatedAt,
 		).
@@ -600,6 +855,10 @@ func (r *PostgresRepository) GetExecutionParameters(
 		&ep.StoreDeadline,
 		&ep.FastDeadline,
 		&ep.MaxConcurrentInspects,
+		&ep.RetainedEpochs,
+		&ep.SnapshotInputInterval,
+		&ep.SnapshotTimeInterval,
+		&ep.SnapshotCycleInterval,
 		&ep.CreatedAt,
 		&ep.UpdatedAt,
 	)
@@ -632,6 +891,10 @@ func (r *PostgresRepository) UpdateExecutionParameters(
 			table.ExecutionParameters.StoreDeadline,
 			table.ExecutionParameters.FastDeadline,
 			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
 		).
 		SET(
 			ep.SnapshotPolicy,
@@ -647,6 +910,10 @@ func (r *PostgresRepository) UpdateExecutionParameters(
 			ep.StoreDeadline,
 			ep.FastDeadline,
 			ep.MaxConcurrentInspects,
+			ep.RetainedEpochs,
+			ep.SnapshotInputInterval,
+			ep.SnapshotTimeInterval,
+			ep.SnapshotCycleInterval,
 		).
 		WHERE(table.ExecutionParameters.ApplicationID.EQ(postgres.Int(ep.ApplicationID)))
 
@@ -0,0 +1,105 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package postgres
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/cartesi/rollups-node/internal/repository/blob"
+)
+
+// blobInlinePrefix is how much of an offloaded payload is kept in "raw_data".
+// It covers the fields matched by the output type and voucher address filters.
+const blobInlinePrefix = 128
+
+type Option func(*PostgresRepository)
+
+// WithBlobStore offloads the input, output and report payloads larger than
+// threshold bytes to store.
+func WithBlobStore(store blob.Store, threshold uint64) Option {
+	return func(r *PostgresRepository) {
+		r.blobs = store
+		r.blobThreshold = max(threshold, blobInlinePrefix)
+	}
+}
+
+// offload moves large payloads to the blob store, returning what should be
+// kept inline and the blob key, or nil if the payload is kept inline. Writers
+// call it before beginning their transaction, so the transaction is not kept
+// open while talking to the blob store.
+//
+// Each blob is locked in the session of conn before it is stored, and the lock
+// is held until release, so a concurrent deleteUnreferencedBlob can not remove
+// it before the rows referencing it are committed.
+func (r *PostgresRepository) offload(ctx context.Context, conn *pgxpool.Conn, data []byte) ([]byte, []byte, error) {
+	if r.blobs == nil || uint64(len(data)) <= r.blobThreshold {
+		return data, nil, nil
+	}
+	key := blob.Key(data)
+	sqlStr, args := blobLock("pg_advisory_lock_shared", key).Sql()
+	if _, err := conn.Exec(ctx, sqlStr, args...); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock blob %s: %w", key, err)
+	}
+	if err := r.blobs.Put(ctx, key, data); err != nil {
+		return nil, nil, err
+	}
+	return data[:blobInlinePrefix], key.Bytes(), nil
+}
+
+func (r *PostgresRepository) offloadAll(ctx context.Context, conn *pgxpool.Conn, dataArray [][]byte) ([][]byte, [][]byte, error) {
+	inline := make([][]byte, len(dataArray))
+	keys := make([][]byte, len(dataArray))
+	for i, data := range dataArray {
+		var err error
+		inline[i], keys[i], err = r.offload(ctx, conn, data)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return inline, keys, nil
+}
+
+// release unlocks the blobs locked by offload and returns conn to the pool.
+func (r *PostgresRepository) release(conn *pgxpool.Conn) {
+	if r.blobs == nil {
+		conn.Release()
+		return
+	}
+	sqlStr, args := postgres.SELECT(postgres.Func("pg_advisory_unlock_all")).Sql()
+	if _, err := conn.Exec(context.Background(), sqlStr, args...); err != nil {
+		// the locks are released with the session
+		_ = conn.Conn().Close(context.Background())
+	}
+	conn.Release()
+}
+
+// blobLock selects the advisory lock function fn on the lock of the blob
+// under key. Keys sharing the same first bytes share the lock.
+func blobLock(fn string, key common.Hash) postgres.Statement {
+	lock := int64(binary.BigEndian.Uint64(key[:8]))
+	return postgres.SELECT(postgres.Func(fn, postgres.Int64(lock)))
+}
+
+// rehydrate replaces the inline part of an offloaded payload with the payload
+// from the blob store.
+func (r *PostgresRepository) rehydrate(ctx context.Context, data *[]byte, key *common.Hash) error {
+	if key == nil {
+		return nil
+	}
+	if r.blobs == nil {
+		return fmt.Errorf("payload %s is in the blob store, but no blob store is configured", key)
+	}
+	payload, err := r.blobs.Get(ctx, *key)
+	if err != nil {
+		return err
+	}
+	*data = payload
+	return nil
+}
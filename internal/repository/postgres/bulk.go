@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-jet/jet/v2/postgres"
@@ -15,6 +16,7 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
 	"github.com/cartesi/rollups-node/internal/repository/postgres/db/rollupsdb/public/table"
 )
 
@@ -107,6 +109,7 @@ func insertOutputs(
 	appID int64,
 	inputIndex uint64,
 	dataArray [][]byte,
+	keys [][]byte,
 ) error {
 	if len(dataArray) < 1 {
 		return nil
@@ -122,6 +125,7 @@ func insertOutputs(
 		table.Output.InputIndex,
 		table.Output.Index,
 		table.Output.RawData,
+		table.Output.RawDataHash,
 	)
 	for i, data := range dataArray {
 		stmt = stmt.VALUES(
@@ -129,6 +133,7 @@ func insertOutputs(
 			inputIndex,
 			nextIndex+uint64(i),
 			data,
+			keys[i],
 		)
 	}
 
@@ -146,6 +151,7 @@ func insertReports(
 	appID int64,
 	inputIndex uint64,
 	dataArray [][]byte,
+	keys [][]byte,
 ) error {
 	if len(dataArray) < 1 {
 		return nil
@@ -161,6 +167,7 @@ func insertReports(
 		table.Report.InputIndex,
 		table.Report.Index,
 		table.Report.RawData,
+		table.Report.RawDataHash,
 	)
 	for i, data := range dataArray {
 		stmt = stmt.VALUES(
@@ -168,6 +175,7 @@ func insertReports(
 			inputIndex,
 			nextIndex+uint64(i),
 			data,
+			keys[i],
 		)
 	}
 
@@ -183,35 +191,49 @@ func updateInput(
 	ctx context.Context,
 	tx pgx.Tx,
 	appID int64,
-	inputIndex uint64,
-	status model.InputCompletionStatus,
-	outputsHash common.Hash,
-	machineHash common.Hash,
+	res *model.AdvanceResult,
 ) error {
+	whereClause := table.Input.EpochApplicationID.EQ(postgres.Int64(appID)).
+		AND(table.Input.Index.EQ(postgres.RawFloat(fmt.Sprintf("%d", res.InputIndex))))
+	if res.InputBlockHash != nil {
+		whereClause = whereClause.AND(table.Input.BlockHash.EQ(postgres.Bytea(res.InputBlockHash.Bytes())))
+	}
 
 	updStmt := table.Input.
 		UPDATE(
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 		).
 		SET(
-			status,
-			machineHash,
-			outputsHash,
+			res.Status,
+			*res.MachineHash,
+			res.OutputsHash,
+			res.Cycles,
+			int64(res.ExecutionTime),
+			uint64(len(res.Outputs)),
+			res.OutputsSize(),
+			uint64(len(res.Reports)),
+			res.ReportsSize(),
 		).
-		WHERE(
-			table.Input.EpochApplicationID.EQ(postgres.Int64(appID)).
-				AND(table.Input.Index.EQ(postgres.RawFloat(fmt.Sprintf("%d", inputIndex)))),
-		)
+		WHERE(whereClause)
 
 	sqlStr, args := updStmt.Sql()
 	cmd, err := tx.Exec(ctx, sqlStr, args...)
 	if err != nil {
-		return err
+		return errors.Join(err, tx.Rollback(ctx))
 	}
 	if cmd.RowsAffected() == 0 {
-		return sql.ErrNoRows
+		if res.InputBlockHash != nil {
+			return errors.Join(repository.ErrInputReverted, tx.Rollback(ctx))
+		}
+		return errors.Join(sql.ErrNoRows, tx.Rollback(ctx))
 	}
 	return nil
 }
@@ -250,22 +272,37 @@ func (r *PostgresRepository) StoreAdvanceResult(
 	appID int64,
 	res *model.AdvanceResult,
 ) error {
-	tx, err := r.db.Begin(ctx)
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.release(conn)
+	outputs, outputKeys, err := r.offloadAll(ctx, conn, res.Outputs)
+	if err != nil {
+		return err
+	}
+	reports, reportKeys, err := r.offloadAll(ctx, conn, res.Reports)
 	if err != nil {
 		return err
 	}
 
-	err = insertOutputs(ctx, tx, appID, res.InputIndex, res.Outputs)
+	tx, err := conn.Begin(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = insertReports(ctx, tx, appID, res.InputIndex, res.Reports)
+	// first, so the result of a reverted input is rejected
+	err = updateInput(ctx, tx, appID, res)
 	if err != nil {
 		return err
 	}
 
-	err = updateInput(ctx, tx, appID, res.InputIndex, res.Status, res.OutputsHash, *res.MachineHash)
+	err = insertOutputs(ctx, tx, appID, res.InputIndex, outputs, outputKeys)
+	if err != nil {
+		return err
+	}
+
+	err = insertReports(ctx, tx, appID, res.InputIndex, reports, reportKeys)
 	if err != nil {
 		return err
 	}
@@ -419,3 +456,360 @@ func (r *PostgresRepository) UpdateInputSnapshotURI(ctx context.Context, appId i
 	}
 	return nil
 }
+
+func (r *PostgresRepository) ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error {
+	updStmt := table.Input.
+		UPDATE(
+			table.Input.SnapshotURI,
+		).
+		SET(
+			postgres.NULL,
+		).
+		WHERE(
+			table.Input.EpochApplicationID.EQ(postgres.Int64(appId)).
+				AND(table.Input.Index.EQ(postgres.RawFloat(fmt.Sprintf("%d", inputIndex)))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	_, err := r.db.Exec(ctx, sqlStr, args...)
+	return err
+}
+
+// selectBlobKeys returns the blob keys selected by query.
+func selectBlobKeys(ctx context.Context, tx pgx.Tx, query postgres.SelectStatement) ([]common.Hash, error) {
+	sqlStr, args := query.Sql()
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, errors.Join(err, tx.Rollback(ctx))
+	}
+	var keys []common.Hash
+	for rows.Next() {
+		var key common.Hash
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, errors.Join(err, tx.Rollback(ctx))
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.Join(err, tx.Rollback(ctx))
+	}
+	return keys, nil
+}
+
+// prunePayloads clears the payloads of the rows matching cond that still have
+// one, returning how many rows were pruned and the blob keys they referenced.
+func prunePayloads(
+	ctx context.Context,
+	tx pgx.Tx,
+	tbl postgres.Table,
+	rawData postgres.ColumnString,
+	rawDataHash postgres.ColumnString,
+	cond postgres.BoolExpression,
+) (uint64, []common.Hash, error) {
+	cond = cond.AND(postgres.OCTET_LENGTH(rawData).GT(postgres.Int(0)))
+
+	keys, err := selectBlobKeys(ctx, tx, tbl.SELECT(rawDataHash).WHERE(cond.AND(rawDataHash.IS_NOT_NULL())))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	updStmt := tbl.
+		UPDATE(
+			rawData,
+			rawDataHash,
+		).
+		SET(
+			[]byte{},
+			postgres.NULL,
+		).
+		WHERE(cond)
+
+	sqlStr, args := updStmt.Sql()
+	cmd, err := tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, nil, errors.Join(err, tx.Rollback(ctx))
+	}
+	return uint64(cmd.RowsAffected()), keys, nil
+}
+
+// deleteUnreferencedBlobs removes the blobs that are no longer referenced by
+// any input, output or report. Blobs are content-addressed, so the same blob
+// may be shared by rows of other applications.
+func (r *PostgresRepository) deleteUnreferencedBlobs(ctx context.Context, keys []common.Hash) error {
+	if r.blobs == nil {
+		return nil
+	}
+	slices.SortFunc(keys, func(a, b common.Hash) int { return a.Cmp(b) })
+	for _, key := range slices.Compact(keys) {
+		if err := r.deleteUnreferencedBlob(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUnreferencedBlob holds the lock of the blob while looking for its
+// references, so it waits for the writers that stored it with offload to
+// commit the rows referencing it, and they wait for it to be deleted before
+// storing it again.
+func (r *PostgresRepository) deleteUnreferencedBlob(ctx context.Context, key common.Hash) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	sqlStr, args := blobLock("pg_advisory_xact_lock", key).Sql()
+	if _, err := tx.Exec(ctx, sqlStr, args...); err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+
+	ref := postgres.Bytea(key.Bytes())
+	query := postgres.SELECT(
+		postgres.EXISTS(
+			table.Input.SELECT(table.Input.Index).WHERE(table.Input.RawDataHash.EQ(ref)),
+		).OR(postgres.EXISTS(
+			table.Output.SELECT(table.Output.Index).WHERE(table.Output.RawDataHash.EQ(ref)),
+		)).OR(postgres.EXISTS(
+			table.Report.SELECT(table.Report.Index).WHERE(table.Report.RawDataHash.EQ(ref)),
+		)),
+	)
+	sqlStr, args = query.Sql()
+	var referenced bool
+	if err := tx.QueryRow(ctx, sqlStr, args...).Scan(&referenced); err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+	if !referenced {
+		if err := r.blobs.Delete(ctx, key); err != nil {
+			return errors.Join(err, tx.Rollback(ctx))
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) PruneEpochs(
+	ctx context.Context,
+	appID int64,
+	epochIndex uint64,
+	inputIndex uint64,
+) (uint64, uint64, error) {
+	// only the accepted epochs before epochIndex are pruned
+	prunedEpoch := table.Epoch.Status.EQ(postgres.NewEnumValue(model.EpochStatus_ClaimAccepted.String())).
+		AND(table.Epoch.Index.LT(postgres.RawFloat(fmt.Sprintf("%d", epochIndex))))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reports, reportKeys, err := prunePayloads(ctx, tx, table.Report, table.Report.RawData, table.Report.RawDataHash,
+		table.Report.InputEpochApplicationID.EQ(postgres.Int64(appID)).
+			AND(postgres.EXISTS(
+				table.Input.
+					INNER_JOIN(table.Epoch, table.Epoch.ApplicationID.EQ(table.Input.EpochApplicationID).
+						AND(table.Epoch.Index.EQ(table.Input.EpochIndex))).
+					SELECT(table.Input.Index).
+					WHERE(
+						table.Input.EpochApplicationID.EQ(table.Report.InputEpochApplicationID).
+							AND(table.Input.Index.EQ(table.Report.InputIndex)).
+							AND(prunedEpoch),
+					),
+			)),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	inputs, inputKeys, err := prunePayloads(ctx, tx, table.Input, table.Input.RawData, table.Input.RawDataHash,
+		table.Input.EpochApplicationID.EQ(postgres.Int64(appID)).
+			AND(table.Input.Index.LT(postgres.RawFloat(fmt.Sprintf("%d", inputIndex)))).
+			AND(postgres.EXISTS(
+				table.Epoch.
+					SELECT(table.Epoch.Index).
+					WHERE(
+						table.Epoch.ApplicationID.EQ(table.Input.EpochApplicationID).
+							AND(table.Epoch.Index.EQ(table.Input.EpochIndex)).
+							AND(prunedEpoch),
+					),
+			)),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	err = r.deleteUnreferencedBlobs(ctx, append(reportKeys, inputKeys...))
+	if err != nil {
+		return inputs, reports, fmt.Errorf("failed to delete pruned payloads from the blob store: %w", err)
+	}
+	return inputs, reports, nil
+}
+
+func (r *PostgresRepository) RevertBlocks(
+	ctx context.Context,
+	appID int64,
+	blockNumber uint64,
+) (uint64, error) {
+	block := postgres.RawFloat(fmt.Sprintf("%d", blockNumber))
+	revertedInput := func(appIDColumn postgres.ColumnInteger, inputIndex postgres.ColumnFloat) postgres.BoolExpression {
+		return postgres.EXISTS(
+			table.Input.
+				SELECT(table.Input.Index).
+				WHERE(
+					table.Input.EpochApplicationID.EQ(appIDColumn).
+						AND(table.Input.Index.EQ(inputIndex)).
+						AND(table.Input.BlockNumber.GT(block)),
+				),
+		)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the epochs with a claim on chain can not be reverted
+	claimedEpoch := table.Epoch.
+		SELECT(table.Epoch.Index, table.Epoch.Status).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Epoch.LastBlock.GT(block)).
+				AND(table.Epoch.Status.IN(
+					postgres.NewEnumValue(model.EpochStatus_ClaimSubmitted.String()),
+					postgres.NewEnumValue(model.EpochStatus_ClaimAccepted.String()),
+				)),
+		).
+		ORDER_BY(table.Epoch.Index.ASC()).
+		LIMIT(1)
+	sqlStr, args := claimedEpoch.Sql()
+	var claimedIndex uint64
+	var claimedStatus model.EpochStatus
+	err = tx.QueryRow(ctx, sqlStr, args...).Scan(&claimedIndex, &claimedStatus)
+	if err == nil {
+		err = fmt.Errorf("%w: epoch %d is %s", repository.ErrClaimedEpochReverted, claimedIndex, claimedStatus)
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	// outputs and reports are deleted in cascade, so collect their blobs first
+	var keys []common.Hash
+	for _, query := range []postgres.SelectStatement{
+		table.Input.SELECT(table.Input.RawDataHash).WHERE(
+			table.Input.EpochApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Input.BlockNumber.GT(block)).
+				AND(table.Input.RawDataHash.IS_NOT_NULL()),
+		),
+		table.Output.SELECT(table.Output.RawDataHash).WHERE(
+			table.Output.InputEpochApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Output.RawDataHash.IS_NOT_NULL()).
+				AND(revertedInput(table.Output.InputEpochApplicationID, table.Output.InputIndex)),
+		),
+		table.Report.SELECT(table.Report.RawDataHash).WHERE(
+			table.Report.InputEpochApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Report.RawDataHash.IS_NOT_NULL()).
+				AND(revertedInput(table.Report.InputEpochApplicationID, table.Report.InputIndex)),
+		),
+	} {
+		found, err := selectBlobKeys(ctx, tx, query)
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, found...)
+	}
+
+	delInputs := table.Input.
+		DELETE().
+		WHERE(
+			table.Input.EpochApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Input.BlockNumber.GT(block)),
+		)
+	sqlStr, args = delInputs.Sql()
+	cmd, err := tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+	inputs := uint64(cmd.RowsAffected())
+
+	delEpochs := table.Epoch.
+		DELETE().
+		WHERE(
+			table.Epoch.ApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Epoch.FirstBlock.GT(block)),
+		)
+	sqlStr, args = delEpochs.Sql()
+	_, err = tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	reopenEpoch := table.Epoch.
+		UPDATE(
+			table.Epoch.Status,
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.BlockHash,
+		).
+		SET(
+			postgres.NewEnumValue(model.EpochStatus_Open.String()),
+			postgres.NULL,
+			postgres.NULL,
+			postgres.NULL,
+		).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(postgres.Int64(appID)).
+				AND(table.Epoch.FirstBlock.LT_EQ(block)).
+				AND(table.Epoch.LastBlock.GT(block)).
+				AND(table.Epoch.Status.NOT_EQ(postgres.NewEnumValue(model.EpochStatus_Open.String()))),
+		)
+	sqlStr, args = reopenEpoch.Sql()
+	_, err = tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	countStmt := table.Input.
+		SELECT(postgres.COUNT(postgres.STAR)).
+		WHERE(table.Input.EpochApplicationID.EQ(postgres.Int64(appID)))
+	sqlStr, args = countStmt.Sql()
+	var remaining uint64
+	err = tx.QueryRow(ctx, sqlStr, args...).Scan(&remaining)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	appUpdateStmt := table.Application.
+		UPDATE(
+			table.Application.ProcessedInputs,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+		).
+		SET(
+			postgres.LEAST(table.Application.ProcessedInputs, postgres.RawFloat(fmt.Sprintf("%d", remaining))),
+			postgres.LEAST(table.Application.LastInputCheckBlock, block),
+			postgres.LEAST(table.Application.LastOutputCheckBlock, block),
+		).
+		WHERE(table.Application.ID.EQ(postgres.Int64(appID)))
+	sqlStr, args = appUpdateStmt.Sql()
+	_, err = tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback(ctx))
+	}
+
+	err = r.deleteUnreferencedBlobs(ctx, keys)
+	if err != nil {
+		return inputs, fmt.Errorf("failed to delete reverted payloads from the blob store: %w", err)
+	}
+	return inputs, nil
+}
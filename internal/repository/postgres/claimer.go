@@ -59,6 +59,7 @@ func (r *PostgresRepository) selectOldestClaimPerApp(
 		table.Application.DataAvailability,
 		table.Application.State,
 		table.Application.Reason,
+		table.Application.ReasonCode,
 		table.Application.IinputboxBlock,
 		table.Application.LastInputCheckBlock,
 		table.Application.LastOutputCheckBlock,
@@ -115,6 +116,7 @@ func (r *PostgresRepository) selectOldestClaimPerApp(
 			&application.DataAvailability,
 			&application.State,
 			&application.Reason,
+			&application.ReasonCode,
 			&application.IInputBoxBlock,
 			&application.LastInputCheckBlock,
 			&application.LastOutputCheckBlock,
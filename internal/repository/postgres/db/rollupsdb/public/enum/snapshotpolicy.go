@@ -10,11 +10,17 @@ package enum
 import "github.com/go-jet/jet/v2/postgres"
 
 var SnapshotPolicy = &struct {
-	None       postgres.StringExpression
-	EveryInput postgres.StringExpression
-	EveryEpoch postgres.StringExpression
+	None          postgres.StringExpression
+	EveryInput    postgres.StringExpression
+	EveryEpoch    postgres.StringExpression
+	EveryNInputs  postgres.StringExpression
+	EveryDuration postgres.StringExpression
+	EveryNCycles  postgres.StringExpression
 }{
-	None:       postgres.NewEnumValue("NONE"),
-	EveryInput: postgres.NewEnumValue("EVERY_INPUT"),
-	EveryEpoch: postgres.NewEnumValue("EVERY_EPOCH"),
+	None:          postgres.NewEnumValue("NONE"),
+	EveryInput:    postgres.NewEnumValue("EVERY_INPUT"),
+	EveryEpoch:    postgres.NewEnumValue("EVERY_EPOCH"),
+	EveryNInputs:  postgres.NewEnumValue("EVERY_N_INPUTS"),
+	EveryDuration: postgres.NewEnumValue("EVERY_DURATION"),
+	EveryNCycles:  postgres.NewEnumValue("EVERY_N_CYCLES"),
 }
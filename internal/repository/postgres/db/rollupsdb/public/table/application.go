@@ -29,6 +29,7 @@ type applicationTable struct {
 	DataAvailability     postgres.ColumnString
 	State                postgres.ColumnString
 	Reason               postgres.ColumnString
+	ReasonCode           postgres.ColumnString
 	LastInputCheckBlock  postgres.ColumnFloat
 	LastOutputCheckBlock postgres.ColumnFloat
 	ProcessedInputs      postgres.ColumnFloat
@@ -86,13 +87,14 @@ func newApplicationTableImpl(schemaName, tableName, alias string) applicationTab
 		DataAvailabilityColumn     = postgres.StringColumn("data_availability")
 		StateColumn                = postgres.StringColumn("state")
 		ReasonColumn               = postgres.StringColumn("reason")
+		ReasonCodeColumn           = postgres.StringColumn("reason_code")
 		LastInputCheckBlockColumn  = postgres.FloatColumn("last_input_check_block")
 		LastOutputCheckBlockColumn = postgres.FloatColumn("last_output_check_block")
 		ProcessedInputsColumn      = postgres.FloatColumn("processed_inputs")
 		CreatedAtColumn            = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn            = postgres.TimestampzColumn("updated_at")
-		allColumns                 = postgres.ColumnList{IDColumn, NameColumn, IapplicationAddressColumn, IconsensusAddressColumn, IinputboxAddressColumn, IinputboxBlockColumn, TemplateHashColumn, TemplateURIColumn, EpochLengthColumn, DataAvailabilityColumn, StateColumn, ReasonColumn, LastInputCheckBlockColumn, LastOutputCheckBlockColumn, ProcessedInputsColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns             = postgres.ColumnList{NameColumn, IapplicationAddressColumn, IconsensusAddressColumn, IinputboxAddressColumn, IinputboxBlockColumn, TemplateHashColumn, TemplateURIColumn, EpochLengthColumn, DataAvailabilityColumn, StateColumn, ReasonColumn, LastInputCheckBlockColumn, LastOutputCheckBlockColumn, ProcessedInputsColumn, CreatedAtColumn, UpdatedAtColumn}
+		allColumns                 = postgres.ColumnList{IDColumn, NameColumn, IapplicationAddressColumn, IconsensusAddressColumn, IinputboxAddressColumn, IinputboxBlockColumn, TemplateHashColumn, TemplateURIColumn, EpochLengthColumn, DataAvailabilityColumn, StateColumn, ReasonColumn, ReasonCodeColumn, LastInputCheckBlockColumn, LastOutputCheckBlockColumn, ProcessedInputsColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns             = postgres.ColumnList{NameColumn, IapplicationAddressColumn, IconsensusAddressColumn, IinputboxAddressColumn, IinputboxBlockColumn, TemplateHashColumn, TemplateURIColumn, EpochLengthColumn, DataAvailabilityColumn, StateColumn, ReasonColumn, ReasonCodeColumn, LastInputCheckBlockColumn, LastOutputCheckBlockColumn, ProcessedInputsColumn, CreatedAtColumn, UpdatedAtColumn}
 	)
 
 	return applicationTable{
@@ -111,6 +113,7 @@ func newApplicationTableImpl(schemaName, tableName, alias string) applicationTab
 		DataAvailability:     DataAvailabilityColumn,
 		State:                StateColumn,
 		Reason:               ReasonColumn,
+		ReasonCode:           ReasonCodeColumn,
 		LastInputCheckBlock:  LastInputCheckBlockColumn,
 		LastOutputCheckBlock: LastOutputCheckBlockColumn,
 		ProcessedInputs:      ProcessedInputsColumn,
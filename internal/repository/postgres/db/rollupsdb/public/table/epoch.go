@@ -27,6 +27,7 @@ type epochTable struct {
 	VirtualIndex         postgres.ColumnFloat
 	CreatedAt            postgres.ColumnTimestampz
 	UpdatedAt            postgres.ColumnTimestampz
+	BlockHash            postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -77,8 +78,9 @@ func newEpochTableImpl(schemaName, tableName, alias string) epochTable {
 		VirtualIndexColumn         = postgres.FloatColumn("virtual_index")
 		CreatedAtColumn            = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn            = postgres.TimestampzColumn("updated_at")
-		allColumns                 = postgres.ColumnList{ApplicationIDColumn, IndexColumn, FirstBlockColumn, LastBlockColumn, ClaimHashColumn, ClaimTransactionHashColumn, StatusColumn, VirtualIndexColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns             = postgres.ColumnList{FirstBlockColumn, LastBlockColumn, ClaimHashColumn, ClaimTransactionHashColumn, StatusColumn, VirtualIndexColumn, CreatedAtColumn, UpdatedAtColumn}
+		BlockHashColumn            = postgres.StringColumn("block_hash")
+		allColumns                 = postgres.ColumnList{ApplicationIDColumn, IndexColumn, FirstBlockColumn, LastBlockColumn, ClaimHashColumn, ClaimTransactionHashColumn, StatusColumn, VirtualIndexColumn, CreatedAtColumn, UpdatedAtColumn, BlockHashColumn}
+		mutableColumns             = postgres.ColumnList{FirstBlockColumn, LastBlockColumn, ClaimHashColumn, ClaimTransactionHashColumn, StatusColumn, VirtualIndexColumn, CreatedAtColumn, UpdatedAtColumn, BlockHashColumn}
 	)
 
 	return epochTable{
@@ -95,6 +97,7 @@ func newEpochTableImpl(schemaName, tableName, alias string) epochTable {
 		VirtualIndex:         VirtualIndexColumn,
 		CreatedAt:            CreatedAtColumn,
 		UpdatedAt:            UpdatedAtColumn,
+		BlockHash:            BlockHashColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
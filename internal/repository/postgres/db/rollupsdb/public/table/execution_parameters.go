@@ -33,6 +33,10 @@ type executionParametersTable struct {
 	MaxConcurrentInspects postgres.ColumnInteger
 	CreatedAt             postgres.ColumnTimestampz
 	UpdatedAt             postgres.ColumnTimestampz
+	RetainedEpochs        postgres.ColumnInteger
+	SnapshotInputInterval postgres.ColumnInteger
+	SnapshotTimeInterval  postgres.ColumnInteger
+	SnapshotCycleInterval postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -89,8 +93,12 @@ func newExecutionParametersTableImpl(schemaName, tableName, alias string) execut
 		MaxConcurrentInspectsColumn = postgres.IntegerColumn("max_concurrent_inspects")
 		CreatedAtColumn             = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn             = postgres.TimestampzColumn("updated_at")
-		allColumns                  = postgres.ColumnList{ApplicationIDColumn, SnapshotPolicyColumn, AdvanceIncCyclesColumn, AdvanceMaxCyclesColumn, InspectIncCyclesColumn, InspectMaxCyclesColumn, AdvanceIncDeadlineColumn, AdvanceMaxDeadlineColumn, InspectIncDeadlineColumn, InspectMaxDeadlineColumn, LoadDeadlineColumn, StoreDeadlineColumn, FastDeadlineColumn, MaxConcurrentInspectsColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns              = postgres.ColumnList{SnapshotPolicyColumn, AdvanceIncCyclesColumn, AdvanceMaxCyclesColumn, InspectIncCyclesColumn, InspectMaxCyclesColumn, AdvanceIncDeadlineColumn, AdvanceMaxDeadlineColumn, InspectIncDeadlineColumn, InspectMaxDeadlineColumn, LoadDeadlineColumn, StoreDeadlineColumn, FastDeadlineColumn, MaxConcurrentInspectsColumn, CreatedAtColumn, UpdatedAtColumn}
+		RetainedEpochsColumn        = postgres.IntegerColumn("retained_epochs")
+		SnapshotInputIntervalColumn = postgres.IntegerColumn("snapshot_input_interval")
+		SnapshotTimeIntervalColumn  = postgres.IntegerColumn("snapshot_time_interval")
+		SnapshotCycleIntervalColumn = postgres.IntegerColumn("snapshot_cycle_interval")
+		allColumns                  = postgres.ColumnList{ApplicationIDColumn, SnapshotPolicyColumn, AdvanceIncCyclesColumn, AdvanceMaxCyclesColumn, InspectIncCyclesColumn, InspectMaxCyclesColumn, AdvanceIncDeadlineColumn, AdvanceMaxDeadlineColumn, InspectIncDeadlineColumn, InspectMaxDeadlineColumn, LoadDeadlineColumn, StoreDeadlineColumn, FastDeadlineColumn, MaxConcurrentInspectsColumn, CreatedAtColumn, UpdatedAtColumn, RetainedEpochsColumn, SnapshotInputIntervalColumn, SnapshotTimeIntervalColumn, SnapshotCycleIntervalColumn}
+		mutableColumns              = postgres.ColumnList{SnapshotPolicyColumn, AdvanceIncCyclesColumn, AdvanceMaxCyclesColumn, InspectIncCyclesColumn, InspectMaxCyclesColumn, AdvanceIncDeadlineColumn, AdvanceMaxDeadlineColumn, InspectIncDeadlineColumn, InspectMaxDeadlineColumn, LoadDeadlineColumn, StoreDeadlineColumn, FastDeadlineColumn, MaxConcurrentInspectsColumn, CreatedAtColumn, UpdatedAtColumn, RetainedEpochsColumn, SnapshotInputIntervalColumn, SnapshotTimeIntervalColumn, SnapshotCycleIntervalColumn}
 	)
 
 	return executionParametersTable{
@@ -113,6 +121,10 @@ func newExecutionParametersTableImpl(schemaName, tableName, alias string) execut
 		MaxConcurrentInspects: MaxConcurrentInspectsColumn,
 		CreatedAt:             CreatedAtColumn,
 		UpdatedAt:             UpdatedAtColumn,
+		RetainedEpochs:        RetainedEpochsColumn,
+		SnapshotInputInterval: SnapshotInputIntervalColumn,
+		SnapshotTimeInterval:  SnapshotTimeIntervalColumn,
+		SnapshotCycleInterval: SnapshotCycleIntervalColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -29,6 +29,15 @@ type inputTable struct {
 	SnapshotURI          postgres.ColumnString
 	CreatedAt            postgres.ColumnTimestampz
 	UpdatedAt            postgres.ColumnTimestampz
+	RawDataHash          postgres.ColumnString
+	BlockHash            postgres.ColumnString
+	Cycles               postgres.ColumnInteger
+	ExecutionTime        postgres.ColumnInteger
+	OutputsCount         postgres.ColumnInteger
+	OutputsSize          postgres.ColumnInteger
+	ReportsCount         postgres.ColumnInteger
+	ReportsSize          postgres.ColumnInteger
+	Sender               postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -81,8 +90,17 @@ func newInputTableImpl(schemaName, tableName, alias string) inputTable {
 		SnapshotURIColumn          = postgres.StringColumn("snapshot_uri")
 		CreatedAtColumn            = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn            = postgres.TimestampzColumn("updated_at")
-		allColumns                 = postgres.ColumnList{EpochApplicationIDColumn, EpochIndexColumn, IndexColumn, BlockNumberColumn, RawDataColumn, StatusColumn, MachineHashColumn, OutputsHashColumn, TransactionReferenceColumn, SnapshotURIColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns             = postgres.ColumnList{EpochIndexColumn, BlockNumberColumn, RawDataColumn, StatusColumn, MachineHashColumn, OutputsHashColumn, TransactionReferenceColumn, SnapshotURIColumn, CreatedAtColumn, UpdatedAtColumn}
+		RawDataHashColumn          = postgres.StringColumn("raw_data_hash")
+		BlockHashColumn            = postgres.StringColumn("block_hash")
+		CyclesColumn               = postgres.IntegerColumn("cycles")
+		ExecutionTimeColumn        = postgres.IntegerColumn("execution_time")
+		OutputsCountColumn         = postgres.IntegerColumn("outputs_count")
+		OutputsSizeColumn          = postgres.IntegerColumn("outputs_size")
+		ReportsCountColumn         = postgres.IntegerColumn("reports_count")
+		ReportsSizeColumn          = postgres.IntegerColumn("reports_size")
+		SenderColumn               = postgres.StringColumn("sender")
+		allColumns                 = postgres.ColumnList{EpochApplicationIDColumn, EpochIndexColumn, IndexColumn, BlockNumberColumn, RawDataColumn, StatusColumn, MachineHashColumn, OutputsHashColumn, TransactionReferenceColumn, SnapshotURIColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn, BlockHashColumn, CyclesColumn, ExecutionTimeColumn, OutputsCountColumn, OutputsSizeColumn, ReportsCountColumn, ReportsSizeColumn, SenderColumn}
+		mutableColumns             = postgres.ColumnList{EpochIndexColumn, BlockNumberColumn, RawDataColumn, StatusColumn, MachineHashColumn, OutputsHashColumn, TransactionReferenceColumn, SnapshotURIColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn, BlockHashColumn, CyclesColumn, ExecutionTimeColumn, OutputsCountColumn, OutputsSizeColumn, ReportsCountColumn, ReportsSizeColumn, SenderColumn}
 	)
 
 	return inputTable{
@@ -101,6 +119,15 @@ func newInputTableImpl(schemaName, tableName, alias string) inputTable {
 		SnapshotURI:          SnapshotURIColumn,
 		CreatedAt:            CreatedAtColumn,
 		UpdatedAt:            UpdatedAtColumn,
+		RawDataHash:          RawDataHashColumn,
+		BlockHash:            BlockHashColumn,
+		Cycles:               CyclesColumn,
+		ExecutionTime:        ExecutionTimeColumn,
+		OutputsCount:         OutputsCountColumn,
+		OutputsSize:          OutputsSizeColumn,
+		ReportsCount:         ReportsCountColumn,
+		ReportsSize:          ReportsSizeColumn,
+		Sender:               SenderColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
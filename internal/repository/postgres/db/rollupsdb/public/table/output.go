@@ -26,6 +26,7 @@ type outputTable struct {
 	ExecutionTransactionHash postgres.ColumnString
 	CreatedAt                postgres.ColumnTimestampz
 	UpdatedAt                postgres.ColumnTimestampz
+	RawDataHash              postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -75,8 +76,9 @@ func newOutputTableImpl(schemaName, tableName, alias string) outputTable {
 		ExecutionTransactionHashColumn = postgres.StringColumn("execution_transaction_hash")
 		CreatedAtColumn                = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn                = postgres.TimestampzColumn("updated_at")
-		allColumns                     = postgres.ColumnList{InputEpochApplicationIDColumn, InputIndexColumn, IndexColumn, RawDataColumn, HashColumn, OutputHashesSiblingsColumn, ExecutionTransactionHashColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns                 = postgres.ColumnList{InputIndexColumn, RawDataColumn, HashColumn, OutputHashesSiblingsColumn, ExecutionTransactionHashColumn, CreatedAtColumn, UpdatedAtColumn}
+		RawDataHashColumn              = postgres.StringColumn("raw_data_hash")
+		allColumns                     = postgres.ColumnList{InputEpochApplicationIDColumn, InputIndexColumn, IndexColumn, RawDataColumn, HashColumn, OutputHashesSiblingsColumn, ExecutionTransactionHashColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn}
+		mutableColumns                 = postgres.ColumnList{InputIndexColumn, RawDataColumn, HashColumn, OutputHashesSiblingsColumn, ExecutionTransactionHashColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn}
 	)
 
 	return outputTable{
@@ -92,6 +94,7 @@ func newOutputTableImpl(schemaName, tableName, alias string) outputTable {
 		ExecutionTransactionHash: ExecutionTransactionHashColumn,
 		CreatedAt:                CreatedAtColumn,
 		UpdatedAt:                UpdatedAtColumn,
+		RawDataHash:              RawDataHashColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -23,6 +23,7 @@ type reportTable struct {
 	RawData                 postgres.ColumnString
 	CreatedAt               postgres.ColumnTimestampz
 	UpdatedAt               postgres.ColumnTimestampz
+	RawDataHash             postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -69,8 +70,9 @@ func newReportTableImpl(schemaName, tableName, alias string) reportTable {
 		RawDataColumn                 = postgres.StringColumn("raw_data")
 		CreatedAtColumn               = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn               = postgres.TimestampzColumn("updated_at")
-		allColumns                    = postgres.ColumnList{InputEpochApplicationIDColumn, InputIndexColumn, IndexColumn, RawDataColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns                = postgres.ColumnList{InputIndexColumn, RawDataColumn, CreatedAtColumn, UpdatedAtColumn}
+		RawDataHashColumn             = postgres.StringColumn("raw_data_hash")
+		allColumns                    = postgres.ColumnList{InputEpochApplicationIDColumn, InputIndexColumn, IndexColumn, RawDataColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn}
+		mutableColumns                = postgres.ColumnList{InputIndexColumn, RawDataColumn, CreatedAtColumn, UpdatedAtColumn, RawDataHashColumn}
 	)
 
 	return reportTable{
@@ -83,6 +85,7 @@ func newReportTableImpl(schemaName, tableName, alias string) reportTable {
 		RawData:                 RawDataColumn,
 		CreatedAt:               CreatedAtColumn,
 		UpdatedAt:               UpdatedAtColumn,
+		RawDataHash:             RawDataHashColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
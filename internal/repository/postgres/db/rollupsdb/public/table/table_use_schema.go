@@ -11,6 +11,7 @@ package table
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
 	Application = Application.FromSchema(schema)
+	ApplicationStateHistory = ApplicationStateHistory.FromSchema(schema)
 	Epoch = Epoch.FromSchema(schema)
 	ExecutionParameters = ExecutionParameters.FromSchema(schema)
 	Input = Input.FromSchema(schema)
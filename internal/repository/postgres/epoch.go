@@ -125,6 +125,7 @@ func (r *PostgresRepository) CreateEpochsAndInputs(
 		table.Epoch.LastBlock,
 		table.Epoch.Status,
 		table.Epoch.VirtualIndex,
+		table.Epoch.BlockHash,
 	)
 
 	inputInsertStmt := table.Input.
@@ -136,9 +137,29 @@ func (r *PostgresRepository) CreateEpochsAndInputs(
 			table.Input.RawData,
 			table.Input.Status,
 			table.Input.TransactionReference,
+			table.Input.RawDataHash,
+			table.Input.BlockHash,
+			table.Input.Sender,
 		)
 
-	tx, err := r.db.Begin(ctx)
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.release(conn)
+	type payload struct{ data, key []byte }
+	payloads := map[*model.Input]payload{}
+	for _, inputs := range epochInputsMap {
+		for _, input := range inputs {
+			data, key, err := r.offload(ctx, conn, input.RawData)
+			if err != nil {
+				return err
+			}
+			payloads[input] = payload{data: data, key: key}
+		}
+	}
+
+	tx, err := conn.Begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -159,6 +180,7 @@ func (r *PostgresRepository) CreateEpochsAndInputs(
 			postgres.RawFloat(fmt.Sprintf("%d", epoch.LastBlock)),
 			postgres.NewEnumValue(epoch.Status.String()),
 			postgres.RawFloat(fmt.Sprintf("%d", nextVirtualIndex)),
+			postgres.Bytea(epoch.BlockHash),
 		).WHERE(
 			whereClause,
 		)
@@ -167,6 +189,7 @@ func (r *PostgresRepository) CreateEpochsAndInputs(
 			ON_CONFLICT(table.Epoch.ApplicationID, table.Epoch.Index).
 			DO_UPDATE(postgres.SET(
 				table.Epoch.Status.SET(postgres.NewEnumValue(epoch.Status.String())),
+				table.Epoch.BlockHash.SET(table.Epoch.EXCLUDED.BlockHash),
 			)).Sql() // FIXME on conflict
 		_, err = tx.Exec(ctx, sqlStr, args...)
 
@@ -180,9 +203,12 @@ func (r *PostgresRepository) CreateEpochsAndInputs(
 				postgres.RawFloat(fmt.Sprintf("%d", epoch.Index)),
 				postgres.RawFloat(fmt.Sprintf("%d", input.Index)),
 				postgres.RawFloat(fmt.Sprintf("%d", input.BlockNumber)),
-				postgres.Bytea(input.RawData),
+				postgres.Bytea(payloads[input].data),
 				postgres.NewEnumValue(input.Status.String()),
 				postgres.Bytea(input.TransactionReference.Bytes()),
+				postgres.Bytea(payloads[input].key),
+				postgres.Bytea(input.BlockHash),
+				postgres.Bytea(repository.InputSender(input.RawData)),
 			).WHERE(
 				whereClause,
 			)
@@ -237,6 +263,7 @@ func (r *PostgresRepository) GetEpoch(
 			table.Epoch.Index,
 			table.Epoch.FirstBlock,
 			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
 			table.Epoch.ClaimHash,
 			table.Epoch.ClaimTransactionHash,
 			table.Epoch.Status,
@@ -264,6 +291,7 @@ func (r *PostgresRepository) GetEpoch(
 		&ep.Index,
 		&ep.FirstBlock,
 		&ep.LastBlock,
+		&ep.BlockHash,
 		&ep.ClaimHash,
 		&ep.ClaimTransactionHash,
 		&ep.Status,
@@ -340,6 +368,7 @@ func (r *PostgresRepository) GetEpochByVirtualIndex(
 			table.Epoch.Index,
 			table.Epoch.FirstBlock,
 			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
 			table.Epoch.ClaimHash,
 			table.Epoch.ClaimTransactionHash,
 			table.Epoch.Status,
@@ -367,6 +396,7 @@ func (r *PostgresRepository) GetEpochByVirtualIndex(
 		&ep.Index,
 		&ep.FirstBlock,
 		&ep.LastBlock,
+		&ep.BlockHash,
 		&ep.ClaimHash,
 		&ep.ClaimTransactionHash,
 		&ep.Status,
@@ -483,6 +513,7 @@ func (r *PostgresRepository) ListEpochs(
 			table.Epoch.Index,
 			table.Epoch.FirstBlock,
 			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
 			table.Epoch.ClaimHash,
 			table.Epoch.ClaimTransactionHash,
 			table.Epoch.Status,
@@ -507,6 +538,10 @@ func (r *PostgresRepository) ListEpochs(
 		conditions = append(conditions, table.Epoch.LastBlock.LT(postgres.RawFloat(fmt.Sprintf("%d", *f.BeforeBlock))))
 	}
 
+	if cursor := getCursorClause(table.Epoch.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
 	sel = sel.WHERE(postgres.AND(conditions...))
 
 	if descending {
@@ -539,6 +574,7 @@ func (r *PostgresRepository) ListEpochs(
 			&ep.Index,
 			&ep.FirstBlock,
 			&ep.LastBlock,
+			&ep.BlockHash,
 			&ep.ClaimHash,
 			&ep.ClaimTransactionHash,
 			&ep.Status,
@@ -33,12 +33,20 @@ func (r *PostgresRepository) GetInput(
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
 			table.Input.TransactionReference,
 			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 			table.Input.CreatedAt,
 			table.Input.UpdatedAt,
 		).
@@ -57,17 +65,26 @@ func (r *PostgresRepository) GetInput(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var inp model.Input
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&inp.EpochApplicationID,
 		&inp.EpochIndex,
 		&inp.Index,
 		&inp.BlockNumber,
+		&inp.BlockHash,
 		&inp.RawData,
+		&rawDataHash,
 		&inp.Status,
 		&inp.MachineHash,
 		&inp.OutputsHash,
 		&inp.TransactionReference,
 		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
 		&inp.CreatedAt,
 		&inp.UpdatedAt,
 	)
@@ -77,6 +94,9 @@ func (r *PostgresRepository) GetInput(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &inp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &inp, nil
 }
 
@@ -101,12 +121,20 @@ func (r *PostgresRepository) GetInputByTxReference(
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
 			table.Input.TransactionReference,
 			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 			table.Input.CreatedAt,
 			table.Input.UpdatedAt,
 		).
@@ -125,17 +153,26 @@ func (r *PostgresRepository) GetInputByTxReference(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var inp model.Input
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&inp.EpochApplicationID,
 		&inp.EpochIndex,
 		&inp.Index,
 		&inp.BlockNumber,
+		&inp.BlockHash,
 		&inp.RawData,
+		&rawDataHash,
 		&inp.Status,
 		&inp.MachineHash,
 		&inp.OutputsHash,
 		&inp.TransactionReference,
 		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
 		&inp.CreatedAt,
 		&inp.UpdatedAt,
 	)
@@ -145,6 +182,9 @@ func (r *PostgresRepository) GetInputByTxReference(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &inp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &inp, nil
 }
 
@@ -165,12 +205,20 @@ func (r *PostgresRepository) GetLastInput(
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
 			table.Input.TransactionReference,
 			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 			table.Input.CreatedAt,
 			table.Input.UpdatedAt,
 		).
@@ -191,17 +239,26 @@ func (r *PostgresRepository) GetLastInput(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var inp model.Input
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&inp.EpochApplicationID,
 		&inp.EpochIndex,
 		&inp.Index,
 		&inp.BlockNumber,
+		&inp.BlockHash,
 		&inp.RawData,
+		&rawDataHash,
 		&inp.Status,
 		&inp.MachineHash,
 		&inp.OutputsHash,
 		&inp.TransactionReference,
 		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
 		&inp.CreatedAt,
 		&inp.UpdatedAt,
 	)
@@ -211,6 +268,9 @@ func (r *PostgresRepository) GetLastInput(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &inp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &inp, nil
 }
 
@@ -230,12 +290,20 @@ func (r *PostgresRepository) GetLastProcessedInput(
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
 			table.Input.TransactionReference,
 			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 			table.Input.CreatedAt,
 			table.Input.UpdatedAt,
 		).
@@ -256,17 +324,26 @@ func (r *PostgresRepository) GetLastProcessedInput(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var inp model.Input
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&inp.EpochApplicationID,
 		&inp.EpochIndex,
 		&inp.Index,
 		&inp.BlockNumber,
+		&inp.BlockHash,
 		&inp.RawData,
+		&rawDataHash,
 		&inp.Status,
 		&inp.MachineHash,
 		&inp.OutputsHash,
 		&inp.TransactionReference,
 		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
 		&inp.CreatedAt,
 		&inp.UpdatedAt,
 	)
@@ -276,6 +353,9 @@ func (r *PostgresRepository) GetLastProcessedInput(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &inp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &inp, nil
 }
 
@@ -298,12 +378,20 @@ func (r *PostgresRepository) ListInputs(
 			table.Input.EpochIndex,
 			table.Input.Index,
 			table.Input.BlockNumber,
+			table.Input.BlockHash,
 			table.Input.RawData,
+			table.Input.RawDataHash,
 			table.Input.Status,
 			table.Input.MachineHash,
 			table.Input.OutputsHash,
 			table.Input.TransactionReference,
 			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
 			table.Input.CreatedAt,
 			table.Input.UpdatedAt,
 			postgres.COUNT(postgres.STAR).OVER().AS("total_count"),
@@ -329,9 +417,11 @@ func (r *PostgresRepository) ListInputs(
 	}
 
 	if f.Sender != nil {
-		conditions = append(conditions,
-			postgres.SUBSTR(table.Input.RawData, postgres.Int(81), postgres.Int(20)).EQ(postgres.Bytea(f.Sender.Bytes())),
-		)
+		conditions = append(conditions, table.Input.Sender.EQ(postgres.Bytea(f.Sender.Bytes())))
+	}
+
+	if cursor := getCursorClause(table.Input.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
 	}
 
 	sel = sel.WHERE(postgres.AND(conditions...))
@@ -360,17 +450,26 @@ func (r *PostgresRepository) ListInputs(
 	var total uint64
 	for rows.Next() {
 		var in model.Input
+		var rawDataHash *common.Hash
 		err := rows.Scan(
 			&in.EpochApplicationID,
 			&in.EpochIndex,
 			&in.Index,
 			&in.BlockNumber,
+			&in.BlockHash,
 			&in.RawData,
+			&rawDataHash,
 			&in.Status,
 			&in.MachineHash,
 			&in.OutputsHash,
 			&in.TransactionReference,
 			&in.SnapshotURI,
+			&in.Cycles,
+			&in.ExecutionTime,
+			&in.OutputsCount,
+			&in.OutputsSize,
+			&in.ReportsCount,
+			&in.ReportsSize,
 			&in.CreatedAt,
 			&in.UpdatedAt,
 			&total,
@@ -378,6 +477,9 @@ func (r *PostgresRepository) ListInputs(
 		if err != nil {
 			return nil, 0, err
 		}
+		if err := r.rehydrate(ctx, &in.RawData, rawDataHash); err != nil {
+			return nil, 0, err
+		}
 		inputs = append(inputs, &in)
 	}
 	return inputs, total, nil
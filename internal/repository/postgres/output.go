@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-jet/jet/v2/postgres"
 
 	"github.com/cartesi/rollups-node/internal/model"
@@ -33,6 +34,7 @@ func (r *PostgresRepository) GetOutput(
 			table.Output.InputIndex,
 			table.Output.Index,
 			table.Output.RawData,
+			table.Output.RawDataHash,
 			table.Output.Hash,
 			table.Output.OutputHashesSiblings,
 			table.Output.ExecutionTransactionHash,
@@ -59,11 +61,13 @@ func (r *PostgresRepository) GetOutput(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var o model.Output
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&o.InputEpochApplicationID,
 		&o.InputIndex,
 		&o.Index,
 		&o.RawData,
+		&rawDataHash,
 		&o.Hash,
 		&o.OutputHashesSiblings,
 		&o.ExecutionTransactionHash,
@@ -77,6 +81,9 @@ func (r *PostgresRepository) GetOutput(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &o.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &o, nil
 }
 
@@ -177,6 +184,7 @@ func (r *PostgresRepository) ListOutputs(
 			table.Output.InputIndex,
 			table.Output.Index,
 			table.Output.RawData,
+			table.Output.RawDataHash,
 			table.Output.Hash,
 			table.Output.OutputHashesSiblings,
 			table.Output.ExecutionTransactionHash,
@@ -226,6 +234,10 @@ func (r *PostgresRepository) ListOutputs(
 		)
 	}
 
+	if cursor := getCursorClause(table.Output.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
 	sel = sel.WHERE(postgres.AND(conditions...))
 
 	if descending {
@@ -252,11 +264,13 @@ func (r *PostgresRepository) ListOutputs(
 	var total uint64
 	for rows.Next() {
 		var out model.Output
+		var rawDataHash *common.Hash
 		err := rows.Scan(
 			&out.InputEpochApplicationID,
 			&out.InputIndex,
 			&out.Index,
 			&out.RawData,
+			&rawDataHash,
 			&out.Hash,
 			&out.OutputHashesSiblings,
 			&out.ExecutionTransactionHash,
@@ -268,6 +282,9 @@ func (r *PostgresRepository) ListOutputs(
 		if err != nil {
 			return nil, 0, err
 		}
+		if err := r.rehydrate(ctx, &out.RawData, rawDataHash); err != nil {
+			return nil, 0, err
+		}
 		outputs = append(outputs, &out)
 	}
 	return outputs, total, nil
@@ -290,6 +307,7 @@ func (r *PostgresRepository) GetLastOutputBeforeBlock(
 			table.Output.InputIndex,
 			table.Output.Index,
 			table.Output.RawData,
+			table.Output.RawDataHash,
 			table.Output.Hash,
 			table.Output.OutputHashesSiblings,
 			table.Output.ExecutionTransactionHash,
@@ -322,11 +340,13 @@ func (r *PostgresRepository) GetLastOutputBeforeBlock(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var out model.Output
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&out.InputEpochApplicationID,
 		&out.InputIndex,
 		&out.Index,
 		&out.RawData,
+		&rawDataHash,
 		&out.Hash,
 		&out.OutputHashesSiblings,
 		&out.ExecutionTransactionHash,
@@ -340,5 +360,8 @@ func (r *PostgresRepository) GetLastOutputBeforeBlock(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &out.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &out, nil
 }
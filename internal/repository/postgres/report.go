@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-jet/jet/v2/postgres"
 
 	"github.com/cartesi/rollups-node/internal/model"
@@ -33,6 +34,7 @@ func (r *PostgresRepository) GetReport(
 			table.Report.InputIndex,
 			table.Report.Index,
 			table.Report.RawData,
+			table.Report.RawDataHash,
 			table.Report.CreatedAt,
 			table.Report.UpdatedAt,
 			table.Input.EpochIndex,
@@ -56,11 +58,13 @@ func (r *PostgresRepository) GetReport(
 	row := r.db.QueryRow(ctx, sqlStr, args...)
 
 	var rp model.Report
+	var rawDataHash *common.Hash
 	err = row.Scan(
 		&rp.InputEpochApplicationID,
 		&rp.InputIndex,
 		&rp.Index,
 		&rp.RawData,
+		&rawDataHash,
 		&rp.CreatedAt,
 		&rp.UpdatedAt,
 		&rp.EpochIndex,
@@ -71,6 +75,9 @@ func (r *PostgresRepository) GetReport(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.rehydrate(ctx, &rp.RawData, rawDataHash); err != nil {
+		return nil, err
+	}
 	return &rp, nil
 }
 
@@ -93,6 +100,7 @@ func (r *PostgresRepository) ListReports(
 			table.Report.InputIndex,
 			table.Report.Index,
 			table.Report.RawData,
+			table.Report.RawDataHash,
 			table.Report.CreatedAt,
 			table.Report.UpdatedAt,
 			table.Input.EpochIndex,
@@ -119,6 +127,10 @@ func (r *PostgresRepository) ListReports(
 		conditions = append(conditions, table.Input.Status.EQ(postgres.NewEnumValue(model.InputCompletionStatus_Accepted.String())))
 	}
 
+	if cursor := getCursorClause(table.Report.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
 	sel = sel.WHERE(postgres.AND(conditions...))
 
 	if descending {
@@ -145,11 +157,13 @@ func (r *PostgresRepository) ListReports(
 	var total uint64
 	for rows.Next() {
 		var rp model.Report
+		var rawDataHash *common.Hash
 		err := rows.Scan(
 			&rp.InputEpochApplicationID,
 			&rp.InputIndex,
 			&rp.Index,
 			&rp.RawData,
+			&rawDataHash,
 			&rp.CreatedAt,
 			&rp.UpdatedAt,
 			&rp.EpochIndex,
@@ -158,6 +172,9 @@ func (r *PostgresRepository) ListReports(
 		if err != nil {
 			return nil, 0, err
 		}
+		if err := r.rehydrate(ctx, &rp.RawData, rawDataHash); err != nil {
+			return nil, 0, err
+		}
 		reports = append(reports, &rp)
 	}
 	return reports, total, nil
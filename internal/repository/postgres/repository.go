@@ -11,12 +11,15 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/blob"
 	"github.com/cartesi/rollups-node/internal/repository/postgres/schema"
 )
 
 // postgresRepository is the concrete type that implements the repository.Repository interface.
 type PostgresRepository struct {
-	db *pgxpool.Pool
+	db            *pgxpool.Pool
+	blobs         blob.Store
+	blobThreshold uint64
 }
 
 func (r *PostgresRepository) Close() {
@@ -25,6 +28,10 @@ func (r *PostgresRepository) Close() {
 	}
 }
 
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.Ping(ctx)
+}
+
 func validateSchema(pool *pgxpool.Pool) error {
 
 	s, err := schema.NewWithPool(pool)
@@ -37,7 +44,13 @@ func validateSchema(pool *pgxpool.Pool) error {
 	return err
 }
 
-func NewPostgresRepository(ctx context.Context, conn string, maxRetries int, delay time.Duration) (repository.Repository, error) {
+func NewPostgresRepository(
+	ctx context.Context,
+	conn string,
+	maxRetries int,
+	delay time.Duration,
+	opts ...Option,
+) (repository.Repository, error) {
 
 	config, err := pgxpool.ParseConfig(conn)
 	if err != nil {
@@ -64,7 +77,11 @@ func NewPostgresRepository(ctx context.Context, conn string, maxRetries int, del
 	for i := range maxRetries {
 		err = validateSchema(pool)
 		if err == nil {
-			return &PostgresRepository{db: pool}, nil
+			repo := &PostgresRepository{db: pool}
+			for _, opt := range opts {
+				opt(repo)
+			}
+			return repo, nil
 		}
 		if i == maxRetries-1 {
 			pool.Close()
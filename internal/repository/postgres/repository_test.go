@@ -0,0 +1,93 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/blob"
+	"github.com/cartesi/rollups-node/test/tooling/db"
+	"github.com/cartesi/rollups-node/test/tooling/repotest"
+)
+
+// blobThreshold is low enough for repotest.LargePayload to be offloaded
+const blobThreshold = 256
+
+type PostgresSuite struct {
+	repotest.RepositorySuite
+	blobs *blob.FileStore
+	pg    *PostgresRepository
+}
+
+func TestPostgresRepository(t *testing.T) {
+	endpoint, err := db.GetTestDatabaseEndpoint()
+	if err != nil {
+		t.Skip(err)
+	}
+	lowerEndpoint := strings.ToLower(endpoint)
+	if !strings.HasPrefix(lowerEndpoint, "postgres://") && !strings.HasPrefix(lowerEndpoint, "postgresql://") {
+		t.Skip("CARTESI_TEST_DATABASE_CONNECTION is not a Postgres database")
+	}
+
+	s := &PostgresSuite{}
+	s.NewRepository = func(t *testing.T) repository.Repository {
+		require.Nil(t, db.SetupTestPostgres(endpoint))
+
+		var err error
+		s.blobs, err = blob.NewFileStore(t.TempDir())
+		require.Nil(t, err)
+
+		repo, err := NewPostgresRepository(context.Background(), endpoint, 1, time.Second,
+			WithBlobStore(s.blobs, blobThreshold))
+		require.Nil(t, err)
+		s.pg = repo.(*PostgresRepository)
+		return repo
+	}
+	suite.Run(t, s)
+}
+
+func (s *PostgresSuite) TestBlobOffloading() {
+	// the payloads are read back from the blob store
+	s.TestLargePayloads()
+
+	for _, prefix := range []string{"input", "output", "report"} {
+		payload := append([]byte(prefix), repotest.LargePayload...)
+		data, err := s.blobs.Get(context.Background(), blob.Key(payload))
+		s.Require().Nil(err, prefix)
+		s.Equal(payload, data, prefix)
+	}
+}
+
+func (s *PostgresSuite) TestBlobDeleteWaitsForWriter() {
+	ctx := context.Background()
+	conn, err := s.pg.db.Acquire(ctx)
+	s.Require().Nil(err)
+	_, key, err := s.pg.offload(ctx, conn, repotest.LargePayload)
+	s.Require().Nil(err)
+	s.Require().NotNil(key)
+
+	deleted := make(chan error)
+	go func() {
+		deleted <- s.pg.deleteUnreferencedBlobs(ctx, []common.Hash{common.BytesToHash(key)})
+	}()
+	select {
+	case <-deleted:
+		s.Require().Fail("the blob was deleted before the writer committed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the writer gave up without referencing the blob
+	s.pg.release(conn)
+	s.Require().Nil(<-deleted)
+	_, err = s.blobs.Get(ctx, common.BytesToHash(key))
+	s.ErrorIs(err, blob.ErrNotFound)
+}
@@ -0,0 +1,13 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- Payloads offloaded to the blob store must be restored to "raw_data" before
+-- running this migration, otherwise only their beginning is kept.
+
+BEGIN;
+
+ALTER TABLE "report" DROP COLUMN IF EXISTS "raw_data_hash";
+ALTER TABLE "output" DROP COLUMN IF EXISTS "raw_data_hash";
+ALTER TABLE "input" DROP COLUMN IF EXISTS "raw_data_hash";
+
+COMMIT;
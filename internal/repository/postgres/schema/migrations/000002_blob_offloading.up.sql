@@ -0,0 +1,12 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- When set, "raw_data" only keeps the beginning of the payload and the whole
+-- payload is kept in the blob store under this keccak256 hash.
+ALTER TABLE "input" ADD COLUMN "raw_data_hash" hash;
+ALTER TABLE "output" ADD COLUMN "raw_data_hash" hash;
+ALTER TABLE "report" ADD COLUMN "raw_data_hash" hash;
+
+COMMIT;
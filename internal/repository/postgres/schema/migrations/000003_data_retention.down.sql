@@ -0,0 +1,8 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TABLE "execution_parameters" DROP COLUMN IF EXISTS "retained_epochs";
+
+COMMIT;
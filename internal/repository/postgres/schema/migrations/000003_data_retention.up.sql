@@ -0,0 +1,11 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Number of most recent accepted epochs whose reports and input payloads are
+-- kept when pruning. Zero keeps everything.
+ALTER TABLE "execution_parameters"
+    ADD COLUMN "retained_epochs" BIGINT NOT NULL CHECK ("retained_epochs" >= 0) DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS "epoch_notify_claim_computed" ON "epoch";
+DROP TRIGGER IF EXISTS "epoch_notify_inputs_processed" ON "epoch";
+DROP TRIGGER IF EXISTS "epoch_notify_closed" ON "epoch";
+DROP TRIGGER IF EXISTS "epoch_notify_insert_closed" ON "epoch";
+DROP FUNCTION IF EXISTS "notify_epoch_status";
+
+DROP TRIGGER IF EXISTS "input_notify_insert" ON "input";
+DROP FUNCTION IF EXISTS "notify_input_status";
+
+COMMIT;
@@ -0,0 +1,50 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Wake up the services waiting for a row to reach a status, each on its own
+-- channel, so a service is not woken up by its own writes. The channel is the
+-- trigger argument and the payload is the application ID, so the
+-- notifications of a bulk change in the same transaction are folded into one.
+
+CREATE FUNCTION "notify_input_status"()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(TG_ARGV[0], NEW.epoch_application_id::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- advancer
+CREATE TRIGGER "input_notify_insert" AFTER INSERT ON "input"
+FOR EACH ROW EXECUTE FUNCTION notify_input_status('input_added');
+
+CREATE FUNCTION "notify_epoch_status"()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(TG_ARGV[0], NEW.application_id::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- advancer
+CREATE TRIGGER "epoch_notify_insert_closed" AFTER INSERT ON "epoch"
+FOR EACH ROW WHEN (NEW.status = 'CLOSED')
+EXECUTE FUNCTION notify_epoch_status('epoch_closed');
+
+CREATE TRIGGER "epoch_notify_closed" AFTER UPDATE OF "status" ON "epoch"
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status = 'CLOSED')
+EXECUTE FUNCTION notify_epoch_status('epoch_closed');
+
+-- validator
+CREATE TRIGGER "epoch_notify_inputs_processed" AFTER UPDATE OF "status" ON "epoch"
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status = 'INPUTS_PROCESSED')
+EXECUTE FUNCTION notify_epoch_status('epoch_inputs_processed');
+
+-- claimer
+CREATE TRIGGER "epoch_notify_claim_computed" AFTER UPDATE OF "status" ON "epoch"
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status = 'CLAIM_COMPUTED')
+EXECUTE FUNCTION notify_epoch_status('epoch_claim_computed');
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TABLE "epoch" DROP COLUMN IF EXISTS "block_hash";
+ALTER TABLE "input" DROP COLUMN IF EXISTS "block_hash";
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Hash of the block an input was read from, and of the last block of a closed
+-- epoch. They are compared against the chain to detect reorganizations.
+ALTER TABLE "input" ADD COLUMN "block_hash" hash;
+ALTER TABLE "epoch" ADD COLUMN "block_hash" hash;
+
+COMMIT;
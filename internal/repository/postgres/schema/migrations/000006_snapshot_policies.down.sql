@@ -0,0 +1,23 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TABLE "execution_parameters"
+    DROP COLUMN IF EXISTS "snapshot_input_interval",
+    DROP COLUMN IF EXISTS "snapshot_time_interval",
+    DROP COLUMN IF EXISTS "snapshot_cycle_interval";
+
+-- Enum values cannot be dropped, so the type is recreated without them.
+-- Applications with the newer policies snapshot every input instead.
+UPDATE "execution_parameters" SET "snapshot_policy" = 'EVERY_INPUT'
+    WHERE "snapshot_policy" IN ('EVERY_N_INPUTS', 'EVERY_DURATION', 'EVERY_N_CYCLES');
+ALTER TABLE "execution_parameters" ALTER COLUMN "snapshot_policy" DROP DEFAULT;
+ALTER TYPE "SnapshotPolicy" RENAME TO "SnapshotPolicy_old";
+CREATE TYPE "SnapshotPolicy" AS ENUM ('NONE', 'EVERY_INPUT', 'EVERY_EPOCH');
+ALTER TABLE "execution_parameters" ALTER COLUMN "snapshot_policy"
+    TYPE "SnapshotPolicy" USING "snapshot_policy"::text::"SnapshotPolicy";
+ALTER TABLE "execution_parameters" ALTER COLUMN "snapshot_policy" SET DEFAULT 'NONE';
+DROP TYPE "SnapshotPolicy_old";
+
+COMMIT;
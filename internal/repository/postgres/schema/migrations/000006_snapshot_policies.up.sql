@@ -0,0 +1,17 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TYPE "SnapshotPolicy" ADD VALUE IF NOT EXISTS 'EVERY_N_INPUTS';
+ALTER TYPE "SnapshotPolicy" ADD VALUE IF NOT EXISTS 'EVERY_DURATION';
+ALTER TYPE "SnapshotPolicy" ADD VALUE IF NOT EXISTS 'EVERY_N_CYCLES';
+
+-- How many inputs, how much wall-clock time (in nanoseconds) or how many
+-- machine cycles separate the snapshots of the corresponding policies.
+ALTER TABLE "execution_parameters"
+    ADD COLUMN "snapshot_input_interval" BIGINT NOT NULL CHECK ("snapshot_input_interval" > 0) DEFAULT 100,
+    ADD COLUMN "snapshot_time_interval" BIGINT NOT NULL CHECK ("snapshot_time_interval" > 0) DEFAULT 3600000000000, -- 1h
+    ADD COLUMN "snapshot_cycle_interval" BIGINT NOT NULL CHECK ("snapshot_cycle_interval" > 0) DEFAULT 1099511627776; -- 1 << 40
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TABLE "input"
+    DROP COLUMN IF EXISTS "cycles",
+    DROP COLUMN IF EXISTS "execution_time",
+    DROP COLUMN IF EXISTS "outputs_count",
+    DROP COLUMN IF EXISTS "outputs_size",
+    DROP COLUMN IF EXISTS "reports_count",
+    DROP COLUMN IF EXISTS "reports_size";
+
+COMMIT;
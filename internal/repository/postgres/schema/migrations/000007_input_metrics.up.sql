@@ -0,0 +1,17 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Cost of processing an input: machine cycles, wall-clock time (in
+-- nanoseconds) and the number and total size of its outputs and reports.
+-- NULL until the input is processed.
+ALTER TABLE "input"
+    ADD COLUMN "cycles" BIGINT CHECK ("cycles" >= 0),
+    ADD COLUMN "execution_time" BIGINT CHECK ("execution_time" >= 0),
+    ADD COLUMN "outputs_count" BIGINT CHECK ("outputs_count" >= 0),
+    ADD COLUMN "outputs_size" BIGINT CHECK ("outputs_size" >= 0),
+    ADD COLUMN "reports_count" BIGINT CHECK ("reports_count" >= 0),
+    ADD COLUMN "reports_size" BIGINT CHECK ("reports_size" >= 0);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+DROP TABLE IF EXISTS "application_state_history";
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Every change of the state or reason of an application, the oldest first.
+CREATE TABLE "application_state_history"
+(
+    "id" BIGSERIAL,
+    "application_id" INT NOT NULL,
+    "previous_state" "ApplicationState" NOT NULL,
+    "state" "ApplicationState" NOT NULL,
+    "reason" VARCHAR(4096),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT "application_state_history_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "application_state_history_application_id_fkey" FOREIGN KEY ("application_id") REFERENCES "application"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "application_state_history_application_id_idx" ON "application_state_history"("application_id");
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+ALTER TABLE "application" DROP COLUMN IF EXISTS "reason_code";
+DROP TYPE IF EXISTS "ReasonCode";
+
+COMMIT;
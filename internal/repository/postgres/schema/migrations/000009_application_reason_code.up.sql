@@ -0,0 +1,19 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+CREATE TYPE "ReasonCode" AS ENUM (
+    'MACHINE_SERVER_FAILURE',
+    'MACHINE_FAILURE',
+    'CLAIM_MISMATCH',
+    'CONSENSUS_CHANGED',
+    'INCONSISTENT_STATE',
+    'UNSUPPORTED_DATA_AVAILABILITY',
+    'CLAIMED_EPOCH_REVERTED'
+);
+
+-- Classifies the reason of an inoperable application, NULL when unclassified.
+ALTER TABLE "application" ADD COLUMN "reason_code" "ReasonCode";
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+-- Enum values can not be dropped, so the type is recreated without it and the
+-- applications classified with it become unclassified.
+UPDATE "application" SET "reason_code" = NULL WHERE "reason_code" = 'INVALID_INPUT';
+
+ALTER TYPE "ReasonCode" RENAME TO "ReasonCode_old";
+CREATE TYPE "ReasonCode" AS ENUM (
+    'MACHINE_SERVER_FAILURE',
+    'MACHINE_FAILURE',
+    'CLAIM_MISMATCH',
+    'CONSENSUS_CHANGED',
+    'INCONSISTENT_STATE',
+    'UNSUPPORTED_DATA_AVAILABILITY',
+    'CLAIMED_EPOCH_REVERTED'
+);
+ALTER TABLE "application"
+    ALTER COLUMN "reason_code" TYPE "ReasonCode" USING "reason_code"::TEXT::"ReasonCode";
+DROP TYPE "ReasonCode_old";
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+BEGIN;
+
+DROP INDEX IF EXISTS "input_sender_idx";
+ALTER TABLE "input" DROP COLUMN IF EXISTS "sender";
+CREATE INDEX "input_sender_idx" ON "input" ("epoch_application_id", substring("raw_data" FROM 81 FOR 20));
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- The sender of the input is kept in its own column, so the inputs can still be
+-- filtered by it once their payloads are pruned.
+
+BEGIN;
+
+ALTER TABLE "input" ADD COLUMN "sender" BYTEA;
+UPDATE "input" SET "sender" = substring("raw_data" FROM 81 FOR 20) WHERE octet_length("raw_data") >= 100;
+
+DROP INDEX IF EXISTS "input_sender_idx";
+CREATE INDEX "input_sender_idx" ON "input"("epoch_application_id", "sender");
+
+COMMIT;
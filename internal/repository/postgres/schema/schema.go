@@ -21,7 +21,7 @@ import (
 //go:embed migrations/*
 var content embed.FS
 
-const ExpectedVersion uint = 1
+const ExpectedVersion uint = 11
 
 type Schema struct {
 	migrate *migrate.Migrate
@@ -4,11 +4,13 @@
 package postgres
 
 import (
+	"fmt"
 	"regexp"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-jet/jet/v2/postgres"
 
+	"github.com/cartesi/rollups-node/internal/repository"
 	"github.com/cartesi/rollups-node/internal/repository/postgres/db/rollupsdb/public/table"
 )
 
@@ -30,3 +32,28 @@ func getWhereClauseFromNameOrAddress(nameOrAddress string) (postgres.BoolExpress
 	}
 	return whereClause, nil
 }
+
+// getCursorClause returns the keyset condition selecting the rows that come
+// after the pagination cursor in the requested order, or nil if there is none.
+func getCursorClause(
+	index postgres.ColumnFloat,
+	p repository.Pagination,
+	descending bool,
+) postgres.BoolExpression {
+	if p.After == nil {
+		return nil
+	}
+	after := postgres.RawFloat(fmt.Sprintf("%d", *p.After))
+	if descending {
+		return index.LT(after)
+	}
+	return index.GT(after)
+}
+
+// equalReasons reports whether two nullable reasons are the same.
+func equalReasons(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
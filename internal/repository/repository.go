@@ -14,12 +14,19 @@ import (
 )
 
 var (
-	ErrNotFound = fmt.Errorf("not found")
+	ErrNotFound      = fmt.Errorf("not found")
+	ErrInputReverted = fmt.Errorf("input was reverted")
+	// ErrClaimedEpochReverted is returned by RevertBlocks, reverting nothing,
+	// when an epoch it would revert has its claim submitted or accepted.
+	ErrClaimedEpochReverted = fmt.Errorf("the claim of a reverted epoch was submitted")
 )
 
 type Pagination struct {
 	Limit  uint64
 	Offset uint64
+	// After is a keyset cursor: when set, only rows whose index comes after it
+	// in the requested order are listed, and the total counts just those rows.
+	After *uint64
 }
 
 type ApplicationFilter struct {
@@ -39,6 +46,17 @@ type InputFilter struct {
 	Sender     *common.Address
 }
 
+// InputSender returns the msgSender argument of the EvmAdvance call encoded in
+// rawData, or nil when rawData is too short to hold it. The repositories store
+// it apart from the payload, so the inputs can be filtered by it once pruned.
+func InputSender(rawData []byte) []byte {
+	const offset = 4 + 32 + 32 + 12 // selector, chainId, appContract, address padding
+	if len(rawData) < offset+common.AddressLength {
+		return nil
+	}
+	return rawData[offset : offset+common.AddressLength]
+}
+
 type Range struct {
 	Start uint64
 	End   uint64
@@ -62,7 +80,15 @@ type ApplicationRepository interface {
 	GetApplication(ctx context.Context, nameOrAddress string) (*Application, error)
 	GetProcessedInputs(ctx context.Context, nameOrAddress string) (uint64, error)
 	UpdateApplication(ctx context.Context, app *Application) error
+	// UpdateApplicationState sets the state and reason of the application,
+	// recording the change in its state history. Its reason code is cleared.
 	UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error
+	// SetApplicationInoperable is UpdateApplicationState to the inoperable
+	// state, with the reason classified by code.
+	SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error
+	// ListApplicationStateHistory lists the state changes of the
+	// application, the oldest first.
+	ListApplicationStateHistory(ctx context.Context, nameOrAddress string) ([]*ApplicationStateChange, error)
 	DeleteApplication(ctx context.Context, id int64) error
 	ListApplications(ctx context.Context, f ApplicationFilter, p Pagination, descending bool) ([]*Application, uint64, error)
 
@@ -72,6 +98,12 @@ type ApplicationRepository interface {
 	UpdateEventLastCheckBlock(ctx context.Context, appIDs []int64, event MonitoredEvent, blockNumber uint64) error
 
 	GetLastSnapshot(ctx context.Context, nameOrAddress string) (*Input, error)
+	// ListSnapshots lists the accepted inputs of the application that have a
+	// snapshot, the most recent first. Their payloads are not loaded.
+	ListSnapshots(ctx context.Context, nameOrAddress string) ([]*Input, error)
+	// ListSnapshotURIs lists the snapshot URIs referenced by the inputs of
+	// every application.
+	ListSnapshotURIs(ctx context.Context) ([]string, error)
 }
 
 type EpochRepository interface {
@@ -110,9 +142,26 @@ type ReportRepository interface {
 }
 
 type BulkOperationsRepository interface {
+	// StoreAdvanceResult stores the outputs and reports of an input and marks
+	// it processed. It returns ErrInputReverted, storing nothing, when the
+	// input in ar.InputBlockHash is no longer the one at ar.InputIndex.
 	StoreAdvanceResult(ctx context.Context, appId int64, ar *AdvanceResult) error
 	StoreClaimAndProofs(ctx context.Context, epoch *Epoch, outputs []*Output) error
 	UpdateInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64, snapshotURI string) error
+	ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error
+	// PruneEpochs clears the report payloads of the accepted epochs before
+	// epochIndex, and the payloads of their inputs before inputIndex. Outputs,
+	// hashes and the rows themselves are kept. It returns how many inputs and
+	// reports were pruned.
+	PruneEpochs(ctx context.Context, appID int64, epochIndex uint64, inputIndex uint64) (inputs uint64, reports uint64, err error)
+	// RevertBlocks undoes what was read from the blocks after blockNumber,
+	// after a chain reorganization orphaned them. Their inputs are deleted
+	// together with their outputs and reports, the epochs starting after it
+	// are deleted and the epoch containing it is reopened. The processed
+	// inputs and the last checked blocks are rewound, so the blocks are read
+	// again and the machine is rebuilt. It returns how many inputs were deleted,
+	// or ErrClaimedEpochReverted.
+	RevertBlocks(ctx context.Context, appID int64, blockNumber uint64) (uint64, error)
 }
 
 type NodeConfigRepository interface {
@@ -156,6 +205,7 @@ type Repository interface {
 	BulkOperationsRepository
 	NodeConfigRepository
 	ClaimerRepository
+	Ping(ctx context.Context) error
 	Close()
 }
 
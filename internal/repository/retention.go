@@ -0,0 +1,69 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+)
+
+type PruneRepository interface {
+	ListEpochs(ctx context.Context, nameOrAddress string, f EpochFilter, p Pagination, descending bool) ([]*Epoch, uint64, error)
+	ListSnapshots(ctx context.Context, nameOrAddress string) ([]*Input, error)
+	PruneEpochs(ctx context.Context, appID int64, epochIndex uint64, inputIndex uint64) (inputs uint64, reports uint64, err error)
+}
+
+// PruneResult tells how many payloads were pruned and up to where.
+type PruneResult struct {
+	// EpochIndex is the first epoch that was kept whole.
+	EpochIndex uint64
+	Inputs     uint64
+	Reports    uint64
+}
+
+// PruneApplication applies the retention policy of the application: it keeps
+// the payloads of its last RetainedEpochs accepted epochs and prunes the older
+// ones. Zero retains everything. The claimer applies it whenever a claim of the
+// application is accepted.
+//
+// Inputs are only pruned up to the oldest retained snapshot, as the ones after
+// it are replayed on top of it when the machine is loaded from it. Without the
+// snapshot, the machine can no longer be rebuilt from the template: the replay
+// refuses the pruned inputs. Outputs are never pruned, so the validator and the
+// claimer still have their hashes and proofs.
+func PruneApplication(ctx context.Context, repo PruneRepository, app *Application) (*PruneResult, error) {
+	ep := app.ExecutionParameters
+	if ep.RetainedEpochs == 0 {
+		return nil, nil
+	}
+
+	nameOrAddress := app.IApplicationAddress.String()
+	status := EpochStatus_ClaimAccepted
+	epochs, _, err := repo.ListEpochs(ctx, nameOrAddress, EpochFilter{Status: &status},
+		Pagination{Limit: 1, Offset: ep.RetainedEpochs - 1}, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the oldest retained epoch: %w", err)
+	}
+	if len(epochs) == 0 {
+		return nil, nil
+	}
+	result := &PruneResult{EpochIndex: epochs[0].Index}
+
+	var inputIndex uint64
+	snapshots, err := repo.ListSnapshots(ctx, nameOrAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the snapshots: %w", err)
+	}
+	if len(snapshots) > 0 {
+		inputIndex = snapshots[len(snapshots)-1].Index + 1
+	}
+
+	result.Inputs, result.Reports, err = repo.PruneEpochs(ctx, app.ID, result.EpochIndex, inputIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune epochs: %w", err)
+	}
+	return result, nil
+}
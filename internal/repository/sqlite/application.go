@@ -0,0 +1,923 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+)
+
+// ------------------------ ApplicationRepository Methods ------------------------ //
+
+func (r *SQLiteRepository) CreateApplication(
+	ctx context.Context,
+	app *model.Application,
+) (int64, error) {
+
+	insertStmt := table.Application.
+		INSERT(
+			table.Application.Name,
+			table.Application.IapplicationAddress,
+			table.Application.IconsensusAddress,
+			table.Application.IinputboxAddress,
+			table.Application.TemplateHash,
+			table.Application.TemplateURI,
+			table.Application.EpochLength,
+			table.Application.DataAvailability,
+			table.Application.State,
+			table.Application.IinputboxBlock,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+			table.Application.ProcessedInputs,
+		).
+		VALUES(
+			app.Name,
+			app.IApplicationAddress,
+			app.IConsensusAddress,
+			app.IInputBoxAddress,
+			app.TemplateHash,
+			app.TemplateURI,
+			app.EpochLength,
+			app.DataAvailability[:],
+			app.State,
+			app.IInputBoxBlock,
+			app.LastInputCheckBlock,
+			app.LastOutputCheckBlock,
+			app.ProcessedInputs,
+		).
+		RETURNING(table.Application.ID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sqlStr, args := insertStmt.Sql()
+	var newID int64
+	err = tx.QueryRowContext(ctx, sqlStr, args...).Scan(&newID)
+	if err != nil {
+		return 0, errors.Join(fmt.Errorf("unable to create database application: %w", err), tx.Rollback())
+	}
+
+	sqlStr, args = table.ExecutionParameters.
+		INSERT(
+			table.ExecutionParameters.ApplicationID,
+		).
+		VALUES(
+			newID,
+		).Sql()
+
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return newID, nil
+}
+
+// GetApplication retrieves one application by ID, optionally loading status & execution parameters.
+func (r *SQLiteRepository) GetApplication(
+	ctx context.Context,
+	nameOrAddress string,
+) (*model.Application, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := table.Application.
+		SELECT(
+			table.Application.ID,
+			table.Application.Name,
+			table.Application.IapplicationAddress,
+			table.Application.IconsensusAddress,
+			table.Application.IinputboxAddress,
+			table.Application.TemplateHash,
+			table.Application.TemplateURI,
+			table.Application.EpochLength,
+			table.Application.DataAvailability,
+			table.Application.State,
+			table.Application.Reason,
+			table.Application.ReasonCode,
+			table.Application.IinputboxBlock,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+			table.Application.ProcessedInputs,
+			table.Application.CreatedAt,
+			table.Application.UpdatedAt,
+			table.ExecutionParameters.ApplicationID,
+			table.ExecutionParameters.SnapshotPolicy,
+			table.ExecutionParameters.AdvanceIncCycles,
+			table.ExecutionParameters.AdvanceMaxCycles,
+			table.ExecutionParameters.InspectIncCycles,
+			table.ExecutionParameters.InspectMaxCycles,
+			table.ExecutionParameters.AdvanceIncDeadline,
+			table.ExecutionParameters.AdvanceMaxDeadline,
+			table.ExecutionParameters.InspectIncDeadline,
+			table.ExecutionParameters.InspectMaxDeadline,
+			table.ExecutionParameters.LoadDeadline,
+			table.ExecutionParameters.StoreDeadline,
+			table.ExecutionParameters.FastDeadline,
+			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
+			table.ExecutionParameters.CreatedAt,
+			table.ExecutionParameters.UpdatedAt,
+		).
+		FROM(
+			table.Application.INNER_JOIN(
+				table.ExecutionParameters,
+				table.ExecutionParameters.ApplicationID.EQ(table.Application.ID),
+			),
+		).
+		WHERE(whereClause)
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var app model.Application
+	err = row.Scan(
+		&app.ID,
+		&app.Name,
+		&app.IApplicationAddress,
+		&app.IConsensusAddress,
+		&app.IInputBoxAddress,
+		&app.TemplateHash,
+		&app.TemplateURI,
+		&app.EpochLength,
+		&app.DataAvailability,
+		&app.State,
+		&app.Reason,
+		&app.ReasonCode,
+		&app.IInputBoxBlock,
+		&app.LastInputCheckBlock,
+		&app.LastOutputCheckBlock,
+		&app.ProcessedInputs,
+		&app.CreatedAt,
+		&app.UpdatedAt,
+		&app.ExecutionParameters.ApplicationID,
+		&app.ExecutionParameters.SnapshotPolicy,
+		&app.ExecutionParameters.AdvanceIncCycles,
+		&app.ExecutionParameters.AdvanceMaxCycles,
+		&app.ExecutionParameters.InspectIncCycles,
+		&app.ExecutionParameters.InspectMaxCycles,
+		&app.ExecutionParameters.AdvanceIncDeadline,
+		&app.ExecutionParameters.AdvanceMaxDeadline,
+		&app.ExecutionParameters.InspectIncDeadline,
+		&app.ExecutionParameters.InspectMaxDeadline,
+		&app.ExecutionParameters.LoadDeadline,
+		&app.ExecutionParameters.StoreDeadline,
+		&app.ExecutionParameters.FastDeadline,
+		&app.ExecutionParameters.MaxConcurrentInspects,
+		&app.ExecutionParameters.RetainedEpochs,
+		&app.ExecutionParameters.SnapshotInputInterval,
+		&app.ExecutionParameters.SnapshotTimeInterval,
+		&app.ExecutionParameters.SnapshotCycleInterval,
+		&app.ExecutionParameters.CreatedAt,
+		&app.ExecutionParameters.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &app, nil
+}
+
+// GetProcessedInputs retrieves the ProcessedInputs field from a application by Name or address.
+func (r *SQLiteRepository) GetProcessedInputs(
+	ctx context.Context,
+	nameOrAddress string,
+) (uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := table.Application.
+		SELECT(table.Application.ProcessedInputs).
+		FROM(
+			table.Application.INNER_JOIN(
+				table.ExecutionParameters,
+				table.ExecutionParameters.ApplicationID.EQ(table.Application.ID),
+			),
+		).
+		WHERE(whereClause)
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var processedInputs uint64
+	err = row.Scan(&processedInputs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	return processedInputs, err
+}
+
+// UpdateApplication updates an existing application row.
+func (r *SQLiteRepository) UpdateApplication(
+	ctx context.Context,
+	app *model.Application,
+) error {
+
+	updateStmt := table.Application.
+		UPDATE(
+			table.Application.Name,
+			table.Application.IapplicationAddress,
+			table.Application.IconsensusAddress,
+			table.Application.IinputboxAddress,
+			table.Application.TemplateHash,
+			table.Application.TemplateURI,
+			table.Application.EpochLength,
+			table.Application.DataAvailability,
+			table.Application.State,
+			table.Application.Reason,
+			table.Application.ReasonCode,
+			table.Application.IinputboxBlock,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+			table.Application.ProcessedInputs,
+		).
+		SET(
+			app.Name,
+			app.IApplicationAddress,
+			app.IConsensusAddress,
+			app.IInputBoxAddress,
+			app.TemplateHash,
+			app.TemplateURI,
+			app.EpochLength,
+			app.DataAvailability[:],
+			app.State,
+			app.Reason,
+			app.ReasonCode,
+			app.IInputBoxBlock,
+			app.LastInputCheckBlock,
+			app.LastOutputCheckBlock,
+			app.ProcessedInputs,
+		).
+		WHERE(table.Application.ID.EQ(sqlite.Int(app.ID)))
+
+	sqlStr, args := updateStmt.Sql()
+	_, err := r.db.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func (r *SQLiteRepository) UpdateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state model.ApplicationState,
+	reason *string,
+) error {
+	return r.updateApplicationState(ctx, appID, state, reason, nil)
+}
+
+func (r *SQLiteRepository) SetApplicationInoperable(
+	ctx context.Context,
+	appID int64,
+	code model.ReasonCode,
+	reason string,
+) error {
+	return r.updateApplicationState(ctx, appID, model.ApplicationState_Inoperable, &reason, &code)
+}
+
+func (r *SQLiteRepository) updateApplicationState(
+	ctx context.Context,
+	appID int64,
+	state model.ApplicationState,
+	reason *string,
+	code *model.ReasonCode,
+) error {
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sqlStr, args := table.Application.
+		SELECT(
+			table.Application.State,
+			table.Application.Reason,
+		).
+		WHERE(table.Application.ID.EQ(sqlite.Int(appID))).
+		Sql()
+
+	var previousState model.ApplicationState
+	var previousReason *string
+	err = tx.QueryRowContext(ctx, sqlStr, args...).Scan(&previousState, &previousReason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx.Rollback()
+	}
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	sqlStr, args = table.Application.
+		UPDATE(
+			table.Application.State,
+			table.Application.Reason,
+			table.Application.ReasonCode,
+		).
+		SET(
+			state,
+			reason,
+			code,
+		).
+		WHERE(table.Application.ID.EQ(sqlite.Int(appID))).
+		Sql()
+
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	if previousState != state || !equalReasons(previousReason, reason) {
+		sqlStr, args = table.ApplicationStateHistory.
+			INSERT(
+				table.ApplicationStateHistory.ApplicationID,
+				table.ApplicationStateHistory.PreviousState,
+				table.ApplicationStateHistory.State,
+				table.ApplicationStateHistory.Reason,
+			).
+			VALUES(
+				appID,
+				previousState,
+				state,
+				reason,
+			).Sql()
+
+		_, err = tx.ExecContext(ctx, sqlStr, args...)
+		if err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) ListApplicationStateHistory(
+	ctx context.Context,
+	nameOrAddress string,
+) ([]*model.ApplicationStateChange, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.ApplicationStateHistory.
+		SELECT(
+			table.ApplicationStateHistory.ID,
+			table.ApplicationStateHistory.ApplicationID,
+			table.ApplicationStateHistory.PreviousState,
+			table.ApplicationStateHistory.State,
+			table.ApplicationStateHistory.Reason,
+			table.ApplicationStateHistory.CreatedAt,
+		).
+		FROM(
+			table.ApplicationStateHistory.
+				INNER_JOIN(table.Application,
+					table.ApplicationStateHistory.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(whereClause).
+		ORDER_BY(table.ApplicationStateHistory.ID.ASC())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.ApplicationStateChange
+	for rows.Next() {
+		var change model.ApplicationStateChange
+		err := rows.Scan(
+			&change.ID,
+			&change.ApplicationID,
+			&change.PreviousState,
+			&change.State,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *SQLiteRepository) UpdateEventLastCheckBlock(
+	ctx context.Context,
+	appIDs []int64,
+	event model.MonitoredEvent,
+	blockNumber uint64,
+) error {
+	var column sqlite.ColumnInteger
+	switch event {
+	case model.MonitoredEvent_InputAdded:
+		column = table.Application.LastInputCheckBlock
+	case model.MonitoredEvent_OutputExecuted:
+		column = table.Application.LastOutputCheckBlock
+	case model.MonitoredEvent_ClaimSubmitted:
+		fallthrough
+	case model.MonitoredEvent_ClaimAccepted:
+		fallthrough
+	default:
+		return fmt.Errorf("invalid monitored event type: %v", event)
+	}
+	if len(appIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]sqlite.Expression, len(appIDs))
+	for i, id := range appIDs {
+		ids[i] = sqlite.Int(id)
+	}
+
+	updateStmt := table.Application.
+		UPDATE(
+			column,
+		).
+		SET(
+			sqlite.Uint64(blockNumber),
+		).
+		WHERE(table.Application.ID.IN(ids...))
+
+	sqlStr, args := updateStmt.Sql()
+	_, err := r.db.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+// GetLastSnapshot retrieves the most recent input with a snapshot for the given application
+func (r *SQLiteRepository) GetLastSnapshot(ctx context.Context, nameOrAddress string) (*model.Input, error) {
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String()))).
+				AND(table.Input.SnapshotURI.IS_NOT_NULL()),
+		).
+		ORDER_BY(table.Input.Index.DESC()).
+		LIMIT(1)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var inp model.Input
+	err = row.Scan(
+		&inp.EpochApplicationID,
+		&inp.EpochIndex,
+		&inp.Index,
+		&inp.BlockNumber,
+		&inp.BlockHash,
+		&inp.RawData,
+		&inp.Status,
+		&inp.MachineHash,
+		&inp.OutputsHash,
+		&inp.TransactionReference,
+		&inp.SnapshotURI,
+		&inp.CreatedAt,
+		&inp.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inp, nil
+}
+
+// ListSnapshots retrieves the accepted inputs with a snapshot for the given
+// application, the most recent first, without their payloads
+func (r *SQLiteRepository) ListSnapshots(ctx context.Context, nameOrAddress string) ([]*model.Input, error) {
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String()))).
+				AND(table.Input.SnapshotURI.IS_NOT_NULL()),
+		).
+		ORDER_BY(table.Input.Index.DESC())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inputs []*model.Input
+	for rows.Next() {
+		var inp model.Input
+		err := rows.Scan(
+			&inp.EpochApplicationID,
+			&inp.EpochIndex,
+			&inp.Index,
+			&inp.BlockNumber,
+			&inp.BlockHash,
+			&inp.Status,
+			&inp.MachineHash,
+			&inp.OutputsHash,
+			&inp.TransactionReference,
+			&inp.SnapshotURI,
+			&inp.CreatedAt,
+			&inp.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, &inp)
+	}
+	return inputs, rows.Err()
+}
+
+// ListSnapshotURIs retrieves the snapshot URIs referenced by the inputs of every application
+func (r *SQLiteRepository) ListSnapshotURIs(ctx context.Context) ([]string, error) {
+	sel := table.Input.
+		SELECT(table.Input.SnapshotURI).
+		WHERE(table.Input.SnapshotURI.IS_NOT_NULL())
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uris []string
+	for rows.Next() {
+		var uri string
+		if err := rows.Scan(&uri); err != nil {
+			return nil, err
+		}
+		uris = append(uris, uri)
+	}
+	return uris, rows.Err()
+}
+
+// DeleteApplication removes the row from "application" by ID.
+func (r *SQLiteRepository) DeleteApplication(
+	ctx context.Context,
+	id int64,
+) error {
+	delStmt := table.Application.
+		DELETE().
+		WHERE(table.Application.ID.EQ(sqlite.Int(id)))
+
+	sqlStr, args := delStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return errors.Join(fmt.Errorf("application with ID %d not found", id), err)
+	}
+	return nil
+}
+
+// ListApplications queries multiple apps with optional filters & pagination.
+func (r *SQLiteRepository) ListApplications(
+	ctx context.Context,
+	f repository.ApplicationFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Application, uint64, error) {
+
+	sel := table.Application.
+		SELECT(
+			table.Application.ID,
+			table.Application.Name,
+			table.Application.IapplicationAddress,
+			table.Application.IconsensusAddress,
+			table.Application.IinputboxAddress,
+			table.Application.TemplateHash,
+			table.Application.TemplateURI,
+			table.Application.EpochLength,
+			table.Application.DataAvailability,
+			table.Application.State,
+			table.Application.Reason,
+			table.Application.ReasonCode,
+			table.Application.IinputboxBlock,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+			table.Application.ProcessedInputs,
+			table.Application.CreatedAt,
+			table.Application.UpdatedAt,
+			table.ExecutionParameters.ApplicationID,
+			table.ExecutionParameters.SnapshotPolicy,
+			table.ExecutionParameters.AdvanceIncCycles,
+			table.ExecutionParameters.AdvanceMaxCycles,
+			table.ExecutionParameters.InspectIncCycles,
+			table.ExecutionParameters.InspectMaxCycles,
+			table.ExecutionParameters.AdvanceIncDeadline,
+			table.ExecutionParameters.AdvanceMaxDeadline,
+			table.ExecutionParameters.InspectIncDeadline,
+			table.ExecutionParameters.InspectMaxDeadline,
+			table.ExecutionParameters.LoadDeadline,
+			table.ExecutionParameters.StoreDeadline,
+			table.ExecutionParameters.FastDeadline,
+			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
+			table.ExecutionParameters.CreatedAt,
+			table.ExecutionParameters.UpdatedAt,
+			sqlite.COUNT(sqlite.STAR).OVER().AS("total_count"),
+		).
+		FROM(
+			table.Application.INNER_JOIN(
+				table.ExecutionParameters,
+				table.ExecutionParameters.ApplicationID.EQ(table.Application.ID),
+			),
+		)
+
+	conditions := []sqlite.BoolExpression{}
+	if f.State != nil {
+		conditions = append(conditions, table.Application.State.EQ(sqlite.String(f.State.String())))
+	}
+	if f.DataAvailability != nil {
+		conditions = append(conditions,
+			sqlite.SUBSTR(
+				table.Application.DataAvailability, sqlite.Int(1), sqlite.Int(4), // nolint: mnd
+			).EQ(blob(f.DataAvailability[:])))
+	}
+
+	if len(conditions) > 0 {
+		sel = sel.WHERE(sqlite.AND(conditions...))
+	}
+
+	if descending {
+		sel = sel.ORDER_BY(table.Application.Name.DESC())
+	} else {
+		sel = sel.ORDER_BY(table.Application.Name.ASC())
+	}
+
+	// Apply pagination
+	if p.Limit > 0 {
+		sel = sel.LIMIT(int64(p.Limit))
+	}
+	if p.Offset > 0 {
+		sel = sel.OFFSET(int64(p.Offset))
+	}
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var apps []*model.Application
+	var total uint64
+	for rows.Next() {
+		var app model.Application
+		err := rows.Scan(
+			&app.ID,
+			&app.Name,
+			&app.IApplicationAddress,
+			&app.IConsensusAddress,
+			&app.IInputBoxAddress,
+			&app.TemplateHash,
+			&app.TemplateURI,
+			&app.EpochLength,
+			&app.DataAvailability,
+			&app.State,
+			&app.Reason,
+			&app.ReasonCode,
+			&app.IInputBoxBlock,
+			&app.LastInputCheckBlock,
+			&app.LastOutputCheckBlock,
+			&app.ProcessedInputs,
+			&app.CreatedAt,
+			&app.UpdatedAt,
+			&app.ExecutionParameters.ApplicationID,
+			&app.ExecutionParameters.SnapshotPolicy,
+			&app.ExecutionParameters.AdvanceIncCycles,
+			&app.ExecutionParameters.AdvanceMaxCycles,
+			&app.ExecutionParameters.InspectIncCycles,
+			&app.ExecutionParameters.InspectMaxCycles,
+			&app.ExecutionParameters.AdvanceIncDeadline,
+			&app.ExecutionParameters.AdvanceMaxDeadline,
+			&app.ExecutionParameters.InspectIncDeadline,
+			&app.ExecutionParameters.InspectMaxDeadline,
+			&app.ExecutionParameters.LoadDeadline,
+			&app.ExecutionParameters.StoreDeadline,
+			&app.ExecutionParameters.FastDeadline,
+			&app.ExecutionParameters.MaxConcurrentInspects,
+			&app.ExecutionParameters.RetainedEpochs,
+			&app.ExecutionParameters.SnapshotInputInterval,
+			&app.ExecutionParameters.SnapshotTimeInterval,
+			&app.ExecutionParameters.SnapshotCycleInterval,
+			&app.ExecutionParameters.CreatedAt,
+			&app.ExecutionParameters.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		apps = append(apps, &app)
+	}
+
+	return apps, total, nil
+}
+
+func (r *SQLiteRepository) GetExecutionParameters(
+	ctx context.Context,
+	applicationID int64,
+) (*model.ExecutionParameters, error) {
+
+	stmt := table.ExecutionParameters.
+		SELECT(
+			table.ExecutionParameters.ApplicationID,
+			table.ExecutionParameters.SnapshotPolicy,
+			table.ExecutionParameters.AdvanceIncCycles,
+			table.ExecutionParameters.AdvanceMaxCycles,
+			table.ExecutionParameters.InspectIncCycles,
+			table.ExecutionParameters.InspectMaxCycles,
+			table.ExecutionParameters.AdvanceIncDeadline,
+			table.ExecutionParameters.AdvanceMaxDeadline,
+			table.ExecutionParameters.InspectIncDeadline,
+			table.ExecutionParameters.InspectMaxDeadline,
+			table.ExecutionParameters.LoadDeadline,
+			table.ExecutionParameters.StoreDeadline,
+			table.ExecutionParameters.FastDeadline,
+			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
+			table.ExecutionParameters.CreatedAt,
+			table.ExecutionParameters.UpdatedAt,
+		).
+		WHERE(table.ExecutionParameters.ApplicationID.EQ(sqlite.Int(applicationID)))
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var ep model.ExecutionParameters
+	err := row.Scan(
+		&ep.ApplicationID,
+		&ep.SnapshotPolicy,
+		&ep.AdvanceIncCycles,
+		&ep.AdvanceMaxCycles,
+		&ep.InspectIncCycles,
+		&ep.InspectMaxCycles,
+		&ep.AdvanceIncDeadline,
+		&ep.AdvanceMaxDeadline,
+		&ep.InspectIncDeadline,
+		&ep.InspectMaxDeadline,
+		&ep.LoadDeadline,
+		&ep.StoreDeadline,
+		&ep.FastDeadline,
+		&ep.MaxConcurrentInspects,
+		&ep.RetainedEpochs,
+		&ep.SnapshotInputInterval,
+		&ep.SnapshotTimeInterval,
+		&ep.SnapshotCycleInterval,
+		&ep.CreatedAt,
+		&ep.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+func (r *SQLiteRepository) UpdateExecutionParameters(
+	ctx context.Context,
+	ep *model.ExecutionParameters,
+) error {
+
+	upd := table.ExecutionParameters.
+		UPDATE(
+			table.ExecutionParameters.SnapshotPolicy,
+			table.ExecutionParameters.AdvanceIncCycles,
+			table.ExecutionParameters.AdvanceMaxCycles,
+			table.ExecutionParameters.InspectIncCycles,
+			table.ExecutionParameters.InspectMaxCycles,
+			table.ExecutionParameters.AdvanceIncDeadline,
+			table.ExecutionParameters.AdvanceMaxDeadline,
+			table.ExecutionParameters.InspectIncDeadline,
+			table.ExecutionParameters.InspectMaxDeadline,
+			table.ExecutionParameters.LoadDeadline,
+			table.ExecutionParameters.StoreDeadline,
+			table.ExecutionParameters.FastDeadline,
+			table.ExecutionParameters.MaxConcurrentInspects,
+			table.ExecutionParameters.RetainedEpochs,
+			table.ExecutionParameters.SnapshotInputInterval,
+			table.ExecutionParameters.SnapshotTimeInterval,
+			table.ExecutionParameters.SnapshotCycleInterval,
+		).
+		SET(
+			ep.SnapshotPolicy,
+			ep.AdvanceIncCycles,
+			ep.AdvanceMaxCycles,
+			ep.InspectIncCycles,
+			ep.InspectMaxCycles,
+			ep.AdvanceIncDeadline,
+			ep.AdvanceMaxDeadline,
+			ep.InspectIncDeadline,
+			ep.InspectMaxDeadline,
+			ep.LoadDeadline,
+			ep.StoreDeadline,
+			ep.FastDeadline,
+			ep.MaxConcurrentInspects,
+			ep.RetainedEpochs,
+			ep.SnapshotInputInterval,
+			ep.SnapshotTimeInterval,
+			ep.SnapshotCycleInterval,
+		).
+		WHERE(table.ExecutionParameters.ApplicationID.EQ(sqlite.Int(ep.ApplicationID)))
+
+	sqlStr, args := upd.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
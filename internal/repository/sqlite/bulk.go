@@ -0,0 +1,640 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+)
+
+func getOutputNextIndex(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+) (uint64, error) {
+
+	query := table.Output.SELECT(
+		sqlite.COALESCE(
+			sqlite.MAXi(table.Output.Index).ADD(sqlite.Int(1)),
+			sqlite.Int(0),
+		),
+	).FROM(
+		table.Output.INNER_JOIN(table.Input, table.Input.EpochApplicationID.EQ(table.Output.InputEpochApplicationID).
+			AND(table.Input.Index.EQ(table.Output.InputIndex))),
+	).WHERE(
+		table.Output.InputEpochApplicationID.EQ(sqlite.Int64(appID)).
+			AND(table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String()))),
+	)
+
+	queryStr, args := query.Sql()
+	var currentIndex uint64
+	err := tx.QueryRowContext(ctx, queryStr, args...).Scan(&currentIndex)
+	if err != nil {
+		err = fmt.Errorf("failed to get the next output index: %w", err)
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return currentIndex, nil
+}
+
+func getReportNextIndex(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+) (uint64, error) {
+
+	// Reports are stored for every processed input, whatever its status, so
+	// the next index comes straight from the application reports.
+	query := table.Report.SELECT(
+		sqlite.COALESCE(
+			sqlite.MAXi(table.Report.Index).ADD(sqlite.Int(1)),
+			sqlite.Int(0),
+		),
+	).WHERE(
+		table.Report.InputEpochApplicationID.EQ(sqlite.Int64(appID)),
+	)
+
+	queryStr, args := query.Sql()
+	var currentIndex uint64
+	err := tx.QueryRowContext(ctx, queryStr, args...).Scan(&currentIndex)
+	if err != nil {
+		err = fmt.Errorf("failed to get the next report index: %w", err)
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return currentIndex, nil
+}
+
+func insertOutputs(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+	inputIndex uint64,
+	dataArray [][]byte,
+) error {
+	if len(dataArray) < 1 {
+		return nil
+	}
+
+	nextIndex, err := getOutputNextIndex(ctx, tx, appID)
+	if err != nil {
+		return err
+	}
+
+	stmt := table.Output.INSERT(
+		table.Output.InputEpochApplicationID,
+		table.Output.InputIndex,
+		table.Output.Index,
+		table.Output.RawData,
+	)
+	for i, data := range dataArray {
+		stmt = stmt.VALUES(
+			appID,
+			inputIndex,
+			nextIndex+uint64(i),
+			data,
+		)
+	}
+
+	sqlStr, args := stmt.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return nil
+}
+
+func insertReports(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+	inputIndex uint64,
+	dataArray [][]byte,
+) error {
+	if len(dataArray) < 1 {
+		return nil
+	}
+
+	nextIndex, err := getReportNextIndex(ctx, tx, appID)
+	if err != nil {
+		return err
+	}
+
+	stmt := table.Report.INSERT(
+		table.Report.InputEpochApplicationID,
+		table.Report.InputIndex,
+		table.Report.Index,
+		table.Report.RawData,
+	)
+	for i, data := range dataArray {
+		stmt = stmt.VALUES(
+			appID,
+			inputIndex,
+			nextIndex+uint64(i),
+			data,
+		)
+	}
+
+	sqlStr, args := stmt.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return nil
+}
+
+func updateInput(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+	res *model.AdvanceResult,
+) error {
+	whereClause := table.Input.EpochApplicationID.EQ(sqlite.Int64(appID)).
+		AND(table.Input.Index.EQ(sqlite.Uint64(res.InputIndex)))
+	if res.InputBlockHash != nil {
+		whereClause = whereClause.AND(table.Input.BlockHash.EQ(blob(res.InputBlockHash.Bytes())))
+	}
+
+	updStmt := table.Input.
+		UPDATE(
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+		).
+		SET(
+			res.Status,
+			*res.MachineHash,
+			res.OutputsHash,
+			res.Cycles,
+			int64(res.ExecutionTime),
+			uint64(len(res.Outputs)),
+			res.OutputsSize(),
+			uint64(len(res.Reports)),
+			res.ReportsSize(),
+		).
+		WHERE(whereClause)
+
+	sqlStr, args := updStmt.Sql()
+	result, err := tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return errors.Join(err, tx.Rollback())
+	} else if n == 0 && res.InputBlockHash != nil {
+		return errors.Join(repository.ErrInputReverted, tx.Rollback())
+	} else if n == 0 {
+		return errors.Join(sql.ErrNoRows, tx.Rollback())
+	}
+	return nil
+}
+
+func updateApp(
+	ctx context.Context,
+	tx *sql.Tx,
+	appID int64,
+	inputIndex uint64,
+) error {
+
+	updStmt := table.Application.
+		UPDATE(
+			table.Application.ProcessedInputs,
+		).
+		SET(
+			sqlite.Uint64(inputIndex + 1),
+		).
+		WHERE(
+			table.Application.ID.EQ(sqlite.Int64(appID)),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return errors.Join(err, tx.Rollback())
+	} else if n == 0 {
+		return errors.Join(sql.ErrNoRows, tx.Rollback())
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) StoreAdvanceResult(
+	ctx context.Context,
+	appID int64,
+	res *model.AdvanceResult,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// first, so the result of a reverted input is rejected
+	err = updateInput(ctx, tx, appID, res)
+	if err != nil {
+		return err
+	}
+
+	err = insertOutputs(ctx, tx, appID, res.InputIndex, res.Outputs)
+	if err != nil {
+		return err
+	}
+
+	err = insertReports(ctx, tx, appID, res.InputIndex, res.Reports)
+	if err != nil {
+		return err
+	}
+
+	err = updateApp(ctx, tx, appID, res.InputIndex)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return nil
+}
+
+func updateEpochClaim(
+	ctx context.Context,
+	tx *sql.Tx,
+	e *model.Epoch,
+) error {
+
+	updStmt := table.Epoch.
+		UPDATE(
+			table.Epoch.ClaimHash,
+			table.Epoch.Status,
+		).
+		SET(
+			e.ClaimHash,
+			sqlite.String(model.EpochStatus_ClaimComputed.String()),
+		).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(e.ApplicationID)).
+				AND(table.Epoch.Index.EQ(sqlite.Uint64(e.Index))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("SetEpochClaimAndInsertProofsTransaction failed: %w", err),
+			tx.Rollback(),
+		)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return errors.Join(err, tx.Rollback())
+	} else if n != 1 {
+		return errors.Join(
+			fmt.Errorf("failed to update application %d epoch %d: no rows affected", e.ApplicationID, e.Index),
+			tx.Rollback(),
+		)
+	}
+	return nil
+}
+
+func updateOutputs(
+	ctx context.Context,
+	tx *sql.Tx,
+	outputs []*model.Output,
+) error {
+	for _, output := range outputs {
+		updStmt := table.Output.
+			UPDATE(
+				table.Output.Hash,
+				table.Output.OutputHashesSiblings,
+			).
+			SET(
+				output.Hash,
+				hashList(output.OutputHashesSiblings),
+			).
+			WHERE(
+				table.Output.InputEpochApplicationID.EQ(sqlite.Int64(output.InputEpochApplicationID)).
+					AND(table.Output.Index.EQ(sqlite.Uint64(output.Index))),
+			)
+
+		sqlStr, args := updStmt.Sql()
+		res, err := tx.ExecContext(ctx, sqlStr, args...)
+		if err != nil {
+			return errors.Join(
+				fmt.Errorf("failed to insert proof for output '%d'. %w", output.Index, err),
+				tx.Rollback(),
+			)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return errors.Join(err, tx.Rollback())
+		} else if n == 0 {
+			return errors.Join(
+				fmt.Errorf(
+					"failed to insert proof for output '%d'. No rows affected",
+					output.Index,
+				),
+				tx.Rollback(),
+			)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) StoreClaimAndProofs(ctx context.Context, epoch *model.Epoch, outputs []*model.Output) error {
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("SetEpochClaimAndInsertProofsTransaction failed: %w", err)
+	}
+
+	err = updateEpochClaim(ctx, tx, epoch)
+	if err != nil {
+		return err
+	}
+
+	err = updateOutputs(ctx, tx, outputs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("SetEpochClaimAndInsertProofsTransaction failed: %w", err),
+			tx.Rollback(),
+		)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) UpdateInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64, snapshotURI string) error {
+	updStmt := table.Input.
+		UPDATE(
+			table.Input.SnapshotURI,
+		).
+		SET(
+			snapshotURI,
+		).
+		WHERE(
+			table.Input.EpochApplicationID.EQ(sqlite.Int64(appId)).
+				AND(table.Input.Index.EQ(sqlite.Uint64(inputIndex))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no input found with appId %d and index %d", appId, inputIndex)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) ClearInputSnapshotURI(ctx context.Context, appId int64, inputIndex uint64) error {
+	updStmt := table.Input.
+		UPDATE(
+			table.Input.SnapshotURI,
+		).
+		SET(
+			sqlite.NULL,
+		).
+		WHERE(
+			table.Input.EpochApplicationID.EQ(sqlite.Int64(appId)).
+				AND(table.Input.Index.EQ(sqlite.Uint64(inputIndex))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	_, err := r.db.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+// prunePayloads clears the payloads of the rows matching cond that still have
+// one, returning how many rows were pruned.
+func prunePayloads(
+	ctx context.Context,
+	tx *sql.Tx,
+	tbl sqlite.Table,
+	rawData sqlite.ColumnString,
+	cond sqlite.BoolExpression,
+) (uint64, error) {
+	updStmt := tbl.
+		UPDATE(
+			rawData,
+		).
+		SET(
+			[]byte{},
+		).
+		WHERE(cond.AND(rawData.NOT_EQ(sqlite.RawString("x''"))))
+
+	sqlStr, args := updStmt.Sql()
+	res, err := tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return uint64(n), nil
+}
+
+func (r *SQLiteRepository) PruneEpochs(
+	ctx context.Context,
+	appID int64,
+	epochIndex uint64,
+	inputIndex uint64,
+) (uint64, uint64, error) {
+	// only the accepted epochs before epochIndex are pruned
+	prunedEpoch := table.Epoch.Status.EQ(sqlite.String(model.EpochStatus_ClaimAccepted.String())).
+		AND(table.Epoch.Index.LT(sqlite.Uint64(epochIndex)))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reports, err := prunePayloads(ctx, tx, table.Report, table.Report.RawData,
+		table.Report.InputEpochApplicationID.EQ(sqlite.Int64(appID)).
+			AND(sqlite.EXISTS(
+				table.Input.
+					INNER_JOIN(table.Epoch, table.Epoch.ApplicationID.EQ(table.Input.EpochApplicationID).
+						AND(table.Epoch.Index.EQ(table.Input.EpochIndex))).
+					SELECT(table.Input.Index).
+					WHERE(
+						table.Input.EpochApplicationID.EQ(table.Report.InputEpochApplicationID).
+							AND(table.Input.Index.EQ(table.Report.InputIndex)).
+							AND(prunedEpoch),
+					),
+			)),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	inputs, err := prunePayloads(ctx, tx, table.Input, table.Input.RawData,
+		table.Input.EpochApplicationID.EQ(sqlite.Int64(appID)).
+			AND(table.Input.Index.LT(sqlite.Uint64(inputIndex))).
+			AND(sqlite.EXISTS(
+				table.Epoch.
+					SELECT(table.Epoch.Index).
+					WHERE(
+						table.Epoch.ApplicationID.EQ(table.Input.EpochApplicationID).
+							AND(table.Epoch.Index.EQ(table.Input.EpochIndex)).
+							AND(prunedEpoch),
+					),
+			)),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, 0, errors.Join(err, tx.Rollback())
+	}
+	return inputs, reports, nil
+}
+
+func (r *SQLiteRepository) RevertBlocks(
+	ctx context.Context,
+	appID int64,
+	blockNumber uint64,
+) (uint64, error) {
+	block := sqlite.Uint64(blockNumber)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// the epochs with a claim on chain can not be reverted
+	claimedEpoch := table.Epoch.
+		SELECT(table.Epoch.Index, table.Epoch.Status).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(appID)).
+				AND(table.Epoch.LastBlock.GT(block)).
+				AND(table.Epoch.Status.IN(
+					sqlite.String(model.EpochStatus_ClaimSubmitted.String()),
+					sqlite.String(model.EpochStatus_ClaimAccepted.String()),
+				)),
+		).
+		ORDER_BY(table.Epoch.Index.ASC()).
+		LIMIT(1)
+	sqlStr, args := claimedEpoch.Sql()
+	var claimedIndex uint64
+	var claimedStatus model.EpochStatus
+	err = tx.QueryRowContext(ctx, sqlStr, args...).Scan(&claimedIndex, &claimedStatus)
+	if err == nil {
+		err = fmt.Errorf("%w: epoch %d is %s", repository.ErrClaimedEpochReverted, claimedIndex, claimedStatus)
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	// outputs and reports are deleted in cascade
+	delInputs := table.Input.
+		DELETE().
+		WHERE(
+			table.Input.EpochApplicationID.EQ(sqlite.Int64(appID)).
+				AND(table.Input.BlockNumber.GT(block)),
+		)
+	sqlStr, args = delInputs.Sql()
+	res, err := tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	inputs, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	delEpochs := table.Epoch.
+		DELETE().
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(appID)).
+				AND(table.Epoch.FirstBlock.GT(block)),
+		)
+	sqlStr, args = delEpochs.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	reopenEpoch := table.Epoch.
+		UPDATE(
+			table.Epoch.Status,
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.BlockHash,
+		).
+		SET(
+			sqlite.String(model.EpochStatus_Open.String()),
+			sqlite.NULL,
+			sqlite.NULL,
+			sqlite.NULL,
+		).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(appID)).
+				AND(table.Epoch.FirstBlock.LT_EQ(block)).
+				AND(table.Epoch.LastBlock.GT(block)).
+				AND(table.Epoch.Status.NOT_EQ(sqlite.String(model.EpochStatus_Open.String()))),
+		)
+	sqlStr, args = reopenEpoch.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	countStmt := table.Input.
+		SELECT(sqlite.COUNT(sqlite.STAR)).
+		WHERE(table.Input.EpochApplicationID.EQ(sqlite.Int64(appID)))
+	sqlStr, args = countStmt.Sql()
+	var remaining uint64
+	err = tx.QueryRowContext(ctx, sqlStr, args...).Scan(&remaining)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	// MIN with several arguments is the scalar function, like LEAST in Postgres
+	appUpdateStmt := table.Application.
+		UPDATE(
+			table.Application.ProcessedInputs,
+			table.Application.LastInputCheckBlock,
+			table.Application.LastOutputCheckBlock,
+		).
+		SET(
+			sqlite.Func("MIN", table.Application.ProcessedInputs, sqlite.Uint64(remaining)),
+			sqlite.Func("MIN", table.Application.LastInputCheckBlock, block),
+			sqlite.Func("MIN", table.Application.LastOutputCheckBlock, block),
+		).
+		WHERE(table.Application.ID.EQ(sqlite.Int64(appID)))
+	sqlStr, args = appUpdateStmt.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return uint64(inputs), nil
+}
@@ -0,0 +1,333 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+)
+
+var (
+	ErrNoUpdate = fmt.Errorf("update did not take effect")
+)
+
+// Retrieve the claim of each application with the smallest index.
+// The query may return either 0 or 1 entries per application.
+func (r *SQLiteRepository) selectOldestClaimPerApp(
+	ctx context.Context,
+	tx *sql.Tx,
+	epochStatus model.EpochStatus,
+) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+	error,
+) {
+	if (epochStatus != model.EpochStatus_ClaimSubmitted) && (epochStatus != model.EpochStatus_ClaimComputed) {
+		return nil, nil, fmt.Errorf("Invalid epoch status: %v", epochStatus)
+	}
+
+	// DISTINCT ON is a postgres extension, so pick the oldest epoch of each
+	// application with a correlated subquery instead.
+	oldest := table.Epoch.AS("oldest")
+	oldestIndex := oldest.SELECT(sqlite.MINi(oldest.Index)).
+		WHERE(
+			oldest.ApplicationID.EQ(table.Epoch.ApplicationID).
+				AND(oldest.Status.EQ(sqlite.String(epochStatus.String()))),
+		)
+
+	stmt := table.Epoch.SELECT(
+		table.Epoch.ApplicationID,
+		table.Epoch.Index,
+		table.Epoch.FirstBlock,
+		table.Epoch.LastBlock,
+		table.Epoch.ClaimHash,
+		table.Epoch.ClaimTransactionHash,
+		table.Epoch.Status,
+		table.Epoch.VirtualIndex,
+		table.Epoch.CreatedAt,
+		table.Epoch.UpdatedAt,
+
+		table.Application.ID,
+		table.Application.Name,
+		table.Application.IapplicationAddress,
+		table.Application.IconsensusAddress,
+		table.Application.IinputboxAddress,
+		table.Application.TemplateHash,
+		table.Application.TemplateURI,
+		table.Application.EpochLength,
+		table.Application.DataAvailability,
+		table.Application.State,
+		table.Application.Reason,
+		table.Application.ReasonCode,
+		table.Application.IinputboxBlock,
+		table.Application.LastInputCheckBlock,
+		table.Application.LastOutputCheckBlock,
+		table.Application.ProcessedInputs,
+		table.Application.CreatedAt,
+		table.Application.UpdatedAt,
+	).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(
+					table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			table.Epoch.Status.EQ(sqlite.String(epochStatus.String())).
+				AND(table.Epoch.Index.EQ(sqlite.IntExp(oldestIndex))).
+				AND(table.Application.State.EQ(sqlite.String(model.ApplicationState_Enabled.String()))),
+		).
+		ORDER_BY(
+			table.Epoch.ApplicationID,
+		)
+
+	sqlStr, args := stmt.Sql()
+	rows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	epochs := map[int64]*model.Epoch{}
+	applications := map[int64]*model.Application{}
+	for rows.Next() {
+		var application model.Application
+		var epoch model.Epoch
+		err := rows.Scan(
+			&epoch.ApplicationID,
+			&epoch.Index,
+			&epoch.FirstBlock,
+			&epoch.LastBlock,
+			&epoch.ClaimHash,
+			&epoch.ClaimTransactionHash,
+			&epoch.Status,
+			&epoch.VirtualIndex,
+			&epoch.CreatedAt,
+			&epoch.UpdatedAt,
+
+			&application.ID,
+			&application.Name,
+			&application.IApplicationAddress,
+			&application.IConsensusAddress,
+			&application.IInputBoxAddress,
+			&application.TemplateHash,
+			&application.TemplateURI,
+			&application.EpochLength,
+			&application.DataAvailability,
+			&application.State,
+			&application.Reason,
+			&application.ReasonCode,
+			&application.IInputBoxBlock,
+			&application.LastInputCheckBlock,
+			&application.LastOutputCheckBlock,
+			&application.ProcessedInputs,
+			&application.CreatedAt,
+			&application.UpdatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		epochs[application.ID] = &epoch
+		applications[application.ID] = &application
+	}
+	return epochs, applications, rows.Err()
+}
+
+// Retrieve the newest accepted claim of each application
+func (r *SQLiteRepository) selectNewestAcceptedClaimPerApp(
+	ctx context.Context,
+	tx *sql.Tx,
+	includeSubmitted bool,
+) (
+	map[int64]*model.Epoch,
+	error,
+) {
+	// DISTINCT ON is a postgres extension, so pick the newest epoch of each
+	// application with a correlated subquery instead.
+	newest := table.Epoch.AS("newest")
+	expr := newest.Status.EQ(sqlite.String(model.EpochStatus_ClaimAccepted.String()))
+	if includeSubmitted {
+		expr = expr.OR(newest.Status.EQ(sqlite.String(model.EpochStatus_ClaimSubmitted.String())))
+	}
+	newestIndex := newest.SELECT(sqlite.MAXi(newest.Index)).
+		WHERE(newest.ApplicationID.EQ(table.Epoch.ApplicationID).AND(expr))
+
+	stmt := table.Epoch.SELECT(
+		table.Epoch.ApplicationID,
+		table.Epoch.Index,
+		table.Epoch.FirstBlock,
+		table.Epoch.LastBlock,
+		table.Epoch.ClaimHash,
+		table.Epoch.ClaimTransactionHash,
+		table.Epoch.Status,
+		table.Epoch.VirtualIndex,
+		table.Epoch.CreatedAt,
+		table.Epoch.UpdatedAt,
+	).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(
+					table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			table.Epoch.Index.EQ(sqlite.IntExp(newestIndex)).
+				AND(table.Application.State.EQ(sqlite.String(model.ApplicationState_Enabled.String()))),
+		).
+		ORDER_BY(
+			table.Epoch.ApplicationID,
+		)
+
+	sqlStr, args := stmt.Sql()
+	rows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	epochs := map[int64]*model.Epoch{}
+	for rows.Next() {
+		var epoch model.Epoch
+		err := rows.Scan(
+			&epoch.ApplicationID,
+			&epoch.Index,
+			&epoch.FirstBlock,
+			&epoch.LastBlock,
+			&epoch.ClaimHash,
+			&epoch.ClaimTransactionHash,
+			&epoch.Status,
+			&epoch.VirtualIndex,
+			&epoch.CreatedAt,
+			&epoch.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		epochs[epoch.ApplicationID] = &epoch
+	}
+	return epochs, rows.Err()
+}
+
+func (r *SQLiteRepository) SelectSubmittedClaimPairsPerApp(ctx context.Context) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+	error,
+) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	computed, applications, err := r.selectOldestClaimPerApp(ctx, tx, model.EpochStatus_ClaimComputed)
+	if err != nil {
+		return nil, nil, nil, errors.Join(err, tx.Rollback())
+	}
+
+	acceptedOrSubmitted, err := r.selectNewestAcceptedClaimPerApp(ctx, tx, true)
+	if err != nil {
+		return nil, nil, nil, errors.Join(err, tx.Rollback())
+	}
+
+	return acceptedOrSubmitted, computed, applications, tx.Commit()
+}
+
+func (r *SQLiteRepository) SelectAcceptedClaimPairsPerApp(ctx context.Context) (
+	map[int64]*model.Epoch,
+	map[int64]*model.Epoch,
+	map[int64]*model.Application,
+	error,
+) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	submitted, applications, err := r.selectOldestClaimPerApp(ctx, tx, model.EpochStatus_ClaimSubmitted)
+	if err != nil {
+		return nil, nil, nil, errors.Join(err, tx.Rollback())
+	}
+
+	accepted, err := r.selectNewestAcceptedClaimPerApp(ctx, tx, false)
+	if err != nil {
+		return nil, nil, nil, errors.Join(err, tx.Rollback())
+	}
+
+	return accepted, submitted, applications, tx.Commit()
+}
+
+func (r *SQLiteRepository) UpdateEpochWithSubmittedClaim(
+	ctx context.Context,
+	application_id int64,
+	index uint64,
+	transaction_hash common.Hash,
+) error {
+	updStmt := table.Epoch.
+		UPDATE(
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.Status,
+		).
+		SET(
+			transaction_hash,
+			sqlite.String(model.EpochStatus_ClaimSubmitted.String()),
+		).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(application_id)).
+				AND(table.Epoch.Index.EQ(sqlite.Uint64(index))).
+				AND(table.Epoch.Status.EQ(sqlite.String(model.EpochStatus_ClaimComputed.String()))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNoUpdate
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) UpdateEpochWithAcceptedClaim(
+	ctx context.Context,
+	application_id int64,
+	index uint64,
+) error {
+	updStmt := table.Epoch.
+		UPDATE(
+			table.Epoch.Status,
+		).
+		SET(
+			sqlite.String(model.EpochStatus_ClaimAccepted.String()),
+		).
+		WHERE(
+			table.Epoch.ApplicationID.EQ(sqlite.Int64(application_id)).
+				AND(table.Epoch.Index.EQ(sqlite.Uint64(index))).
+				AND(table.Epoch.Status.EQ(sqlite.String(model.EpochStatus_ClaimSubmitted.String()))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNoUpdate
+	}
+	return nil
+}
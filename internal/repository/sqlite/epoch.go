@@ -0,0 +1,574 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+func (r *SQLiteRepository) CreateEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	e *model.Epoch,
+) error {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return err
+	}
+
+	selectQuery := sqlite.SELECT(
+		table.Application.ID,
+		sqlite.Uint64(e.Index),
+		sqlite.Uint64(e.FirstBlock),
+		sqlite.Uint64(e.LastBlock),
+		nullableHash(e.ClaimHash),
+		nullableHash(e.ClaimTransactionHash),
+		sqlite.String(e.Status.String()),
+		sqlite.Uint64(e.VirtualIndex),
+	).FROM(
+		table.Application,
+	).WHERE(
+		whereClause,
+	)
+
+	insertStmt := table.Epoch.INSERT(
+		table.Epoch.ApplicationID,
+		table.Epoch.Index,
+		table.Epoch.FirstBlock,
+		table.Epoch.LastBlock,
+		table.Epoch.ClaimHash,
+		table.Epoch.ClaimTransactionHash,
+		table.Epoch.Status,
+		table.Epoch.VirtualIndex,
+	).QUERY(
+		selectQuery,
+	)
+
+	sqlStr, args := insertStmt.Sql()
+	_, err = r.db.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func getEpochNextVirtualIndex(
+	ctx context.Context,
+	tx *sql.Tx,
+	nameOrAddress string,
+) (uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	query := table.Epoch.SELECT(
+		sqlite.COALESCE(
+			sqlite.MAXi(table.Epoch.VirtualIndex).ADD(sqlite.Int(1)),
+			sqlite.Int(0),
+		),
+	).FROM(
+		table.Epoch.INNER_JOIN(table.Application, table.Epoch.ApplicationID.EQ(table.Application.ID)),
+	).WHERE(
+		whereClause,
+	)
+
+	queryStr, args := query.Sql()
+	var currentIndex uint64
+	err = tx.QueryRowContext(ctx, queryStr, args...).Scan(&currentIndex)
+	if err != nil {
+		err = fmt.Errorf("failed to get the next epoch virtual index: %w", err)
+		return 0, errors.Join(err, tx.Rollback())
+	}
+	return currentIndex, nil
+}
+
+func orderEpochs(epochInputsMap map[*model.Epoch][]*model.Input) []*model.Epoch {
+	epochs := make([]*model.Epoch, 0, len(epochInputsMap))
+	for e := range epochInputsMap {
+		epochs = append(epochs, e)
+	}
+
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i].FirstBlock < epochs[j].FirstBlock
+	})
+
+	return epochs
+}
+
+func (r *SQLiteRepository) CreateEpochsAndInputs(
+	ctx context.Context,
+	nameOrAddress string,
+	epochInputsMap map[*model.Epoch][]*model.Input,
+	blockNumber uint64,
+) error {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return err
+	}
+
+	epochInsertStmt := table.Epoch.INSERT(
+		table.Epoch.ApplicationID,
+		table.Epoch.Index,
+		table.Epoch.FirstBlock,
+		table.Epoch.LastBlock,
+		table.Epoch.Status,
+		table.Epoch.VirtualIndex,
+		table.Epoch.BlockHash,
+	)
+
+	inputInsertStmt := table.Input.
+		INSERT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.TransactionReference,
+			table.Input.BlockHash,
+			table.Input.Sender,
+		)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	epochs := orderEpochs(epochInputsMap)
+	for _, epoch := range epochs {
+		inputs := epochInputsMap[epoch]
+
+		nextVirtualIndex, err := getEpochNextVirtualIndex(ctx, tx, nameOrAddress)
+		if err != nil {
+			return err
+		}
+
+		epochSelectQuery := table.Application.SELECT(
+			table.Application.ID,
+			sqlite.Uint64(epoch.Index),
+			sqlite.Uint64(epoch.FirstBlock),
+			sqlite.Uint64(epoch.LastBlock),
+			sqlite.String(epoch.Status.String()),
+			sqlite.Uint64(nextVirtualIndex),
+			nullableHash(epoch.BlockHash),
+		).WHERE(
+			whereClause,
+		)
+
+		sqlStr, args := epochInsertStmt.QUERY(epochSelectQuery).
+			ON_CONFLICT(table.Epoch.ApplicationID, table.Epoch.Index).
+			DO_UPDATE(sqlite.SET(
+				table.Epoch.Status.SET(sqlite.String(epoch.Status.String())),
+				table.Epoch.BlockHash.SET(table.Epoch.EXCLUDED.BlockHash),
+			)).Sql() // FIXME on conflict
+		_, err = tx.ExecContext(ctx, sqlStr, args...)
+
+		if err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+
+		for _, input := range inputs {
+			inputSelectQuery := table.Application.SELECT(
+				table.Application.ID,
+				sqlite.Uint64(epoch.Index),
+				sqlite.Uint64(input.Index),
+				sqlite.Uint64(input.BlockNumber),
+				blob(input.RawData),
+				sqlite.String(input.Status.String()),
+				blob(input.TransactionReference.Bytes()),
+				nullableHash(input.BlockHash),
+				blob(repository.InputSender(input.RawData)),
+			).WHERE(
+				whereClause,
+			)
+
+			sqlStr, args := inputInsertStmt.QUERY(inputSelectQuery).Sql()
+			_, err := tx.ExecContext(ctx, sqlStr, args...)
+			if err != nil {
+				return errors.Join(err, tx.Rollback())
+			}
+		}
+	}
+
+	// Update last processed block
+	appUpdateStmt := table.Application.
+		UPDATE(
+			table.Application.LastInputCheckBlock,
+		).
+		SET(
+			sqlite.Uint64(blockNumber),
+		).
+		WHERE(whereClause)
+
+	sqlStr, args := appUpdateStmt.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	index uint64,
+) (*model.Epoch, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := table.Epoch.
+		SELECT(
+			table.Epoch.ApplicationID,
+			table.Epoch.Index,
+			table.Epoch.FirstBlock,
+			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.Status,
+			table.Epoch.VirtualIndex,
+			table.Epoch.CreatedAt,
+			table.Epoch.UpdatedAt,
+		).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Epoch.Index.EQ(sqlite.Uint64(index))),
+		)
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var ep model.Epoch
+	err = row.Scan(
+		&ep.ApplicationID,
+		&ep.Index,
+		&ep.FirstBlock,
+		&ep.LastBlock,
+		&ep.BlockHash,
+		&ep.ClaimHash,
+		&ep.ClaimTransactionHash,
+		&ep.Status,
+		&ep.VirtualIndex,
+		&ep.CreatedAt,
+		&ep.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+func (r *SQLiteRepository) GetLastAcceptedEpochIndex(
+	ctx context.Context,
+	nameOrAddress string,
+) (uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := table.Epoch.
+		SELECT(
+			table.Epoch.Index,
+		).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Epoch.Status.EQ(sqlite.String(model.EpochStatus_ClaimAccepted.String()))),
+		).
+		ORDER_BY(table.Epoch.Index.DESC()).
+		LIMIT(1)
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var index uint64
+	err = row.Scan(
+		&index,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+func (r *SQLiteRepository) GetEpochByVirtualIndex(
+	ctx context.Context,
+	nameOrAddress string,
+	index uint64,
+) (*model.Epoch, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := table.Epoch.
+		SELECT(
+			table.Epoch.ApplicationID,
+			table.Epoch.Index,
+			table.Epoch.FirstBlock,
+			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.Status,
+			table.Epoch.VirtualIndex,
+			table.Epoch.CreatedAt,
+			table.Epoch.UpdatedAt,
+		).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Epoch.VirtualIndex.EQ(sqlite.Uint64(index))),
+		)
+
+	sqlStr, args := stmt.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var ep model.Epoch
+	err = row.Scan(
+		&ep.ApplicationID,
+		&ep.Index,
+		&ep.FirstBlock,
+		&ep.LastBlock,
+		&ep.BlockHash,
+		&ep.ClaimHash,
+		&ep.ClaimTransactionHash,
+		&ep.Status,
+		&ep.VirtualIndex,
+		&ep.CreatedAt,
+		&ep.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+func (r *SQLiteRepository) UpdateEpoch(
+	ctx context.Context,
+	nameOrAddress string,
+	e *model.Epoch,
+) error {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return err
+	}
+
+	updStmt := table.Epoch.
+		UPDATE(
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.Status,
+		).
+		SET(
+			e.ClaimHash,
+			e.ClaimTransactionHash,
+			e.Status,
+		).
+		FROM(
+			table.Application,
+		).
+		WHERE(
+			whereClause.
+				AND(table.Epoch.ApplicationID.EQ(table.Application.ID)).
+				AND(table.Epoch.Index.EQ(sqlite.Uint64(e.Index))),
+		)
+
+	sqlStr, args := updStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) UpdateEpochsInputsProcessed(
+	ctx context.Context,
+	nameOrAddress string,
+) (int64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	subSelect := table.Input.SELECT(sqlite.Raw("1")).
+		WHERE(
+			table.Input.EpochApplicationID.EQ(table.Epoch.ApplicationID).
+				AND(table.Input.EpochIndex.EQ(table.Epoch.Index)).
+				AND(table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_None.String()))),
+		)
+
+	notExistsClause := sqlite.NOT(
+		sqlite.EXISTS(subSelect),
+	)
+
+	updateStmt := table.Epoch.UPDATE(table.Epoch.Status).
+		SET(sqlite.String(model.EpochStatus_InputsProcessed.String())).
+		FROM(table.Application).
+		WHERE(
+			table.Epoch.Status.EQ(sqlite.String(model.EpochStatus_Closed.String())).
+				AND(table.Epoch.ApplicationID.EQ(table.Application.ID)).
+				AND(whereClause).
+				AND(notExistsClause),
+		)
+
+	sqlStr, args := updateStmt.Sql()
+	res, err := r.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *SQLiteRepository) ListEpochs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.EpochFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Epoch, uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sel := table.Epoch.
+		SELECT(
+			table.Epoch.ApplicationID,
+			table.Epoch.Index,
+			table.Epoch.FirstBlock,
+			table.Epoch.LastBlock,
+			table.Epoch.BlockHash,
+			table.Epoch.ClaimHash,
+			table.Epoch.ClaimTransactionHash,
+			table.Epoch.Status,
+			table.Epoch.VirtualIndex,
+			table.Epoch.CreatedAt,
+			table.Epoch.UpdatedAt,
+			sqlite.COUNT(sqlite.STAR).OVER().AS("total_count"),
+		).
+		FROM(
+			table.Epoch.
+				INNER_JOIN(table.Application,
+					table.Epoch.ApplicationID.EQ(table.Application.ID),
+				),
+		)
+
+	conditions := []sqlite.BoolExpression{whereClause}
+	if f.Status != nil {
+		conditions = append(conditions, table.Epoch.Status.EQ(sqlite.String(f.Status.String())))
+	}
+
+	if f.BeforeBlock != nil {
+		conditions = append(conditions, table.Epoch.LastBlock.LT(sqlite.Uint64(*f.BeforeBlock)))
+	}
+
+	if cursor := getCursorClause(table.Epoch.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
+	sel = sel.WHERE(sqlite.AND(conditions...))
+
+	if descending {
+		sel = sel.ORDER_BY(table.Epoch.Index.DESC())
+	} else {
+		sel = sel.ORDER_BY(table.Epoch.Index.ASC())
+	}
+
+	// pagination
+	if p.Limit > 0 {
+		sel = sel.LIMIT(int64(p.Limit))
+	}
+	if p.Offset > 0 {
+		sel = sel.OFFSET(int64(p.Offset))
+	}
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var epochs []*model.Epoch
+	var total uint64
+	for rows.Next() {
+		var ep model.Epoch
+		err := rows.Scan(
+			&ep.ApplicationID,
+			&ep.Index,
+			&ep.FirstBlock,
+			&ep.LastBlock,
+			&ep.BlockHash,
+			&ep.ClaimHash,
+			&ep.ClaimTransactionHash,
+			&ep.Status,
+			&ep.VirtualIndex,
+			&ep.CreatedAt,
+			&ep.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		epochs = append(epochs, &ep)
+	}
+	return epochs, total, nil
+}
@@ -0,0 +1,456 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+func (r *SQLiteRepository) GetInput(
+	ctx context.Context,
+	nameOrAddress string,
+	inputIndex uint64,
+) (*model.Input, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.Index.EQ(sqlite.Uint64(inputIndex))),
+		)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var inp model.Input
+	err = row.Scan(
+		&inp.EpochApplicationID,
+		&inp.EpochIndex,
+		&inp.Index,
+		&inp.BlockNumber,
+		&inp.BlockHash,
+		&inp.RawData,
+		&inp.Status,
+		&inp.MachineHash,
+		&inp.OutputsHash,
+		&inp.TransactionReference,
+		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
+		&inp.CreatedAt,
+		&inp.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inp, nil
+}
+
+func (r *SQLiteRepository) GetInputByTxReference(
+	ctx context.Context,
+	nameOrAddress string,
+	ref *common.Hash,
+) (*model.Input, error) {
+
+	if ref == nil {
+		return nil, fmt.Errorf("tx reference is nil")
+	}
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.TransactionReference.EQ(blob(ref.Bytes()))),
+		)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var inp model.Input
+	err = row.Scan(
+		&inp.EpochApplicationID,
+		&inp.EpochIndex,
+		&inp.Index,
+		&inp.BlockNumber,
+		&inp.BlockHash,
+		&inp.RawData,
+		&inp.Status,
+		&inp.MachineHash,
+		&inp.OutputsHash,
+		&inp.TransactionReference,
+		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
+		&inp.CreatedAt,
+		&inp.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inp, nil
+}
+
+func (r *SQLiteRepository) GetLastInput(
+	ctx context.Context,
+	nameOrAddress string,
+	epochIndex uint64,
+) (*model.Input, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.EpochIndex.EQ(sqlite.Uint64(epochIndex))),
+		).
+		ORDER_BY(table.Input.Index.DESC()).
+		LIMIT(1)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var inp model.Input
+	err = row.Scan(
+		&inp.EpochApplicationID,
+		&inp.EpochIndex,
+		&inp.Index,
+		&inp.BlockNumber,
+		&inp.BlockHash,
+		&inp.RawData,
+		&inp.Status,
+		&inp.MachineHash,
+		&inp.OutputsHash,
+		&inp.TransactionReference,
+		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
+		&inp.CreatedAt,
+		&inp.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inp, nil
+}
+
+func (r *SQLiteRepository) GetLastProcessedInput(
+	ctx context.Context,
+	nameOrAddress string,
+) (*model.Input, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Input.Status.NOT_EQ(sqlite.String(model.InputCompletionStatus_None.String()))),
+		).
+		ORDER_BY(table.Input.Index.DESC()).
+		LIMIT(1)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var inp model.Input
+	err = row.Scan(
+		&inp.EpochApplicationID,
+		&inp.EpochIndex,
+		&inp.Index,
+		&inp.BlockNumber,
+		&inp.BlockHash,
+		&inp.RawData,
+		&inp.Status,
+		&inp.MachineHash,
+		&inp.OutputsHash,
+		&inp.TransactionReference,
+		&inp.SnapshotURI,
+		&inp.Cycles,
+		&inp.ExecutionTime,
+		&inp.OutputsCount,
+		&inp.OutputsSize,
+		&inp.ReportsCount,
+		&inp.ReportsSize,
+		&inp.CreatedAt,
+		&inp.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inp, nil
+}
+
+func (r *SQLiteRepository) ListInputs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.InputFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Input, uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sel := table.Input.
+		SELECT(
+			table.Input.EpochApplicationID,
+			table.Input.EpochIndex,
+			table.Input.Index,
+			table.Input.BlockNumber,
+			table.Input.BlockHash,
+			table.Input.RawData,
+			table.Input.Status,
+			table.Input.MachineHash,
+			table.Input.OutputsHash,
+			table.Input.TransactionReference,
+			table.Input.SnapshotURI,
+			table.Input.Cycles,
+			table.Input.ExecutionTime,
+			table.Input.OutputsCount,
+			table.Input.OutputsSize,
+			table.Input.ReportsCount,
+			table.Input.ReportsSize,
+			table.Input.CreatedAt,
+			table.Input.UpdatedAt,
+			sqlite.COUNT(sqlite.STAR).OVER().AS("total_count"),
+		).
+		FROM(
+			table.Input.
+				INNER_JOIN(table.Application,
+					table.Input.EpochApplicationID.EQ(table.Application.ID),
+				),
+		)
+
+	conditions := []sqlite.BoolExpression{whereClause}
+	if f.EpochIndex != nil {
+		conditions = append(conditions, table.Input.EpochIndex.EQ(sqlite.Uint64(*f.EpochIndex)))
+	}
+
+	if f.Status != nil {
+		conditions = append(conditions, table.Input.Status.EQ(sqlite.String(f.Status.String())))
+	}
+
+	if f.NotStatus != nil {
+		conditions = append(conditions, table.Input.Status.NOT_EQ(sqlite.String(f.NotStatus.String())))
+	}
+
+	if f.Sender != nil {
+		conditions = append(conditions, table.Input.Sender.EQ(blob(f.Sender.Bytes())))
+	}
+
+	if cursor := getCursorClause(table.Input.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
+	sel = sel.WHERE(sqlite.AND(conditions...))
+
+	if descending {
+		sel = sel.ORDER_BY(table.Input.Index.DESC())
+	} else {
+		sel = sel.ORDER_BY(table.Input.Index.ASC())
+	}
+
+	if p.Limit > 0 {
+		sel = sel.LIMIT(int64(p.Limit))
+	}
+	if p.Offset > 0 {
+		sel = sel.OFFSET(int64(p.Offset))
+	}
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var inputs []*model.Input
+	var total uint64
+	for rows.Next() {
+		var in model.Input
+		err := rows.Scan(
+			&in.EpochApplicationID,
+			&in.EpochIndex,
+			&in.Index,
+			&in.BlockNumber,
+			&in.BlockHash,
+			&in.RawData,
+			&in.Status,
+			&in.MachineHash,
+			&in.OutputsHash,
+			&in.TransactionReference,
+			&in.SnapshotURI,
+			&in.Cycles,
+			&in.ExecutionTime,
+			&in.OutputsCount,
+			&in.OutputsSize,
+			&in.ReportsCount,
+			&in.ReportsSize,
+			&in.CreatedAt,
+			&in.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		inputs = append(inputs, &in)
+	}
+	return inputs, total, nil
+}
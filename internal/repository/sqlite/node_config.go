@@ -0,0 +1,67 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+func (r *SQLiteRepository) SaveNodeConfigRaw(ctx context.Context, key string, rawJSON []byte) error {
+
+	insertStmt := table.NodeConfig.
+		INSERT(
+			table.NodeConfig.Key,
+			table.NodeConfig.Value,
+		).
+		VALUES(
+			key,
+			string(rawJSON),
+		).
+		ON_CONFLICT(table.NodeConfig.Key).
+		DO_UPDATE(sqlite.SET(table.NodeConfig.Value.SET(sqlite.String(string(rawJSON)))))
+
+	sqlStr, args := insertStmt.Sql()
+	_, err := r.db.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func (r *SQLiteRepository) LoadNodeConfigRaw(ctx context.Context, key string) ([]byte, time.Time, time.Time, error) {
+	sel := table.NodeConfig.
+		SELECT(
+			table.NodeConfig.Value,
+			table.NodeConfig.CreatedAt,
+			table.NodeConfig.UpdatedAt,
+		).
+		WHERE(table.NodeConfig.Key.EQ(sqlite.String(key))).
+		LIMIT(1)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var (
+		value     []byte
+		createdAt time.Time
+		updatedAt time.Time
+	)
+	err := row.Scan(
+		&value,
+		&createdAt,
+		&updatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, time.Time{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	return value, createdAt, updatedAt, nil
+}
@@ -0,0 +1,350 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+)
+
+func (r *SQLiteRepository) GetOutput(
+	ctx context.Context,
+	nameOrAddress string,
+	outputIndex uint64,
+) (*model.Output, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Output.
+		SELECT(
+			table.Output.InputEpochApplicationID,
+			table.Output.InputIndex,
+			table.Output.Index,
+			table.Output.RawData,
+			table.Output.Hash,
+			table.Output.OutputHashesSiblings,
+			table.Output.ExecutionTransactionHash,
+			table.Output.CreatedAt,
+			table.Output.UpdatedAt,
+			table.Input.EpochIndex,
+		).
+		FROM(
+			table.Output.INNER_JOIN(
+				table.Application,
+				table.Output.InputEpochApplicationID.EQ(table.Application.ID),
+			).INNER_JOIN(
+				table.Input,
+				table.Output.InputIndex.EQ(table.Input.Index).
+					AND(table.Output.InputEpochApplicationID.EQ(table.Input.EpochApplicationID)),
+			),
+		).
+		WHERE(
+			whereClause.
+				AND(table.Output.Index.EQ(sqlite.Uint64(outputIndex))),
+		)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var o model.Output
+	err = row.Scan(
+		&o.InputEpochApplicationID,
+		&o.InputIndex,
+		&o.Index,
+		&o.RawData,
+		&o.Hash,
+		(*hashList)(&o.OutputHashesSiblings),
+		&o.ExecutionTransactionHash,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+		&o.EpochIndex,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *SQLiteRepository) UpdateOutputsExecution(
+	ctx context.Context,
+	nameOrAddress string,
+	outputs []*model.Output,
+	lastOutputCheckBlock uint64,
+) error {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range outputs {
+		if o.ExecutionTransactionHash == nil {
+			return errors.Join(
+				fmt.Errorf("output ExecutionTransactionHash must be not nil when updating app %s output %d", nameOrAddress, o.Index),
+				tx.Rollback(),
+			)
+		}
+		updStmt := table.Output.
+			UPDATE(
+				table.Output.ExecutionTransactionHash,
+			).
+			SET(
+				blob(o.ExecutionTransactionHash.Bytes()),
+			).
+			FROM(
+				table.Application,
+			).
+			WHERE(
+				whereClause.
+					AND(table.Output.InputEpochApplicationID.EQ(table.Application.ID)).
+					AND(table.Output.Index.EQ(sqlite.Uint64(o.Index))),
+			)
+
+		sqlStr, args := updStmt.Sql()
+		res, err := tx.ExecContext(ctx, sqlStr, args...)
+		if err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return errors.Join(err, tx.Rollback())
+		} else if n != 1 {
+			return errors.Join(
+				fmt.Errorf("no row affected when updating app %s epoch %d", nameOrAddress, o.Index),
+				tx.Rollback(),
+			)
+		}
+	}
+
+	// Update last claim check block
+	appUpdateStmt := table.Application.
+		UPDATE(
+			table.Application.LastOutputCheckBlock,
+		).
+		SET(
+			sqlite.Uint64(lastOutputCheckBlock),
+		).
+		WHERE(whereClause)
+
+	sqlStr, args := appUpdateStmt.Sql()
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) ListOutputs(
+	ctx context.Context,
+	nameOrAddress string,
+	f repository.OutputFilter,
+	p repository.Pagination,
+	descending bool,
+) ([]*model.Output, uint64, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sel := table.Output.
+		SELECT(
+			table.Output.InputEpochApplicationID,
+			table.Output.InputIndex,
+			table.Output.Index,
+			table.Output.RawData,
+			table.Output.Hash,
+			table.Output.OutputHashesSiblings,
+			table.Output.ExecutionTransactionHash,
+			table.Output.CreatedAt,
+			table.Output.UpdatedAt,
+			table.Input.EpochIndex,
+			sqlite.COUNT(sqlite.STAR).OVER().AS("total_count"),
+		).
+		FROM(
+			table.Output.INNER_JOIN(
+				table.Application,
+				table.Output.InputEpochApplicationID.EQ(table.Application.ID),
+			).INNER_JOIN(
+				table.Input,
+				table.Output.InputIndex.EQ(table.Input.Index).
+					AND(table.Output.InputEpochApplicationID.EQ(table.Input.EpochApplicationID)),
+			),
+		)
+
+	conditions := []sqlite.BoolExpression{whereClause}
+	if f.BlockRange != nil {
+		conditions = append(conditions, table.Input.BlockNumber.BETWEEN(
+			sqlite.Uint64(f.BlockRange.Start),
+			sqlite.Uint64(f.BlockRange.End),
+		))
+		conditions = append(conditions, table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String())))
+	}
+
+	if f.EpochIndex != nil {
+		conditions = append(conditions, table.Input.EpochIndex.EQ(sqlite.Uint64(*f.EpochIndex)))
+		conditions = append(conditions, table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String())))
+	}
+
+	if f.InputIndex != nil {
+		conditions = append(conditions, table.Output.InputIndex.EQ(sqlite.Uint64(*f.InputIndex)))
+	}
+
+	if f.OutputType != nil {
+		conditions = append(conditions,
+			sqlite.SUBSTR(table.Output.RawData, sqlite.Int(1), sqlite.Int(4)).EQ(blob(*f.OutputType)),
+		)
+	}
+
+	if f.VoucherAddress != nil {
+		conditions = append(conditions,
+			sqlite.SUBSTR(table.Output.RawData, sqlite.Int(17), sqlite.Int(20)).EQ(blob(f.VoucherAddress.Bytes())),
+		)
+	}
+
+	if cursor := getCursorClause(table.Output.Index, p, descending); cursor != nil {
+		conditions = append(conditions, cursor)
+	}
+
+	sel = sel.WHERE(sqlite.AND(conditions...))
+
+	if descending {
+		sel = sel.ORDER_BY(table.Output.Index.DESC())
+	} else {
+		sel = sel.ORDER_BY(table.Output.Index.ASC())
+	}
+
+	if p.Limit > 0 {
+		sel = sel.LIMIT(int64(p.Limit))
+	}
+	if p.Offset > 0 {
+		sel = sel.OFFSET(int64(p.Offset))
+	}
+
+	sqlStr, args := sel.Sql()
+	rows, err := r.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var outputs []*model.Output
+	var total uint64
+	for rows.Next() {
+		var out model.Output
+		err := rows.Scan(
+			&out.InputEpochApplicationID,
+			&out.InputIndex,
+			&out.Index,
+			&out.RawData,
+			&out.Hash,
+			(*hashList)(&out.OutputHashesSiblings),
+			&out.ExecutionTransactionHash,
+			&out.CreatedAt,
+			&out.UpdatedAt,
+			&out.EpochIndex,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		outputs = append(outputs, &out)
+	}
+	return outputs, total, nil
+}
+
+func (r *SQLiteRepository) GetLastOutputBeforeBlock(
+	ctx context.Context,
+	nameOrAddress string,
+	block uint64,
+) (*model.Output, error) {
+
+	whereClause, err := getWhereClauseFromNameOrAddress(nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := table.Output.
+		SELECT(
+			table.Output.InputEpochApplicationID,
+			table.Output.InputIndex,
+			table.Output.Index,
+			table.Output.RawData,
+			table.Output.Hash,
+			table.Output.OutputHashesSiblings,
+			table.Output.ExecutionTransactionHash,
+			table.Output.CreatedAt,
+			table.Output.UpdatedAt,
+			table.Input.EpochIndex,
+		).
+		FROM(
+			table.Output.INNER_JOIN(
+				table.Application,
+				table.Output.InputEpochApplicationID.EQ(table.Application.ID),
+			).INNER_JOIN(
+				table.Input,
+				table.Output.InputEpochApplicationID.EQ(table.Input.EpochApplicationID).AND(
+					table.Output.InputIndex.EQ(table.Input.Index),
+				),
+			),
+		).
+		WHERE(
+			sqlite.AND(
+				whereClause,
+				table.Input.BlockNumber.LT(sqlite.Uint64(block)),
+				table.Input.Status.EQ(sqlite.String(model.InputCompletionStatus_Accepted.String())),
+			),
+		).
+		ORDER_BY(table.Output.Index.DESC()).
+		LIMIT(1)
+
+	sqlStr, args := sel.Sql()
+	row := r.db.QueryRowContext(ctx, sqlStr, args...)
+
+	var out model.Output
+	err = row.Scan(
+		&out.InputEpochApplicationID,
+		&out.InputIndex,
+		&out.Index,
+		&out.RawData,
+		&out.Hash,
+		(*hashList)(&out.OutputHashesSiblings),
+		&out.ExecutionTransactionHash,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+		&out.EpochIndex,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,69 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/schema"
+)
+
+// SQLiteRepository is the concrete type that implements the repository.Repository interface
+// on top of a single SQLite database file.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func (r *SQLiteRepository) Close() {
+	if r.db != nil {
+		r.db.Close()
+	}
+}
+
+func (r *SQLiteRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func validateSchema(conn string) error {
+
+	s, err := schema.New(conn)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	_, err = s.ValidateVersion()
+	return err
+}
+
+// NewSQLiteRepository opens the database referenced by a "sqlite://path/to/file.db" connection
+// string. The schema must already be at the expected version.
+func NewSQLiteRepository(ctx context.Context, conn string) (repository.Repository, error) {
+
+	dsn, err := schema.DataSourceName(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to ping SQLite database: %w", err), db.Close())
+	}
+
+	if err := validateSchema(conn); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to validate SQLite schema version: %w", err), db.Close())
+	}
+
+	return &SQLiteRepository{db: db}, nil
+}
@@ -0,0 +1,34 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/schema"
+	"github.com/cartesi/rollups-node/test/tooling/repotest"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	suite.Run(t, &repotest.RepositorySuite{
+		NewRepository: func(t *testing.T) repository.Repository {
+			conn := "sqlite://" + filepath.Join(t.TempDir(), "rollups.db")
+
+			s, err := schema.New(conn)
+			require.Nil(t, err)
+			require.Nil(t, s.Upgrade())
+			s.Close()
+
+			repo, err := NewSQLiteRepository(context.Background(), conn)
+			require.Nil(t, err)
+			return repo
+		},
+	})
+}
@@ -0,0 +1,31 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+DROP TRIGGER IF EXISTS "config_set_updated_at";
+DROP TABLE IF EXISTS "node_config";
+
+DROP TRIGGER IF EXISTS "report_set_updated_at";
+DROP TABLE IF EXISTS "report";
+
+DROP TRIGGER IF EXISTS "output_set_updated_at";
+DROP INDEX IF EXISTS "output_raw_data_address_idx";
+DROP INDEX IF EXISTS "output_raw_data_type_idx";
+DROP TABLE IF EXISTS "output";
+
+DROP TRIGGER IF EXISTS "input_set_updated_at";
+DROP INDEX IF EXISTS "input_sender_idx";
+DROP INDEX IF EXISTS "input_status_idx";
+DROP INDEX IF EXISTS "input_block_number_idx";
+DROP TABLE IF EXISTS "input";
+
+DROP TRIGGER IF EXISTS "epoch_set_updated_at";
+DROP INDEX IF EXISTS "epoch_status_idx";
+DROP INDEX IF EXISTS "epoch_last_block_idx";
+DROP TABLE IF EXISTS "epoch";
+
+DROP TRIGGER IF EXISTS "execution_parameters_set_updated_at";
+DROP TABLE IF EXISTS "execution_parameters";
+
+DROP TRIGGER IF EXISTS "application_set_updated_at";
+DROP INDEX IF EXISTS "application_data_availability_selector_idx";
+DROP TABLE IF EXISTS "application";
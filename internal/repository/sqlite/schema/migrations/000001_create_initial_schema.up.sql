@@ -0,0 +1,194 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000001_create_initial_schema migration.
+-- Domains and enums become CHECK constraints, BYTEA[] becomes a BLOB of
+-- concatenated 32-byte hashes and the uint64 domain is limited to the
+-- positive range of a SQLite INTEGER.
+
+CREATE TABLE "application"
+(
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "name" TEXT UNIQUE NOT NULL CHECK (length("name") BETWEEN 1 AND 4096 AND "name" NOT GLOB '*[^a-z0-9_-]*'),
+    "iapplication_address" BLOB UNIQUE NOT NULL CHECK (length("iapplication_address") = 20),
+    "iconsensus_address" BLOB NOT NULL CHECK (length("iconsensus_address") = 20),
+    "iinputbox_address" BLOB NOT NULL CHECK (length("iinputbox_address") = 20),
+    "iinputbox_block" INTEGER NOT NULL CHECK ("iinputbox_block" >= 0),
+    "template_hash" BLOB NOT NULL CHECK (length("template_hash") = 32),
+    "template_uri" TEXT NOT NULL CHECK (length("template_uri") <= 4096),
+    "epoch_length" INTEGER NOT NULL CHECK ("epoch_length" >= 0),
+    "data_availability" BLOB NOT NULL CHECK (length("data_availability") >= 4),
+    "state" TEXT NOT NULL CHECK ("state" IN ('ENABLED', 'DISABLED', 'INOPERABLE')),
+    "reason" TEXT CHECK (length("reason") <= 4096),
+    "last_input_check_block" INTEGER NOT NULL CHECK ("last_input_check_block" >= 0),
+    "last_output_check_block" INTEGER NOT NULL CHECK ("last_output_check_block" >= 0),
+    "processed_inputs" INTEGER NOT NULL CHECK ("processed_inputs" >= 0),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "reason_required_for_inoperable" CHECK (NOT ("state" = 'INOPERABLE' AND ("reason" IS NULL OR length("reason") = 0)))
+);
+
+CREATE INDEX "application_data_availability_selector_idx" ON "application"(substr("data_availability", 1, 4));
+
+CREATE TRIGGER "application_set_updated_at" AFTER UPDATE ON "application"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "application" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE "id" = NEW."id";
+END;
+
+CREATE TABLE "execution_parameters" (
+    "application_id" INTEGER PRIMARY KEY,
+    "snapshot_policy" TEXT NOT NULL DEFAULT 'NONE' CHECK ("snapshot_policy" IN ('NONE', 'EVERY_INPUT', 'EVERY_EPOCH')),
+    "advance_inc_cycles" INTEGER NOT NULL CHECK ("advance_inc_cycles" > 0) DEFAULT 4194304, -- 1 << 22
+    "advance_max_cycles" INTEGER NOT NULL CHECK ("advance_max_cycles" > 0) DEFAULT 4611686018427387903, -- uint64 max >> 2
+    "inspect_inc_cycles" INTEGER NOT NULL CHECK ("inspect_inc_cycles" > 0) DEFAULT 4194304, -- 1 << 22
+    "inspect_max_cycles" INTEGER NOT NULL CHECK ("inspect_max_cycles" > 0) DEFAULT 4611686018427387903,
+    "advance_inc_deadline" INTEGER NOT NULL CHECK ("advance_inc_deadline" > 0) DEFAULT 10000000000, -- 10s
+    "advance_max_deadline" INTEGER NOT NULL CHECK ("advance_max_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "inspect_inc_deadline" INTEGER NOT NULL CHECK ("inspect_inc_deadline" > 0) DEFAULT 10000000000, --10s
+    "inspect_max_deadline" INTEGER NOT NULL CHECK ("inspect_max_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "load_deadline" INTEGER NOT NULL CHECK ("load_deadline" > 0) DEFAULT 300000000000, -- 300s
+    "store_deadline" INTEGER NOT NULL CHECK ("store_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "fast_deadline" INTEGER NOT NULL CHECK ("fast_deadline" > 0) DEFAULT 5000000000, -- 5s
+    "max_concurrent_inspects" INTEGER NOT NULL CHECK ("max_concurrent_inspects" > 0) DEFAULT 10,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "execution_parameters_application_id_fkey" FOREIGN KEY ("application_id") REFERENCES "application"("id") ON DELETE CASCADE
+);
+
+CREATE TRIGGER "execution_parameters_set_updated_at" AFTER UPDATE ON "execution_parameters"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "execution_parameters" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE "application_id" = NEW."application_id";
+END;
+
+CREATE TABLE "epoch"
+(
+    "application_id" INTEGER NOT NULL,
+    "index" INTEGER NOT NULL CHECK ("index" >= 0),
+    "first_block" INTEGER NOT NULL CHECK ("first_block" >= 0),
+    "last_block" INTEGER NOT NULL CHECK ("last_block" >= 0),
+    "claim_hash" BLOB CHECK (length("claim_hash") = 32),
+    "claim_transaction_hash" BLOB CHECK (length("claim_transaction_hash") = 32),
+    "status" TEXT NOT NULL CHECK ("status" IN ('OPEN', 'CLOSED', 'INPUTS_PROCESSED', 'CLAIM_COMPUTED', 'CLAIM_SUBMITTED', 'CLAIM_ACCEPTED', 'CLAIM_REJECTED')),
+    "virtual_index" INTEGER NOT NULL CHECK ("virtual_index" >= 0),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "epoch_pkey" PRIMARY KEY ("application_id", "index"),
+    CONSTRAINT "epoch_application_id_virtual_index_unique" UNIQUE ("application_id", "virtual_index"),
+    CONSTRAINT "epoch_application_id_fkey" FOREIGN KEY ("application_id") REFERENCES "application"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "epoch_last_block_idx" ON "epoch"("application_id", "last_block");
+CREATE INDEX "epoch_status_idx" ON "epoch"("application_id", "status");
+
+CREATE TRIGGER "epoch_set_updated_at" AFTER UPDATE ON "epoch"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "epoch" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE "application_id" = NEW."application_id" AND "index" = NEW."index";
+END;
+
+CREATE TABLE "input"
+(
+    "epoch_application_id" INTEGER NOT NULL,
+    "epoch_index" INTEGER NOT NULL CHECK ("epoch_index" >= 0),
+    "index" INTEGER NOT NULL CHECK ("index" >= 0),
+    "block_number" INTEGER NOT NULL CHECK ("block_number" >= 0),
+    "raw_data" BLOB NOT NULL,
+    "status" TEXT NOT NULL CHECK ("status" IN (
+        'NONE',
+        'ACCEPTED',
+        'REJECTED',
+        'EXCEPTION',
+        'MACHINE_HALTED',
+        'OUTPUTS_LIMIT_EXCEEDED',
+        'CYCLE_LIMIT_EXCEEDED',
+        'TIME_LIMIT_EXCEEDED',
+        'PAYLOAD_LENGTH_LIMIT_EXCEEDED')),
+    "machine_hash" BLOB CHECK (length("machine_hash") = 32),
+    "outputs_hash" BLOB CHECK (length("outputs_hash") = 32),
+    "transaction_reference" BLOB CHECK (length("transaction_reference") = 32),
+    "snapshot_uri" TEXT CHECK (length("snapshot_uri") <= 4096),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "input_pkey" PRIMARY KEY ("epoch_application_id", "index"),
+    CONSTRAINT "input_application_id_tx_reference_unique" UNIQUE ("epoch_application_id", "transaction_reference"),
+    CONSTRAINT "input_epoch_id_fkey" FOREIGN KEY ("epoch_application_id", "epoch_index") REFERENCES "epoch"("application_id", "index") ON DELETE CASCADE
+);
+
+CREATE INDEX "input_block_number_idx" ON "input"("epoch_application_id", "block_number");
+CREATE INDEX "input_status_idx" ON "input"("epoch_application_id", "status");
+
+CREATE INDEX "input_sender_idx" ON "input" ("epoch_application_id", substr("raw_data", 81, 20));
+
+CREATE TRIGGER "input_set_updated_at" AFTER UPDATE ON "input"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "input" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE "epoch_application_id" = NEW."epoch_application_id" AND "index" = NEW."index";
+END;
+
+CREATE TABLE "output"
+(
+    "input_epoch_application_id" INTEGER NOT NULL,
+    "input_index" INTEGER NOT NULL CHECK ("input_index" >= 0),
+    "index" INTEGER NOT NULL CHECK ("index" >= 0),
+    "raw_data" BLOB NOT NULL,
+    "hash" BLOB CHECK (length("hash") = 32),
+    "output_hashes_siblings" BLOB,
+    "execution_transaction_hash" BLOB CHECK (length("execution_transaction_hash") = 32),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "output_pkey" PRIMARY KEY ("input_epoch_application_id", "index"),
+    CONSTRAINT "output_input_id_fkey" FOREIGN KEY ("input_epoch_application_id",  "input_index") REFERENCES "input"("epoch_application_id", "index") ON DELETE CASCADE,
+    CONSTRAINT "output_hashes_siblings_length_check" CHECK (length("output_hashes_siblings") % 32 = 0)
+);
+
+CREATE INDEX "output_raw_data_type_idx" ON "output" ("input_epoch_application_id", substr("raw_data", 1, 4));
+
+CREATE INDEX "output_raw_data_address_idx" ON "output" ("input_epoch_application_id", substr("raw_data", 17, 20))
+WHERE substr("raw_data", 1, 4) IN (
+    x'10321e8b',  -- DelegateCallVoucher
+    x'237a816f'   -- Voucher
+);
+
+CREATE TRIGGER "output_set_updated_at" AFTER UPDATE ON "output"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "output" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE "input_epoch_application_id" = NEW."input_epoch_application_id" AND "index" = NEW."index";
+END;
+
+CREATE TABLE "report"
+(
+    "input_epoch_application_id" INTEGER NOT NULL,
+    "input_index" INTEGER NOT NULL CHECK ("input_index" >= 0),
+    "index" INTEGER NOT NULL CHECK ("index" >= 0),
+    "raw_data" BLOB NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "report_pkey" PRIMARY KEY ("input_epoch_application_id", "index"),
+    CONSTRAINT "report_input_id_fkey" FOREIGN KEY ("input_epoch_application_id", "input_index") REFERENCES "input"("epoch_application_id", "index") ON DELETE CASCADE
+);
+
+CREATE TRIGGER "report_set_updated_at" AFTER UPDATE ON "report"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "report" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE "input_epoch_application_id" = NEW."input_epoch_application_id" AND "index" = NEW."index";
+END;
+
+CREATE TABLE "node_config"
+(
+    "key" TEXT PRIMARY KEY CHECK (length("key") <= 255),
+    "value" TEXT NOT NULL CHECK (json_valid("value")),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE TRIGGER "config_set_updated_at" AFTER UPDATE ON "node_config"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "node_config" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE "key" = NEW."key";
+END;
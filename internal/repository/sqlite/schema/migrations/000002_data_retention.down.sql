@@ -0,0 +1,4 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+ALTER TABLE "execution_parameters" DROP COLUMN "retained_epochs";
@@ -0,0 +1,7 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000003_data_retention migration.
+
+ALTER TABLE "execution_parameters"
+    ADD COLUMN "retained_epochs" INTEGER NOT NULL CHECK ("retained_epochs" >= 0) DEFAULT 0;
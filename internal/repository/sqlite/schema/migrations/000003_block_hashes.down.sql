@@ -0,0 +1,5 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+ALTER TABLE "epoch" DROP COLUMN "block_hash";
+ALTER TABLE "input" DROP COLUMN "block_hash";
@@ -0,0 +1,7 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000005_block_hashes migration.
+
+ALTER TABLE "input" ADD COLUMN "block_hash" BLOB CHECK (length("block_hash") = 32);
+ALTER TABLE "epoch" ADD COLUMN "block_hash" BLOB CHECK (length("block_hash") = 32);
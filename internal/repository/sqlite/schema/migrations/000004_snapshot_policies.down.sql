@@ -0,0 +1,43 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+CREATE TABLE "execution_parameters_old" (
+    "application_id" INTEGER PRIMARY KEY,
+    "snapshot_policy" TEXT NOT NULL DEFAULT 'NONE' CHECK ("snapshot_policy" IN ('NONE', 'EVERY_INPUT', 'EVERY_EPOCH')),
+    "advance_inc_cycles" INTEGER NOT NULL CHECK ("advance_inc_cycles" > 0) DEFAULT 4194304, -- 1 << 22
+    "advance_max_cycles" INTEGER NOT NULL CHECK ("advance_max_cycles" > 0) DEFAULT 4611686018427387903, -- uint64 max >> 2
+    "inspect_inc_cycles" INTEGER NOT NULL CHECK ("inspect_inc_cycles" > 0) DEFAULT 4194304, -- 1 << 22
+    "inspect_max_cycles" INTEGER NOT NULL CHECK ("inspect_max_cycles" > 0) DEFAULT 4611686018427387903,
+    "advance_inc_deadline" INTEGER NOT NULL CHECK ("advance_inc_deadline" > 0) DEFAULT 10000000000, -- 10s
+    "advance_max_deadline" INTEGER NOT NULL CHECK ("advance_max_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "inspect_inc_deadline" INTEGER NOT NULL CHECK ("inspect_inc_deadline" > 0) DEFAULT 10000000000, --10s
+    "inspect_max_deadline" INTEGER NOT NULL CHECK ("inspect_max_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "load_deadline" INTEGER NOT NULL CHECK ("load_deadline" > 0) DEFAULT 300000000000, -- 300s
+    "store_deadline" INTEGER NOT NULL CHECK ("store_deadline" > 0) DEFAULT 180000000000, -- 180s
+    "fast_deadline" INTEGER NOT NULL CHECK ("fast_deadline" > 0) DEFAULT 5000000000, -- 5s
+    "max_concurrent_inspects" INTEGER NOT NULL CHECK ("max_concurrent_inspects" > 0) DEFAULT 10,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "retained_epochs" INTEGER NOT NULL CHECK ("retained_epochs" >= 0) DEFAULT 0,
+    CONSTRAINT "execution_parameters_application_id_fkey" FOREIGN KEY ("application_id") REFERENCES "application"("id") ON DELETE CASCADE
+);
+
+-- Applications with the newer policies snapshot every input instead.
+INSERT INTO "execution_parameters_old"
+SELECT
+    "application_id",
+    CASE WHEN "snapshot_policy" IN ('NONE', 'EVERY_INPUT', 'EVERY_EPOCH') THEN "snapshot_policy" ELSE 'EVERY_INPUT' END,
+    "advance_inc_cycles", "advance_max_cycles",
+    "inspect_inc_cycles", "inspect_max_cycles", "advance_inc_deadline", "advance_max_deadline",
+    "inspect_inc_deadline", "inspect_max_deadline", "load_deadline", "store_deadline",
+    "fast_deadline", "max_concurrent_inspects", "created_at", "updated_at", "retained_epochs"
+FROM "execution_parameters";
+
+DROP TABLE "execution_parameters";
+ALTER TABLE "execution_parameters_old" RENAME TO "execution_parameters";
+
+CREATE TRIGGER "execution_parameters_set_updated_at" AFTER UPDATE ON "execution_parameters"
+FOR EACH ROW WHEN NEW."updated_at" = OLD."updated_at"
+BEGIN
+    UPDATE "execution_parameters" SET "updated_at" = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE "application_id" = NEW."application_id";
+END;
@@ -0,0 +1,9 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+ALTER TABLE "input" DROP COLUMN "reports_size";
+ALTER TABLE "input" DROP COLUMN "reports_count";
+ALTER TABLE "input" DROP COLUMN "outputs_size";
+ALTER TABLE "input" DROP COLUMN "outputs_count";
+ALTER TABLE "input" DROP COLUMN "execution_time";
+ALTER TABLE "input" DROP COLUMN "cycles";
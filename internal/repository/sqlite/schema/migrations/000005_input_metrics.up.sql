@@ -0,0 +1,11 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000007_input_metrics migration.
+
+ALTER TABLE "input" ADD COLUMN "cycles" INTEGER CHECK ("cycles" >= 0);
+ALTER TABLE "input" ADD COLUMN "execution_time" INTEGER CHECK ("execution_time" >= 0);
+ALTER TABLE "input" ADD COLUMN "outputs_count" INTEGER CHECK ("outputs_count" >= 0);
+ALTER TABLE "input" ADD COLUMN "outputs_size" INTEGER CHECK ("outputs_size" >= 0);
+ALTER TABLE "input" ADD COLUMN "reports_count" INTEGER CHECK ("reports_count" >= 0);
+ALTER TABLE "input" ADD COLUMN "reports_size" INTEGER CHECK ("reports_size" >= 0);
@@ -0,0 +1,17 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000008_application_state_history migration.
+
+CREATE TABLE "application_state_history"
+(
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "application_id" INTEGER NOT NULL,
+    "previous_state" TEXT NOT NULL CHECK ("previous_state" IN ('ENABLED', 'DISABLED', 'INOPERABLE')),
+    "state" TEXT NOT NULL CHECK ("state" IN ('ENABLED', 'DISABLED', 'INOPERABLE')),
+    "reason" TEXT CHECK (length("reason") <= 4096),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT "application_state_history_application_id_fkey" FOREIGN KEY ("application_id") REFERENCES "application"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "application_state_history_application_id_idx" ON "application_state_history"("application_id");
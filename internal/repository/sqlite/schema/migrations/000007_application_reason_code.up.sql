@@ -0,0 +1,14 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000009_application_reason_code migration.
+
+ALTER TABLE "application" ADD COLUMN "reason_code" TEXT CHECK ("reason_code" IN (
+    'MACHINE_SERVER_FAILURE',
+    'MACHINE_FAILURE',
+    'CLAIM_MISMATCH',
+    'CONSENSUS_CHANGED',
+    'INCONSISTENT_STATE',
+    'UNSUPPORTED_DATA_AVAILABILITY',
+    'CLAIMED_EPOCH_REVERTED'
+));
@@ -0,0 +1,15 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+ALTER TABLE "application" ADD COLUMN "reason_code_old" TEXT CHECK ("reason_code_old" IN (
+    'MACHINE_SERVER_FAILURE',
+    'MACHINE_FAILURE',
+    'CLAIM_MISMATCH',
+    'CONSENSUS_CHANGED',
+    'INCONSISTENT_STATE',
+    'UNSUPPORTED_DATA_AVAILABILITY',
+    'CLAIMED_EPOCH_REVERTED'
+));
+UPDATE "application" SET "reason_code_old" = "reason_code" WHERE "reason_code" != 'INVALID_INPUT';
+ALTER TABLE "application" DROP COLUMN "reason_code";
+ALTER TABLE "application" RENAME COLUMN "reason_code_old" TO "reason_code";
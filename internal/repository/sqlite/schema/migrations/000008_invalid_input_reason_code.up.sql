@@ -0,0 +1,20 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000010_invalid_input_reason_code migration.
+-- The CHECK constraint of a column can not be altered, so the column is
+-- replaced by one that accepts the new code.
+
+ALTER TABLE "application" ADD COLUMN "reason_code_new" TEXT CHECK ("reason_code_new" IN (
+    'MACHINE_SERVER_FAILURE',
+    'MACHINE_FAILURE',
+    'CLAIM_MISMATCH',
+    'CONSENSUS_CHANGED',
+    'INCONSISTENT_STATE',
+    'UNSUPPORTED_DATA_AVAILABILITY',
+    'CLAIMED_EPOCH_REVERTED',
+    'INVALID_INPUT'
+));
+UPDATE "application" SET "reason_code_new" = "reason_code";
+ALTER TABLE "application" DROP COLUMN "reason_code";
+ALTER TABLE "application" RENAME COLUMN "reason_code_new" TO "reason_code";
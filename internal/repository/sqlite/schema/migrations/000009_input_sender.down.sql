@@ -0,0 +1,6 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+DROP INDEX IF EXISTS "input_sender_idx";
+ALTER TABLE "input" DROP COLUMN "sender";
+CREATE INDEX "input_sender_idx" ON "input" ("epoch_application_id", substr("raw_data", 81, 20));
@@ -0,0 +1,10 @@
+-- (c) Cartesi and individual authors (see AUTHORS)
+-- SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+-- SQLite mirror of the Postgres 000011_input_sender migration.
+
+ALTER TABLE "input" ADD COLUMN "sender" BLOB;
+UPDATE "input" SET "sender" = substr("raw_data", 81, 20) WHERE length("raw_data") >= 100;
+
+DROP INDEX IF EXISTS "input_sender_idx";
+CREATE INDEX "input_sender_idx" ON "input"("epoch_application_id", "sender");
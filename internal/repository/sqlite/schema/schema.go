@@ -0,0 +1,151 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package schema
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*
+var content embed.FS
+
+const ExpectedVersion uint = 9
+
+const connectionPrefix = "sqlite://"
+
+type Schema struct {
+	migrate *migrate.Migrate
+}
+
+var (
+	ErrMigrationNotCompleted = errors.New("Schema version is dirty")
+	ErrNoValidDatabaseSchema = errors.New("No valid database schema found")
+)
+
+// defaultParams are the go-sqlite3 connection parameters used unless the
+// connection string overrides them. Foreign keys are required for the ON DELETE
+// CASCADE clauses, WAL + busy timeout allow concurrent readers while a service
+// writes, and immediate transactions avoid lock upgrade deadlocks.
+var defaultParams = map[string]string{
+	"_foreign_keys": "1",
+	"_busy_timeout": "10000",
+	"_journal_mode": "WAL",
+	"_txlock":       "immediate",
+}
+
+// IsConnectionString reports whether conn selects the SQLite backend.
+func IsConnectionString(conn string) bool {
+	return strings.HasPrefix(strings.ToLower(conn), connectionPrefix)
+}
+
+// DataSourceName converts a "sqlite://path/to/file.db?param=value" connection
+// string into a go-sqlite3 data source name.
+func DataSourceName(conn string) (string, error) {
+	if !IsConnectionString(conn) {
+		return "", fmt.Errorf("invalid SQLite connection string: %s", conn)
+	}
+	path, rawQuery, _ := strings.Cut(conn[len(connectionPrefix):], "?")
+	if path == "" {
+		return "", fmt.Errorf("missing database file in SQLite connection string: %s", conn)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse SQLite connection parameters: %w", err)
+	}
+	for key, value := range defaultParams {
+		if !query.Has(key) {
+			query.Set(key, value)
+		}
+	}
+	return "file:" + path + "?" + query.Encode(), nil
+}
+
+func New(sqliteEndpoint string) (*Schema, error) {
+	dsn, err := DataSourceName(sqliteEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := iofs.New(content, "migrations")
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		err = fmt.Errorf("could not instantiate sqlite3 migrate driver: %v", err)
+		return nil, errors.Join(err, db.Close())
+	}
+
+	migrate, err := migrate.NewWithInstance("iofs", source, "sqlite3", driver)
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	return &Schema{migrate: migrate}, nil
+}
+
+func (s *Schema) Version() (uint, bool, error) {
+	version, dirty, err := s.migrate.Version()
+	if err != nil && errors.Is(err, migrate.ErrNilVersion) {
+		return version, dirty, ErrNoValidDatabaseSchema
+	}
+	return version, dirty, err
+}
+
+func (s *Schema) Upgrade() error {
+	if err := s.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+func (s *Schema) Downgrade() error {
+	if err := s.migrate.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+func (s *Schema) Close() {
+	source, db := s.migrate.Close()
+	if source != nil {
+		slog.Error("Error releasing migration sources", "error", source)
+	}
+	if db != nil {
+		slog.Error("Error closing db connection", "error", db)
+	}
+}
+
+func (s *Schema) ValidateVersion() (uint, error) {
+	version, dirty, err := s.Version()
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, ErrMigrationNotCompleted
+	}
+
+	if version != ExpectedVersion {
+		format := "database schema version mismatch. Expected %d but it is %d"
+		return 0, fmt.Errorf(format, ExpectedVersion, version)
+	}
+	return version, nil
+}
@@ -0,0 +1,109 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/repository/sqlite/db/table"
+)
+
+var hexAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+func isHexAddress(s string) bool {
+	return hexAddressRegex.MatchString(s)
+}
+
+// blob creates a BLOB literal expression. SQLite never considers TEXT equal to
+// BLOB, so byte strings must be bound as []byte parameters to match BLOB columns.
+func blob(value []byte) sqlite.StringExpression {
+	return sqlite.StringExp(sqlite.Raw("#blob", sqlite.RawArgs{"#blob": value}))
+}
+
+func getWhereClauseFromNameOrAddress(nameOrAddress string) (sqlite.BoolExpression, error) {
+
+	var whereClause sqlite.BoolExpression
+	if isHexAddress(nameOrAddress) {
+		address := common.HexToAddress(nameOrAddress)
+		whereClause = table.Application.IapplicationAddress.EQ(blob(address.Bytes()))
+	} else {
+		// treat as name
+		whereClause = table.Application.Name.EQ(sqlite.String(nameOrAddress))
+	}
+	return whereClause, nil
+}
+
+// getCursorClause returns the keyset condition selecting the rows that come
+// after the pagination cursor in the requested order, or nil if there is none.
+func getCursorClause(
+	index sqlite.ColumnInteger,
+	p repository.Pagination,
+	descending bool,
+) sqlite.BoolExpression {
+	if p.After == nil {
+		return nil
+	}
+	after := sqlite.Uint64(*p.After)
+	if descending {
+		return index.LT(after)
+	}
+	return index.GT(after)
+}
+
+// hashList stores a list of hashes as a single BLOB of concatenated 32-byte
+// values, standing in for the Postgres BYTEA[] column.
+type hashList []common.Hash
+
+func (h hashList) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	data := make([]byte, 0, len(h)*common.HashLength)
+	for _, hash := range h {
+		data = append(data, hash[:]...)
+	}
+	return data, nil
+}
+
+func (h *hashList) Scan(src any) error {
+	if src == nil {
+		*h = nil
+		return nil
+	}
+	data, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("can't scan %T into hashList", src)
+	}
+	if len(data)%common.HashLength != 0 {
+		return fmt.Errorf("invalid hash list length %d", len(data))
+	}
+	list := make(hashList, len(data)/common.HashLength)
+	for i := range list {
+		list[i] = common.BytesToHash(data[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	*h = list
+	return nil
+}
+
+// nullableHash creates a BLOB literal for the hash or a NULL when it is unset.
+func nullableHash(hash *common.Hash) sqlite.Expression {
+	if hash == nil {
+		return sqlite.NULL
+	}
+	return blob(hash.Bytes())
+}
+
+// equalReasons reports whether two nullable reasons are the same.
+func equalReasons(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,142 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package repository
+
+import (
+	"context"
+	"iter"
+
+	. "github.com/cartesi/rollups-node/internal/model"
+)
+
+// DefaultStreamPageSize is the number of rows fetched per query by the Stream*
+// functions when the pagination does not set a limit.
+const DefaultStreamPageSize = 1000
+
+type InputLister interface {
+	ListInputs(ctx context.Context, nameOrAddress string, f InputFilter, p Pagination, descending bool) ([]*Input, uint64, error)
+}
+
+type OutputLister interface {
+	ListOutputs(ctx context.Context, nameOrAddress string, f OutputFilter, p Pagination, descending bool) ([]*Output, uint64, error)
+}
+
+type ReportLister interface {
+	ListReports(ctx context.Context, nameOrAddress string, f ReportFilter, p Pagination, descending bool) ([]*Report, uint64, error)
+}
+
+type EpochLister interface {
+	ListEpochs(ctx context.Context, nameOrAddress string, f EpochFilter, p Pagination, descending bool) ([]*Epoch, uint64, error)
+}
+
+// stream walks a listing page by page, using the index of the last row of each
+// page as the cursor for the next one. Only one page is kept in memory at a
+// time. p.Limit is the page size and p.After the starting cursor; p.Offset is
+// ignored. The first error is yielded and ends the iteration.
+func stream[T any](
+	ctx context.Context,
+	p Pagination,
+	list func(ctx context.Context, p Pagination) ([]*T, uint64, error),
+	index func(row *T) uint64,
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		page := Pagination{Limit: p.Limit, After: p.After}
+		if page.Limit == 0 {
+			page.Limit = DefaultStreamPageSize
+		}
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			rows, _, err := list(ctx, page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, row := range rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if uint64(len(rows)) < page.Limit {
+				return
+			}
+			after := index(rows[len(rows)-1])
+			page.After = &after
+		}
+	}
+}
+
+// StreamInputs iterates over all the inputs selected by f, fetching them from
+// the repository one page at a time.
+func StreamInputs(
+	ctx context.Context,
+	r InputLister,
+	nameOrAddress string,
+	f InputFilter,
+	p Pagination,
+	descending bool,
+) iter.Seq2[*Input, error] {
+	return stream(ctx, p,
+		func(ctx context.Context, p Pagination) ([]*Input, uint64, error) {
+			return r.ListInputs(ctx, nameOrAddress, f, p, descending)
+		},
+		func(in *Input) uint64 { return in.Index },
+	)
+}
+
+// StreamOutputs iterates over all the outputs selected by f, fetching them
+// from the repository one page at a time.
+func StreamOutputs(
+	ctx context.Context,
+	r OutputLister,
+	nameOrAddress string,
+	f OutputFilter,
+	p Pagination,
+	descending bool,
+) iter.Seq2[*Output, error] {
+	return stream(ctx, p,
+		func(ctx context.Context, p Pagination) ([]*Output, uint64, error) {
+			return r.ListOutputs(ctx, nameOrAddress, f, p, descending)
+		},
+		func(o *Output) uint64 { return o.Index },
+	)
+}
+
+// StreamReports iterates over all the reports selected by f, fetching them
+// from the repository one page at a time.
+func StreamReports(
+	ctx context.Context,
+	r ReportLister,
+	nameOrAddress string,
+	f ReportFilter,
+	p Pagination,
+	descending bool,
+) iter.Seq2[*Report, error] {
+	return stream(ctx, p,
+		func(ctx context.Context, p Pagination) ([]*Report, uint64, error) {
+			return r.ListReports(ctx, nameOrAddress, f, p, descending)
+		},
+		func(rp *Report) uint64 { return rp.Index },
+	)
+}
+
+// StreamEpochs iterates over all the epochs selected by f, fetching them from
+// the repository one page at a time.
+func StreamEpochs(
+	ctx context.Context,
+	r EpochLister,
+	nameOrAddress string,
+	f EpochFilter,
+	p Pagination,
+	descending bool,
+) iter.Seq2[*Epoch, error] {
+	return stream(ctx, p,
+		func(ctx context.Context, p Pagination) ([]*Epoch, uint64, error) {
+			return r.ListEpochs(ctx, nameOrAddress, f, p, descending)
+		},
+		func(e *Epoch) uint64 { return e.Index },
+	)
+}
@@ -0,0 +1,107 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// FileStorage keeps each snapshot in its own subdirectory.
+type FileStorage struct {
+	dir string
+}
+
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("snapshot storage directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil { // nolint: mnd
+		return nil, fmt.Errorf("failed to create snapshot storage directory: %w", err)
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (s *FileStorage) Upload(ctx context.Context, dir string, name string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(s.dir, name)
+
+	// Copy to a temporary directory first so readers never see partial snapshots.
+	tmpDir, err := os.MkdirTemp(s.dir, name+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "snapshot")
+	if err := copyDir(ctx, dir, tmpPath); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: dst}).String(), nil
+}
+
+func (s *FileStorage) Download(ctx context.Context, uri string, dir string) error {
+	src, err := filePath(uri)
+	if err != nil {
+		return err
+	}
+	return copyDir(ctx, src, dir)
+}
+
+func (s *FileStorage) Delete(ctx context.Context, uri string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p, err := filePath(uri)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(p)
+}
+
+func filePath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("invalid file snapshot URI: %s", uri)
+	}
+	return u.Host + u.Path, nil
+}
+
+func copyDir(ctx context.Context, src string, dst string) error {
+	files, err := snapshotFiles(src)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("snapshot %s is empty or does not exist", src)
+	}
+	for _, name := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := copyFromFile(filepath.Join(dst, name), filepath.Join(src, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFromFile(dst string, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	return errors.Join(copyFile(dst, f), f.Close())
+}
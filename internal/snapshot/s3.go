@@ -0,0 +1,170 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/cartesi/rollups-node/internal/repository/blob"
+)
+
+// S3Storage keeps each file of a snapshot as an object under the prefix of
+// the snapshot.
+type S3Storage struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Storage creates a storage from a "s3://bucket/prefix" URL.
+func NewS3Storage(ctx context.Context, u *url.URL) (*S3Storage, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing bucket in snapshot storage URI: %s", u.Redacted())
+	}
+	client, err := blob.NewS3Client(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Storage{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *S3Storage) Upload(ctx context.Context, dir string, name string) (string, error) {
+	key := path.Join(s.prefix, name)
+	uri := (&url.URL{Scheme: "s3", Host: s.bucket, Path: "/" + key}).String()
+
+	// Remove what a previous upload may have left, the manifest first
+	if err := s.Delete(ctx, uri); err != nil {
+		return "", err
+	}
+
+	files, err := snapshotFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if err := s.put(ctx, path.Join(key, file), filepath.Join(dir, file)); err != nil {
+			return "", err
+		}
+	}
+	return uri, nil
+}
+
+func (s *S3Storage) put(ctx context.Context, key string, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          f,
+		ContentLength: aws.Int64(info.Size()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put snapshot object %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Storage) Download(ctx context.Context, uri string, dir string) error {
+	bucket, key, err := s3Location(uri)
+	if err != nil {
+		return err
+	}
+	keys, err := s.list(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("snapshot %s not found", uri)
+	}
+	for _, objectKey := range keys {
+		out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(objectKey),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot object %s: %w", objectKey, err)
+		}
+		name := strings.TrimPrefix(objectKey, key+"/")
+		err = errors.Join(copyFile(filepath.Join(dir, filepath.FromSlash(name)), out.Body), out.Body.Close())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := s3Location(uri)
+	if err != nil {
+		return err
+	}
+	keys, err := s.list(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+	// Remove the manifest first, so a partially deleted snapshot is incomplete
+	manifest := path.Join(key, ManifestFileName)
+	for i, objectKey := range keys {
+		if objectKey == manifest {
+			keys[0], keys[i] = keys[i], keys[0]
+		}
+	}
+	for _, objectKey := range keys {
+		_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(objectKey),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete snapshot object %s: %w", objectKey, err)
+		}
+	}
+	return nil
+}
+
+// list returns the keys of the objects of the snapshot under key
+func (s *S3Storage) list(ctx context.Context, bucket string, key string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key + "/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshot objects: %w", err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
+func s3Location(uri string) (bucket string, key string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid S3 snapshot URI: %s", uri)
+	}
+	return u.Host, strings.Trim(u.Path, "/"), nil
+}
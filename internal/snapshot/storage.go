@@ -0,0 +1,122 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package snapshot implements remote storages for machine snapshots, so they
+// outlive the local snapshots directory of the node.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFileName is the name of the snapshot manifest, written by the
+// machine manager. It is uploaded last, so a remote snapshot without one is
+// incomplete.
+const ManifestFileName = "manifest.json"
+
+// Storage keeps snapshot directories under URIs.
+type Storage interface {
+	// Upload copies the snapshot in dir to the storage under name and
+	// returns its URI. Uploading the same name again replaces it.
+	Upload(ctx context.Context, dir string, name string) (string, error)
+	// Download copies the snapshot at uri to dir, which must not exist.
+	Download(ctx context.Context, uri string, dir string) error
+	// Delete removes the snapshot at uri, if any.
+	Delete(ctx context.Context, uri string) error
+}
+
+// New chooses the storage implementation based on the URI. For instance:
+//   - "file:///mnt/snapshots" => filesystem directory, like a network volume
+//   - "s3://bucket/prefix" => S3-compatible object storage
+//
+// S3 endpoints other than AWS, like MinIO, can be set with the "endpoint" query
+// parameter, and the region with "region". Credentials are taken from the
+// standard AWS environment variables and configuration files.
+func New(ctx context.Context, uri string) (Storage, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot storage URI: %w", err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "file":
+		return NewFileStorage(u.Host + u.Path)
+	case "s3":
+		return NewS3Storage(ctx, u)
+	default:
+		return nil, fmt.Errorf("unrecognized snapshot storage URI format: %s", uri)
+	}
+}
+
+// IsRemote tells whether uri refers to a snapshot in a storage rather than to
+// a local path.
+func IsRemote(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "file" || scheme == "s3"
+}
+
+// Name returns the name of the snapshot at uri, remote or local.
+func Name(uri string) string {
+	if u, err := url.Parse(uri); err == nil && IsRemote(uri) {
+		return path.Base(u.Path)
+	}
+	return filepath.Base(uri)
+}
+
+// snapshotFiles lists the regular files of the snapshot in dir, by path
+// relative to it, leaving the manifest last.
+func snapshotFiles(dir string) ([]string, error) {
+	var files []string
+	hasManifest := false
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if name == ManifestFileName {
+			hasManifest = true
+			return nil
+		}
+		files = append(files, name)
+		return nil
+	})
+	if hasManifest {
+		files = append(files, ManifestFileName)
+	}
+	return files, err
+}
+
+// copyFile copies the contents of r to a new file at path, creating its
+// parent directories.
+func copyFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { // nolint: mnd
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644) // nolint: mnd
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package snapshot
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cartesi/rollups-node/test/tooling/fakes3"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSnapshot(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.Nil(t, os.WriteFile(p, []byte(data), 0644))
+	}
+}
+
+func readSnapshot(t *testing.T, dir string) map[string]string {
+	files, err := snapshotFiles(dir)
+	require.Nil(t, err)
+	contents := map[string]string{}
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		require.Nil(t, err)
+		contents[name] = string(data)
+	}
+	return contents
+}
+
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+	files := map[string]string{
+		"config.json":    "{}",
+		"0000-1000.bin":  "memory",
+		"hash/tree.bin":  "tree",
+		ManifestFileName: `{"inputIndex":3}`,
+	}
+	src := t.TempDir()
+	writeSnapshot(t, src, files)
+
+	uri, err := s.Upload(ctx, src, "app_epoch1_input3")
+	require.Nil(t, err)
+	require.True(t, IsRemote(uri))
+	require.Equal(t, "app_epoch1_input3", Name(uri))
+
+	// uploading again replaces the previous copy
+	uri2, err := s.Upload(ctx, src, "app_epoch1_input3")
+	require.Nil(t, err)
+	require.Equal(t, uri, uri2)
+
+	dst := filepath.Join(t.TempDir(), "download")
+	require.Nil(t, s.Download(ctx, uri, dst))
+	require.Equal(t, files, readSnapshot(t, dst))
+
+	require.Nil(t, s.Delete(ctx, uri))
+	require.NotNil(t, s.Download(ctx, uri, filepath.Join(t.TempDir(), "download")))
+	require.Nil(t, s.Delete(ctx, uri))
+}
+
+func TestFileStorage(t *testing.T) {
+	s, err := New(context.Background(), "file://"+t.TempDir())
+	require.Nil(t, err)
+	require.IsType(t, &FileStorage{}, s)
+	testStorage(t, s)
+}
+
+func TestS3Storage(t *testing.T) {
+	fakes3.SetCredentials(t)
+	fake := fakes3.New()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := New(context.Background(), "s3://bucket/snapshots?region=us-east-1&endpoint="+server.URL)
+	require.Nil(t, err)
+	require.IsType(t, &S3Storage{}, s)
+	testStorage(t, s)
+
+	// files are stored path-style under the prefix of the snapshot
+	src := t.TempDir()
+	writeSnapshot(t, src, map[string]string{"config.json": "{}", "hash/tree.bin": "tree"})
+	uri, err := s.Upload(context.Background(), src, "app_epoch0_input0")
+	require.Nil(t, err)
+	require.Equal(t, "s3://bucket/snapshots/app_epoch0_input0", uri)
+	require.Equal(t, []string{
+		"/bucket/snapshots/app_epoch0_input0/config.json",
+		"/bucket/snapshots/app_epoch0_input0/hash/tree.bin",
+	}, fake.Keys())
+}
+
+// TestS3StorageMinIO runs against a real S3-compatible server, like the one
+// started by `make start-minio`, when CARTESI_TEST_SNAPSHOTS_S3_URI is set.
+// Credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func TestS3StorageMinIO(t *testing.T) {
+	uri, ok := os.LookupEnv("CARTESI_TEST_SNAPSHOTS_S3_URI")
+	if !ok {
+		t.Skip("CARTESI_TEST_SNAPSHOTS_S3_URI is not set")
+	}
+	s, err := New(context.Background(), uri)
+	require.Nil(t, err)
+	testStorage(t, s)
+}
+
+func TestNameAndIsRemote(t *testing.T) {
+	require.True(t, IsRemote("s3://bucket/prefix/app_epoch1_input2"))
+	require.True(t, IsRemote("file:///mnt/snapshots/app_epoch1_input2"))
+	require.False(t, IsRemote("/var/lib/cartesi-rollups-node/snapshots/app_epoch1_input2"))
+	require.Equal(t, "app_epoch1_input2", Name("s3://bucket/prefix/app_epoch1_input2"))
+	require.Equal(t, "app_epoch1_input2", Name("/var/lib/snapshots/app_epoch1_input2"))
+
+	_, err := New(context.Background(), "ftp://somewhere")
+	require.NotNil(t, err)
+}
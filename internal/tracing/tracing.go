@@ -0,0 +1,101 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package tracing sets up the OpenTelemetry tracing of the node services.
+//
+// Services create their spans from the global tracer provider. When
+// CARTESI_TRACING_OTLP_ENDPOINT is set, [Setup] installs a provider that
+// exports them to an OTLP collector; otherwise the default no-op provider is
+// kept and spans cost next to nothing.
+//
+// Each service traces its own work, so spans of the same input or epoch are
+// correlated by their attributes rather than by a shared trace.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cartesi/rollups-node/internal/config"
+	"github.com/cartesi/rollups-node/internal/version"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes that correlate the spans of the different services.
+const (
+	ApplicationAddressKey = attribute.Key("cartesi.application.address")
+	InputIndexKey         = attribute.Key("cartesi.input.index")
+	EpochIndexKey         = attribute.Key("cartesi.epoch.index")
+)
+
+// Setup installs the global tracer provider of serviceName, if an OTLP
+// endpoint is configured. The returned function flushes the pending spans and
+// must be called before the program exits.
+func Setup(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	endpoint, err := config.GetTracingOtlpEndpoint()
+	if errors.Is(err, config.ErrNotDefined) {
+		return func(context.Context) error { return nil }, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName("cartesi-rollups-"+serviceName),
+			semconv.ServiceVersion(version.BuildVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// ApplicationAddress returns the attribute of the application address.
+func ApplicationAddress(address common.Address) attribute.KeyValue {
+	return ApplicationAddressKey.String(address.String())
+}
+
+// InputIndex returns the attribute of the input index.
+func InputIndex(index uint64) attribute.KeyValue {
+	return InputIndexKey.Int64(int64(index)) // nolint: gosec
+}
+
+// EpochIndex returns the attribute of the epoch index.
+func EpochIndex(index uint64) attribute.KeyValue {
+	return EpochIndexKey.Int64(int64(index)) // nolint: gosec
+}
+
+// Start starts a span of tracer with the given attributes. A nil ctx starts
+// a root span.
+func Start(
+	ctx context.Context,
+	tracer trace.Tracer,
+	name string,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
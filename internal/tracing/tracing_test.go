@@ -0,0 +1,53 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	t.Setenv("CARTESI_TRACING_OTLP_ENDPOINT", "")
+	shutdown, err := Setup(context.Background(), "test")
+	require.Nil(t, err)
+	require.Nil(t, shutdown(context.Background()))
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	require.False(t, ok)
+}
+
+func TestSetupInvalidEndpoint(t *testing.T) {
+	t.Setenv("CARTESI_TRACING_OTLP_ENDPOINT", "://collector")
+	_, err := Setup(context.Background(), "test")
+	require.ErrorContains(t, err, "CARTESI_TRACING_OTLP_ENDPOINT")
+}
+
+func TestSetupExportsSpans(t *testing.T) {
+	var requests atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			requests.Add(1)
+		}
+	}))
+	defer collector.Close()
+
+	t.Setenv("CARTESI_TRACING_OTLP_ENDPOINT", collector.URL+"/v1/traces")
+	shutdown, err := Setup(context.Background(), "test")
+	require.Nil(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "span")
+	span.SetAttributes(ApplicationAddress(common.HexToAddress("0x01")), InputIndex(1))
+	End(span, nil)
+
+	require.Nil(t, shutdown(context.Background()))
+	require.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,16 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package validator
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var claimsComputedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "cartesi",
+	Subsystem: "validator",
+	Name:      "claims_computed_total",
+	Help:      "Claims computed and stored, per application.",
+}, []string{"application"})
@@ -14,14 +14,21 @@ import (
 	"github.com/cartesi/rollups-node/internal/merkle"
 	. "github.com/cartesi/rollups-node/internal/model"
 	"github.com/cartesi/rollups-node/internal/repository"
+	"github.com/cartesi/rollups-node/internal/tracing"
 	"github.com/cartesi/rollups-node/pkg/service"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/internal/validator")
+
 type Service struct {
 	service.Service
 	repository ValidatorRepository
+	// configuration in effect, updated by Reload
+	config config.ValidatorConfig
 
 	// cached constants
 	pristineRootHash    common.Hash
@@ -45,6 +52,12 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 	s := &Service{}
 	c.Impl = s
 
+	// wake up on epochs with all inputs processed instead of waiting for the next poll
+	if c.NotifyConnection == "" && c.Config.DatabaseConnection != nil {
+		c.NotifyConnection = c.Config.DatabaseConnection.String()
+		c.NotifyChannels = []string{repository.EpochInputsProcessedChannel}
+	}
+
 	err = service.Create(ctx, &c.CreateInfo, &s.Service)
 	if err != nil {
 		return nil, err
@@ -55,15 +68,32 @@ func Create(ctx context.Context, c *CreateInfo) (*Service, error) {
 		return nil, fmt.Errorf("repository on validator service Create is nil")
 	}
 
+	s.config = c.Config
 	s.pristinePostContext = merkle.CreatePostContext()
 	s.pristineRootHash = s.pristinePostContext[merkle.TREE_DEPTH]
 
+	s.AddHealthCheck("database", c.Repository.Ping)
+	s.AddHealthCheck("tick", s.TickCheck)
+
 	return s, nil
 }
 
-func (s *Service) Alive() bool     { return true }
-func (s *Service) Ready() bool     { return true }
-func (s *Service) Reload() []error { return nil }
+func (s *Service) Alive() bool { return true }
+func (s *Service) Ready() bool { return s.Health(s.Context).Ready }
+
+// Reload applies the log level and the polling interval
+func (s *Service) Reload() []error {
+	c, err := config.LoadValidatorConfig()
+	if err != nil {
+		return []error{err}
+	}
+	s.SetLogLevel(c.LogLevel)
+	s.SetPollInterval(c.ValidatorPollingInterval)
+	s.config.LogLevel = c.LogLevel
+	s.config.ValidatorPollingInterval = c.ValidatorPollingInterval
+	s.ReportRestartRequired(config.Changed(&s.config, c))
+	return nil
+}
 
 // Tick executes the Validator main logic of producing claims and/or proofs
 // for processed epochs of all running applications.
@@ -96,7 +126,7 @@ const MAX_OUTPUT_TREE_HEIGHT = merkle.TREE_DEPTH
 
 type ValidatorRepository interface {
 	ListApplications(ctx context.Context, f repository.ApplicationFilter, p repository.Pagination, descending bool) ([]*Application, uint64, error)
-	UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error
+	SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error
 	ListOutputs(ctx context.Context, nameOrAddress string, f repository.OutputFilter, p repository.Pagination, descending bool) ([]*Output, uint64, error)
 	GetLastOutputBeforeBlock(ctx context.Context, nameOrAddress string, block uint64) (*Output, error)
 	ListEpochs(ctx context.Context, nameOrAddress string, f repository.EpochFilter, p repository.Pagination, descending bool) ([]*Epoch, uint64, error)
@@ -115,19 +145,25 @@ func getProcessedEpochs(ctx context.Context, er ValidatorRepository, address str
 	return er.ListEpochs(ctx, address, f, repository.Pagination{}, false)
 }
 
-// setApplicationInoperable marks an application as inoperable with the given reason,
+// setApplicationInoperable marks an application as inoperable with the given code and reason,
 // logs any error that occurs during the update, and returns an error with the reason.
-func (v *Service) setApplicationInoperable(ctx context.Context, app *Application, reasonFmt string, args ...interface{}) error {
+func (v *Service) setApplicationInoperable(
+	ctx context.Context,
+	app *Application,
+	code ReasonCode,
+	reasonFmt string,
+	args ...interface{},
+) error {
 	reason := fmt.Sprintf(reasonFmt, args...)
-	appAddress := app.IApplicationAddress.String()
 
 	// Log the reason first
-	v.Logger.Error(reason, "application", appAddress)
+	v.Logger.Error(reason, "application", app.Name, "address", app.IApplicationAddress)
 
 	// Update application state
-	err := v.repository.UpdateApplicationState(ctx, app.ID, ApplicationState_Inoperable, &reason)
+	err := v.repository.SetApplicationInoperable(ctx, app.ID, code, reason)
 	if err != nil {
-		v.Logger.Error("failed to update application state to inoperable", "app", appAddress, "err", err)
+		v.Logger.Error("failed to update application state to inoperable",
+			"application", app.Name, "address", app.IApplicationAddress, "error", err)
 	}
 
 	// Return the error with the reason
@@ -148,21 +184,28 @@ func (v *Service) validateApplication(ctx context.Context, app *Application) err
 	}
 
 	for _, epoch := range processedEpochs {
+		// leave the remaining epochs to the next run when stopping
+		if v.Stopping() {
+			return nil
+		}
+		v.Heartbeat()
 		v.Logger.Debug("Started calculating claim",
-			"application", appAddress,
+			"application", app.Name,
+			"address", app.IApplicationAddress,
 			"epoch_index", epoch.Index,
 			"last_block", epoch.LastBlock,
 		)
 		claim, outputs, err := v.createClaimAndProofs(ctx, app, epoch)
 		if err != nil {
-			v.Logger.Error("failed to create claim and proofs.", "error", err)
+			v.Logger.Error("failed to create claim and proofs.", "application", app.Name, "error", err)
 			return err
 		}
 
 		v.Logger.Info("Claim Computed",
-			"application", appAddress,
+			"application", app.Name,
+			"address", app.IApplicationAddress,
 			"epoch_index", epoch.Index,
-			"claimHash", *claim,
+			"claim_hash", *claim,
 		)
 
 		// The Cartesi Machine calculates the root hash of the outputs Merkle
@@ -183,14 +226,14 @@ func (v *Service) validateApplication(ctx context.Context, app *Application) err
 		}
 
 		if input.OutputsHash == nil {
-			return v.setApplicationInoperable(ctx, app,
+			return v.setApplicationInoperable(ctx, app, ReasonCode_InconsistentState,
 				"inconsistent state: machine claim for epoch %v of application %v was not found",
 				epoch.Index, appAddress)
 		}
 
 		// ...and compare it to the hash calculated by the Validator
 		if *input.OutputsHash != *claim {
-			return v.setApplicationInoperable(ctx, app,
+			return v.setApplicationInoperable(ctx, app, ReasonCode_ClaimMismatch,
 				"validator claim does not match machine claim for epoch %v of application %v. Expected: %v, Got %v",
 				epoch.Index, appAddress, *input.OutputsHash, *claim)
 		}
@@ -207,11 +250,13 @@ func (v *Service) validateApplication(ctx context.Context, app *Application) err
 				epoch.Index, appAddress, err,
 			)
 		}
+		claimsComputedMetric.WithLabelValues(app.Name).Inc()
 	}
 
 	if len(processedEpochs) == 0 {
 		v.Logger.Debug("no processed epochs to validate",
-			"app", app.IApplicationAddress,
+			"application", app.Name,
+			"address", app.IApplicationAddress,
 		)
 	}
 
@@ -226,7 +271,12 @@ func (v *Service) createClaimAndProofs(
 	ctx context.Context,
 	app *Application,
 	epoch *Epoch,
-) (*common.Hash, []*Output, error) {
+) (_ *common.Hash, _ []*Output, err error) {
+	ctx, span := tracing.Start(ctx, tracer, "CreateClaimAndProofs",
+		tracing.ApplicationAddress(app.IApplicationAddress),
+		tracing.EpochIndex(epoch.Index))
+	defer func() { tracing.End(span, err) }()
+
 	appAddress := app.IApplicationAddress.String()
 	epochOutputs, _, err := v.repository.ListOutputs(ctx, appAddress, repository.OutputFilter{
 		BlockRange: &repository.Range{
@@ -242,6 +292,7 @@ func (v *Service) createClaimAndProofs(
 			epoch.Index, epoch.VirtualIndex, appAddress, err,
 		)
 	}
+	span.SetAttributes(attribute.Int("cartesi.outputs.count", len(epochOutputs)))
 
 	var previousEpoch *Epoch
 	if epoch.VirtualIndex > 0 {
@@ -263,7 +314,7 @@ func (v *Service) createClaimAndProofs(
 		}
 		// if there are no outputs and there is a previous epoch, return its claim
 		if previousEpoch.ClaimHash == nil {
-			return nil, nil, v.setApplicationInoperable(ctx, app,
+			return nil, nil, v.setApplicationInoperable(ctx, app, ReasonCode_InconsistentState,
 				"invalid application state for epoch %v (%v) of application %v. Previous epoch has no claim.",
 				epoch.Index, epoch.VirtualIndex, appAddress)
 		}
@@ -293,7 +344,7 @@ func (v *Service) createClaimAndProofs(
 		} else {
 			// there are previous outputs, create a pre context from the last output.
 			if lastOutput.Hash == nil || len(lastOutput.OutputHashesSiblings) != merkle.TREE_DEPTH {
-				return nil, nil, v.setApplicationInoperable(ctx, app,
+				return nil, nil, v.setApplicationInoperable(ctx, app, ReasonCode_InconsistentState,
 					"Inconsistent application state (%v). Last output (%d) before epoch %d has no hash or invalid hash siblings.",
 					app.Name, lastOutput.Index, epoch.Index)
 			}
@@ -302,7 +353,7 @@ func (v *Service) createClaimAndProofs(
 
 			// make sure no output got skipped
 			if index != epochOutputs[0].Index {
-				return nil, nil, v.setApplicationInoperable(ctx, app,
+				return nil, nil, v.setApplicationInoperable(ctx, app, ReasonCode_InconsistentState,
 					"Inconsistent application state (%v). Output index mismatch. "+
 						"Last output (%d) before epoch %d and first output (%d) are not sequential.",
 					app.Name, lastOutput.Index, epoch.Index, epochOutputs[0].Index)
@@ -249,8 +249,8 @@ func (s *ValidatorSuite) TestCreateClaimAndProofFailures() {
 			mock.Anything, mock.Anything, mock.Anything,
 		).Return(&invalidEpoch, nil).Once()
 
-		repo.On("UpdateApplicationState",
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		repo.On("SetApplicationInoperable",
+			mock.Anything, mock.Anything, ReasonCode_InconsistentState, mock.Anything,
 		).Return(nil).Once()
 
 		_, _, err := validator.createClaimAndProofs(nil, &app, &dummyEpochs[1])
@@ -291,8 +291,8 @@ func (s *ValidatorSuite) TestCreateClaimAndProofFailures() {
 			mock.Anything, mock.Anything, mock.Anything,
 		).Return(&Output{}, nil).Once()
 
-		repo.On("UpdateApplicationState",
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		repo.On("SetApplicationInoperable",
+			mock.Anything, mock.Anything, ReasonCode_InconsistentState, mock.Anything,
 		).Return(nil).Once()
 
 		_, _, err := validator.createClaimAndProofs(nil, &app, &dummyEpochs[1])
@@ -314,8 +314,8 @@ func (s *ValidatorSuite) TestCreateClaimAndProofFailures() {
 			mock.Anything, mock.Anything, mock.Anything,
 		).Return(&dummyOutputs[0], nil).Once()
 
-		repo.On("UpdateApplicationState",
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		repo.On("SetApplicationInoperable",
+			mock.Anything, mock.Anything, ReasonCode_InconsistentState, mock.Anything,
 		).Return(nil).Once()
 
 		_, _, err := validator.createClaimAndProofs(nil, &app, &dummyEpochs[1])
@@ -438,8 +438,8 @@ func (s *ValidatorSuite) TestValidateApplicationFailure() {
 			mock.Anything, app.IApplicationAddress.String(), dummyEpochs[0].Index,
 		).Return(&input, nil).Once()
 
-		repo.On("UpdateApplicationState",
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		repo.On("SetApplicationInoperable",
+			mock.Anything, mock.Anything, ReasonCode_InconsistentState, mock.Anything,
 		).Return(nil).Once()
 
 		err := validator.validateApplication(nil, &app)
@@ -466,8 +466,8 @@ func (s *ValidatorSuite) TestValidateApplicationFailure() {
 			mock.Anything, app.IApplicationAddress.String(), dummyEpochs[0].Index,
 		).Return(&input, nil).Once()
 
-		repo.On("UpdateApplicationState",
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		repo.On("SetApplicationInoperable",
+			mock.Anything, mock.Anything, ReasonCode_ClaimMismatch, mock.Anything,
 		).Return(nil).Once()
 
 		err := validator.validateApplication(nil, &app)
@@ -573,7 +573,7 @@ func (m *Mockrepo) StoreClaimAndProofs(ctx context.Context, epoch *Epoch, output
 	return args.Error(0)
 }
 
-func (m *Mockrepo) UpdateApplicationState(ctx context.Context, appID int64, state ApplicationState, reason *string) error {
-	args := m.Called(ctx, appID, state, reason)
+func (m *Mockrepo) SetApplicationInoperable(ctx context.Context, appID int64, code ReasonCode, reason string) error {
+	args := m.Called(ctx, appID, code, reason)
 	return args.Error(0)
 }
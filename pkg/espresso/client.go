@@ -0,0 +1,144 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package espresso reads blocks and namespace transactions from an Espresso
+// query service, and decodes the messages applications receive through it.
+package espresso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNotFound = errors.New("not found")
+
+const defaultTimeout = 30 * time.Second
+
+// L1BlockInfo identifies an L1 block referenced by an Espresso header.
+type L1BlockInfo struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Header holds the fields of an Espresso block header used by the node.
+type Header struct {
+	Height uint64 `json:"height"`
+	L1Head uint64 `json:"l1_head"`
+	// L1Finalized is nil until the sequencer has seen a finalized L1 block.
+	L1Finalized *L1BlockInfo `json:"l1_finalized"`
+}
+
+// L1FinalizedNumber returns the number of the finalized L1 block, or zero if there is none.
+func (h *Header) L1FinalizedNumber() uint64 {
+	if h.L1Finalized == nil {
+		return 0
+	}
+	return h.L1Finalized.Number
+}
+
+type Transaction struct {
+	Namespace uint32 `json:"namespace"`
+	Payload   []byte `json:"payload"`
+}
+
+// Client queries the availability and status APIs of an Espresso query service.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Espresso query service URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid Espresso query service URL scheme %q", u.Scheme)
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(u.String(), "/"),
+		http:    &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s: %w", path, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s: unexpected status %d: %s", path, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", path, err)
+	}
+	return nil
+}
+
+// BlockHeight returns the number of blocks available, so the latest block is
+// at height BlockHeight() - 1.
+func (c *Client) BlockHeight(ctx context.Context) (uint64, error) {
+	var height uint64
+	err := c.get(ctx, "/v0/status/block-height", &height)
+	return height, err
+}
+
+// Header returns the header of the block at height.
+func (c *Client) Header(ctx context.Context, height uint64) (*Header, error) {
+	// newer query services wrap the header fields with their version
+	var resp struct {
+		Header
+		Fields *Header `json:"fields"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/v0/availability/header/%d", height), &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Fields != nil {
+		return resp.Fields, nil
+	}
+	return &resp.Header, nil
+}
+
+// NamespaceTransactions returns the transactions of namespace in the block at
+// height, in the order they were sequenced.
+func (c *Client) NamespaceTransactions(
+	ctx context.Context,
+	height uint64,
+	namespace uint32,
+) ([]Transaction, error) {
+	var resp struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/v0/availability/block/%d/namespace/%d", height, namespace), &resp)
+	if errors.Is(err, ErrNotFound) {
+		// blocks without transactions of the namespace may have no entry for it
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Transactions, nil
+}
@@ -0,0 +1,113 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package espresso
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Message is an input sent to an application through Espresso. It is signed
+// by its sender following EIP-712, with the domain below and the chain ID of
+// the application.
+//
+//	EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)
+//	CartesiMessage(address app,uint64 nonce,bytes data)
+type Message struct {
+	App   common.Address `json:"app"`
+	Nonce uint64         `json:"nonce"`
+	Data  hexutil.Bytes  `json:"data"`
+}
+
+// SignedMessage is the JSON payload of an Espresso namespace transaction.
+type SignedMessage struct {
+	Message   Message       `json:"message"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+const (
+	domainName    = "Cartesi"
+	domainVersion = "0.1.0"
+)
+
+var messageTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"CartesiMessage": {
+		{Name: "app", Type: "address"},
+		{Name: "nonce", Type: "uint64"},
+		{Name: "data", Type: "bytes"},
+	},
+}
+
+func DecodeSignedMessage(payload []byte) (*SignedMessage, error) {
+	var m SignedMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, fmt.Errorf("invalid signed message: %w", err)
+	}
+	if len(m.Signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(m.Signature))
+	}
+	return &m, nil
+}
+
+// Hash returns the EIP-712 hash signed by the sender of the message.
+func (m Message) Hash(chainId uint64) (common.Hash, error) {
+	typedData := apitypes.TypedData{
+		Types:       messageTypes,
+		PrimaryType: "CartesiMessage",
+		Domain: apitypes.TypedDataDomain{
+			Name:              domainName,
+			Version:           domainVersion,
+			ChainId:           (*math.HexOrDecimal256)(new(big.Int).SetUint64(chainId)),
+			VerifyingContract: common.Address{}.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"app":   m.App.Hex(),
+			"nonce": new(big.Int).SetUint64(m.Nonce),
+			"data":  []byte(m.Data),
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Sender recovers the address that signed the message.
+func (m *SignedMessage) Sender(chainId uint64) (common.Address, error) {
+	hash, err := m.Message.Hash(chainId)
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := common.CopyBytes(m.Signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Reference identifies the message by its sender and nonce. Each pair is
+// accepted only once, so signed messages cannot be replayed.
+func (m Message) Reference(sender common.Address) common.Hash {
+	nonce := binary.BigEndian.AppendUint64(nil, m.Nonce)
+	return crypto.Keccak256Hash(sender.Bytes(), nonce)
+}
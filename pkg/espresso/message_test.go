@@ -0,0 +1,49 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package espresso
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedMessage(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	msg := SignedMessage{
+		Message: Message{App: common.HexToAddress("0x01"), Nonce: 3, Data: []byte("hello")},
+	}
+	hash, err := msg.Message.Hash(31337)
+	require.Nil(t, err)
+	msg.Signature, err = crypto.Sign(hash.Bytes(), key)
+	require.Nil(t, err)
+	msg.Signature[crypto.RecoveryIDOffset] += 27
+
+	payload, err := json.Marshal(msg)
+	require.Nil(t, err)
+	decoded, err := DecodeSignedMessage(payload)
+	require.Nil(t, err)
+	require.Equal(t, msg, *decoded)
+
+	recovered, err := decoded.Sender(31337)
+	require.Nil(t, err)
+	require.Equal(t, sender, recovered)
+
+	// signed for another chain
+	recovered, err = decoded.Sender(1)
+	require.Nil(t, err)
+	require.NotEqual(t, sender, recovered)
+
+	require.NotEqual(t, msg.Message.Reference(sender), Message{Nonce: 4}.Reference(sender))
+	require.Equal(t, msg.Message.Reference(sender), Message{Nonce: 3}.Reference(sender))
+
+	_, err = DecodeSignedMessage([]byte(`{"message":{},"signature":"0x01"}`))
+	require.NotNil(t, err)
+}
@@ -32,4 +32,6 @@ type CartesiMachine interface {
 
 	PayloadLengthLimit() uint
 	Address() string
+	// Pid returns the process id of the server, or zero when it is unknown.
+	Pid() uint32
 }
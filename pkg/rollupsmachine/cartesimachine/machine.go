@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 
 	"github.com/cartesi/rollups-node/internal/model"
@@ -32,6 +34,53 @@ type cartesiMachine struct {
 	server *emulator.RemoteMachine
 
 	address string // address of the JSON RPC remote cartesi machine server
+	pid     uint32 // process id of the server, if it was spawned or forked
+}
+
+// Spawn starts a JSON RPC remote cartesi machine server with verbosity, writing
+// its output to stdout and stderr line by line, and loads the machine stored
+// at path into it. The server is shut down if the machine fails to load.
+func Spawn(ctx context.Context,
+	path string,
+	config *emulator.MachineRuntimeConfig,
+	executionParameters *model.ExecutionParameters,
+	logger *slog.Logger,
+	verbosity MachineLogLevel,
+	stdout, stderr io.Writer,
+) (CartesiMachine, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	jsonConf, err := json.Marshal(config)
+	if err != nil {
+		err = fmt.Errorf("could not marshal machine runtime config: %w", err)
+		return nil, errCartesiMachine(err)
+	}
+
+	process, err := LaunchServer(logger, verbosity, 0, stdout, stderr)
+	if err != nil {
+		err = fmt.Errorf("could not start the remote machine server: %w", err)
+		return nil, errCartesiMachine(err)
+	}
+	server, err := emulator.ConnectServer(process.Address, executionParameters.FastDeadline)
+	if err != nil {
+		err = fmt.Errorf("could not connect to the remote machine: %w", err)
+		return nil, errors.Join(errCartesiMachine(err), process.Kill())
+	}
+	machine := &cartesiMachine{server: server, address: process.Address, pid: process.Pid}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, errors.Join(err, machine.shutdown())
+	}
+
+	err = machine.server.Load(path, string(jsonConf))
+	if err != nil {
+		err = fmt.Errorf("could not load the machine: %w", err)
+		return nil, errors.Join(errCartesiMachine(err), machine.shutdown())
+	}
+
+	return machine, nil
 }
 
 // Load loads the machine stored at path into the remote server from address.
@@ -91,13 +140,14 @@ func (machine *cartesiMachine) Fork(ctx context.Context) (CartesiMachine, error)
 	}
 
 	// Forks the server.
-	newServer, address, _, err := machine.server.ForkServer()
+	newServer, address, pid, err := machine.server.ForkServer()
 	if err != nil {
 		err = fmt.Errorf("could not fork the machine: %w", err)
 		return nil, errCartesiMachine(err)
 	}
 	newMachine.address = address
 	newMachine.server = newServer
+	newMachine.pid = pid
 
 	return newMachine, nil
 }
@@ -253,6 +303,10 @@ func (machine cartesiMachine) PayloadLengthLimit() uint {
 	return payloadLengthLimit
 }
 
+func (machine cartesiMachine) Pid() uint32 {
+	return machine.pid
+}
+
 func (machine cartesiMachine) Address() string {
 	return machine.address
 }
@@ -277,6 +331,11 @@ func (machine *cartesiMachine) Close(ctx context.Context) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
+	return machine.shutdown()
+}
+
+// shutdown shuts down the remote cartesi machine server.
+func (machine *cartesiMachine) shutdown() error {
 	err := machine.server.ShutdownServer()
 	if err != nil {
 		err = fmt.Errorf("could not shut down the server: %w", err)
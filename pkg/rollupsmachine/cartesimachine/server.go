@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -23,7 +24,7 @@ const (
 	MachineLogLevelTrace MachineLogLevel = "trace"
 	MachineLogLevelDebug MachineLogLevel = "debug"
 	MachineLogLevelInfo  MachineLogLevel = "info"
-	MachineLogLevelWarn  MachineLogLevel = "warn"
+	MachineLogLevelWarn  MachineLogLevel = "warning"
 	MachineLogLevelError MachineLogLevel = "error"
 	MachineLogLevelFatal MachineLogLevel = "fatal"
 )
@@ -36,7 +37,7 @@ func ParseMachineLogLevel(level string) (MachineLogLevel, error) {
 		return MachineLogLevelDebug, nil
 	case string(MachineLogLevelInfo):
 		return MachineLogLevelInfo, nil
-	case string(MachineLogLevelWarn):
+	case string(MachineLogLevelWarn), "warn":
 		return MachineLogLevelWarn, nil
 	case string(MachineLogLevelError):
 		return MachineLogLevelError, nil
@@ -51,6 +52,24 @@ var (
 	ErrNilLogger = errors.New("logger must not be nil")
 )
 
+// Server is a JSON RPC remote cartesi machine server process started by LaunchServer.
+type Server struct {
+	Address string
+	Pid     uint32
+	process *os.Process
+	exited  chan struct{}
+}
+
+// Kill kills the server process.
+func (server *Server) Kill() error {
+	return server.process.Kill()
+}
+
+// Exited is closed when the server process exits and its output was written.
+func (server *Server) Exited() <-chan struct{} {
+	return server.exited
+}
+
 // StartServer starts a JSON RPC remote cartesi machine server.
 //
 // It configures the server's logging verbosity and initializes its address to 127.0.0.1:port.
@@ -61,8 +80,25 @@ var (
 //
 // It returns the server's address.
 func StartServer(logger *slog.Logger, verbosity MachineLogLevel, port uint32, stdout, stderr io.Writer) (string, error) {
+	server, err := LaunchServer(logger, verbosity, port, stdout, stderr)
+	if err != nil {
+		return "", err
+	}
+	return server.Address, nil
+}
+
+// LaunchServer is like StartServer, but it returns the server process. The
+// process is reaped when it exits, and its stdout and stderr are written to
+// the provided io.Writers line by line.
+func LaunchServer(logger *slog.Logger, verbosity MachineLogLevel, port uint32, stdout, stderr io.Writer) (*Server, error) {
 	if logger == nil {
-		return "", ErrNilLogger
+		return nil, ErrNilLogger
+	}
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
 	}
 
 	// Configures the command's arguments.
@@ -83,24 +119,40 @@ func StartServer(logger *slog.Logger, verbosity MachineLogLevel, port uint32, st
 		port:  make(chan uint32),
 		found: new(bool),
 	}
-	cmd.Stdout = stdout
+	cmd.Stdout = linewriter.New(stdout)
 	cmd.Stderr = linewriter.New(interceptor)
 
 	// Starts the server.
 	logger.Info("Starting remote machine server", "command", cmd.String())
 	if err := cmd.Start(); err != nil {
-		return "", err
+		return nil, err
 	}
+	server := &Server{
+		Pid:     uint32(cmd.Process.Pid), // nolint: gosec
+		process: cmd.Process,
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		_ = cmd.Wait()
+		close(server.exited)
+	}()
 
 	// Waits for the interceptor to write the port to the channel.
-	if actualPort := <-interceptor.port; port == 0 {
+	var actualPort uint32
+	select {
+	case actualPort = <-interceptor.port:
+	case <-server.exited:
+		return nil, fmt.Errorf("remote machine server exited on startup: %v", cmd.ProcessState)
+	}
+	if port == 0 {
 		port = actualPort
 	} else if port != actualPort {
-		return "", fmt.Errorf("mismatching ports (%d != %d)", port, actualPort)
+		_ = server.Kill()
+		return nil, fmt.Errorf("mismatching ports (%d != %d)", port, actualPort)
 	}
 
-	address := fmt.Sprintf("127.0.0.1:%d", port)
-	return address, nil
+	server.Address = fmt.Sprintf("127.0.0.1:%d", port)
+	return server, nil
 }
 
 // StopServer shuts down the JSON RPC remote cartesi machine server hosted at address.
@@ -12,6 +12,10 @@ import (
 
 	"github.com/cartesi/rollups-node/pkg/emulator"
 	"github.com/cartesi/rollups-node/pkg/rollupsmachine/cartesimachine"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -20,6 +24,8 @@ const (
 	hashLength    = 32
 )
 
+var tracer = otel.Tracer("github.com/cartesi/rollups-node/pkg/rollupsmachine")
+
 // Convenient type aliases.
 type (
 	Cycle   = uint64
@@ -42,6 +48,9 @@ type RollupsMachine interface {
 	// OutputsHash returns the outputs hash stored in the cmio tx buffer.
 	OutputsHash(context.Context) (Hash, error)
 
+	// Cycle returns the machine's current cycle.
+	Cycle(context.Context) (Cycle, error)
+
 	// Advance sends an input to the machine.
 	// It returns a boolean indicating whether or not the request was accepted.
 	// It also returns the corresponding outputs, reports, and the hash of the outputs.
@@ -59,6 +68,9 @@ type RollupsMachine interface {
 	// Close closes the inner cartesi machine.
 	// It returns nil if the machine has already been closed.
 	Close(context.Context) error
+
+	// Pid returns the process id of the machine server, or zero when it is unknown.
+	Pid() uint32
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -140,6 +152,10 @@ func (machine *rollupsMachine) OutputsHash(ctx context.Context) (Hash, error) {
 	return outputsHash, nil
 }
 
+func (machine *rollupsMachine) Cycle(ctx context.Context) (Cycle, error) {
+	return machine.inner.ReadCycle(ctx)
+}
+
 func (machine *rollupsMachine) Advance(
 	ctx context.Context,
 	input []byte,
@@ -180,6 +196,13 @@ func (machine *rollupsMachine) Store(ctx context.Context, path string) error {
 	return machine.inner.Store(ctx, path)
 }
 
+func (machine *rollupsMachine) Pid() uint32 {
+	if machine.inner == nil {
+		return 0
+	}
+	return machine.inner.Pid()
+}
+
 func (machine *rollupsMachine) Close(ctx context.Context) error {
 	if machine.inner == nil {
 		return nil
@@ -331,9 +354,19 @@ func (machine *rollupsMachine) run(ctx context.Context) ([]Output, []Report, err
 func (machine *rollupsMachine) step(ctx context.Context,
 	currentCycle Cycle,
 	limitCycle Cycle,
-) (*yieldType, Cycle, error) {
+) (_ *yieldType, _ Cycle, err error) {
 	startingCycle := currentCycle
 
+	ctx, span := tracer.Start(ctx, "step", trace.WithAttributes(
+		attribute.Int64("cartesi.machine.starting_cycle", int64(startingCycle)))) // nolint: gosec
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	// Returns with an error if the next run would exceed limitCycle.
 	if currentCycle >= limitCycle && machine.inc != 0 {
 		return nil, 0, ErrCycleLimitExceeded
@@ -354,6 +387,10 @@ func (machine *rollupsMachine) step(ctx context.Context,
 		return nil, 0, err
 	}
 
+	span.SetAttributes(
+		attribute.Int64("cartesi.machine.current_cycle", int64(currentCycle)), // nolint: gosec
+		attribute.String("cartesi.machine.break_reason", breakReason.String()))
+
 	machine.logger.Debug("step",
 		"startingCycle", startingCycle,
 		"increment", increment,
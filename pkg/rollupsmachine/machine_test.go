@@ -1246,6 +1246,10 @@ func (machine *CartesiMachineMock) Address() string {
 	return machine.AddressReturn
 }
 
+func (machine *CartesiMachineMock) Pid() uint32 {
+	return 0
+}
+
 func (machine *CartesiMachineMock) Store(_ context.Context, _ string) error {
 	return machine.StoreError
 }
@@ -0,0 +1,100 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Time limit of each health check.
+const HealthCheckTimeout = 5 * time.Second
+
+// How many poll intervals may pass without a heartbeat before the service is
+// reported as not ready.
+const maxMissedTicks = 3
+
+// HealthCheck returns why a dependency of the service is unhealthy, or nil.
+type HealthCheck func(ctx context.Context) error
+
+// Check is the result of a [HealthCheck].
+type Check struct {
+	Name  string `json:"name"`
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Health details the readiness of a service, and of the services it is
+// composed of, if any.
+type Health struct {
+	Service  string   `json:"service"`
+	Ready    bool     `json:"ready"`
+	Checks   []Check  `json:"checks,omitempty"`
+	Services []Health `json:"services,omitempty"`
+}
+
+// HealthReporter is implemented by services that detail their readiness.
+// Services embedding [Service] implement it with its registered checks.
+type HealthReporter interface {
+	Health(ctx context.Context) Health
+}
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// AddHealthCheck registers a check of the service readiness.
+func (s *Service) AddHealthCheck(name string, check HealthCheck) {
+	s.checksMutex.Lock()
+	defer s.checksMutex.Unlock()
+	s.checks = append(s.checks, namedCheck{name: name, check: check})
+}
+
+// Health runs the registered checks of the service. It is ready when all
+// of them pass.
+func (s *Service) Health(ctx context.Context) Health {
+	s.checksMutex.Lock()
+	checks := append([]namedCheck{}, s.checks...)
+	s.checksMutex.Unlock()
+
+	health := Health{Service: s.Name, Ready: true, Checks: []Check{}}
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+		err := c.check(checkCtx)
+		cancel()
+		result := Check{Name: c.name, Ok: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+			health.Ready = false
+		}
+		health.Checks = append(health.Checks, result)
+	}
+	return health
+}
+
+// Heartbeat records that Tick is making progress. Tick calls it when it starts
+// and ends; long ticks also call it between the inputs, epochs or claims they
+// handle, so TickCheck does not fail while they run.
+func (s *Service) Heartbeat() {
+	s.heartbeat.Store(time.Now().UnixNano())
+}
+
+// TickCheck fails when no Tick started yet, or when none made progress for a
+// few poll intervals. Failed ticks count as progress: the errors of single
+// applications do not make the service unready, the checks of its
+// dependencies do.
+func (s *Service) TickCheck(ctx context.Context) error {
+	last := s.heartbeat.Load()
+	if last == 0 {
+		return errors.New("no tick yet")
+	}
+	age := time.Since(time.Unix(0, last))
+	if age > maxMissedTicks*s.PollInterval {
+		return fmt.Errorf("no tick progress for %v", age.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingService struct {
+	tickingService
+}
+
+func (s *failingService) Tick() []error {
+	return []error{errors.New("application failed")}
+}
+
+func TestTickCheck(t *testing.T) {
+	t.Run("FailedTick", func(t *testing.T) {
+		s := &failingService{}
+		err := Create(context.Background(), &CreateInfo{
+			Name:         "failing",
+			Impl:         s,
+			PollInterval: time.Minute,
+		}, &s.Service)
+		require.Nil(t, err)
+		require.ErrorContains(t, s.TickCheck(context.Background()), "no tick yet")
+
+		require.Len(t, s.Service.Tick(), 1)
+		require.Nil(t, s.TickCheck(context.Background()))
+	})
+
+	t.Run("LongTick", func(t *testing.T) {
+		s := newTickingService(t, time.Minute)
+		s.PollInterval = 20 * time.Millisecond
+		go s.Service.Tick()
+		<-s.started
+		require.Nil(t, s.TickCheck(context.Background()))
+
+		require.Eventually(t, func() bool {
+			return s.TickCheck(context.Background()) != nil
+		}, time.Second, time.Millisecond)
+		s.Heartbeat()
+		require.Nil(t, s.TickCheck(context.Background()))
+		close(s.release)
+	})
+}
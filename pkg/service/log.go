@@ -0,0 +1,57 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// LogFormat selects how the log records are written.
+type LogFormat uint8
+
+const (
+	// LogFormatText writes human readable lines, optionally colored.
+	LogFormatText LogFormat = iota
+	// LogFormatJSON writes a JSON object per line, for log pipelines.
+	LogFormatJSON
+)
+
+func (f LogFormat) String() string {
+	switch f {
+	case LogFormatText:
+		return "text"
+	case LogFormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("LogFormat(%d)", uint8(f))
+	}
+}
+
+// ParseLogFormat parses one of "text" or "json".
+func ParseLogFormat(s string) (LogFormat, error) {
+	switch s {
+	case "text":
+		return LogFormatText, nil
+	case "json":
+		return LogFormatJSON, nil
+	default:
+		return LogFormatText, fmt.Errorf("invalid log format: %s", s)
+	}
+}
+
+// OpenLogOutput returns the destination of the log records: "stdout",
+// "stderr" or the path of a file, which is appended to. The file stays open
+// until the process exits, so open it once and share it between services.
+func OpenLogOutput(name string) (io.Writer, error) {
+	switch name {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		return os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) // nolint: mnd
+	}
+}
@@ -0,0 +1,59 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogFormat(t *testing.T) {
+	format, err := ParseLogFormat("json")
+	require.Nil(t, err)
+	require.Equal(t, LogFormatJSON, format)
+
+	format, err = ParseLogFormat("text")
+	require.Nil(t, err)
+	require.Equal(t, LogFormatText, format)
+
+	_, err = ParseLogFormat("xml")
+	require.ErrorContains(t, err, "invalid log format")
+}
+
+func TestNewLoggerToJSON(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLoggerTo(&output, LogFormatJSON, slog.LevelInfo, true).With("service", "test")
+	logger.Info("Processing input", "application", "app", "epoch_index", 1, "input_index", 2)
+	logger.Debug("Filtered out")
+
+	var record map[string]any
+	require.Nil(t, json.Unmarshal(output.Bytes(), &record))
+	require.Equal(t, "Processing input", record["msg"])
+	require.Equal(t, "test", record["service"])
+	require.Equal(t, "app", record["application"])
+	require.Equal(t, float64(1), record["epoch_index"])
+	require.Equal(t, float64(2), record["input_index"])
+}
+
+func TestOpenLogOutput(t *testing.T) {
+	output, err := OpenLogOutput("stderr")
+	require.Nil(t, err)
+	require.Equal(t, os.Stderr, output)
+
+	path := filepath.Join(t.TempDir(), "node.log")
+	output, err = OpenLogOutput(path)
+	require.Nil(t, err)
+	defer output.(*os.File).Close()
+	NewLoggerTo(output, LogFormatText, slog.LevelInfo, false).Info("Written")
+
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Contains(t, string(contents), "Written")
+}
@@ -0,0 +1,108 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultNotifyRetryInterval is how long a [Subscriber] waits before
+// reconnecting after losing its database connection.
+const DefaultNotifyRetryInterval = 5 * time.Second
+
+// Subscriber listens to Postgres notifications (LISTEN/NOTIFY) and signals C
+// whenever one arrives on any of its channels. Notifications are coalesced:
+// C holds at most one pending signal, so a burst wakes the receiver once.
+type Subscriber struct {
+	C             <-chan struct{}
+	RetryInterval time.Duration
+
+	c        chan struct{}
+	conn     string
+	channels []string
+	logger   *slog.Logger
+}
+
+// IsNotifyConnection reports whether conn is a database that supports notifications.
+func IsNotifyConnection(conn string) bool {
+	lowerConn := strings.ToLower(conn)
+	return strings.HasPrefix(lowerConn, "postgres://") || strings.HasPrefix(lowerConn, "postgresql://")
+}
+
+func NewSubscriber(conn string, channels []string, logger *slog.Logger) (*Subscriber, error) {
+	if !IsNotifyConnection(conn) {
+		return nil, fmt.Errorf("%w: notifications require a Postgres database", ErrInvalid)
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("%w: no notification channels", ErrInvalid)
+	}
+	c := make(chan struct{}, 1)
+	return &Subscriber{
+		C:             c,
+		c:             c,
+		conn:          conn,
+		channels:      channels,
+		logger:        logger,
+		RetryInterval: DefaultNotifyRetryInterval,
+	}, nil
+}
+
+func (sub *Subscriber) wake() {
+	select {
+	case sub.c <- struct{}{}:
+	default: // a wake up is already pending
+	}
+}
+
+// Run listens until ctx is canceled, reconnecting whenever the connection is lost.
+func (sub *Subscriber) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := sub.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		sub.logger.Warn("Lost database notifications, reconnecting",
+			"error", err,
+			"retry_interval", sub.RetryInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sub.RetryInterval):
+		}
+	}
+}
+
+func (sub *Subscriber) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, sub.conn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	for _, channel := range sub.channels {
+		_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+		if err != nil {
+			return err
+		}
+	}
+	sub.logger.Debug("Listening to database notifications", "channels", sub.channels)
+
+	// notifications sent while disconnected are lost, so check right away
+	sub.wake()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		sub.logger.Debug("Notification", "channel", n.Channel, "payload", n.Payload)
+		sub.wake()
+	}
+}
@@ -0,0 +1,80 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingService struct {
+	Service
+	ticks chan struct{}
+}
+
+func (s *countingService) Alive() bool     { return true }
+func (s *countingService) Ready() bool     { return true }
+func (s *countingService) Reload() []error { return nil }
+func (s *countingService) Stop(bool) []error {
+	return nil
+}
+func (s *countingService) Tick() []error {
+	s.ticks <- struct{}{}
+	return nil
+}
+
+func TestNewSubscriber(t *testing.T) {
+	_, err := NewSubscriber("sqlite://node.db", []string{"inputs"}, slog.Default())
+	require.ErrorIs(t, err, ErrInvalid)
+	_, err = NewSubscriber("postgres://localhost/node", nil, slog.Default())
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestSubscriberCoalescesNotifications(t *testing.T) {
+	sub, err := NewSubscriber("postgres://localhost/node", []string{"inputs"}, slog.Default())
+	require.Nil(t, err)
+
+	sub.wake()
+	sub.wake()
+	require.Len(t, sub.C, 1)
+	<-sub.C
+	require.Empty(t, sub.C)
+}
+
+func TestWakeupTicksBeforePollInterval(t *testing.T) {
+	s := &countingService{ticks: make(chan struct{}, 1)}
+	err := Create(context.Background(), &CreateInfo{
+		Name:         "counting",
+		Impl:         s,
+		PollInterval: time.Hour,
+	}, &s.Service)
+	require.Nil(t, err)
+
+	sub, err := NewSubscriber("postgres://localhost/node", []string{"inputs"}, s.Logger)
+	require.Nil(t, err)
+	s.Wakeup = sub.C
+
+	served := make(chan error)
+	go func() { served <- s.Serve() }()
+
+	select {
+	case <-s.ticks:
+	case <-time.After(time.Second):
+		require.Fail(t, "no tick when serving starts")
+	}
+
+	sub.wake()
+	select {
+	case <-s.ticks:
+	case <-time.After(time.Second):
+		require.Fail(t, "no tick after a notification")
+	}
+
+	require.Empty(t, s.Shutdown(false))
+	require.Nil(t, <-served)
+}
@@ -0,0 +1,48 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"log/slog"
+	"syscall"
+	"time"
+)
+
+// RequestReload makes Serve call Reload, as if the process received a SIGHUP.
+// Pending requests are merged into a single reload.
+func (s *Service) RequestReload() {
+	select {
+	case s.Sighup <- syscall.SIGHUP:
+	default:
+	}
+}
+
+// SetLogLevel changes the level of the service logger, if it was created by
+// the service.
+func (s *Service) SetLogLevel(level slog.Level) {
+	if s.LogLevel == nil || s.LogLevel.Level() == level {
+		return
+	}
+	s.Logger.Info("Changing the log level", "from", s.LogLevel.Level(), "to", level)
+	s.LogLevel.Set(level)
+}
+
+// SetPollInterval changes the interval between ticks. It must be called from
+// the goroutine that runs Serve, like Reload and Tick.
+func (s *Service) SetPollInterval(interval time.Duration) {
+	if interval <= 0 || interval == s.PollInterval {
+		return
+	}
+	s.Logger.Info("Changing the poll interval", "from", s.PollInterval, "to", interval)
+	s.PollInterval = interval
+	s.Ticker.Reset(interval)
+}
+
+// ReportRestartRequired warns about configuration variables that changed but
+// only take effect when the service restarts.
+func (s *Service) ReportRestartRequired(variables []string) {
+	if len(variables) > 0 {
+		s.Logger.Warn("Configuration changes require a restart", "variables", variables)
+	}
+}
@@ -18,13 +18,25 @@
 //   - Name: string representing this service, will show up in the logs.
 //   - Impl: what to use as the ServiceImpl interface, use itself in this case.
 //   - LogLevel: One of 'debug', 'info', 'warn', 'error'.
+//   - LogFormat, LogOutput: Write the logs as text or JSON, to stdout or another writer.
 //   - ProcOwner: Declare this as the process owner and run additional setup.
 //   - TelemetryCreate: Setup a http.ServeMux and serve a HTTP endpoint in a go routine.
 //   - TelemetryAddress: Address to use when TelemetryCreate is enabled.
+//   - NotifyConnection: Postgres database to LISTEN on for NotifyChannels.
+//     A notification wakes up Tick before PollInterval, which is kept as a fallback.
 //
-// Hook up the `livez` and `readyz` handlers into the HTTP mux.
+// Hook up the `livez` and `readyz` handlers into the HTTP mux, along with
+// `metrics`, which exposes the Prometheus metrics registered by the services.
 // Then Run the server
 //
+// The `readyz` handler reports the result of the checks registered with
+// AddHealthCheck as JSON, like the database connection or whether Tick keeps
+// making progress (TickCheck).
+//
+// On SIGHUP, or RequestReload, Serve calls Reload, which applies the settings
+// that may change at runtime, like the log level (SetLogLevel) and the poll
+// interval (SetPollInterval), and reports the others (ReportRestartRequired).
+//
 // Example shows the creation of a [DummyService] by calling [CreateDummy].
 //
 //	package main
@@ -56,17 +68,21 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/cartesi/rollups-node/internal/version"
 	"github.com/lmittmann/tint"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -87,7 +103,9 @@ type IService interface {
 	Reload() []error
 	Tick() []error
 	Stop(bool) []error
+	Shutdown(bool) []error
 	Serve() error
+	RequestReload()
 	String() string
 }
 
@@ -96,10 +114,15 @@ type CreateInfo struct {
 	Name                 string
 	LogLevel             slog.Level
 	LogColor             bool
+	LogFormat            LogFormat
+	LogOutput            io.Writer // defaults to stdout
 	EnableSignalHandling bool
 	TelemetryCreate      bool
 	TelemetryAddress     string
 	PollInterval         time.Duration
+	ShutdownTimeout      time.Duration // defaults to DefaultShutdownTimeout
+	NotifyConnection     string
+	NotifyChannels       []string
 	Impl                 ServiceImpl
 	ServeMux             *http.ServeMux
 	Context              context.Context
@@ -107,19 +130,30 @@ type CreateInfo struct {
 
 // Service stores runtime information.
 type Service struct {
-	Running       atomic.Bool
-	Name          string
-	Impl          ServiceImpl
-	Logger        *slog.Logger
-	Ticker        *time.Ticker
-	PollInterval  time.Duration
-	Context       context.Context
-	Cancel        context.CancelFunc
-	Sighup        chan os.Signal // SIGHUP to reload
-	Sigint        chan os.Signal // SIGINT to exit gracefully
-	ServeMux      *http.ServeMux
-	Telemetry     *http.Server
-	TelemetryFunc func() error
+	Running      atomic.Bool
+	Name         string
+	Impl         ServiceImpl
+	Logger       *slog.Logger
+	LogLevel     *slog.LevelVar // level of Logger, unless it was provided
+	Ticker       *time.Ticker
+	PollInterval time.Duration
+	// how long Stop waits for the work in progress before canceling it
+	ShutdownTimeout time.Duration
+	Wakeup          <-chan struct{} // wakes up Tick before the Ticker
+	Context         context.Context
+	Cancel          context.CancelFunc
+	Sighup          chan os.Signal // SIGHUP to reload
+	Sigint          chan os.Signal // SIGINT to exit gracefully
+	ServeMux        *http.ServeMux
+	Telemetry       *http.Server
+	TelemetryFunc   func() error
+
+	checks      []namedCheck
+	checksMutex sync.Mutex
+	heartbeat   atomic.Int64 // last start, progress or end of Tick, in Unix nanoseconds
+	tickMutex   sync.Mutex   // held while Tick runs
+	stopping    atomic.Bool
+	stopped     chan struct{} // closed once Shutdown is done
 }
 
 // Create a service by:
@@ -135,12 +169,15 @@ func Create(ctx context.Context, c *CreateInfo, s *Service) error {
 	}
 
 	s.Running.Store(false)
+	s.stopped = make(chan struct{})
 	s.Name = c.Name
 	s.Impl = c.Impl
 
 	// log
 	if s.Logger == nil {
-		s.Logger = NewLogger(c.LogLevel, c.LogColor).With("service", s.Name)
+		s.LogLevel = new(slog.LevelVar)
+		s.LogLevel.Set(c.LogLevel)
+		s.Logger = NewLoggerTo(c.LogOutput, c.LogFormat, s.LogLevel, c.LogColor).With("service", s.Name)
 	}
 
 	// context and cancelation
@@ -163,15 +200,39 @@ func Create(ctx context.Context, c *CreateInfo, s *Service) error {
 		s.Ticker = time.NewTicker(s.PollInterval)
 	}
 
-	// signal handling
-	if c.EnableSignalHandling {
-		if s.Sighup == nil {
-			s.Sighup = make(chan os.Signal, 1)
+	if s.ShutdownTimeout == 0 {
+		if c.ShutdownTimeout == 0 {
+			c.ShutdownTimeout = DefaultShutdownTimeout
+		}
+		s.ShutdownTimeout = c.ShutdownTimeout
+	}
+
+	// notifications
+	if s.Wakeup == nil && c.NotifyConnection != "" && len(c.NotifyChannels) > 0 {
+		if IsNotifyConnection(c.NotifyConnection) {
+			sub, err := NewSubscriber(c.NotifyConnection, c.NotifyChannels, s.Logger)
+			if err != nil {
+				return err
+			}
+			s.Wakeup = sub.C
+			go sub.Run(s.Context)
+		} else {
+			s.Logger.Info("Database does not support notifications, polling only",
+				"poll_interval", s.PollInterval)
+		}
+	}
+
+	// signal handling, reloads may also be requested with RequestReload
+	if s.Sighup == nil {
+		s.Sighup = make(chan os.Signal, 1)
+		if c.EnableSignalHandling {
 			signal.Notify(s.Sighup, syscall.SIGHUP)
 		}
+	}
+	if c.EnableSignalHandling {
 		if s.Sigint == nil {
 			s.Sigint = make(chan os.Signal, 1)
-			signal.Notify(s.Sigint, syscall.SIGINT)
+			signal.Notify(s.Sigint, syscall.SIGINT, syscall.SIGTERM)
 		}
 	}
 
@@ -222,9 +283,17 @@ func (s *Service) Reload() []error {
 }
 
 func (s *Service) Tick() []error {
+	s.tickMutex.Lock()
+	defer s.tickMutex.Unlock()
+	if s.stopping.Load() {
+		return nil
+	}
+
+	s.Heartbeat()
 	start := time.Now()
 	errs := s.Impl.Tick()
 	elapsed := time.Since(start)
+	s.Heartbeat()
 
 	if len(errs) > 0 {
 		s.Logger.Error("Tick",
@@ -238,14 +307,42 @@ func (s *Service) Tick() []error {
 }
 
 func (s *Service) Stop(force bool) []error {
+	return s.Shutdown(force)
+}
+
+// Shutdown stops the service, once: it stops starting new ticks, waits for
+// the one in progress, or cancels it if force is set or ShutdownTimeout
+// passes, lets Impl.Stop release its resources and then stops the telemetry
+// server and the background work bound to Context.
+// Types that embed Service shadow Stop with their ServiceImpl one, so callers
+// use Shutdown to stop them.
+func (s *Service) Shutdown(force bool) []error {
+	if !s.stopping.CompareAndSwap(false, true) {
+		return nil
+	}
 	start := time.Now()
+	s.Logger.Info("Stopping", "force", force, "timeout", s.ShutdownTimeout)
+	s.Running.Store(false)
+	if force {
+		s.cancel()
+	}
+	s.waitForTick()
+
 	errs := s.Impl.Stop(force)
 	if s.Telemetry != nil {
-		s.Telemetry.Shutdown(s.Context)
+		s.Logger.Info("Stopping the telemetry server")
+		ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+		if err := s.Telemetry.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		cancel()
+	}
+	s.cancel()
+	if s.stopped != nil {
+		close(s.stopped)
 	}
 	elapsed := time.Since(start)
 
-	s.Running.Store(false)
 	if len(errs) > 0 {
 		s.Logger.Error("Stop",
 			"force", force,
@@ -256,24 +353,32 @@ func (s *Service) Stop(force bool) []error {
 			"force", force,
 			"duration", elapsed)
 	}
-	return nil
+	return errs
 }
 
 func (s *Service) Serve() error {
 	s.Running.Store(true)
+	if s.Sigint != nil {
+		go s.handleSignals()
+	}
 	s.Tick()
 	for s.Running.Load() {
 		select {
 		case <-s.Sighup:
 			s.Reload()
-		case <-s.Sigint:
-			s.Stop(false)
 		case <-s.Context.Done():
-			s.Stop(true)
+			s.Shutdown(true)
 		case <-s.Ticker.C:
 			s.Tick()
+		case <-s.Wakeup:
+			s.Tick()
+			s.Ticker.Reset(s.PollInterval)
 		}
 	}
+	// the shutdown may have started elsewhere, wait for it to finish
+	if s.stopped != nil {
+		<-s.stopped
+	}
 	return nil
 }
 
@@ -281,15 +386,34 @@ func (s *Service) String() string {
 	return s.Name
 }
 
-func NewLogger(level slog.Level, color bool) *slog.Logger {
+// NewLogger creates a logger that writes text to stdout.
+func NewLogger(level slog.Leveler, color bool) *slog.Logger {
+	return NewLoggerTo(os.Stdout, LogFormatText, level, color)
+}
+
+// NewLoggerTo creates a logger that writes in format to output, or to stdout
+// if it is nil. Colors only apply to the text format.
+func NewLoggerTo(output io.Writer, format LogFormat, level slog.Leveler, color bool) *slog.Logger {
+	if output == nil {
+		output = os.Stdout
+	}
+	addSource := level.Level() == slog.LevelDebug
+
+	if format == LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{
+			Level:     level,
+			AddSource: addSource,
+		}))
+	}
+
 	opts := &tint.Options{
 		Level:     level,
-		AddSource: level == slog.LevelDebug,
+		AddSource: addSource,
 		// RFC3339 with milliseconds and without timezone
 		TimeFormat: "2006-01-02T15:04:05.000",
 		NoColor:    !color,
 	}
-	handler := tint.NewHandler(os.Stdout, opts)
+	handler := tint.NewHandler(output, opts)
 	return slog.New(handler)
 }
 
@@ -301,6 +425,7 @@ func (s *Service) CreateDefaultTelemetry(
 ) (*http.Server, func() error) {
 	s.ServeMux.Handle("/readyz", http.HandlerFunc(s.ReadyHandler))
 	s.ServeMux.Handle("/livez", http.HandlerFunc(s.AliveHandler))
+	s.ServeMux.Handle("/metrics", promhttp.Handler())
 
 	server := &http.Server{
 		Addr:     addr,
@@ -326,13 +451,22 @@ func (s *Service) CreateDefaultTelemetry(
 	}
 }
 
-// HTTP handler for `/s.Name/readyz` that exposes the value of Ready()
+// HTTP handler for `/s.Name/readyz` that details the health of the service
+// as JSON. It responds with an error status when the service is not ready.
 func (s *Service) ReadyHandler(w http.ResponseWriter, r *http.Request) {
-	if !s.Ready() {
-		http.Error(w, s.Name+": ready check failed",
-			http.StatusInternalServerError)
+	var health Health
+	if reporter, ok := s.Impl.(HealthReporter); ok {
+		health = reporter.Health(r.Context())
 	} else {
-		fmt.Fprintf(w, "%s: ready\n", s.Name)
+		health = Health{Service: s.Name, Ready: s.Ready()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !health.Ready {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		s.Logger.Warn("Failed to write the health report", "error", err)
 	}
 }
 
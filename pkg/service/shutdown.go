@@ -0,0 +1,59 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"time"
+)
+
+// DefaultShutdownTimeout is how long Stop waits for the work in progress when
+// CreateInfo.ShutdownTimeout is not set.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Stopping reports whether Stop was called. Long running ticks check it
+// between inputs, claims or epochs to leave as soon as the current one is
+// done.
+func (s *Service) Stopping() bool {
+	return s.stopping.Load()
+}
+
+// waitForTick blocks until the Tick in progress, if any, returns. When it
+// takes longer than ShutdownTimeout the service context is canceled to
+// interrupt it.
+func (s *Service) waitForTick() {
+	done := make(chan struct{})
+	go func() {
+		s.tickMutex.Lock()
+		defer s.tickMutex.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(s.ShutdownTimeout):
+		s.Logger.Warn("The work in progress did not finish in time, canceling it",
+			"timeout", s.ShutdownTimeout)
+		s.cancel()
+	}
+	<-done
+}
+
+// handleSignals shuts the service down on SIGINT or SIGTERM. It runs apart
+// from the Serve loop, so the Tick in progress sees Stopping and
+// ShutdownTimeout starts counting as soon as the signal arrives.
+func (s *Service) handleSignals() {
+	select {
+	case sig := <-s.Sigint:
+		s.Logger.Info("Received signal", "signal", sig)
+		s.Shutdown(false)
+	case <-s.Context.Done():
+	}
+}
+
+func (s *Service) cancel() {
+	if s.Cancel != nil {
+		s.Cancel()
+	}
+}
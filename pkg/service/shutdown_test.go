@@ -0,0 +1,111 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package service
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tickingService struct {
+	Service
+	started  chan struct{}
+	release  chan struct{}
+	finished bool
+	stops    int
+}
+
+func (s *tickingService) Alive() bool     { return true }
+func (s *tickingService) Ready() bool     { return true }
+func (s *tickingService) Reload() []error { return nil }
+func (s *tickingService) Tick() []error {
+	close(s.started)
+	select {
+	case <-s.release:
+		s.finished = true
+	case <-s.Context.Done():
+	}
+	return nil
+}
+func (s *tickingService) Stop(bool) []error {
+	s.stops++
+	return nil
+}
+
+func newTickingService(t *testing.T, timeout time.Duration) *tickingService {
+	s := &tickingService{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	err := Create(context.Background(), &CreateInfo{
+		Name:            "ticking",
+		Impl:            s,
+		ShutdownTimeout: timeout,
+	}, &s.Service)
+	require.Nil(t, err)
+	return s
+}
+
+func TestShutdownWaitsForTick(t *testing.T) {
+	s := newTickingService(t, time.Minute)
+	go s.Service.Tick()
+	<-s.started
+
+	time.AfterFunc(10*time.Millisecond, func() { close(s.release) })
+	require.Empty(t, s.Shutdown(false))
+	require.True(t, s.finished)
+	require.Equal(t, 1, s.stops)
+	require.True(t, s.Stopping())
+
+	require.Empty(t, s.Shutdown(false))
+	require.Equal(t, 1, s.stops, "the second shutdown does nothing")
+	require.Empty(t, s.Service.Tick())
+}
+
+func TestShutdownCancelsSlowTick(t *testing.T) {
+	s := newTickingService(t, 10*time.Millisecond)
+	go s.Service.Tick()
+	<-s.started
+
+	require.Empty(t, s.Shutdown(false))
+	require.False(t, s.finished)
+	require.Equal(t, 1, s.stops)
+	require.ErrorIs(t, s.Context.Err(), context.Canceled)
+}
+
+func TestSignalStopsDuringTick(t *testing.T) {
+	s := newTickingService(t, time.Minute)
+	s.Sigint = make(chan os.Signal, 1)
+	served := make(chan error)
+	go func() { served <- s.Serve() }()
+	<-s.started
+
+	s.Sigint <- syscall.SIGTERM
+	require.Eventually(t, s.Stopping, time.Second, time.Millisecond)
+	require.Zero(t, s.stops, "the shutdown waits for the tick")
+
+	close(s.release)
+	require.Nil(t, <-served)
+	require.True(t, s.finished)
+	require.Equal(t, 1, s.stops)
+}
+
+func TestSignalCancelsSlowTick(t *testing.T) {
+	s := newTickingService(t, 10*time.Millisecond)
+	s.Sigint = make(chan os.Signal, 1)
+	served := make(chan error)
+	go func() { served <- s.Serve() }()
+	<-s.started
+
+	s.Sigint <- syscall.SIGINT
+	require.Nil(t, <-served)
+	require.False(t, s.finished)
+	require.Equal(t, 1, s.stops)
+	require.ErrorIs(t, s.Context.Err(), context.Canceled)
+}
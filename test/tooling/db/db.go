@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/cartesi/rollups-node/internal/repository/memory"
 	"github.com/cartesi/rollups-node/internal/repository/postgres/schema"
+	sqliteschema "github.com/cartesi/rollups-node/internal/repository/sqlite/schema"
 )
 
 func GetTestDatabaseEndpoint() (string, error) {
@@ -38,3 +40,36 @@ func SetupTestPostgres(endpoint string) error {
 
 	return nil
 }
+
+func SetupTestSQLite(endpoint string) error {
+
+	schema, err := sqliteschema.New(endpoint)
+	if err != nil {
+		return err
+	}
+	defer schema.Close()
+
+	err = schema.Downgrade()
+	if err != nil {
+		return err
+	}
+
+	err = schema.Upgrade()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetupTestDatabase resets the schema of the database selected by endpoint,
+// either Postgres or SQLite. In-memory repositories always start empty.
+func SetupTestDatabase(endpoint string) error {
+	if memory.IsConnectionString(endpoint) {
+		return nil
+	}
+	if sqliteschema.IsConnectionString(endpoint) {
+		return SetupTestSQLite(endpoint)
+	}
+	return SetupTestPostgres(endpoint)
+}
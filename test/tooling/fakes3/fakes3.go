@@ -0,0 +1,99 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package fakes3 implements a minimal stand-in for an S3-compatible server
+// such as MinIO, for the tests of the remote storages.
+package fakes3
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server answers path-style object and listing requests from memory.
+type Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func New() *Server {
+	return &Server{objects: map[string][]byte{}}
+}
+
+// SetCredentials makes the AWS SDK use fixed credentials for the test,
+// ignoring the configuration files of the user.
+func SetCredentials(t testing.TB) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "minioadmin")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "minioadmin")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+}
+
+// Keys returns the paths of the stored objects, sorted, as /bucket/key.
+func (f *Server) Keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+type listBucketResult struct {
+	XMLName  xml.Name `xml:"ListBucketResult"`
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated bool `xml:"IsTruncated"`
+}
+
+func (f *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			bucket := strings.Trim(r.URL.Path, "/")
+			prefix := "/" + bucket + "/" + r.URL.Query().Get("prefix")
+			var result listBucketResult
+			for path := range f.objects {
+				if strings.HasPrefix(path, prefix) {
+					result.Contents = append(result.Contents, struct {
+						Key string `xml:"Key"`
+					}{strings.TrimPrefix(path, "/"+bucket+"/")})
+				}
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			_ = xml.NewEncoder(w).Encode(result)
+			return
+		}
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>`+
+				`<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,796 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// Package repotest implements a test suite shared by the repository.Repository backends.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cartesi/rollups-node/internal/model"
+	"github.com/cartesi/rollups-node/internal/repository"
+)
+
+// RepositorySuite checks the behavior every repository.Repository implementation
+// must share. NewRepository is called before each test and must return an empty repository.
+type RepositorySuite struct {
+	suite.Suite
+	NewRepository func(t *testing.T) repository.Repository
+
+	ctx  context.Context
+	repo repository.Repository
+	app  *model.Application
+}
+
+func (s *RepositorySuite) SetupTest() {
+	var err error
+	s.ctx = context.Background()
+	s.repo = s.NewRepository(s.T())
+
+	s.app = &model.Application{
+		Name:                "test-app",
+		IApplicationAddress: common.HexToAddress("0x01"),
+		IConsensusAddress:   common.HexToAddress("0x02"),
+		IInputBoxAddress:    common.HexToAddress("0x03"),
+		TemplateHash:        common.HexToHash("0x04"),
+		TemplateURI:         "/template/path",
+		DataAvailability:    model.DataAvailability_InputBox[:],
+		EpochLength:         10,
+		State:               model.ApplicationState_Enabled,
+	}
+	s.app.ID, err = s.repo.CreateApplication(s.ctx, s.app)
+	s.Require().Nil(err)
+}
+
+func (s *RepositorySuite) TearDownTest() {
+	s.repo.Close()
+}
+
+func (s *RepositorySuite) TestApplicationStateHistory() {
+	reason := "machine server crashed"
+	s.Require().Nil(s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Inoperable, &reason))
+	// setting the same state and reason again is not a change
+	s.Require().Nil(s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Inoperable, &reason))
+	s.Require().Nil(s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Enabled, nil))
+
+	// an invalid change is not recorded
+	s.Error(s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Inoperable, nil))
+
+	history, err := s.repo.ListApplicationStateHistory(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Require().Len(history, 2)
+	s.Equal(model.ApplicationState_Enabled, history[0].PreviousState)
+	s.Equal(model.ApplicationState_Inoperable, history[0].State)
+	s.Equal(reason, *history[0].Reason)
+	s.Equal(s.app.ID, history[0].ApplicationID)
+	s.Equal(model.ApplicationState_Inoperable, history[1].PreviousState)
+	s.Equal(model.ApplicationState_Enabled, history[1].State)
+	s.Nil(history[1].Reason)
+	s.Less(history[0].ID, history[1].ID)
+
+	s.Require().Nil(s.repo.DeleteApplication(s.ctx, s.app.ID))
+	history, err = s.repo.ListApplicationStateHistory(s.ctx, s.app.Name)
+	s.Nil(err)
+	s.Empty(history)
+}
+
+func (s *RepositorySuite) TestApplicationReasonCode() {
+	reason := "machine server crashed"
+	code := model.ReasonCode_MachineServerFailure
+	s.Require().Nil(s.repo.SetApplicationInoperable(s.ctx, s.app.ID, code, reason))
+
+	app, err := s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(model.ApplicationState_Inoperable, app.State)
+	s.Equal(reason, *app.Reason)
+	s.Equal(&code, app.ReasonCode)
+
+	state := model.ApplicationState_Inoperable
+	apps, _, err := s.repo.ListApplications(s.ctx,
+		repository.ApplicationFilter{State: &state}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(apps, 1)
+	s.Equal(&code, apps[0].ReasonCode)
+
+	// setting the state otherwise clears the code
+	s.Require().Nil(s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Inoperable, &reason))
+	app, err = s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Nil(app.ReasonCode)
+
+	history, err := s.repo.ListApplicationStateHistory(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Len(history, 1)
+
+	// the schema accepts every code
+	for _, code := range model.ReasonCodeAllValues {
+		s.Require().Nil(s.repo.SetApplicationInoperable(s.ctx, s.app.ID, code, reason), code)
+		app, err = s.repo.GetApplication(s.ctx, s.app.Name)
+		s.Require().Nil(err)
+		s.Equal(&code, app.ReasonCode)
+	}
+}
+
+func (s *RepositorySuite) createEpoch(index uint64, status model.EpochStatus, inputs ...*model.Input) {
+	epoch := &model.Epoch{
+		ApplicationID: s.app.ID,
+		Index:         index,
+		VirtualIndex:  index,
+		FirstBlock:    index * s.app.EpochLength,
+		LastBlock:     index*s.app.EpochLength + s.app.EpochLength - 1,
+		Status:        status,
+	}
+	for _, input := range inputs {
+		input.EpochIndex = index
+		input.Status = model.InputCompletionStatus_None
+	}
+	err := s.repo.CreateEpochsAndInputs(s.ctx, s.app.Name,
+		map[*model.Epoch][]*model.Input{epoch: inputs}, epoch.LastBlock)
+	s.Require().Nil(err)
+}
+
+func (s *RepositorySuite) TestApplication() {
+	byName, err := s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	byAddress, err := s.repo.GetApplication(s.ctx, s.app.IApplicationAddress.String())
+	s.Require().Nil(err)
+	s.Equal(byName.ID, byAddress.ID)
+	s.Equal(s.app.IApplicationAddress, byName.IApplicationAddress)
+	s.Equal(s.app.DataAvailability, byName.DataAvailability)
+	s.Equal(model.ApplicationState_Enabled, byName.State)
+
+	missing, err := s.repo.GetApplication(s.ctx, "missing")
+	s.Nil(err)
+	s.Nil(missing)
+
+	reason := "broken"
+	err = s.repo.UpdateApplicationState(s.ctx, s.app.ID, model.ApplicationState_Inoperable, &reason)
+	s.Require().Nil(err)
+
+	state := model.ApplicationState_Inoperable
+	apps, total, err := s.repo.ListApplications(s.ctx,
+		repository.ApplicationFilter{State: &state}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(1), total)
+	s.Require().Len(apps, 1)
+	s.Equal(reason, *apps[0].Reason)
+
+	params, err := s.repo.GetExecutionParameters(s.ctx, s.app.ID)
+	s.Require().Nil(err)
+	s.Equal(uint64(100), params.SnapshotInputInterval)
+	s.Equal(time.Hour, params.SnapshotTimeInterval)
+	s.Equal(uint64(1<<40), params.SnapshotCycleInterval)
+	params.AdvanceMaxCycles = 42
+	params.SnapshotPolicy = model.SnapshotPolicy_EveryNCycles
+	params.SnapshotCycleInterval = 1 << 30
+	s.Require().Nil(s.repo.UpdateExecutionParameters(s.ctx, params))
+	params, err = s.repo.GetExecutionParameters(s.ctx, s.app.ID)
+	s.Require().Nil(err)
+	s.Equal(uint64(42), params.AdvanceMaxCycles)
+	s.Equal(model.SnapshotPolicy_EveryNCycles, params.SnapshotPolicy)
+	s.Equal(uint64(1<<30), params.SnapshotCycleInterval)
+
+	s.Require().Nil(s.repo.DeleteApplication(s.ctx, s.app.ID))
+	params, err = s.repo.GetExecutionParameters(s.ctx, s.app.ID)
+	s.Nil(err)
+	s.Nil(params)
+}
+
+func (s *RepositorySuite) TestStoreAdvanceResult() {
+	s.createEpoch(0, model.EpochStatus_Closed,
+		&model.Input{Index: 0, BlockNumber: 1, RawData: []byte("a"), TransactionReference: common.HexToHash("0xa")},
+		&model.Input{Index: 1, BlockNumber: 2, RawData: []byte("b"), TransactionReference: common.HexToHash("0xb")},
+	)
+
+	input, err := s.repo.GetInput(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Nil(input.Cycles)
+	s.Nil(input.ExecutionTime)
+
+	machineHash := common.HexToHash("0xff")
+	err = s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+		InputIndex:    0,
+		Status:        model.InputCompletionStatus_Accepted,
+		Outputs:       [][]byte{[]byte("out0"), []byte("out1")},
+		Reports:       [][]byte{[]byte("rep0")},
+		OutputsHash:   common.HexToHash("0xee"),
+		MachineHash:   &machineHash,
+		Cycles:        1234,
+		ExecutionTime: 5 * time.Millisecond,
+	})
+	s.Require().Nil(err)
+
+	input, err = s.repo.GetInput(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Require().NotNil(input.Cycles)
+	s.Equal(uint64(1234), *input.Cycles)
+	s.Require().NotNil(input.ExecutionTime)
+	s.Equal(5*time.Millisecond, *input.ExecutionTime)
+	s.Require().NotNil(input.OutputsCount)
+	s.Equal(uint64(2), *input.OutputsCount)
+	s.Require().NotNil(input.OutputsSize)
+	s.Equal(uint64(8), *input.OutputsSize)
+	s.Require().NotNil(input.ReportsCount)
+	s.Equal(uint64(1), *input.ReportsCount)
+	s.Require().NotNil(input.ReportsSize)
+	s.Equal(uint64(4), *input.ReportsSize)
+	err = s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+		InputIndex:  1,
+		Status:      model.InputCompletionStatus_Accepted,
+		Outputs:     [][]byte{[]byte("out2")},
+		Reports:     [][]byte{[]byte("rep1")},
+		OutputsHash: common.HexToHash("0xee"),
+		MachineHash: &machineHash,
+	})
+	s.Require().Nil(err)
+
+	processed, err := s.repo.GetProcessedInputs(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), processed)
+
+	inputIndex := uint64(1)
+	outputs, total, err := s.repo.ListOutputs(s.ctx, s.app.Name,
+		repository.OutputFilter{InputIndex: &inputIndex}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(1), total)
+	s.Require().Len(outputs, 1)
+	s.Equal(uint64(2), outputs[0].Index)
+	s.Equal([]byte("out2"), outputs[0].RawData)
+
+	reports, total, err := s.repo.ListReports(s.ctx, s.app.Name,
+		repository.ReportFilter{}, repository.Pagination{Limit: 1, Offset: 1}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), total)
+	s.Require().Len(reports, 1)
+	s.Equal([]byte("rep1"), reports[0].RawData)
+
+	status := model.InputCompletionStatus_Accepted
+	inputs, total, err := s.repo.ListInputs(s.ctx, s.app.Name,
+		repository.InputFilter{Status: &status}, repository.Pagination{}, true)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), total)
+	s.Require().Len(inputs, 2)
+	s.Equal(uint64(1), inputs[0].Index)
+	s.Equal(machineHash, *inputs[0].MachineHash)
+
+	updated, err := s.repo.UpdateEpochsInputsProcessed(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(int64(1), updated)
+
+	siblings := []common.Hash{common.HexToHash("0x1"), common.HexToHash("0x2")}
+	outputs[0].OutputHashesSiblings = siblings
+	outputHash := common.HexToHash("0x3")
+	outputs[0].Hash = &outputHash
+	claim := common.HexToHash("0x4")
+	err = s.repo.StoreClaimAndProofs(s.ctx, &model.Epoch{
+		ApplicationID: s.app.ID,
+		Index:         0,
+		ClaimHash:     &claim,
+		Status:        model.EpochStatus_ClaimComputed,
+	}, outputs)
+	s.Require().Nil(err)
+
+	output, err := s.repo.GetOutput(s.ctx, s.app.Name, 2)
+	s.Require().Nil(err)
+	s.Equal(siblings, output.OutputHashesSiblings)
+	s.Equal(outputHash, *output.Hash)
+}
+
+// LargePayload is larger than the blob threshold the backends that offload
+// payloads are tested with
+var LargePayload = func() []byte {
+	data := make([]byte, 1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}()
+
+func (s *RepositorySuite) TestLargePayloads() {
+	input := append([]byte("input"), LargePayload...)
+	output := append([]byte("output"), LargePayload...)
+	report := append([]byte("report"), LargePayload...)
+	s.createEpoch(0, model.EpochStatus_Closed,
+		&model.Input{Index: 0, BlockNumber: 1, RawData: input, TransactionReference: common.HexToHash("0xa")},
+	)
+	err := s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+		InputIndex:  0,
+		Status:      model.InputCompletionStatus_Accepted,
+		Outputs:     [][]byte{output},
+		Reports:     [][]byte{report},
+		OutputsHash: common.HexToHash("0xee"),
+		MachineHash: &common.Hash{0xff},
+	})
+	s.Require().Nil(err)
+
+	got, err := s.repo.GetInput(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Equal(input, got.RawData)
+	inputs, _, err := s.repo.ListInputs(s.ctx, s.app.Name, repository.InputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(inputs, 1)
+	s.Equal(input, inputs[0].RawData)
+
+	gotOutput, err := s.repo.GetOutput(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Equal(output, gotOutput.RawData)
+	outputs, _, err := s.repo.ListOutputs(s.ctx, s.app.Name, repository.OutputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(outputs, 1)
+	s.Equal(output, outputs[0].RawData)
+
+	gotReport, err := s.repo.GetReport(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Equal(report, gotReport.RawData)
+	reports, _, err := s.repo.ListReports(s.ctx, s.app.Name, repository.ReportFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(reports, 1)
+	s.Equal(report, reports[0].RawData)
+}
+
+func (s *RepositorySuite) TestClaimPairs() {
+	claim := common.HexToHash("0xc1")
+	for i := range uint64(3) {
+		s.createEpoch(i, model.EpochStatus_Closed)
+	}
+	for i, status := range []model.EpochStatus{
+		model.EpochStatus_ClaimAccepted,
+		model.EpochStatus_ClaimComputed,
+		model.EpochStatus_ClaimComputed,
+	} {
+		err := s.repo.UpdateEpoch(s.ctx, s.app.Name, &model.Epoch{
+			ApplicationID: s.app.ID,
+			Index:         uint64(i),
+			ClaimHash:     &claim,
+			Status:        status,
+		})
+		s.Require().Nil(err)
+	}
+
+	accepted, computed, apps, err := s.repo.SelectSubmittedClaimPairsPerApp(s.ctx)
+	s.Require().Nil(err)
+	s.Require().Contains(accepted, s.app.ID)
+	s.Require().Contains(computed, s.app.ID)
+	s.Require().Contains(apps, s.app.ID)
+	s.Equal(uint64(0), accepted[s.app.ID].Index)
+	s.Equal(uint64(1), computed[s.app.ID].Index)
+
+	txHash := common.HexToHash("0xdd")
+	s.Require().Nil(s.repo.UpdateEpochWithSubmittedClaim(s.ctx, s.app.ID, 1, txHash))
+	s.NotNil(s.repo.UpdateEpochWithSubmittedClaim(s.ctx, s.app.ID, 1, txHash))
+
+	accepted, submitted, _, err := s.repo.SelectAcceptedClaimPairsPerApp(s.ctx)
+	s.Require().Nil(err)
+	s.Equal(uint64(0), accepted[s.app.ID].Index)
+	s.Equal(uint64(1), submitted[s.app.ID].Index)
+	s.Equal(txHash, *submitted[s.app.ID].ClaimTransactionHash)
+
+	s.Require().Nil(s.repo.UpdateEpochWithAcceptedClaim(s.ctx, s.app.ID, 1))
+	index, err := s.repo.GetLastAcceptedEpochIndex(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(uint64(1), index)
+}
+
+func (s *RepositorySuite) TestNodeConfig() {
+	nc := &model.NodeConfig[map[string]int]{Key: "test", Value: map[string]int{"a": 1}}
+	s.Require().Nil(repository.SaveNodeConfig(s.ctx, s.repo, nc))
+	nc.Value["a"] = 2
+	s.Require().Nil(repository.SaveNodeConfig(s.ctx, s.repo, nc))
+
+	loaded, err := repository.LoadNodeConfig[map[string]int](s.ctx, s.repo, "test")
+	s.Require().Nil(err)
+	s.Equal(2, loaded.Value["a"])
+	s.False(loaded.CreatedAt.IsZero())
+}
+
+func (s *RepositorySuite) TestPing() {
+	s.Nil(s.repo.Ping(s.ctx))
+	s.repo.Close()
+	s.NotNil(s.repo.Ping(s.ctx))
+}
+
+func (s *RepositorySuite) TestCreateEpochsAndInputsIsAtomic() {
+	s.createEpoch(0, model.EpochStatus_Closed,
+		&model.Input{Index: 0, BlockNumber: 1, RawData: []byte("a"), TransactionReference: common.HexToHash("0xa")},
+	)
+
+	// the second input reuses a transaction reference, so nothing must be stored
+	epoch := &model.Epoch{ApplicationID: s.app.ID, Index: 1, FirstBlock: 10, LastBlock: 19, Status: model.EpochStatus_Open}
+	err := s.repo.CreateEpochsAndInputs(s.ctx, s.app.Name, map[*model.Epoch][]*model.Input{epoch: {
+		{Index: 1, BlockNumber: 11, RawData: []byte("b"), Status: model.InputCompletionStatus_None,
+			TransactionReference: common.HexToHash("0xb")},
+		{Index: 2, BlockNumber: 12, RawData: []byte("c"), Status: model.InputCompletionStatus_None,
+			TransactionReference: common.HexToHash("0xa")},
+	}}, 19)
+	s.NotNil(err)
+
+	e, err := s.repo.GetEpoch(s.ctx, s.app.Name, 1)
+	s.Require().Nil(err)
+	s.Nil(e)
+	in, err := s.repo.GetInput(s.ctx, s.app.Name, 1)
+	s.Require().Nil(err)
+	s.Nil(in)
+	app, err := s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(uint64(9), app.LastInputCheckBlock)
+}
+
+func (s *RepositorySuite) TestOutputIndexes() {
+	s.createEpoch(0, model.EpochStatus_Closed,
+		&model.Input{Index: 0, BlockNumber: 1, RawData: []byte("a"), TransactionReference: common.HexToHash("0xa")},
+		&model.Input{Index: 1, BlockNumber: 2, RawData: []byte("b"), TransactionReference: common.HexToHash("0xb")},
+		&model.Input{Index: 2, BlockNumber: 3, RawData: []byte("c"), TransactionReference: common.HexToHash("0xc")},
+	)
+	machineHash := common.HexToHash("0xff")
+	for i, status := range []model.InputCompletionStatus{
+		model.InputCompletionStatus_Accepted,
+		model.InputCompletionStatus_Rejected,
+		model.InputCompletionStatus_Accepted,
+	} {
+		res := &model.AdvanceResult{
+			InputIndex:  uint64(i),
+			Status:      status,
+			Reports:     [][]byte{[]byte("rep")},
+			MachineHash: &machineHash,
+		}
+		if status == model.InputCompletionStatus_Accepted {
+			res.Outputs = [][]byte{[]byte("out")}
+		}
+		s.Require().Nil(s.repo.StoreAdvanceResult(s.ctx, s.app.ID, res))
+	}
+
+	outputs, _, err := s.repo.ListOutputs(s.ctx, s.app.Name, repository.OutputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(outputs, 2)
+	s.Equal(uint64(0), outputs[0].InputIndex)
+	s.Equal(uint64(1), outputs[1].Index)
+	s.Equal(uint64(2), outputs[1].InputIndex)
+
+	// reports are kept for every input
+	reports, _, err := s.repo.ListReports(s.ctx, s.app.Name, repository.ReportFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(reports, 3)
+	s.Equal(uint64(2), reports[2].Index)
+
+	// a failed advance result leaves no trace
+	err = s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+		InputIndex:  3,
+		Status:      model.InputCompletionStatus_Accepted,
+		Reports:     [][]byte{[]byte("rep")},
+		MachineHash: &machineHash,
+	})
+	s.NotNil(err)
+	_, total, err := s.repo.ListReports(s.ctx, s.app.Name, repository.ReportFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(3), total)
+}
+
+func (s *RepositorySuite) TestCursorPagination() {
+	var inputs []*model.Input
+	for i := range uint64(5) {
+		inputs = append(inputs, &model.Input{Index: i, BlockNumber: i + 1, RawData: []byte{byte(i)},
+			TransactionReference: common.BigToHash(new(big.Int).SetUint64(i + 1))})
+	}
+	s.createEpoch(0, model.EpochStatus_Open, inputs...)
+
+	after := uint64(1)
+	page, total, err := s.repo.ListInputs(s.ctx, s.app.Name, repository.InputFilter{},
+		repository.Pagination{Limit: 2, After: &after}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(3), total)
+	s.Require().Len(page, 2)
+	s.Equal(uint64(2), page[0].Index)
+	s.Equal(uint64(3), page[1].Index)
+
+	after = 3
+	page, total, err = s.repo.ListInputs(s.ctx, s.app.Name, repository.InputFilter{},
+		repository.Pagination{After: &after}, true)
+	s.Require().Nil(err)
+	s.Equal(uint64(3), total)
+	s.Require().Len(page, 3)
+	s.Equal(uint64(2), page[0].Index)
+	s.Equal(uint64(0), page[2].Index)
+
+	epochs, _, err := s.repo.ListEpochs(s.ctx, s.app.Name, repository.EpochFilter{},
+		repository.Pagination{After: &after}, false)
+	s.Require().Nil(err)
+	s.Empty(epochs)
+}
+
+func (s *RepositorySuite) TestStreamInputs() {
+	var inputs []*model.Input
+	for i := range uint64(7) {
+		inputs = append(inputs, &model.Input{Index: i, BlockNumber: i + 1, RawData: []byte{byte(i)},
+			TransactionReference: common.BigToHash(new(big.Int).SetUint64(i + 1))})
+	}
+	s.createEpoch(0, model.EpochStatus_Open, inputs...)
+
+	var indexes []uint64
+	for in, err := range repository.StreamInputs(s.ctx, s.repo, s.app.Name, repository.InputFilter{},
+		repository.Pagination{Limit: 3}, false) {
+		s.Require().Nil(err)
+		indexes = append(indexes, in.Index)
+	}
+	s.Equal([]uint64{0, 1, 2, 3, 4, 5, 6}, indexes)
+
+	indexes = nil
+	after := uint64(4)
+	for in, err := range repository.StreamInputs(s.ctx, s.repo, s.app.Name, repository.InputFilter{},
+		repository.Pagination{Limit: 2, After: &after}, true) {
+		s.Require().Nil(err)
+		indexes = append(indexes, in.Index)
+	}
+	s.Equal([]uint64{3, 2, 1, 0}, indexes)
+
+	// stopping early must not fetch more pages
+	indexes = nil
+	for in, err := range repository.StreamInputs(s.ctx, s.repo, s.app.Name, repository.InputFilter{},
+		repository.Pagination{Limit: 2}, false) {
+		s.Require().Nil(err)
+		indexes = append(indexes, in.Index)
+		if in.Index == 2 {
+			break
+		}
+	}
+	s.Equal([]uint64{0, 1, 2}, indexes)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	for _, err := range repository.StreamInputs(ctx, s.repo, s.app.Name, repository.InputFilter{},
+		repository.Pagination{}, false) {
+		s.ErrorIs(err, context.Canceled)
+	}
+}
+
+func (s *RepositorySuite) TestPruneApplication() {
+	machineHash := common.HexToHash("0xff")
+	// the msgSender argument of the EvmAdvance call starts at byte 80
+	sender := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	payload := append(append(make([]byte, 80), sender.Bytes()...), "input"...) // nolint: mnd
+	for i := range uint64(3) {
+		s.createEpoch(i, model.EpochStatus_ClaimAccepted, &model.Input{
+			Index:                i,
+			BlockNumber:          i * s.app.EpochLength,
+			RawData:              payload,
+			TransactionReference: common.BigToHash(new(big.Int).SetUint64(i)),
+		})
+		err := s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+			InputIndex:  i,
+			Status:      model.InputCompletionStatus_Accepted,
+			Outputs:     [][]byte{[]byte("output")},
+			Reports:     [][]byte{[]byte("report")},
+			OutputsHash: common.HexToHash("0xee"),
+			MachineHash: &machineHash,
+		})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.repo.UpdateInputSnapshotURI(s.ctx, s.app.ID, 0, "/snapshots/0"))
+
+	app, err := s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	result, err := repository.PruneApplication(s.ctx, s.repo, app)
+	s.Require().Nil(err)
+	s.Nil(result, "retaining every epoch by default")
+
+	app.ExecutionParameters.RetainedEpochs = 1
+	s.Require().Nil(s.repo.UpdateExecutionParameters(s.ctx, &app.ExecutionParameters))
+	app, err = s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(uint64(1), app.ExecutionParameters.RetainedEpochs)
+
+	result, err = repository.PruneApplication(s.ctx, s.repo, app)
+	s.Require().Nil(err)
+	s.Require().NotNil(result)
+	s.Equal(uint64(2), result.EpochIndex)
+	s.Equal(uint64(2), result.Reports)
+	s.Equal(uint64(1), result.Inputs, "inputs after the snapshot are kept for replay")
+
+	inputs, _, err := s.repo.ListInputs(s.ctx, s.app.Name,
+		repository.InputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(inputs, 3)
+	s.Empty(inputs[0].RawData)
+	s.Equal(payload, inputs[1].RawData)
+	s.Equal(payload, inputs[2].RawData)
+
+	// the pruned inputs are still found by their sender
+	inputs, total, err := s.repo.ListInputs(s.ctx, s.app.Name,
+		repository.InputFilter{Sender: &sender}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(3), total)
+	s.Len(inputs, 3)
+	other := common.HexToAddress("0x2")
+	_, total, err = s.repo.ListInputs(s.ctx, s.app.Name,
+		repository.InputFilter{Sender: &other}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Equal(uint64(0), total)
+
+	reports, _, err := s.repo.ListReports(s.ctx, s.app.Name,
+		repository.ReportFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(reports, 3)
+	s.Empty(reports[0].RawData)
+	s.Empty(reports[1].RawData)
+	s.Equal([]byte("report"), reports[2].RawData)
+
+	outputs, _, err := s.repo.ListOutputs(s.ctx, s.app.Name,
+		repository.OutputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(outputs, 3)
+	for _, output := range outputs {
+		s.Equal([]byte("output"), output.RawData)
+	}
+
+	// pruning again has nothing left to do
+	result, err = repository.PruneApplication(s.ctx, s.repo, app)
+	s.Require().Nil(err)
+	s.Equal(uint64(0), result.Inputs)
+	s.Equal(uint64(0), result.Reports)
+}
+
+func (s *RepositorySuite) TestRevertBlocks() {
+	machineHash := common.HexToHash("0xff")
+	blocks := []uint64{2, 12, 15, 22}
+	for i, block := range blocks {
+		blockHash := common.BigToHash(new(big.Int).SetUint64(block))
+		s.createEpoch(block/s.app.EpochLength, model.EpochStatus_Open, &model.Input{
+			Index:                uint64(i),
+			BlockNumber:          block,
+			BlockHash:            &blockHash,
+			RawData:              []byte("input"),
+			TransactionReference: common.BigToHash(big.NewInt(int64(i))),
+		})
+		err := s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+			InputIndex:  uint64(i),
+			Status:      model.InputCompletionStatus_Accepted,
+			Outputs:     [][]byte{[]byte("output")},
+			Reports:     [][]byte{[]byte("report")},
+			OutputsHash: common.HexToHash("0xee"),
+			MachineHash: &machineHash,
+		})
+		s.Require().Nil(err)
+	}
+
+	// close epoch 1 recording the hash of its last block
+	closedHash := common.HexToHash("0x19")
+	err := s.repo.CreateEpochsAndInputs(s.ctx, s.app.Name, map[*model.Epoch][]*model.Input{
+		{Index: 1, FirstBlock: 10, LastBlock: 19, BlockHash: &closedHash, Status: model.EpochStatus_Closed}: nil,
+	}, 29)
+	s.Require().Nil(err)
+
+	epoch, err := s.repo.GetEpoch(s.ctx, s.app.Name, 1)
+	s.Require().Nil(err)
+	s.Equal(model.EpochStatus_Closed, epoch.Status)
+	s.Equal(&closedHash, epoch.BlockHash)
+	input, err := s.repo.GetInput(s.ctx, s.app.Name, 2)
+	s.Require().Nil(err)
+	s.Equal(common.BigToHash(big.NewInt(15)), *input.BlockHash)
+
+	inputs, err := s.repo.RevertBlocks(s.ctx, s.app.ID, 13)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), inputs)
+
+	app, err := s.repo.GetApplication(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), app.ProcessedInputs)
+	s.Equal(uint64(13), app.LastInputCheckBlock)
+
+	epochs, _, err := s.repo.ListEpochs(s.ctx, s.app.Name,
+		repository.EpochFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Require().Len(epochs, 2)
+	s.Equal(model.EpochStatus_Open, epochs[1].Status)
+	s.Nil(epochs[1].BlockHash)
+
+	outputs, _, err := s.repo.ListOutputs(s.ctx, s.app.Name,
+		repository.OutputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Len(outputs, 2)
+	reports, _, err := s.repo.ListReports(s.ctx, s.app.Name,
+		repository.ReportFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Len(reports, 2)
+
+	// the reverted blocks can be read again
+	newHash := common.BigToHash(big.NewInt(14))
+	s.createEpoch(1, model.EpochStatus_Open, &model.Input{
+		Index:                2,
+		BlockNumber:          14,
+		BlockHash:            &newHash,
+		RawData:              []byte("input"),
+		TransactionReference: common.BigToHash(big.NewInt(2)),
+	})
+	s.createEpoch(2, model.EpochStatus_Open)
+	epoch, err = s.repo.GetEpoch(s.ctx, s.app.Name, 2)
+	s.Require().Nil(err)
+	s.Equal(uint64(2), epoch.VirtualIndex)
+
+	// the result of the reverted input is not stored on the one read again
+	oldHash := common.BigToHash(big.NewInt(15))
+	result := &model.AdvanceResult{
+		InputIndex:     2,
+		Status:         model.InputCompletionStatus_Accepted,
+		Outputs:        [][]byte{[]byte("stale")},
+		OutputsHash:    common.HexToHash("0xee"),
+		MachineHash:    &machineHash,
+		InputBlockHash: &oldHash,
+	}
+	err = s.repo.StoreAdvanceResult(s.ctx, s.app.ID, result)
+	s.ErrorIs(err, repository.ErrInputReverted)
+	input, err = s.repo.GetInput(s.ctx, s.app.Name, 2)
+	s.Require().Nil(err)
+	s.Equal(model.InputCompletionStatus_None, input.Status)
+	outputs, _, err = s.repo.ListOutputs(s.ctx, s.app.Name,
+		repository.OutputFilter{}, repository.Pagination{}, false)
+	s.Require().Nil(err)
+	s.Len(outputs, 2)
+
+	result.InputBlockHash = &newHash
+	s.Require().Nil(s.repo.StoreAdvanceResult(s.ctx, s.app.ID, result))
+}
+
+func (s *RepositorySuite) TestRevertBlocksKeepsClaimedEpochs() {
+	s.createEpoch(0, model.EpochStatus_ClaimSubmitted, &model.Input{
+		Index:                0,
+		BlockNumber:          5,
+		RawData:              []byte("input"),
+		TransactionReference: common.HexToHash("0xa"),
+	})
+
+	_, err := s.repo.RevertBlocks(s.ctx, s.app.ID, 3)
+	s.ErrorIs(err, repository.ErrClaimedEpochReverted)
+
+	epoch, err := s.repo.GetEpoch(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.Equal(model.EpochStatus_ClaimSubmitted, epoch.Status)
+	input, err := s.repo.GetInput(s.ctx, s.app.Name, 0)
+	s.Require().Nil(err)
+	s.NotNil(input)
+}
+
+func (s *RepositorySuite) TestListSnapshots() {
+	machineHash := common.HexToHash("0xff")
+	for i := range uint64(3) {
+		s.createEpoch(i, model.EpochStatus_Open, &model.Input{
+			Index:                i,
+			BlockNumber:          i * s.app.EpochLength,
+			RawData:              []byte("input"),
+			TransactionReference: common.BigToHash(new(big.Int).SetUint64(i)),
+		})
+		err := s.repo.StoreAdvanceResult(s.ctx, s.app.ID, &model.AdvanceResult{
+			InputIndex:  i,
+			Status:      model.InputCompletionStatus_Accepted,
+			OutputsHash: common.HexToHash("0xee"),
+			MachineHash: &machineHash,
+		})
+		s.Require().Nil(err)
+		s.Require().Nil(s.repo.UpdateInputSnapshotURI(s.ctx, s.app.ID, i, fmt.Sprintf("/snapshots/%d", i)))
+	}
+
+	snapshots, err := s.repo.ListSnapshots(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Require().Len(snapshots, 3)
+	for i, snapshot := range snapshots {
+		s.Equal(uint64(2-i), snapshot.Index)
+		s.Equal(fmt.Sprintf("/snapshots/%d", 2-i), *snapshot.SnapshotURI)
+		s.Equal(&machineHash, snapshot.MachineHash)
+	}
+
+	s.Require().Nil(s.repo.ClearInputSnapshotURI(s.ctx, s.app.ID, 1))
+	snapshots, err = s.repo.ListSnapshots(s.ctx, s.app.Name)
+	s.Require().Nil(err)
+	s.Require().Len(snapshots, 2)
+	s.Equal(uint64(2), snapshots[0].Index)
+	s.Equal(uint64(0), snapshots[1].Index)
+
+	uris, err := s.repo.ListSnapshotURIs(s.ctx)
+	s.Require().Nil(err)
+	s.ElementsMatch([]string{"/snapshots/0", "/snapshots/2"}, uris)
+}
@@ -31,7 +31,7 @@ type ValidatorRepositoryIntegrationSuite struct {
 	cancel           context.CancelFunc
 	validator        *validator.Service
 	repository       repository.Repository
-	postgresEndpoint config.Redacted[string]
+	databaseEndpoint config.Redacted[string]
 }
 
 func TestValidatorRepositoryIntegration(t *testing.T) {
@@ -42,19 +42,19 @@ func (s *ValidatorRepositoryIntegrationSuite) SetupSuite() {
 	s.ctx, s.cancel = context.WithTimeout(context.Background(), testTimeout)
 
 	var err error
-	s.postgresEndpoint.Value, err = db.GetTestDatabaseEndpoint()
+	s.databaseEndpoint.Value, err = db.GetTestDatabaseEndpoint()
 	s.Require().Nil(err)
 
-	err = db.SetupTestPostgres(s.postgresEndpoint.Value)
+	err = db.SetupTestDatabase(s.databaseEndpoint.Value)
 	s.Require().Nil(err)
 }
 
 func (s *ValidatorRepositoryIntegrationSuite) SetupSubTest() {
 	var err error
-	s.repository, err = factory.NewRepositoryFromConnectionString(s.ctx, s.postgresEndpoint.Value)
+	s.repository, err = factory.NewRepositoryFromConnectionString(s.ctx, s.databaseEndpoint.Value)
 	s.Require().Nil(err)
 
-	err = db.SetupTestPostgres(s.postgresEndpoint.Value)
+	err = db.SetupTestDatabase(s.databaseEndpoint.Value)
 	s.Require().Nil(err)
 
 	serviceArgs := validator.CreateInfo{